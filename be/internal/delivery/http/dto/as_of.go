@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+// AsOfQuery asks for the tree as it was at a point in time instead of as it is
+type AsOfQuery struct {
+	AsOf *time.Time `form:"as_of" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type RestoreAsOfRequest struct {
+	AsOf   time.Time `json:"as_of" binding:"required"`
+	RootID *int      `json:"root_id" binding:"omitempty,min=1"`
+}
+
+type RestoreAsOfSkipResponse struct {
+	MemberID *int   `json:"member_id,omitempty"`
+	SpouseID *int   `json:"spouse_id,omitempty"`
+	Error    string `json:"error"`
+}
+
+type RestoreAsOfResponse struct {
+	MembersRestored int                       `json:"members_restored"`
+	MembersUpdated  int                       `json:"members_updated"`
+	MembersDeleted  int                       `json:"members_deleted"`
+	SpousesRestored int                       `json:"spouses_restored"`
+	SpousesUpdated  int                       `json:"spouses_updated"`
+	SpousesRemoved  int                       `json:"spouses_removed"`
+	Skipped         []RestoreAsOfSkipResponse `json:"skipped"`
+}
@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditQuery struct {
+	TreeID      *int       `form:"tree_id" binding:"omitempty,min=1"`
+	ActorUserID *int       `form:"actor_user_id" binding:"omitempty,min=1"`
+	Action      *string    `form:"action" binding:"omitempty,max=50"`
+	TargetType  *string    `form:"target_type" binding:"omitempty,max=30"`
+	TargetID    *string    `form:"target_id" binding:"omitempty,max=100"`
+	From        *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To          *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	PaginationQuery
+}
+
+type AuditEventResponse struct {
+	AuditID     int64           `json:"audit_id"`
+	ActorUserID *int            `json:"actor_user_id"`
+	ActorName   *string         `json:"actor_name"`
+	Action      string          `json:"action"`
+	TargetType  string          `json:"target_type"`
+	TargetID    *string         `json:"target_id"`
+	TreeID      *int            `json:"tree_id"`
+	Details     json.RawMessage `json:"details,omitempty"`
+	IPAddress   string          `json:"ip_address"`
+	UserAgent   string          `json:"user_agent"`
+	RequestID   string          `json:"request_id"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+type PaginatedAuditEventsResponse struct {
+	Events     []AuditEventResponse `json:"events"`
+	NextCursor *string              `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+type BiographyRequest struct {
+	Biography string `json:"biography" binding:"max=100000"` // Markdown, empty removes it
+	Version   int    `json:"version" binding:"required,min=1"`
+}
+
+type BiographyResponse struct {
+	Biographies map[string]string `json:"biographies"` // language_code -> Markdown
+	Version     int               `json:"version"`
+}
+
+type NotesRequest struct {
+	Notes   string `json:"notes" binding:"max=50000"`
+	Version int    `json:"version" binding:"required,min=1"`
+}
+
+type NotesResponse struct {
+	Notes   *string `json:"notes"`
+	Version int     `json:"version"`
+}
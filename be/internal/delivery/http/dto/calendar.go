@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+type CalendarFeedIDUri struct {
+	TreeID int `uri:"tree_id" binding:"required,min=1"`
+	FeedID int `uri:"feed_id" binding:"required,min=1"`
+}
+
+type PublicCalendarUri struct {
+	Token string `uri:"token" binding:"required"`
+}
+
+type CalendarFeedResponse struct {
+	FeedID         int        `json:"feed_id"`
+	TreeID         int        `json:"tree_id"`
+	Token          string     `json:"token"`
+	URL            string     `json:"url"`
+	CreatedAt      time.Time  `json:"created_at"`
+	LastAccessedAt *time.Time `json:"last_accessed_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+}
+
+type CalendarFeedListResponse struct {
+	Feeds []CalendarFeedResponse `json:"feeds"`
+}
@@ -0,0 +1,44 @@
+package dto
+
+import "time"
+
+type ChangesetIDUri struct {
+	TreeID      int    `uri:"tree_id" binding:"required,min=1"`
+	ChangesetID string `uri:"changeset_id" binding:"required,uuid"`
+}
+
+type CreateChangesetRequest struct {
+	Description *string `json:"description" binding:"omitempty,max=255"`
+}
+
+type ChangesetResponse struct {
+	ChangesetID  string     `json:"changeset_id"`
+	TreeID       int        `json:"tree_id"`
+	UserID       int        `json:"user_id"`
+	UserFullName string     `json:"user_full_name"`
+	Description  *string    `json:"description,omitempty"`
+	ChangeTypes  []string   `json:"change_types"`
+	MemberIDs    []int      `json:"member_ids"`
+	EntryCount   int        `json:"entry_count"`
+	Points       int        `json:"points"`
+	CreatedAt    time.Time  `json:"created_at"`
+	LastChangeAt *time.Time `json:"last_change_at,omitempty"`
+	RevertedAt   *time.Time `json:"reverted_at,omitempty"`
+	RevertedBy   *int       `json:"reverted_by,omitempty"`
+	RevertedIn   *string    `json:"reverted_in,omitempty"`
+}
+
+type PaginatedChangesetsResponse struct {
+	Changesets []ChangesetResponse `json:"changesets"`
+	NextCursor *string             `json:"next_cursor,omitempty"`
+}
+
+type RevertChangesetResponse struct {
+	Changeset       ChangesetResponse `json:"changeset"`
+	MembersRestored int               `json:"members_restored"`
+	MembersUpdated  int               `json:"members_updated"`
+	MembersDeleted  int               `json:"members_deleted"`
+	SpousesRestored int               `json:"spouses_restored"`
+	SpousesUpdated  int               `json:"spouses_updated"`
+	SpousesRemoved  int               `json:"spouses_removed"`
+}
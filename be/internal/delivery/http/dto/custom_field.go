@@ -0,0 +1,42 @@
+package dto
+
+import "time"
+
+type CustomFieldIDUri struct {
+	TreeID  int `uri:"tree_id" binding:"required,min=1"`
+	FieldID int `uri:"field_id" binding:"required,min=1"`
+}
+
+type CreateCustomFieldRequest struct {
+	Key          string            `json:"key" binding:"required,max=50"`
+	Names        map[string]string `json:"names" binding:"required,min=1"` // language_code -> name
+	Type         string            `json:"type" binding:"required,oneof=text number date enum member"`
+	Options      []string          `json:"options" binding:"omitempty,max=100,dive,max=255"`
+	IsRequired   bool              `json:"is_required"`
+	DisplayOrder int               `json:"display_order"`
+}
+
+type UpdateCustomFieldRequest struct {
+	Names        map[string]string `json:"names" binding:"required,min=1"` // language_code -> name
+	Options      []string          `json:"options" binding:"omitempty,max=100,dive,max=255"`
+	IsRequired   bool              `json:"is_required"`
+	DisplayOrder int               `json:"display_order"`
+}
+
+type CustomFieldResponse struct {
+	FieldID      int               `json:"field_id"`
+	TreeID       int               `json:"tree_id"`
+	Key          string            `json:"key"`
+	Name         string            `json:"name"`
+	Names        map[string]string `json:"names"`
+	Type         string            `json:"type"`
+	Options      []string          `json:"options"`
+	IsRequired   bool              `json:"is_required"`
+	DisplayOrder int               `json:"display_order"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+type CustomFieldListResponse struct {
+	Fields []CustomFieldResponse `json:"fields"`
+}
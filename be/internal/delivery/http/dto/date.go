@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"strconv"
 	"time"
+
+	"github.com/escalopa/family-tree/internal/pkg/hijri"
 )
 
 type Date struct {
@@ -91,3 +93,64 @@ func FromTimePtr(t *time.Time) *Date {
 	}
 	return &Date{Time: *t}
 }
+
+// HijriDate is a date in the Hijri calendar, written as YYYY-MM-DD. It is kept
+// apart from Date because Hijri months have days, like 30 Safar, that do not
+// exist in the Gregorian month with the same number
+type HijriDate struct {
+	hijri.Date
+}
+
+func (d *HijriDate) UnmarshalJSON(b []byte) error {
+	s := string(b)
+
+	if s == "null" || s == `""` || s == "" {
+		d.Date = hijri.Date{}
+		return nil
+	}
+
+	s, err := strconv.Unquote(s)
+	if err != nil {
+		return fmt.Errorf("invalid hijri date format, expected YYYY-MM-DD: %w", err)
+	}
+
+	date, err := hijri.Parse(s)
+	if err != nil {
+		return err
+	}
+
+	d.Date = date
+	return nil
+}
+
+func (d HijriDate) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(`"` + d.Date.String() + `"`), nil
+}
+
+func (d HijriDate) IsZero() bool {
+	return d.Date == hijri.Date{}
+}
+
+func (d *HijriDate) ToTimePtr() *time.Time {
+	if d == nil || d.IsZero() {
+		return nil
+	}
+	t := d.ToGregorian()
+	return &t
+}
+
+// HijriFromTimePtr returns the Hijri twin of a stored date, or nil when there
+// is none, which includes dates whose year is hidden
+func HijriFromTimePtr(t *time.Time) *HijriDate {
+	if t == nil {
+		return nil
+	}
+	date, ok := hijri.FromGregorian(*t)
+	if !ok {
+		return nil
+	}
+	return &HijriDate{Date: date}
+}
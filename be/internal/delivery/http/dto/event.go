@@ -0,0 +1,39 @@
+package dto
+
+import "time"
+
+type EventRequest struct {
+	EventType      string     `json:"event_type" binding:"required,oneof=education migration military_service hajj residence religious custom"`
+	CustomType     *string    `json:"custom_type" binding:"omitempty,max=100"`
+	Date           *Date      `json:"date"`
+	DateQualifier  string     `json:"date_qualifier" binding:"omitempty,oneof=exact about before after between year month"`
+	DateEnd        *Date      `json:"date_end"`
+	DateCalendar   string     `json:"date_calendar" binding:"omitempty,oneof=gregorian hijri"`
+	DateHijri      *HijriDate `json:"date_hijri"`
+	DateEndHijri   *HijriDate `json:"date_end_hijri"`
+	PlaceID        *int       `json:"place_id" binding:"omitempty,min=1"`
+	Description    *string    `json:"description" binding:"omitempty,max=2000"`
+	ParticipantIDs []int      `json:"participant_ids" binding:"omitempty,dive,min=1"`
+}
+
+type EventResponse struct {
+	EventID        int        `json:"event_id"`
+	MemberID       int        `json:"member_id"`
+	EventType      string     `json:"event_type"`
+	CustomType     *string    `json:"custom_type,omitempty"`
+	Date           *Date      `json:"date"`
+	DateQualifier  string     `json:"date_qualifier"`
+	DateEnd        *Date      `json:"date_end,omitempty"`
+	DateCalendar   string     `json:"date_calendar"`
+	DateHijri      *HijriDate `json:"date_hijri,omitempty"`
+	DateEndHijri   *HijriDate `json:"date_end_hijri,omitempty"`
+	PlaceID        *int       `json:"place_id"`
+	Description    *string    `json:"description"`
+	ParticipantIDs []int      `json:"participant_ids"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type EventListResponse struct {
+	Events []EventResponse `json:"events"`
+}
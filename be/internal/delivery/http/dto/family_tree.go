@@ -11,6 +11,11 @@ type ShareIDUri struct {
 	ShareID int `uri:"share_id" binding:"required,min=1"`
 }
 
+type MembershipUri struct {
+	TreeID int `uri:"tree_id" binding:"required,min=1"`
+	UserID int `uri:"user_id" binding:"required,min=1"`
+}
+
 type InvitationIDUri struct {
 	InvitationID int `uri:"invitation_id" binding:"required,min=1"`
 }
@@ -26,26 +31,65 @@ type CreateFamilyTreeRequest struct {
 
 type InviteToTreeRequest struct {
 	Email     string  `json:"email" binding:"required,email"`
+	Role      string  `json:"role" binding:"omitempty,oneof=editor viewer"` // role granted on accepting, editor by default
 	Message   *string `json:"message"`
 	ExpiresAt *Date   `json:"expires_at"`
 }
 
 type CreateShareLinkRequest struct {
-	ExpiresAt *Date `json:"expires_at"`
-	MaxVisits *int  `json:"max_visits" binding:"omitempty,min=1"`
+	ExpiresAt    *Date  `json:"expires_at"`
+	MaxVisits    *int   `json:"max_visits" binding:"omitempty,min=1"`
+	LivingPolicy string `json:"living_policy" binding:"omitempty,oneof=redact names show"` // what the link shows of living members, redact by default
+}
+
+type UpdateShareLinkRequest struct {
+	LivingPolicy string `json:"living_policy" binding:"required,oneof=redact names show"`
+}
+
+type UpdateMembershipRequest struct {
+	Role string `json:"role" binding:"required,oneof=editor viewer"`
+}
+
+type TransferOwnershipRequest struct {
+	UserID int `json:"user_id" binding:"required,min=1"` // member who becomes the owner
+}
+
+// NameSettings picks the full-name format per language: lineage, nasab, patronymic or western
+type NameSettings struct {
+	Formats         map[string]string `json:"formats" binding:"omitempty,dive,keys,min=2,max=10,endkeys,oneof=lineage nasab patronymic western"` // language_code -> format
+	NasabDepth      int               `json:"nasab_depth" binding:"min=0,max=100"`                                                               // fathers named in a nasab, 0 names them all
+	NasabConnectors bool              `json:"nasab_connectors"`                                                                                  // put بن/بنت between the names
+}
+
+// PrivacyRule limits a field of the matching members for the matching
+// viewers, conditions left empty match everyone
+type PrivacyRule struct {
+	Field      string   `json:"field" binding:"required,oneof=names dates picture places profession nicknames custom_fields"`
+	Visibility string   `json:"visibility" binding:"required,oneof=visible no_year hidden"` // no_year only applies to dates
+	TreeRoles  []string `json:"tree_roles,omitempty" binding:"omitempty,dive,oneof=owner editor viewer public"`
+	BelowRole  int      `json:"below_role,omitempty" binding:"omitempty,oneof=100 200 300 400"` // viewers with a lower global role
+	Gender     string   `json:"gender,omitempty" binding:"omitempty,oneof=M F"`
+	Living     *bool    `json:"living,omitempty"`
+	MemberIDs  []int    `json:"member_ids,omitempty" binding:"omitempty,dive,min=1"`
+}
+
+// PrivacyPolicy holds the rules of a tree, the most restrictive matching rule wins
+type PrivacyPolicy struct {
+	Rules []PrivacyRule `json:"rules" binding:"max=100,dive"`
 }
 
 type FamilyTreeResponse struct {
-	TreeID      int       `json:"tree_id"`
-	Name        string    `json:"name"`
-	Description *string   `json:"description"`
-	OwnerUserID int       `json:"owner_user_id"`
-	OwnerName   string    `json:"owner_name,omitempty"`
-	OwnerEmail  string    `json:"owner_email,omitempty"`
-	UserRole    string    `json:"user_role,omitempty"`
-	MemberCount int       `json:"member_count"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	TreeID       int          `json:"tree_id"`
+	Name         string       `json:"name"`
+	Description  *string      `json:"description"`
+	OwnerUserID  int          `json:"owner_user_id"`
+	OwnerName    string       `json:"owner_name,omitempty"`
+	OwnerEmail   string       `json:"owner_email,omitempty"`
+	UserRole     string       `json:"user_role,omitempty"`
+	MemberCount  int          `json:"member_count"`
+	NameSettings NameSettings `json:"name_settings"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
 }
 
 type FamilyTreeListResponse struct {
@@ -60,6 +104,7 @@ type FamilyTreeInvitationResponse struct {
 	InviterName   string     `json:"inviter_name,omitempty"`
 	InviteeUserID *int       `json:"invitee_user_id"`
 	InviteeEmail  string     `json:"invitee_email"`
+	Role          string     `json:"role"`
 	Status        string     `json:"status"`
 	Message       *string    `json:"message"`
 	CreatedAt     time.Time  `json:"created_at"`
@@ -71,17 +116,31 @@ type FamilyTreeInvitationListResponse struct {
 	Invitations []FamilyTreeInvitationResponse `json:"invitations"`
 }
 
+type FamilyTreeMembershipResponse struct {
+	UserID   int       `json:"user_id"`
+	FullName string    `json:"full_name"`
+	Email    string    `json:"email"`
+	Avatar   *string   `json:"avatar"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type FamilyTreeMembershipListResponse struct {
+	Memberships []FamilyTreeMembershipResponse `json:"memberships"`
+}
+
 type FamilyTreeShareLinkResponse struct {
-	ShareID    int        `json:"share_id"`
-	TreeID     int        `json:"tree_id"`
-	Token      string     `json:"token"`
-	URL        string     `json:"url"`
-	CreatedBy  int        `json:"created_by"`
-	CreatedAt  time.Time  `json:"created_at"`
-	ExpiresAt  *time.Time `json:"expires_at"`
-	MaxVisits  *int       `json:"max_visits"`
-	VisitCount int        `json:"visit_count"`
-	RevokedAt  *time.Time `json:"revoked_at"`
+	ShareID      int        `json:"share_id"`
+	TreeID       int        `json:"tree_id"`
+	Token        string     `json:"token"`
+	URL          string     `json:"url"`
+	CreatedBy    int        `json:"created_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+	MaxVisits    *int       `json:"max_visits"`
+	VisitCount   int        `json:"visit_count"`
+	RevokedAt    *time.Time `json:"revoked_at"`
+	LivingPolicy string     `json:"living_policy"`
 }
 
 type FamilyTreeShareLinkListResponse struct {
@@ -0,0 +1,52 @@
+package dto
+
+type FamilyUnitIDUri struct {
+	TreeID       int `uri:"tree_id" binding:"required,min=1"`
+	FamilyUnitID int `uri:"family_unit_id" binding:"required,min=1"`
+}
+
+type FamilyUnitChildUri struct {
+	TreeID       int `uri:"tree_id" binding:"required,min=1"`
+	FamilyUnitID int `uri:"family_unit_id" binding:"required,min=1"`
+	ChildID      int `uri:"child_id" binding:"required,min=1"`
+}
+
+type CreateFamilyUnitRequest struct {
+	PartnerIDs       []int  `json:"partner_ids" binding:"required,min=1,max=2,dive,min=1"`
+	RelationshipType string `json:"relationship_type" binding:"required,oneof=marriage partnership unknown"`
+	Status           string `json:"status" binding:"required,oneof=active divorced separated widowed unknown"`
+	StartDate        *Date  `json:"start_date"`
+	EndDate          *Date  `json:"end_date"`
+}
+
+type UpdateFamilyUnitRequest struct {
+	RelationshipType string `json:"relationship_type" binding:"required,oneof=marriage partnership unknown"`
+	Status           string `json:"status" binding:"required,oneof=active divorced separated widowed unknown"`
+	StartDate        *Date  `json:"start_date"`
+	EndDate          *Date  `json:"end_date"`
+}
+
+// UpdateFamilyUnitStatusRequest marks how a partnership stands, the end date
+// is kept when omitted
+type UpdateFamilyUnitStatusRequest struct {
+	Status  string `json:"status" binding:"required,oneof=active divorced separated widowed unknown"`
+	EndDate *Date  `json:"end_date"`
+}
+
+type FamilyUnitChildRequest struct {
+	RelationType string `json:"relation_type" binding:"required,oneof=adopted step foster unknown"`
+}
+
+type FamilyUnitResponse struct {
+	FamilyUnitID     int            `json:"family_unit_id"`
+	TreeID           int            `json:"tree_id"`
+	RelationshipType string         `json:"relationship_type"`
+	Status           string         `json:"status"`
+	StartDate        *Date          `json:"start_date"`
+	EndDate          *Date          `json:"end_date"`
+	Source           string         `json:"source"`
+	SourceSpouseID   *int           `json:"source_spouse_id,omitempty"`
+	PartnerIDs       []int          `json:"partner_ids"`
+	ChildIDs         []int          `json:"child_ids"`
+	ChildRelations   map[int]string `json:"child_relations"`
+}
@@ -15,6 +15,21 @@ type MemberHistoryQuery struct {
 	PaginationQuery
 }
 
+type ActivityQuery struct {
+	UserID      *int       `form:"user_id" binding:"omitempty,min=1"`
+	ChangeType  []string   `form:"change_type" binding:"omitempty,dive,max=50"`
+	MemberID    *int       `form:"member_id" binding:"omitempty,min=1"`
+	ChangesetID *string    `form:"changeset_id" binding:"omitempty,uuid"`
+	From        *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To          *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	PaginationQuery
+}
+
+type HistoryDiffQuery struct {
+	From int `form:"from" binding:"required,min=1"`
+	To   int `form:"to" binding:"required,min=1"`
+}
+
 type RollbackMemberRequest struct {
 	HistoryID int `json:"history_id" binding:"required,min=1"`
 }
@@ -31,9 +46,41 @@ type HistoryResponse struct {
 	OldValues     json.RawMessage `json:"old_values"`
 	NewValues     json.RawMessage `json:"new_values"`
 	MemberVersion int             `json:"member_version"`
+	ChangesetID   *string         `json:"changeset_id,omitempty"`
 }
 
 type PaginatedHistoryResponse struct {
 	History    []HistoryResponse `json:"history"`
 	NextCursor *string           `json:"next_cursor,omitempty"`
 }
+
+type NameChangeResponse struct {
+	Language      string  `json:"language"`
+	LanguageLabel string  `json:"language_label"`
+	Before        *string `json:"before"`
+	After         *string `json:"after"`
+}
+
+type NicknameChangesResponse struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+type SpouseChangeResponse struct {
+	SpouseID      int                   `json:"spouse_id"`
+	PartnerID     int                   `json:"partner_id"`
+	PartnerName   string                `json:"partner_name,omitempty"`
+	PartnerGender string                `json:"partner_gender,omitempty"`
+	Change        string                `json:"change"`
+	Fields        []FieldChangeResponse `json:"fields"`
+}
+
+type HistoryDiffResponse struct {
+	MemberID  int                     `json:"member_id"`
+	From      int                     `json:"from"`
+	To        int                     `json:"to"`
+	Fields    []FieldChangeResponse   `json:"fields"`
+	Names     []NameChangeResponse    `json:"names"`
+	Nicknames NicknameChangesResponse `json:"nicknames"`
+	Spouses   []SpouseChangeResponse  `json:"spouses"`
+}
@@ -0,0 +1,56 @@
+package dto
+
+import "time"
+
+type MediaRequest struct {
+	Caption       *string    `json:"caption" binding:"omitempty,max=2000"`
+	Date          *Date      `json:"date"`
+	DateQualifier string     `json:"date_qualifier" binding:"omitempty,oneof=exact about before after between year month"`
+	DateEnd       *Date      `json:"date_end"`
+	DateCalendar  string     `json:"date_calendar" binding:"omitempty,oneof=gregorian hijri"`
+	DateHijri     *HijriDate `json:"date_hijri"`
+	DateEndHijri  *HijriDate `json:"date_end_hijri"`
+	PlaceID       *int       `json:"place_id" binding:"omitempty,min=1"`
+}
+
+// FaceRect is given as fractions of the image width and height
+type FaceRect struct {
+	X      float64 `json:"x" binding:"min=0,max=1"`
+	Y      float64 `json:"y" binding:"min=0,max=1"`
+	Width  float64 `json:"width" binding:"gt=0,max=1"`
+	Height float64 `json:"height" binding:"gt=0,max=1"`
+}
+
+type MediaTagRequest struct {
+	Face *FaceRect `json:"face"`
+}
+
+type ProfilePictureRequest struct {
+	MediaID int `json:"media_id" binding:"required,min=1"`
+}
+
+type MediaTagResponse struct {
+	MemberID int       `json:"member_id"`
+	Face     *FaceRect `json:"face"`
+}
+
+type MediaResponse struct {
+	MediaID       int                `json:"media_id"`
+	TreeID        int                `json:"tree_id"`
+	Caption       *string            `json:"caption"`
+	Date          *Date              `json:"date"`
+	DateQualifier string             `json:"date_qualifier"`
+	DateEnd       *Date              `json:"date_end,omitempty"`
+	DateCalendar  string             `json:"date_calendar"`
+	DateHijri     *HijriDate         `json:"date_hijri,omitempty"`
+	DateEndHijri  *HijriDate         `json:"date_end_hijri,omitempty"`
+	PlaceID       *int               `json:"place_id"`
+	UploadedBy    int                `json:"uploaded_by"`
+	Tags          []MediaTagResponse `json:"tags"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
+
+type MediaListResponse struct {
+	Media []MediaResponse `json:"media"`
+}
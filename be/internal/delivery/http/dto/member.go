@@ -1,26 +1,67 @@
 package dto
 
+import "encoding/json"
+
 type CreateMemberRequest struct {
-	Names       map[string]string `json:"names" binding:"required,min=1"` // language_code -> name
-	Gender      string            `json:"gender" binding:"required,oneof=M F"`
-	DateOfBirth *Date             `json:"date_of_birth"`
-	DateOfDeath *Date             `json:"date_of_death"`
-	FatherID    *int              `json:"father_id"`
-	MotherID    *int              `json:"mother_id"`
-	Nicknames   []string          `json:"nicknames"`
-	Profession  *string           `json:"profession"`
+	Names                map[string]string    `json:"names" binding:"required,min=1"`      // language_code -> name
+	NameParts            map[string]NameParts `json:"name_parts" binding:"omitempty,dive"` // language_code -> parts, omit on update to keep them
+	Gender               string               `json:"gender" binding:"required,oneof=M F"`
+	DateOfBirth          *Date                `json:"date_of_birth"`
+	DateOfBirthQualifier string               `json:"date_of_birth_qualifier" binding:"omitempty,oneof=exact about before after between year month"`
+	DateOfBirthEnd       *Date                `json:"date_of_birth_end"`
+	DateOfBirthCalendar  string               `json:"date_of_birth_calendar" binding:"omitempty,oneof=gregorian hijri"`
+	DateOfBirthHijri     *HijriDate           `json:"date_of_birth_hijri"`
+	DateOfBirthEndHijri  *HijriDate           `json:"date_of_birth_end_hijri"`
+	DateOfDeath          *Date                `json:"date_of_death"`
+	DateOfDeathQualifier string               `json:"date_of_death_qualifier" binding:"omitempty,oneof=exact about before after between year month"`
+	DateOfDeathEnd       *Date                `json:"date_of_death_end"`
+	DateOfDeathCalendar  string               `json:"date_of_death_calendar" binding:"omitempty,oneof=gregorian hijri"`
+	DateOfDeathHijri     *HijriDate           `json:"date_of_death_hijri"`
+	DateOfDeathEndHijri  *HijriDate           `json:"date_of_death_end_hijri"`
+	BirthPlaceID         *int                 `json:"birth_place_id" binding:"omitempty,min=1"`
+	DeathPlaceID         *int                 `json:"death_place_id" binding:"omitempty,min=1"`
+	BurialPlaceID        *int                 `json:"burial_place_id" binding:"omitempty,min=1"`
+	FatherID             *int                 `json:"father_id"`
+	MotherID             *int                 `json:"mother_id"`
+	Nicknames            []string             `json:"nicknames"`
+	Profession           *string              `json:"profession"`
+	CustomFields         map[string]string    `json:"custom_fields"`                                           // field_key -> value
+	LivingStatus         string               `json:"living_status" binding:"omitempty,oneof=living deceased"` // empty infers it from dates and descendants
 }
 
 type UpdateMemberRequest struct {
-	Names       map[string]string `json:"names" binding:"required,min=1"` // language_code -> name
-	Gender      string            `json:"gender" binding:"required,oneof=M F"`
-	DateOfBirth *Date             `json:"date_of_birth"`
-	DateOfDeath *Date             `json:"date_of_death"`
-	FatherID    *int              `json:"father_id"`
-	MotherID    *int              `json:"mother_id"`
-	Nicknames   []string          `json:"nicknames"`
-	Profession  *string           `json:"profession"`
-	Version     int               `json:"version" binding:"required,min=1"`
+	Names                map[string]string    `json:"names" binding:"required,min=1"`      // language_code -> name
+	NameParts            map[string]NameParts `json:"name_parts" binding:"omitempty,dive"` // language_code -> parts, omit on update to keep them
+	Gender               string               `json:"gender" binding:"required,oneof=M F"`
+	DateOfBirth          *Date                `json:"date_of_birth"`
+	DateOfBirthQualifier string               `json:"date_of_birth_qualifier" binding:"omitempty,oneof=exact about before after between year month"`
+	DateOfBirthEnd       *Date                `json:"date_of_birth_end"`
+	DateOfBirthCalendar  string               `json:"date_of_birth_calendar" binding:"omitempty,oneof=gregorian hijri"`
+	DateOfBirthHijri     *HijriDate           `json:"date_of_birth_hijri"`
+	DateOfBirthEndHijri  *HijriDate           `json:"date_of_birth_end_hijri"`
+	DateOfDeath          *Date                `json:"date_of_death"`
+	DateOfDeathQualifier string               `json:"date_of_death_qualifier" binding:"omitempty,oneof=exact about before after between year month"`
+	DateOfDeathEnd       *Date                `json:"date_of_death_end"`
+	DateOfDeathCalendar  string               `json:"date_of_death_calendar" binding:"omitempty,oneof=gregorian hijri"`
+	DateOfDeathHijri     *HijriDate           `json:"date_of_death_hijri"`
+	DateOfDeathEndHijri  *HijriDate           `json:"date_of_death_end_hijri"`
+	BirthPlaceID         *int                 `json:"birth_place_id" binding:"omitempty,min=1"`
+	DeathPlaceID         *int                 `json:"death_place_id" binding:"omitempty,min=1"`
+	BurialPlaceID        *int                 `json:"burial_place_id" binding:"omitempty,min=1"`
+	FatherID             *int                 `json:"father_id"`
+	MotherID             *int                 `json:"mother_id"`
+	Nicknames            []string             `json:"nicknames"`
+	Profession           *string              `json:"profession"`
+	CustomFields         map[string]string    `json:"custom_fields"`                                           // field_key -> value, omit to keep the current values
+	LivingStatus         string               `json:"living_status" binding:"omitempty,oneof=living deceased"` // empty infers it from dates and descendants
+	Version              int                  `json:"version" binding:"required,min=1"`
+	Merge                bool                 `json:"merge"` // merge with changes made since version instead of failing
+}
+
+type NameParts struct {
+	Kunya  string `json:"kunya" binding:"max=255"`
+	Laqab  string `json:"laqab" binding:"max=255"`
+	Family string `json:"family" binding:"max=255"` // tribal nisba or family name
 }
 
 type MemberListQuery struct {
@@ -29,20 +70,34 @@ type MemberListQuery struct {
 	EnglishName *string `form:"english_name" binding:"omitempty,max=100"`
 	Gender      *string `form:"gender" binding:"omitempty,oneof=M F"`
 	Married     *bool   `form:"married" binding:"omitempty"`
+	CustomField *string `form:"custom_field" binding:"omitempty,max=50"`
+	CustomValue *string `form:"custom_value" binding:"omitempty,max=100"`
 	Cursor      *string `form:"cursor" binding:"omitempty"`
 	Limit       int     `form:"limit,default=20" binding:"omitempty,min=1,max=1000"`
 }
 
 type MemberListItem struct {
-	MemberID    int               `json:"member_id"`
-	TreeID      int               `json:"tree_id"`
-	Name        string            `json:"name"`
-	Names       map[string]string `json:"names,omitempty"`
-	Gender      string            `json:"gender"`
-	Picture     *string           `json:"picture"`
-	DateOfBirth *Date             `json:"date_of_birth"`
-	DateOfDeath *Date             `json:"date_of_death"`
-	IsMarried   bool              `json:"is_married"`
+	MemberID             int               `json:"member_id"`
+	TreeID               int               `json:"tree_id"`
+	Name                 string            `json:"name"`
+	Names                map[string]string `json:"names,omitempty"`
+	SuggestedNames       []string          `json:"suggested_names,omitempty"`
+	Gender               string            `json:"gender"`
+	Picture              *string           `json:"picture"`
+	DateOfBirth          *Date             `json:"date_of_birth"`
+	DateOfBirthQualifier string            `json:"date_of_birth_qualifier"`
+	DateOfBirthEnd       *Date             `json:"date_of_birth_end,omitempty"`
+	DateOfBirthCalendar  string            `json:"date_of_birth_calendar"`
+	DateOfBirthHijri     *HijriDate        `json:"date_of_birth_hijri,omitempty"`
+	DateOfBirthEndHijri  *HijriDate        `json:"date_of_birth_end_hijri,omitempty"`
+	DateOfDeath          *Date             `json:"date_of_death"`
+	DateOfDeathQualifier string            `json:"date_of_death_qualifier"`
+	DateOfDeathEnd       *Date             `json:"date_of_death_end,omitempty"`
+	DateOfDeathCalendar  string            `json:"date_of_death_calendar"`
+	DateOfDeathHijri     *HijriDate        `json:"date_of_death_hijri,omitempty"`
+	DateOfDeathEndHijri  *HijriDate        `json:"date_of_death_end_hijri,omitempty"`
+	IsMarried            bool              `json:"is_married"`
+	CustomFields         map[string]string `json:"custom_fields,omitempty"`
 }
 
 type PaginatedMembersResponse struct {
@@ -58,27 +113,64 @@ type MemberInfo struct {
 }
 
 type MemberResponse struct {
-	MemberID        int               `json:"member_id"`
-	TreeID          int               `json:"tree_id"`
-	Name            string            `json:"name"`                 // Name in user's preferred language
-	Names           map[string]string `json:"names"`                // language_code -> name (for editing)
-	FullName        string            `json:"full_name,omitempty"`  // Full name in user's preferred language
-	FullNames       map[string]string `json:"full_names,omitempty"` // language_code -> full_name (for editing)
-	Gender          string            `json:"gender"`
-	Picture         *string           `json:"picture"`
-	DateOfBirth     *Date             `json:"date_of_birth"`
-	DateOfDeath     *Date             `json:"date_of_death"`
-	FatherID        *int              `json:"father_id"`
-	MotherID        *int              `json:"mother_id"`
-	Father          *MemberInfo       `json:"father,omitempty"`
-	Mother          *MemberInfo       `json:"mother,omitempty"`
-	Nicknames       []string          `json:"nicknames"`
-	Profession      *string           `json:"profession"`
-	Version         int               `json:"version"`
-	Age             *int              `json:"age,omitempty"`
-	GenerationLevel int               `json:"generation_level,omitempty"`
-	IsMarried       bool              `json:"is_married"`
-	Spouses         []SpouseInfo      `json:"spouses,omitempty"`
-	Children        []MemberInfo      `json:"children,omitempty"`
-	Siblings        []MemberInfo      `json:"siblings,omitempty"`
+	MemberID             int                  `json:"member_id"`
+	TreeID               int                  `json:"tree_id"`
+	Name                 string               `json:"name"`                 // Name in user's preferred language
+	Names                map[string]string    `json:"names"`                // language_code -> name (for editing)
+	NameParts            map[string]NameParts `json:"name_parts"`           // language_code -> kunya, laqab and family name
+	SuggestedNames       []string             `json:"suggested_names"`      // language codes of names waiting for an editor to confirm them
+	FullName             string               `json:"full_name,omitempty"`  // Full name in user's preferred language
+	FullNames            map[string]string    `json:"full_names,omitempty"` // language_code -> full_name (for editing)
+	Gender               string               `json:"gender"`
+	Picture              *string              `json:"picture"`
+	DateOfBirth          *Date                `json:"date_of_birth"`
+	DateOfBirthQualifier string               `json:"date_of_birth_qualifier"`
+	DateOfBirthEnd       *Date                `json:"date_of_birth_end,omitempty"`
+	DateOfBirthCalendar  string               `json:"date_of_birth_calendar"`
+	DateOfBirthHijri     *HijriDate           `json:"date_of_birth_hijri,omitempty"`
+	DateOfBirthEndHijri  *HijriDate           `json:"date_of_birth_end_hijri,omitempty"`
+	DateOfDeath          *Date                `json:"date_of_death"`
+	DateOfDeathQualifier string               `json:"date_of_death_qualifier"`
+	DateOfDeathEnd       *Date                `json:"date_of_death_end,omitempty"`
+	DateOfDeathCalendar  string               `json:"date_of_death_calendar"`
+	DateOfDeathHijri     *HijriDate           `json:"date_of_death_hijri,omitempty"`
+	DateOfDeathEndHijri  *HijriDate           `json:"date_of_death_end_hijri,omitempty"`
+	BirthPlaceID         *int                 `json:"birth_place_id"`
+	DeathPlaceID         *int                 `json:"death_place_id"`
+	BurialPlaceID        *int                 `json:"burial_place_id"`
+	FatherID             *int                 `json:"father_id"`
+	MotherID             *int                 `json:"mother_id"`
+	Father               *MemberInfo          `json:"father,omitempty"`
+	Mother               *MemberInfo          `json:"mother,omitempty"`
+	Nicknames            []string             `json:"nicknames"`
+	Profession           *string              `json:"profession"`
+	CustomFields         map[string]string    `json:"custom_fields"` // field_key -> value
+	LivingStatus         string               `json:"living_status"`
+	IsLiving             bool                 `json:"is_living,omitempty"`
+	IsRedacted           bool                 `json:"is_redacted,omitempty"` // details hidden for a living member
+	Version              int                  `json:"version"`
+	Age                  *int                 `json:"age,omitempty"`
+	AgeMin               *int                 `json:"age_min,omitempty"`
+	AgeMax               *int                 `json:"age_max,omitempty"`
+	GenerationLevel      int                  `json:"generation_level,omitempty"`
+	IsMarried            bool                 `json:"is_married"`
+	Spouses              []SpouseInfo         `json:"spouses,omitempty"`
+	Children             []MemberInfo         `json:"children,omitempty"`
+	Siblings             []MemberInfo         `json:"siblings,omitempty"`
+	CitationCounts       map[string]int       `json:"citation_counts,omitempty"` // field_name -> citations
+}
+
+type MergeConflictResponse struct {
+	Field   string          `json:"field"`
+	Label   string          `json:"label"`
+	Base    json.RawMessage `json:"base"`
+	Current json.RawMessage `json:"current"`
+	Mine    json.RawMessage `json:"mine"`
+}
+
+type MemberConflictResponse struct {
+	Current   MemberResponse          `json:"current"`
+	Changes   []FieldChangeResponse   `json:"changes"`
+	History   []HistoryResponse       `json:"history"`
+	Conflicts []MergeConflictResponse `json:"conflicts"`
 }
@@ -0,0 +1,64 @@
+package dto
+
+import "time"
+
+type PlaceIDUri struct {
+	TreeID  int `uri:"tree_id" binding:"required,min=1"`
+	PlaceID int `uri:"place_id" binding:"required,min=1"`
+}
+
+type PlaceRequest struct {
+	Names     map[string]string `json:"names" binding:"required,min=1"` // language_code -> name
+	Latitude  *float64          `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude *float64          `json:"longitude" binding:"omitempty,min=-180,max=180"`
+}
+
+type PlaceResponse struct {
+	PlaceID   int               `json:"place_id"`
+	TreeID    int               `json:"tree_id"`
+	Name      string            `json:"name"`
+	Names     map[string]string `json:"names"`
+	Latitude  *float64          `json:"latitude"`
+	Longitude *float64          `json:"longitude"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+type PlaceListResponse struct {
+	Places []PlaceResponse `json:"places"`
+}
+
+type MapQuery struct {
+	MinGeneration *int       `form:"min_generation" binding:"omitempty,min=1"`
+	MaxGeneration *int       `form:"max_generation" binding:"omitempty,min=1"`
+	From          *time.Time `form:"from" time_format:"2006-01-02"`
+	To            *time.Time `form:"to" time_format:"2006-01-02"`
+}
+
+// GeoJSONFeatureCollection is an RFC 7946 feature collection of points
+type GeoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []GeoJSONFeature `json:"features"`
+}
+
+type GeoJSONFeature struct {
+	Type       string               `json:"type"`
+	Geometry   GeoJSONPoint         `json:"geometry"`
+	Properties MapFeatureProperties `json:"properties"`
+}
+
+// GeoJSONPoint holds its coordinates as longitude then latitude
+type GeoJSONPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+type MapFeatureProperties struct {
+	EventType  string `json:"event_type"`
+	MemberID   int    `json:"member_id"`
+	MemberName string `json:"member_name"`
+	Generation int    `json:"generation"`
+	Date       *Date  `json:"date"`
+	PlaceID    int    `json:"place_id"`
+	PlaceName  string `json:"place_name"`
+}
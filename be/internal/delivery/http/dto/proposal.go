@@ -0,0 +1,83 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type ProposalIDUri struct {
+	TreeID     int `uri:"tree_id" binding:"required,min=1"`
+	ProposalID int `uri:"proposal_id" binding:"required,min=1"`
+}
+
+// ProposalPayload is what a proposal suggests. Member proposals carry the
+// member, and the version edited when updating. Added spouses carry the
+// partners and spouse relationship proposals the relationship dates.
+type ProposalPayload struct {
+	Member   *CreateMemberRequest `json:"member"`
+	Version  *int                 `json:"version" binding:"omitempty,min=1"`
+	FatherID *int                 `json:"father_id" binding:"omitempty,min=1"`
+	MotherID *int                 `json:"mother_id" binding:"omitempty,min=1"`
+	Spouse   *UpdateSpouseRequest `json:"spouse"`
+	Comment  *string              `json:"comment" binding:"omitempty,max=2000"`
+}
+
+type SubmitProposalRequest struct {
+	Kind     string `json:"kind" binding:"required,oneof=create_member update_member add_spouse update_spouse remove_spouse"`
+	MemberID *int   `json:"member_id" binding:"omitempty,min=1"`
+	SpouseID *int   `json:"spouse_id" binding:"omitempty,min=1"`
+	ProposalPayload
+}
+
+type ReviseProposalRequest struct {
+	ProposalPayload
+}
+
+type ApproveProposalRequest struct {
+	Comment *string `json:"comment" binding:"omitempty,max=2000"`
+}
+
+type RejectProposalRequest struct {
+	Reason string `json:"reason" binding:"required,max=2000"`
+}
+
+type RequestProposalChangesRequest struct {
+	Comment string `json:"comment" binding:"required,max=2000"`
+}
+
+type ProposalListQuery struct {
+	Status *string `form:"status" binding:"omitempty,oneof=pending changes_requested approved rejected"`
+	PaginationQuery
+}
+
+type FieldChangeResponse struct {
+	Field  string          `json:"field"`
+	Label  string          `json:"label"`
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+type ProposalResponse struct {
+	ProposalID     int                   `json:"proposal_id"`
+	TreeID         int                   `json:"tree_id"`
+	ProposerUserID int                   `json:"proposer_user_id"`
+	ProposerName   string                `json:"proposer_name"`
+	Kind           string                `json:"kind"`
+	MemberID       *int                  `json:"member_id"`
+	SpouseID       *int                  `json:"spouse_id"`
+	MemberName     string                `json:"member_name,omitempty"`
+	Comment        *string               `json:"comment"`
+	Status         string                `json:"status"`
+	Stale          bool                  `json:"stale"`
+	Changes        []FieldChangeResponse `json:"changes"`
+	ReviewerUserID *int                  `json:"reviewer_user_id"`
+	ReviewComment  *string               `json:"review_comment"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+	ReviewedAt     *time.Time            `json:"reviewed_at"`
+}
+
+type PaginatedProposalsResponse struct {
+	Proposals  []ProposalResponse `json:"proposals"`
+	NextCursor *string            `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,74 @@
+package dto
+
+import "time"
+
+type SourceRequest struct {
+	SourceType  string  `json:"source_type" binding:"required,oneof=document interview gravestone registry other"`
+	Title       string  `json:"title" binding:"required,max=255"`
+	Author      *string `json:"author" binding:"omitempty,max=255"`
+	Description *string `json:"description" binding:"omitempty,max=5000"`
+}
+
+type SourceResponse struct {
+	SourceID      int       `json:"source_id"`
+	TreeID        int       `json:"tree_id"`
+	SourceType    string    `json:"source_type"`
+	Title         string    `json:"title"`
+	Author        *string   `json:"author"`
+	Description   *string   `json:"description"`
+	HasAttachment bool      `json:"has_attachment"`
+	CreatedBy     int       `json:"created_by"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type SourceListResponse struct {
+	Sources []SourceResponse `json:"sources"`
+}
+
+type CitationRequest struct {
+	SourceID    int     `json:"source_id" binding:"required,min=1"`
+	SubjectType string  `json:"subject_type" binding:"required,oneof=member spouse event"`
+	SubjectID   int     `json:"subject_id" binding:"required,min=1"`
+	FieldName   string  `json:"field_name" binding:"required,max=50"`
+	Confidence  string  `json:"confidence" binding:"required,oneof=low medium high"`
+	Detail      *string `json:"detail" binding:"omitempty,max=2000"`
+}
+
+type CitationUpdateRequest struct {
+	Confidence string  `json:"confidence" binding:"required,oneof=low medium high"`
+	Detail     *string `json:"detail" binding:"omitempty,max=2000"`
+}
+
+type CitationQuery struct {
+	SubjectType string `form:"subject_type" binding:"required,oneof=member spouse event"`
+	SubjectID   int    `form:"subject_id" binding:"required,min=1"`
+}
+
+type CitationResponse struct {
+	CitationID  int       `json:"citation_id"`
+	SourceID    int       `json:"source_id"`
+	SubjectType string    `json:"subject_type"`
+	SubjectID   int       `json:"subject_id"`
+	FieldName   string    `json:"field_name"`
+	Confidence  string    `json:"confidence"`
+	Detail      *string   `json:"detail"`
+	CreatedBy   int       `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type CitationListResponse struct {
+	Citations []CitationResponse `json:"citations"`
+}
+
+type UnsourcedFactResponse struct {
+	SubjectType string `json:"subject_type"`
+	SubjectID   int    `json:"subject_id"`
+	FieldName   string `json:"field_name"`
+	MemberIDs   []int  `json:"member_ids"`
+}
+
+type UnsourcedFactListResponse struct {
+	Facts []UnsourcedFactResponse `json:"facts"`
+}
@@ -19,25 +19,59 @@ func CalculateMarriedYears(marriageDate, divorceDate *time.Time) *int {
 }
 
 type CreateSpouseRequest struct {
-	FatherID     int   `json:"father_id" binding:"required,min=1"`
-	MotherID     int   `json:"mother_id" binding:"required,min=1"`
-	MarriageDate *Date `json:"marriage_date"`
-	DivorceDate  *Date `json:"divorce_date"`
+	FatherID              int        `json:"father_id" binding:"required,min=1"`
+	MotherID              int        `json:"mother_id" binding:"required,min=1"`
+	MarriageDate          *Date      `json:"marriage_date"`
+	MarriageDateQualifier string     `json:"marriage_date_qualifier" binding:"omitempty,oneof=exact about before after between year month"`
+	MarriageDateEnd       *Date      `json:"marriage_date_end"`
+	MarriageDateCalendar  string     `json:"marriage_date_calendar" binding:"omitempty,oneof=gregorian hijri"`
+	MarriageDateHijri     *HijriDate `json:"marriage_date_hijri"`
+	MarriageDateEndHijri  *HijriDate `json:"marriage_date_end_hijri"`
+	DivorceDate           *Date      `json:"divorce_date"`
+	DivorceDateQualifier  string     `json:"divorce_date_qualifier" binding:"omitempty,oneof=exact about before after between year month"`
+	DivorceDateEnd        *Date      `json:"divorce_date_end"`
+	DivorceDateCalendar   string     `json:"divorce_date_calendar" binding:"omitempty,oneof=gregorian hijri"`
+	DivorceDateHijri      *HijriDate `json:"divorce_date_hijri"`
+	DivorceDateEndHijri   *HijriDate `json:"divorce_date_end_hijri"`
+	MarriagePlaceID       *int       `json:"marriage_place_id" binding:"omitempty,min=1"`
 }
 
 type UpdateSpouseRequest struct {
-	MarriageDate *Date `json:"marriage_date"`
-	DivorceDate  *Date `json:"divorce_date"`
+	MarriageDate          *Date      `json:"marriage_date"`
+	MarriageDateQualifier string     `json:"marriage_date_qualifier" binding:"omitempty,oneof=exact about before after between year month"`
+	MarriageDateEnd       *Date      `json:"marriage_date_end"`
+	MarriageDateCalendar  string     `json:"marriage_date_calendar" binding:"omitempty,oneof=gregorian hijri"`
+	MarriageDateHijri     *HijriDate `json:"marriage_date_hijri"`
+	MarriageDateEndHijri  *HijriDate `json:"marriage_date_end_hijri"`
+	DivorceDate           *Date      `json:"divorce_date"`
+	DivorceDateQualifier  string     `json:"divorce_date_qualifier" binding:"omitempty,oneof=exact about before after between year month"`
+	DivorceDateEnd        *Date      `json:"divorce_date_end"`
+	DivorceDateCalendar   string     `json:"divorce_date_calendar" binding:"omitempty,oneof=gregorian hijri"`
+	DivorceDateHijri      *HijriDate `json:"divorce_date_hijri"`
+	DivorceDateEndHijri   *HijriDate `json:"divorce_date_end_hijri"`
+	MarriagePlaceID       *int       `json:"marriage_place_id" binding:"omitempty,min=1"`
 }
 
 type SpouseInfo struct {
-	SpouseID     int               `json:"spouse_id"`
-	MemberID     int               `json:"member_id"`
-	Name         string            `json:"name"`
-	Names        map[string]string `json:"names,omitempty"`
-	Gender       string            `json:"gender"`
-	Picture      *string           `json:"picture"`
-	MarriageDate *Date             `json:"marriage_date"`
-	DivorceDate  *Date             `json:"divorce_date"`
-	MarriedYears *int              `json:"married_years"`
+	SpouseID              int               `json:"spouse_id"`
+	MemberID              int               `json:"member_id"`
+	Name                  string            `json:"name"`
+	Names                 map[string]string `json:"names,omitempty"`
+	Gender                string            `json:"gender"`
+	Picture               *string           `json:"picture"`
+	MarriageDate          *Date             `json:"marriage_date"`
+	MarriageDateQualifier string            `json:"marriage_date_qualifier"`
+	MarriageDateEnd       *Date             `json:"marriage_date_end,omitempty"`
+	MarriageDateCalendar  string            `json:"marriage_date_calendar"`
+	MarriageDateHijri     *HijriDate        `json:"marriage_date_hijri,omitempty"`
+	MarriageDateEndHijri  *HijriDate        `json:"marriage_date_end_hijri,omitempty"`
+	DivorceDate           *Date             `json:"divorce_date"`
+	DivorceDateQualifier  string            `json:"divorce_date_qualifier"`
+	DivorceDateEnd        *Date             `json:"divorce_date_end,omitempty"`
+	DivorceDateCalendar   string            `json:"divorce_date_calendar"`
+	DivorceDateHijri      *HijriDate        `json:"divorce_date_hijri,omitempty"`
+	DivorceDateEndHijri   *HijriDate        `json:"divorce_date_end_hijri,omitempty"`
+	MarriagePlaceID       *int              `json:"marriage_place_id"`
+	MarriedYears          *int              `json:"married_years"`
+	IsRedacted            bool              `json:"is_redacted,omitempty"`
 }
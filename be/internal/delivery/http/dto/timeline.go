@@ -0,0 +1,35 @@
+package dto
+
+import "time"
+
+type TimelineQuery struct {
+	From     *time.Time `form:"from" time_format:"2006-01-02"`
+	To       *time.Time `form:"to" time_format:"2006-01-02"`
+	MemberID *int       `form:"member_id" binding:"omitempty,min=1"`
+	Degree   int        `form:"degree,default=1" binding:"omitempty,min=1,max=10"`
+	PaginationQuery
+}
+
+type TimelineEntryResponse struct {
+	EventType     string     `json:"event_type"`
+	Date          Date       `json:"date"`
+	DateQualifier string     `json:"date_qualifier"`
+	DateEnd       *Date      `json:"date_end,omitempty"`
+	DateCalendar  string     `json:"date_calendar"`
+	DateHijri     *HijriDate `json:"date_hijri,omitempty"`
+	DateEndHijri  *HijriDate `json:"date_end_hijri,omitempty"`
+	Title         string     `json:"title"`
+	MemberID      int        `json:"member_id"`
+	MemberName    string     `json:"member_name"`
+	PartnerID     *int       `json:"partner_id,omitempty"`
+	PartnerName   *string    `json:"partner_name,omitempty"`
+	FamilyUnitID  *int       `json:"family_unit_id,omitempty"`
+	EventID       *int       `json:"event_id,omitempty"`
+	CustomType    *string    `json:"custom_type,omitempty"`
+	PlaceID       *int       `json:"place_id,omitempty"`
+}
+
+type PaginatedTimelineResponse struct {
+	Entries    []TimelineEntryResponse `json:"entries"`
+	NextCursor *string                 `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+type NameSpellingRequest struct {
+	Arabic       string `json:"arabic" binding:"required,max=255"` // a whole name or a single word
+	LanguageCode string `json:"language_code" binding:"required,min=2,max=10"`
+	Spelling     string `json:"spelling" binding:"required,max=255"`
+}
+
+type NameSpellingQuery struct {
+	Arabic       string `form:"arabic" binding:"required,max=255"`
+	LanguageCode string `form:"language_code" binding:"required,min=2,max=10"`
+}
+
+type NameSpellingResponse struct {
+	Arabic       string    `json:"arabic"`
+	LanguageCode string    `json:"language_code"`
+	Spelling     string    `json:"spelling"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type SuggestNamesResponse struct {
+	UpdatedMembers int `json:"updated_members"`
+}
@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+type TrashedMemberResponse struct {
+	MemberID  int               `json:"member_id"`
+	Name      string            `json:"name"`
+	Names     map[string]string `json:"names"`
+	Gender    string            `json:"gender"`
+	FatherID  *int              `json:"father_id"`
+	MotherID  *int              `json:"mother_id"`
+	DeletedAt time.Time         `json:"deleted_at"`
+}
+
+type TrashedSpouseResponse struct {
+	SpouseID     int        `json:"spouse_id"`
+	FatherID     int        `json:"father_id"`
+	MotherID     int        `json:"mother_id"`
+	MarriageDate *Date      `json:"marriage_date"`
+	DivorceDate  *Date      `json:"divorce_date"`
+	DeletedAt    *time.Time `json:"deleted_at"`
+}
+
+type TrashResponse struct {
+	Members []TrashedMemberResponse `json:"members"`
+	Spouses []TrashedSpouseResponse `json:"spouses"`
+}
+
+type PurgePicturesResponse struct {
+	DeletedPictures int `json:"deleted_pictures"`
+}
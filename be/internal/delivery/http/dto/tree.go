@@ -1,11 +1,13 @@
 package dto
 
 type TreeQuery struct {
+	AsOfQuery
 	RootID *int   `form:"root"`
 	Style  string `form:"style" binding:"required,oneof=tree list"`
 }
 
 type RelationQuery struct {
+	AsOfQuery
 	Member1ID int `form:"member1" binding:"required,min=1"`
 	Member2ID int `form:"member2" binding:"required,min=1"`
 }
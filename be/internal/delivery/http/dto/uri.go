@@ -19,3 +19,34 @@ type CodeUri struct {
 type ProviderUri struct {
 	Provider string `uri:"provider" binding:"required,min=2,max=20"`
 }
+
+type EventIDUri struct {
+	MemberID int `uri:"member_id" binding:"required,min=1"`
+	EventID  int `uri:"event_id" binding:"required,min=1"`
+}
+
+type MediaIDUri struct {
+	TreeID  int `uri:"tree_id" binding:"required,min=1"`
+	MediaID int `uri:"media_id" binding:"required,min=1"`
+}
+
+type MediaTagUri struct {
+	TreeID   int `uri:"tree_id" binding:"required,min=1"`
+	MediaID  int `uri:"media_id" binding:"required,min=1"`
+	MemberID int `uri:"member_id" binding:"required,min=1"`
+}
+
+type SourceIDUri struct {
+	TreeID   int `uri:"tree_id" binding:"required,min=1"`
+	SourceID int `uri:"source_id" binding:"required,min=1"`
+}
+
+type CitationIDUri struct {
+	TreeID     int `uri:"tree_id" binding:"required,min=1"`
+	CitationID int `uri:"citation_id" binding:"required,min=1"`
+}
+
+type MemberLanguageUri struct {
+	MemberID int    `uri:"member_id" binding:"required,min=1"`
+	Code     string `uri:"code" binding:"required,min=2,max=10"`
+}
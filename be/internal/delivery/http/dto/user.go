@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 type UpdateUserRequest struct {
 	RoleID   *int  `json:"role_id,omitempty" binding:"omitempty,oneof=100 200 300 400"`
 	IsActive *bool `json:"is_active,omitempty" binding:"omitempty"`
@@ -39,3 +41,19 @@ type UserScore struct {
 	TotalScore int     `json:"total_score"`
 	Rank       int     `json:"rank"`
 }
+
+type RoleHistoryResponse struct {
+	HistoryID     int       `json:"history_id"`
+	UserID        int       `json:"user_id"`
+	OldRoleID     *int      `json:"old_role_id"`
+	NewRoleID     *int      `json:"new_role_id"`
+	ChangedBy     int       `json:"changed_by"`
+	ChangedByName string    `json:"changed_by_name"`
+	ChangedAt     time.Time `json:"changed_at"`
+	ActionType    string    `json:"action_type"`
+}
+
+type PaginatedRoleHistoryResponse struct {
+	History    []RoleHistoryResponse `json:"history"`
+	NextCursor *string               `json:"next_cursor,omitempty"`
+}
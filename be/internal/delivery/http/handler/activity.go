@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"github.com/escalopa/family-tree/internal/delivery"
+	"github.com/escalopa/family-tree/internal/delivery/http/dto"
+	"github.com/escalopa/family-tree/internal/delivery/http/middleware"
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type activityHandler struct {
+	activityUseCase   ActivityUseCase
+	familyTreeUseCase FamilyTreeUseCase
+}
+
+func NewActivityHandler(activityUseCase ActivityUseCase, familyTreeUseCase FamilyTreeUseCase) *activityHandler {
+	return &activityHandler{activityUseCase: activityUseCase, familyTreeUseCase: familyTreeUseCase}
+}
+
+func (h *activityHandler) List(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var query dto.ActivityQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	viewer := viewerOf(c)
+	if err := h.familyTreeUseCase.EnsureAccess(c.Request.Context(), uri.TreeID, userID); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	filter := domain.ActivityFilter{
+		TreeID:      uri.TreeID,
+		UserID:      query.UserID,
+		ChangeTypes: query.ChangeType,
+		MemberID:    query.MemberID,
+		ChangesetID: query.ChangesetID,
+		From:        query.From,
+		To:          query.To,
+	}
+
+	history, nextCursor, err := h.activityUseCase.List(c.Request.Context(), filter, viewer, query.Cursor, query.Limit)
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	preferredLang := middleware.GetPreferredLanguage(c)
+
+	response := dto.PaginatedHistoryResponse{
+		History:    make([]dto.HistoryResponse, 0, len(history)),
+		NextCursor: nextCursor,
+	}
+	for _, entry := range history {
+		response.History = append(response.History, dto.HistoryResponse{
+			HistoryID:     entry.HistoryID,
+			MemberID:      entry.MemberID,
+			MemberName:    extractName(entry.MemberNames, preferredLang),
+			UserID:        entry.UserID,
+			UserFullName:  entry.UserFullName,
+			UserEmail:     entry.UserEmail,
+			ChangedAt:     entry.ChangedAt,
+			ChangeType:    entry.ChangeType,
+			OldValues:     entry.OldValues,
+			NewValues:     entry.NewValues,
+			MemberVersion: entry.MemberVersion,
+			ChangesetID:   entry.ChangesetID,
+		})
+	}
+
+	delivery.SuccessWithData(c, response)
+}
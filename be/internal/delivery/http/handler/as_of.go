@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"github.com/escalopa/family-tree/internal/delivery"
+	"github.com/escalopa/family-tree/internal/delivery/http/dto"
+	"github.com/escalopa/family-tree/internal/delivery/http/middleware"
+	"github.com/escalopa/family-tree/internal/pkg/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// RestoreAsOf puts the tree, or the subtree under a member, back the way it
+// was at a point in time and reports the changes it had to skip
+func (h *memberHandler) RestoreAsOf(c *gin.Context) {
+	treeID, ok := h.requireTreeAccess(c)
+	if !ok {
+		return
+	}
+
+	var req dto.RestoreAsOfRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	restore, err := h.memberUseCase.RestoreAsOf(c.Request.Context(), treeID, req.RootID, req.AsOf, middleware.GetUserID(c))
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	lang := middleware.GetInterfaceLanguage(c)
+	response := dto.RestoreAsOfResponse{
+		MembersRestored: restore.MembersRestored,
+		MembersUpdated:  restore.MembersUpdated,
+		MembersDeleted:  restore.MembersDeleted,
+		SpousesRestored: restore.SpousesRestored,
+		SpousesUpdated:  restore.SpousesUpdated,
+		SpousesRemoved:  restore.SpousesRemoved,
+		Skipped:         make([]dto.RestoreAsOfSkipResponse, 0, len(restore.Skipped)),
+	}
+	for _, skipped := range restore.Skipped {
+		response.Skipped = append(response.Skipped, dto.RestoreAsOfSkipResponse{
+			MemberID: skipped.MemberID,
+			SpouseID: skipped.SpouseID,
+			Error:    i18n.Translate(skipped.Err.TranslationKey, lang, skipped.Err.Params),
+		})
+	}
+
+	delivery.SuccessWithData(c, response)
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"github.com/escalopa/family-tree/internal/delivery"
+	"github.com/escalopa/family-tree/internal/delivery/http/dto"
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type auditHandler struct {
+	auditUseCase AuditUseCase
+}
+
+func NewAuditHandler(auditUseCase AuditUseCase) *auditHandler {
+	return &auditHandler{auditUseCase: auditUseCase}
+}
+
+// List returns audit events newest first, tree owners must filter by their tree
+func (h *auditHandler) List(c *gin.Context) {
+	var query dto.AuditQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	filter := domain.AuditFilter{
+		TreeID:      query.TreeID,
+		ActorUserID: query.ActorUserID,
+		Action:      query.Action,
+		TargetType:  query.TargetType,
+		TargetID:    query.TargetID,
+		From:        query.From,
+		To:          query.To,
+	}
+	events, nextCursor, err := h.auditUseCase.List(c.Request.Context(), filter, viewerOf(c), query.Cursor, query.Limit)
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	response := dto.PaginatedAuditEventsResponse{
+		Events:     make([]dto.AuditEventResponse, 0, len(events)),
+		NextCursor: nextCursor,
+	}
+	for _, event := range events {
+		response.Events = append(response.Events, dto.AuditEventResponse{
+			AuditID:     event.AuditID,
+			ActorUserID: event.ActorUserID,
+			ActorName:   event.ActorName,
+			Action:      event.Action,
+			TargetType:  event.TargetType,
+			TargetID:    event.TargetID,
+			TreeID:      event.TreeID,
+			Details:     event.Details,
+			IPAddress:   event.IPAddress,
+			UserAgent:   event.UserAgent,
+			RequestID:   event.RequestID,
+			CreatedAt:   event.CreatedAt,
+		})
+	}
+
+	delivery.SuccessWithData(c, response)
+}
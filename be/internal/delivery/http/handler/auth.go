@@ -115,7 +115,7 @@ func (h *authHandler) HandleCallback(c *gin.Context) {
 func (h *authHandler) Logout(c *gin.Context) {
 	sessionID := middleware.GetSessionID(c)
 
-	if err := h.authUseCase.Logout(c.Request.Context(), sessionID); err != nil {
+	if err := h.authUseCase.Logout(c.Request.Context(), sessionID, middleware.GetUserID(c)); err != nil {
 		delivery.Error(c, err)
 		return
 	}
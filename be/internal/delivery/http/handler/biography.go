@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"github.com/escalopa/family-tree/internal/delivery"
+	"github.com/escalopa/family-tree/internal/delivery/http/dto"
+	"github.com/escalopa/family-tree/internal/delivery/http/middleware"
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *memberHandler) GetBiography(c *gin.Context) {
+	var uri dto.MemberIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	member, _, ok := h.requireMemberInTree(c, uri.MemberID)
+	if !ok {
+		return
+	}
+
+	biographies, err := h.memberUseCase.GetBiographies(c.Request.Context(), uri.MemberID)
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, dto.BiographyResponse{Biographies: biographies, Version: member.Version})
+}
+
+func (h *memberHandler) UpdateBiography(c *gin.Context) {
+	var uri dto.MemberLanguageUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var req dto.BiographyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if _, _, ok := h.requireMemberInTree(c, uri.MemberID); !ok {
+		return
+	}
+
+	language, err := h.languageUseCase.Get(c.Request.Context(), uri.Code)
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	if !language.IsActive {
+		delivery.Error(c, domain.NewValidationError("error.language.not_active"))
+		return
+	}
+
+	err = h.memberUseCase.UpdateBiography(c.Request.Context(), uri.MemberID, language.LanguageCode, req.Biography, req.Version, middleware.GetUserID(c))
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.member.biography_updated", nil)
+}
+
+func (h *memberHandler) GetNotes(c *gin.Context) {
+	var uri dto.MemberIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	member, _, ok := h.requireMemberInTree(c, uri.MemberID)
+	if !ok {
+		return
+	}
+
+	notes, err := h.memberUseCase.GetNotes(c.Request.Context(), uri.MemberID)
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, dto.NotesResponse{Notes: notes, Version: member.Version})
+}
+
+func (h *memberHandler) UpdateNotes(c *gin.Context) {
+	var uri dto.MemberIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var req dto.NotesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if _, _, ok := h.requireMemberInTree(c, uri.MemberID); !ok {
+		return
+	}
+
+	if err := h.memberUseCase.UpdateNotes(c.Request.Context(), uri.MemberID, req.Notes, req.Version, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.member.notes_updated", nil)
+}
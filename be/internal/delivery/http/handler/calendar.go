@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/escalopa/family-tree/internal/delivery"
+	"github.com/escalopa/family-tree/internal/delivery/http/dto"
+	"github.com/escalopa/family-tree/internal/delivery/http/middleware"
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/escalopa/family-tree/internal/pkg/i18n"
+	"github.com/escalopa/family-tree/internal/pkg/ical"
+	"github.com/gin-gonic/gin"
+)
+
+const calendarProdID = "-//escalopa//family-tree//EN"
+
+type calendarHandler struct {
+	calendarUseCase CalendarUseCase
+}
+
+func NewCalendarHandler(calendarUseCase CalendarUseCase) *calendarHandler {
+	return &calendarHandler{calendarUseCase: calendarUseCase}
+}
+
+func (h *calendarHandler) CreateFeed(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	feed, err := h.calendarUseCase.CreateFeed(c.Request.Context(), uri.TreeID, middleware.GetUserID(c))
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	delivery.SuccessWithData(c, toCalendarFeedResponse(feed, publicShareBaseURL(c)))
+}
+
+func (h *calendarHandler) ListFeeds(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	feeds, err := h.calendarUseCase.ListFeeds(c.Request.Context(), uri.TreeID, middleware.GetUserID(c))
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	baseURL := publicShareBaseURL(c)
+	response := dto.CalendarFeedListResponse{Feeds: make([]dto.CalendarFeedResponse, 0, len(feeds))}
+	for _, feed := range feeds {
+		response.Feeds = append(response.Feeds, toCalendarFeedResponse(feed, baseURL))
+	}
+	delivery.SuccessWithData(c, response)
+}
+
+func (h *calendarHandler) RevokeFeed(c *gin.Context) {
+	var uri dto.CalendarFeedIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	if err := h.calendarUseCase.RevokeFeed(c.Request.Context(), uri.TreeID, uri.FeedID, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	delivery.Success(c, "success.calendar_feed.revoked", nil)
+}
+
+func (h *calendarHandler) GetPublicCalendar(c *gin.Context) {
+	var uri dto.PublicCalendarUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	content, err := h.calendarUseCase.GetFeedContent(c.Request.Context(), strings.TrimSuffix(uri.Token, ".ics"))
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	lang := i18n.NormalizeLanguage(content.Language)
+	host := c.Request.Host
+
+	calendar := ical.Calendar{
+		ProdID: calendarProdID,
+		Name:   content.TreeName,
+		Events: make([]ical.Event, 0, len(content.Events)),
+	}
+	for _, event := range content.Events {
+		params := map[string]string{"name": extractName(event.MemberNames, content.Language)}
+		if event.PartnerID != nil {
+			params["partner"] = extractName(event.PartnerNames, content.Language)
+		}
+		calendar.Events = append(calendar.Events, ical.Event{
+			UID:     fmt.Sprintf("%s@%s", event.UID, host),
+			Summary: i18n.Translate("calendar."+event.EventType, lang, params),
+			Date:    event.Date,
+			Yearly:  true,
+		})
+	}
+
+	c.Header("Cache-Control", "private, max-age=3600")
+	c.Data(http.StatusOK, ical.ContentType, calendar.Marshal())
+}
+
+func toCalendarFeedResponse(feed *domain.FamilyTreeCalendarFeed, baseURL string) dto.CalendarFeedResponse {
+	return dto.CalendarFeedResponse{
+		FeedID:         feed.FeedID,
+		TreeID:         feed.TreeID,
+		Token:          feed.Token,
+		URL:            fmt.Sprintf("%s/public/calendars/%s.ics", strings.TrimRight(baseURL, "/"), feed.Token),
+		CreatedAt:      feed.CreatedAt,
+		LastAccessedAt: feed.LastAccessedAt,
+		RevokedAt:      feed.RevokedAt,
+	}
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"github.com/escalopa/family-tree/internal/delivery"
+	"github.com/escalopa/family-tree/internal/delivery/http/dto"
+	"github.com/escalopa/family-tree/internal/delivery/http/middleware"
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type changesetHandler struct {
+	changesetUseCase  ChangesetUseCase
+	memberUseCase     MemberUseCase
+	familyTreeUseCase FamilyTreeUseCase
+}
+
+func NewChangesetHandler(changesetUseCase ChangesetUseCase, memberUseCase MemberUseCase, familyTreeUseCase FamilyTreeUseCase) *changesetHandler {
+	return &changesetHandler{
+		changesetUseCase:  changesetUseCase,
+		memberUseCase:     memberUseCase,
+		familyTreeUseCase: familyTreeUseCase,
+	}
+}
+
+func (h *changesetHandler) requireTreeAccess(c *gin.Context, treeID int) bool {
+	if err := h.familyTreeUseCase.EnsureAccess(c.Request.Context(), treeID, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return false
+	}
+	return true
+}
+
+// Create opens an explicit changeset, requests sent with its id in the
+// X-Changeset-ID header write in it
+func (h *changesetHandler) Create(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var req dto.CreateChangesetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	changeset := &domain.Changeset{
+		TreeID:      uri.TreeID,
+		UserID:      middleware.GetUserID(c),
+		Description: req.Description,
+		ChangeTypes: []string{},
+		MemberIDs:   []int{},
+	}
+	if err := h.changesetUseCase.Create(c.Request.Context(), changeset); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, toChangesetResponse(changeset))
+}
+
+func (h *changesetHandler) List(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var query dto.PaginationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	changesets, nextCursor, err := h.changesetUseCase.List(c.Request.Context(), uri.TreeID, query.Cursor, query.Limit)
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	response := dto.PaginatedChangesetsResponse{
+		Changesets: make([]dto.ChangesetResponse, 0, len(changesets)),
+		NextCursor: nextCursor,
+	}
+	for _, changeset := range changesets {
+		response.Changesets = append(response.Changesets, toChangesetResponse(changeset))
+	}
+
+	delivery.SuccessWithData(c, response)
+}
+
+// Revert undoes a whole changeset or nothing of it
+func (h *changesetHandler) Revert(c *gin.Context) {
+	var uri dto.ChangesetIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	changeset, restore, err := h.memberUseCase.RevertChangeset(c.Request.Context(), uri.TreeID, uri.ChangesetID, middleware.GetUserID(c))
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, dto.RevertChangesetResponse{
+		Changeset:       toChangesetResponse(changeset),
+		MembersRestored: restore.MembersRestored,
+		MembersUpdated:  restore.MembersUpdated,
+		MembersDeleted:  restore.MembersDeleted,
+		SpousesRestored: restore.SpousesRestored,
+		SpousesUpdated:  restore.SpousesUpdated,
+		SpousesRemoved:  restore.SpousesRemoved,
+	})
+}
+
+func toChangesetResponse(changeset *domain.Changeset) dto.ChangesetResponse {
+	return dto.ChangesetResponse{
+		ChangesetID:  changeset.ChangesetID,
+		TreeID:       changeset.TreeID,
+		UserID:       changeset.UserID,
+		UserFullName: changeset.UserFullName,
+		Description:  changeset.Description,
+		ChangeTypes:  changeset.ChangeTypes,
+		MemberIDs:    changeset.MemberIDs,
+		EntryCount:   changeset.EntryCount,
+		Points:       changeset.Points,
+		CreatedAt:    changeset.CreatedAt,
+		LastChangeAt: changeset.LastChangeAt,
+		RevertedAt:   changeset.RevertedAt,
+		RevertedBy:   changeset.RevertedBy,
+		RevertedIn:   changeset.RevertedIn,
+	}
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"github.com/escalopa/family-tree/internal/delivery"
+	"github.com/escalopa/family-tree/internal/delivery/http/dto"
+	"github.com/escalopa/family-tree/internal/delivery/http/middleware"
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type customFieldHandler struct {
+	customFieldUseCase CustomFieldUseCase
+	familyTreeUseCase  FamilyTreeUseCase
+}
+
+func NewCustomFieldHandler(customFieldUseCase CustomFieldUseCase, familyTreeUseCase FamilyTreeUseCase) *customFieldHandler {
+	return &customFieldHandler{customFieldUseCase: customFieldUseCase, familyTreeUseCase: familyTreeUseCase}
+}
+
+func (h *customFieldHandler) requireTreeAccess(c *gin.Context, treeID int) bool {
+	if err := h.familyTreeUseCase.EnsureAccess(c.Request.Context(), treeID, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return false
+	}
+	return true
+}
+
+// requireTreeManager guards schema changes, only members who manage the tree
+// shape the member data
+func (h *customFieldHandler) requireTreeManager(c *gin.Context, treeID int) bool {
+	if err := h.familyTreeUseCase.EnsurePermission(c.Request.Context(), treeID, middleware.GetUserID(c), domain.TreePermissionManage); err != nil {
+		delivery.Error(c, err)
+		return false
+	}
+	return true
+}
+
+func (h *customFieldHandler) Create(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var req dto.CreateCustomFieldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeManager(c, uri.TreeID) {
+		return
+	}
+
+	field := &domain.CustomField{
+		TreeID:       uri.TreeID,
+		Key:          req.Key,
+		Names:        req.Names,
+		Type:         req.Type,
+		Options:      req.Options,
+		IsRequired:   req.IsRequired,
+		DisplayOrder: req.DisplayOrder,
+	}
+	if err := h.customFieldUseCase.Create(c.Request.Context(), field); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, toCustomFieldResponse(field, middleware.GetPreferredLanguage(c)))
+}
+
+func (h *customFieldHandler) Get(c *gin.Context) {
+	var uri dto.CustomFieldIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	field, err := h.customFieldUseCase.Get(c.Request.Context(), uri.TreeID, uri.FieldID)
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, toCustomFieldResponse(field, middleware.GetPreferredLanguage(c)))
+}
+
+func (h *customFieldHandler) List(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	fields, err := h.customFieldUseCase.List(c.Request.Context(), uri.TreeID)
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	preferredLang := middleware.GetPreferredLanguage(c)
+	response := dto.CustomFieldListResponse{Fields: make([]dto.CustomFieldResponse, 0, len(fields))}
+	for _, field := range fields {
+		response.Fields = append(response.Fields, toCustomFieldResponse(field, preferredLang))
+	}
+	delivery.SuccessWithData(c, response)
+}
+
+func (h *customFieldHandler) Update(c *gin.Context) {
+	var uri dto.CustomFieldIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var req dto.UpdateCustomFieldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeManager(c, uri.TreeID) {
+		return
+	}
+
+	field := &domain.CustomField{
+		FieldID:      uri.FieldID,
+		TreeID:       uri.TreeID,
+		Names:        req.Names,
+		Options:      req.Options,
+		IsRequired:   req.IsRequired,
+		DisplayOrder: req.DisplayOrder,
+	}
+	if err := h.customFieldUseCase.Update(c.Request.Context(), field); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.custom_field.updated", nil)
+}
+
+func (h *customFieldHandler) Delete(c *gin.Context) {
+	var uri dto.CustomFieldIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeManager(c, uri.TreeID) {
+		return
+	}
+
+	if err := h.customFieldUseCase.Delete(c.Request.Context(), uri.TreeID, uri.FieldID); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.custom_field.deleted", nil)
+}
+
+func toCustomFieldResponse(field *domain.CustomField, preferredLang string) dto.CustomFieldResponse {
+	return dto.CustomFieldResponse{
+		FieldID:      field.FieldID,
+		TreeID:       field.TreeID,
+		Key:          field.Key,
+		Name:         extractName(field.Names, preferredLang),
+		Names:        field.Names,
+		Type:         field.Type,
+		Options:      field.Options,
+		IsRequired:   field.IsRequired,
+		DisplayOrder: field.DisplayOrder,
+		CreatedAt:    field.CreatedAt,
+		UpdatedAt:    field.UpdatedAt,
+	}
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"github.com/escalopa/family-tree/internal/delivery"
+	"github.com/escalopa/family-tree/internal/delivery/http/dto"
+	"github.com/escalopa/family-tree/internal/delivery/http/middleware"
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type eventHandler struct {
+	eventUseCase      EventUseCase
+	memberUseCase     MemberUseCase
+	familyTreeUseCase FamilyTreeUseCase
+}
+
+func NewEventHandler(eventUseCase EventUseCase, memberUseCase MemberUseCase, familyTreeUseCase FamilyTreeUseCase) *eventHandler {
+	return &eventHandler{
+		eventUseCase:      eventUseCase,
+		memberUseCase:     memberUseCase,
+		familyTreeUseCase: familyTreeUseCase,
+	}
+}
+
+func (h *eventHandler) requireMemberInTree(c *gin.Context, memberID int) bool {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return false
+	}
+	if err := h.familyTreeUseCase.EnsureAccess(c.Request.Context(), uri.TreeID, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return false
+	}
+	member, err := h.memberUseCase.Get(c.Request.Context(), memberID)
+	if err != nil {
+		delivery.Error(c, err)
+		return false
+	}
+	if member.TreeID != uri.TreeID {
+		delivery.Error(c, domain.NewNotFoundError("member"))
+		return false
+	}
+	return true
+}
+
+func (h *eventHandler) Create(c *gin.Context) {
+	var uri dto.MemberIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var req dto.EventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireMemberInTree(c, uri.MemberID) {
+		return
+	}
+
+	event := toEventDomain(&req)
+	event.MemberID = uri.MemberID
+	if err := h.eventUseCase.Create(c.Request.Context(), event, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, toEventResponse(event))
+}
+
+func (h *eventHandler) Get(c *gin.Context) {
+	var uri dto.EventIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireMemberInTree(c, uri.MemberID) {
+		return
+	}
+
+	event, err := h.eventUseCase.Get(c.Request.Context(), uri.MemberID, uri.EventID, viewerOf(c))
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, toEventResponse(event))
+}
+
+func (h *eventHandler) List(c *gin.Context) {
+	var uri dto.MemberIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireMemberInTree(c, uri.MemberID) {
+		return
+	}
+
+	events, err := h.eventUseCase.List(c.Request.Context(), uri.MemberID, viewerOf(c))
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	response := dto.EventListResponse{Events: make([]dto.EventResponse, 0, len(events))}
+	for _, event := range events {
+		response.Events = append(response.Events, toEventResponse(event))
+	}
+	delivery.SuccessWithData(c, response)
+}
+
+func (h *eventHandler) Update(c *gin.Context) {
+	var uri dto.EventIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var req dto.EventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireMemberInTree(c, uri.MemberID) {
+		return
+	}
+
+	event := toEventDomain(&req)
+	event.EventID = uri.EventID
+	event.MemberID = uri.MemberID
+	if err := h.eventUseCase.Update(c.Request.Context(), event, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.event.updated", nil)
+}
+
+func (h *eventHandler) Delete(c *gin.Context) {
+	var uri dto.EventIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireMemberInTree(c, uri.MemberID) {
+		return
+	}
+
+	if err := h.eventUseCase.Delete(c.Request.Context(), uri.MemberID, uri.EventID, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.event.deleted", nil)
+}
+
+func toEventDomain(req *dto.EventRequest) *domain.MemberEvent {
+	return &domain.MemberEvent{
+		EventType:      req.EventType,
+		CustomType:     req.CustomType,
+		Date:           resolveDate(req.DateCalendar, req.Date, req.DateHijri),
+		DateQualifier:  req.DateQualifier,
+		DateEnd:        resolveDate(req.DateCalendar, req.DateEnd, req.DateEndHijri),
+		DateCalendar:   req.DateCalendar,
+		PlaceID:        req.PlaceID,
+		Description:    req.Description,
+		ParticipantIDs: req.ParticipantIDs,
+	}
+}
+
+func toEventResponse(event *domain.MemberEvent) dto.EventResponse {
+	participantIDs := event.ParticipantIDs
+	if participantIDs == nil {
+		participantIDs = []int{}
+	}
+	return dto.EventResponse{
+		EventID:        event.EventID,
+		MemberID:       event.MemberID,
+		EventType:      event.EventType,
+		CustomType:     event.CustomType,
+		Date:           dto.FromTimePtr(event.Date),
+		DateQualifier:  event.DateQualifier,
+		DateEnd:        dto.FromTimePtr(event.DateEnd),
+		DateCalendar:   event.DateCalendar,
+		DateHijri:      dto.HijriFromTimePtr(event.Date),
+		DateEndHijri:   dto.HijriFromTimePtr(event.DateEnd),
+		PlaceID:        event.PlaceID,
+		Description:    event.Description,
+		ParticipantIDs: participantIDs,
+		CreatedAt:      event.CreatedAt,
+		UpdatedAt:      event.UpdatedAt,
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/escalopa/family-tree/internal/delivery/http/dto"
 	"github.com/escalopa/family-tree/internal/delivery/http/middleware"
 	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/escalopa/family-tree/internal/pkg/i18n"
 	"github.com/gin-gonic/gin"
 )
 
@@ -63,6 +64,109 @@ func (h *familyTreeHandler) Get(c *gin.Context) {
 	delivery.SuccessWithData(c, toFamilyTreeResponse(tree))
 }
 
+func (h *familyTreeHandler) UpdateNameSettings(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var req dto.NameSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	settings := &domain.NameSettings{
+		Formats:         req.Formats,
+		NasabDepth:      req.NasabDepth,
+		NasabConnectors: req.NasabConnectors,
+	}
+	if err := h.treeUseCase.UpdateNameSettings(c.Request.Context(), uri.TreeID, middleware.GetUserID(c), settings); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.family_tree.name_settings_updated", nil)
+}
+
+func (h *familyTreeHandler) GetPrivacyPolicy(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	policy, err := h.treeUseCase.GetPrivacyPolicy(c.Request.Context(), uri.TreeID, middleware.GetUserID(c))
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	response := dto.PrivacyPolicy{Rules: make([]dto.PrivacyRule, 0, len(policy.Rules))}
+	for _, rule := range policy.Rules {
+		response.Rules = append(response.Rules, dto.PrivacyRule{
+			Field:      rule.Field,
+			Visibility: rule.Visibility,
+			TreeRoles:  rule.TreeRoles,
+			BelowRole:  rule.BelowRole,
+			Gender:     rule.Gender,
+			Living:     rule.Living,
+			MemberIDs:  rule.MemberIDs,
+		})
+	}
+	delivery.SuccessWithData(c, response)
+}
+
+func (h *familyTreeHandler) UpdatePrivacyPolicy(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var req dto.PrivacyPolicy
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	policy := &domain.PrivacyPolicy{Rules: make([]domain.PrivacyRule, 0, len(req.Rules))}
+	for _, rule := range req.Rules {
+		policy.Rules = append(policy.Rules, domain.PrivacyRule{
+			Field:      rule.Field,
+			Visibility: rule.Visibility,
+			TreeRoles:  rule.TreeRoles,
+			BelowRole:  rule.BelowRole,
+			Gender:     rule.Gender,
+			Living:     rule.Living,
+			MemberIDs:  rule.MemberIDs,
+		})
+	}
+	if err := h.treeUseCase.UpdatePrivacyPolicy(c.Request.Context(), uri.TreeID, middleware.GetUserID(c), policy); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.family_tree.privacy_policy_updated", nil)
+}
+
+// ResetPrivacyPolicy drops the tree's own rules in favour of the default policy
+func (h *familyTreeHandler) ResetPrivacyPolicy(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if err := h.treeUseCase.UpdatePrivacyPolicy(c.Request.Context(), uri.TreeID, middleware.GetUserID(c), nil); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.family_tree.privacy_policy_reset", nil)
+}
+
 func (h *familyTreeHandler) ListMyInvitations(c *gin.Context) {
 	invitations, err := h.treeUseCase.ListMyInvitations(c.Request.Context(), middleware.GetUserID(c))
 	if err != nil {
@@ -88,6 +192,7 @@ func (h *familyTreeHandler) Invite(c *gin.Context) {
 		uri.TreeID,
 		middleware.GetUserID(c),
 		req.Email,
+		req.Role,
 		req.Message,
 		req.ExpiresAt.ToTimePtr(),
 	)
@@ -98,6 +203,93 @@ func (h *familyTreeHandler) Invite(c *gin.Context) {
 	delivery.SuccessWithData(c, toInvitationResponse(invitation))
 }
 
+func (h *familyTreeHandler) ListMemberships(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	memberships, err := h.treeUseCase.ListMemberships(c.Request.Context(), uri.TreeID, middleware.GetUserID(c))
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	response := dto.FamilyTreeMembershipListResponse{Memberships: make([]dto.FamilyTreeMembershipResponse, 0, len(memberships))}
+	for _, membership := range memberships {
+		response.Memberships = append(response.Memberships, dto.FamilyTreeMembershipResponse{
+			UserID:   membership.UserID,
+			FullName: membership.FullName,
+			Email:    membership.Email,
+			Avatar:   membership.Avatar,
+			Role:     membership.Role,
+			JoinedAt: membership.JoinedAt,
+		})
+	}
+	delivery.SuccessWithData(c, response)
+}
+
+func (h *familyTreeHandler) UpdateMembership(c *gin.Context) {
+	var uri dto.MembershipUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	var req dto.UpdateMembershipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	if err := h.treeUseCase.UpdateMembershipRole(c.Request.Context(), uri.TreeID, middleware.GetUserID(c), uri.UserID, req.Role); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	delivery.Success(c, "success.membership.updated", nil)
+}
+
+func (h *familyTreeHandler) RemoveMembership(c *gin.Context) {
+	var uri dto.MembershipUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	if err := h.treeUseCase.RemoveMembership(c.Request.Context(), uri.TreeID, middleware.GetUserID(c), uri.UserID); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	delivery.Success(c, "success.membership.removed", nil)
+}
+
+func (h *familyTreeHandler) Leave(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	if err := h.treeUseCase.Leave(c.Request.Context(), uri.TreeID, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	delivery.Success(c, "success.membership.left", nil)
+}
+
+func (h *familyTreeHandler) TransferOwnership(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	var req dto.TransferOwnershipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	if err := h.treeUseCase.TransferOwnership(c.Request.Context(), uri.TreeID, middleware.GetUserID(c), req.UserID); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	delivery.Success(c, "success.membership.ownership_transferred", nil)
+}
+
 func (h *familyTreeHandler) ListInvitations(c *gin.Context) {
 	var uri dto.TreeIDUri
 	if err := c.ShouldBindUri(&uri); err != nil {
@@ -149,7 +341,7 @@ func (h *familyTreeHandler) CreateShareLink(c *gin.Context) {
 		delivery.Error(c, err)
 		return
 	}
-	link, err := h.treeUseCase.CreateShareLink(c.Request.Context(), uri.TreeID, middleware.GetUserID(c), req.ExpiresAt.ToTimePtr(), req.MaxVisits)
+	link, err := h.treeUseCase.CreateShareLink(c.Request.Context(), uri.TreeID, middleware.GetUserID(c), req.ExpiresAt.ToTimePtr(), req.MaxVisits, req.LivingPolicy)
 	if err != nil {
 		delivery.Error(c, err)
 		return
@@ -176,6 +368,24 @@ func (h *familyTreeHandler) ListShareLinks(c *gin.Context) {
 	delivery.SuccessWithData(c, response)
 }
 
+func (h *familyTreeHandler) UpdateShareLink(c *gin.Context) {
+	var uri dto.ShareIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	var req dto.UpdateShareLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	if err := h.treeUseCase.UpdateShareLinkPolicy(c.Request.Context(), uri.TreeID, uri.ShareID, middleware.GetUserID(c), req.LivingPolicy); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	delivery.Success(c, "success.share_link.updated", nil)
+}
+
 func (h *familyTreeHandler) RevokeShareLink(c *gin.Context) {
 	var uri dto.ShareIDUri
 	if err := c.ShouldBindUri(&uri); err != nil {
@@ -200,17 +410,38 @@ func (h *familyTreeHandler) GetPublicTree(c *gin.Context) {
 		delivery.Error(c, err)
 		return
 	}
-	tree, err := h.treeDiagramUseCase.Get(c.Request.Context(), link.TreeID, nil, domain.RoleGuest)
+	tree, err := h.treeDiagramUseCase.GetPublic(c.Request.Context(), link.TreeID, link.LivingPolicy)
 	if err != nil {
 		delivery.Error(c, err)
 		return
 	}
+
+	response := h.convertToTreeResponse(tree, "en")
+	labelRedacted(response, i18n.Translate("privacy.living", middleware.GetInterfaceLanguage(c), nil))
 	delivery.SuccessWithData(c, dto.PublicTreeResponse{
 		Share: toShareLinkResponse(link, publicShareBaseURL(c)),
-		Tree:  h.convertToTreeResponse(tree, "en"),
+		Tree:  response,
 	})
 }
 
+// labelRedacted names the members whose details a living policy hid
+func labelRedacted(node *dto.TreeNodeResponse, label string) {
+	if node == nil {
+		return
+	}
+	if node.Member.IsRedacted {
+		node.Member.Name = label
+	}
+	for i := range node.Member.Spouses {
+		if node.Member.Spouses[i].IsRedacted {
+			node.Member.Spouses[i].Name = label
+		}
+	}
+	for _, child := range node.Children {
+		labelRedacted(child, label)
+	}
+}
+
 func (h *familyTreeHandler) convertToTreeResponse(node *domain.MemberTreeNode, preferredLang string) *dto.TreeNodeResponse {
 	treeHandler := &treeHandler{}
 	return treeHandler.convertToTreeResponse(node, preferredLang)
@@ -226,8 +457,13 @@ func toFamilyTreeResponse(tree *domain.FamilyTree) dto.FamilyTreeResponse {
 		OwnerEmail:  tree.OwnerEmail,
 		UserRole:    tree.UserRole,
 		MemberCount: tree.MemberCount,
-		CreatedAt:   tree.CreatedAt,
-		UpdatedAt:   tree.UpdatedAt,
+		NameSettings: dto.NameSettings{
+			Formats:         tree.NameSettings.Formats,
+			NasabDepth:      tree.NameSettings.NasabDepth,
+			NasabConnectors: tree.NameSettings.NasabConnectors,
+		},
+		CreatedAt: tree.CreatedAt,
+		UpdatedAt: tree.UpdatedAt,
 	}
 }
 
@@ -248,6 +484,7 @@ func toInvitationResponse(invitation *domain.FamilyTreeInvitation) dto.FamilyTre
 		InviterName:   invitation.InviterName,
 		InviteeUserID: invitation.InviteeUserID,
 		InviteeEmail:  invitation.InviteeEmail,
+		Role:          invitation.Role,
 		Status:        invitation.Status,
 		Message:       invitation.Message,
 		CreatedAt:     invitation.CreatedAt,
@@ -258,16 +495,17 @@ func toInvitationResponse(invitation *domain.FamilyTreeInvitation) dto.FamilyTre
 
 func toShareLinkResponse(link *domain.FamilyTreeShareLink, baseURL string) dto.FamilyTreeShareLinkResponse {
 	return dto.FamilyTreeShareLinkResponse{
-		ShareID:    link.ShareID,
-		TreeID:     link.TreeID,
-		Token:      link.Token,
-		URL:        fmt.Sprintf("%s/public/trees/%s", strings.TrimRight(baseURL, "/"), link.Token),
-		CreatedBy:  link.CreatedBy,
-		CreatedAt:  link.CreatedAt,
-		ExpiresAt:  link.ExpiresAt,
-		MaxVisits:  link.MaxVisits,
-		VisitCount: link.VisitCount,
-		RevokedAt:  link.RevokedAt,
+		ShareID:      link.ShareID,
+		TreeID:       link.TreeID,
+		Token:        link.Token,
+		URL:          fmt.Sprintf("%s/public/trees/%s", strings.TrimRight(baseURL, "/"), link.Token),
+		CreatedBy:    link.CreatedBy,
+		CreatedAt:    link.CreatedAt,
+		ExpiresAt:    link.ExpiresAt,
+		MaxVisits:    link.MaxVisits,
+		VisitCount:   link.VisitCount,
+		RevokedAt:    link.RevokedAt,
+		LivingPolicy: link.LivingPolicy,
 	}
 }
 
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"github.com/escalopa/family-tree/internal/delivery"
+	"github.com/escalopa/family-tree/internal/delivery/http/dto"
+	"github.com/escalopa/family-tree/internal/delivery/http/middleware"
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type familyUnitHandler struct {
+	familyUnitUseCase FamilyUnitUseCase
+	familyTreeUseCase FamilyTreeUseCase
+}
+
+func NewFamilyUnitHandler(familyUnitUseCase FamilyUnitUseCase, familyTreeUseCase FamilyTreeUseCase) *familyUnitHandler {
+	return &familyUnitHandler{familyUnitUseCase: familyUnitUseCase, familyTreeUseCase: familyTreeUseCase}
+}
+
+func (h *familyUnitHandler) requireTreeAccess(c *gin.Context, treeID int) bool {
+	if err := h.familyTreeUseCase.EnsureAccess(c.Request.Context(), treeID, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return false
+	}
+	return true
+}
+
+func (h *familyUnitHandler) Get(c *gin.Context) {
+	var uri dto.FamilyUnitIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	unit, err := h.familyUnitUseCase.Get(c.Request.Context(), uri.TreeID, uri.FamilyUnitID)
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, toFamilyUnitResponse(unit))
+}
+
+func (h *familyUnitHandler) Create(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var req dto.CreateFamilyUnitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	unit := &domain.FamilyUnit{
+		TreeID:           uri.TreeID,
+		RelationshipType: req.RelationshipType,
+		Status:           req.Status,
+		StartDate:        req.StartDate.ToTimePtr(),
+		EndDate:          req.EndDate.ToTimePtr(),
+		PartnerIDs:       req.PartnerIDs,
+	}
+	if err := h.familyUnitUseCase.Create(c.Request.Context(), unit, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, toFamilyUnitResponse(unit))
+}
+
+func (h *familyUnitHandler) Update(c *gin.Context) {
+	var uri dto.FamilyUnitIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var req dto.UpdateFamilyUnitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	unit := &domain.FamilyUnit{
+		FamilyUnitID:     uri.FamilyUnitID,
+		TreeID:           uri.TreeID,
+		RelationshipType: req.RelationshipType,
+		Status:           req.Status,
+		StartDate:        req.StartDate.ToTimePtr(),
+		EndDate:          req.EndDate.ToTimePtr(),
+	}
+	if err := h.familyUnitUseCase.Update(c.Request.Context(), unit, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.family_unit.updated", nil)
+}
+
+// UpdateStatus marks a unit divorced, widowed, separated or active again
+// without touching its other fields
+func (h *familyUnitHandler) UpdateStatus(c *gin.Context) {
+	var uri dto.FamilyUnitIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var req dto.UpdateFamilyUnitStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	unit, err := h.familyUnitUseCase.Get(c.Request.Context(), uri.TreeID, uri.FamilyUnitID)
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	unit.Status = req.Status
+	if req.EndDate != nil {
+		unit.EndDate = req.EndDate.ToTimePtr()
+	} else if req.Status == domain.FamilyStatusActive {
+		unit.EndDate = nil
+	}
+
+	if err := h.familyUnitUseCase.Update(c.Request.Context(), unit, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.family_unit.status_updated", nil)
+}
+
+func (h *familyUnitHandler) Delete(c *gin.Context) {
+	var uri dto.FamilyUnitIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	if err := h.familyUnitUseCase.Delete(c.Request.Context(), uri.TreeID, uri.FamilyUnitID, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.family_unit.deleted", nil)
+}
+
+func (h *familyUnitHandler) AddChild(c *gin.Context) {
+	var uri dto.FamilyUnitChildUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var req dto.FamilyUnitChildRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	if err := h.familyUnitUseCase.AddChild(c.Request.Context(), uri.TreeID, uri.FamilyUnitID, uri.ChildID, req.RelationType, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.family_unit.child_added", nil)
+}
+
+func (h *familyUnitHandler) RemoveChild(c *gin.Context) {
+	var uri dto.FamilyUnitChildUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	if err := h.familyUnitUseCase.RemoveChild(c.Request.Context(), uri.TreeID, uri.FamilyUnitID, uri.ChildID, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.family_unit.child_removed", nil)
+}
+
+func toFamilyUnitResponse(unit *domain.FamilyUnit) dto.FamilyUnitResponse {
+	return dto.FamilyUnitResponse{
+		FamilyUnitID:     unit.FamilyUnitID,
+		TreeID:           unit.TreeID,
+		RelationshipType: unit.RelationshipType,
+		Status:           unit.Status,
+		StartDate:        dto.FromTimePtr(unit.StartDate),
+		EndDate:          dto.FromTimePtr(unit.EndDate),
+		Source:           unit.Source,
+		SourceSpouseID:   unit.SourceSpouseID,
+		PartnerIDs:       unit.PartnerIDs,
+		ChildIDs:         unit.ChildIDs,
+		ChildRelations:   unit.ChildRelations,
+	}
+}
@@ -1,5 +1,25 @@
 package handler
 
+import (
+	"strings"
+	"time"
+
+	"github.com/escalopa/family-tree/internal/delivery"
+	"github.com/escalopa/family-tree/internal/delivery/http/dto"
+	"github.com/escalopa/family-tree/internal/delivery/http/middleware"
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/escalopa/family-tree/internal/pkg/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// viewerOf is the signed-in user as the privacy policy sees them, their role
+// in the tree is looked up with the tree's policy
+// viewerOf is the caller with their role in the tree of the route, the role
+// is looked up later on routes outside a tree
+func viewerOf(c *gin.Context) domain.Viewer {
+	return domain.Viewer{UserID: middleware.GetUserID(c), Role: middleware.GetUserRole(c), TreeRole: middleware.GetTreeRole(c)}
+}
+
 func extractName(names map[string]string, preferredLang string) string {
 	if name, ok := names[preferredLang]; ok && name != "" {
 		return name
@@ -11,3 +31,80 @@ func extractName(names map[string]string, preferredLang string) string {
 	}
 	return ""
 }
+
+// resolveDate picks the date a request sent for the calendar it was entered in
+func resolveDate(calendar string, date *dto.Date, hijriDate *dto.HijriDate) *time.Time {
+	if calendar == domain.CalendarHijri {
+		return hijriDate.ToTimePtr()
+	}
+	return date.ToTimePtr()
+}
+
+// toNameParts keeps a missing map nil, so an update without name parts keeps them
+func toNameParts(parts map[string]dto.NameParts) map[string]domain.NameParts {
+	if parts == nil {
+		return nil
+	}
+	result := make(map[string]domain.NameParts, len(parts))
+	for langCode, part := range parts {
+		result[langCode] = domain.NameParts{Kunya: part.Kunya, Laqab: part.Laqab, Family: part.Family}
+	}
+	return result
+}
+
+func toNamePartsResponse(parts map[string]domain.NameParts) map[string]dto.NameParts {
+	result := make(map[string]dto.NameParts, len(parts))
+	for langCode, part := range parts {
+		result[langCode] = dto.NameParts{Kunya: part.Kunya, Laqab: part.Laqab, Family: part.Family}
+	}
+	return result
+}
+
+// validateNames requires a name in every active language, except the ones
+// that can be suggested from the Arabic name
+func validateNames(c *gin.Context, languageUseCase LanguageUseCase, names map[string]string) error {
+	activeLanguages, err := languageUseCase.List(c.Request.Context(), true)
+	if err != nil {
+		delivery.Error(c, err)
+		return err
+	}
+
+	// Names that can be transliterated are suggested from the Arabic name
+	canSuggest := strings.TrimSpace(names[domain.TransliterationSource]) != ""
+	for _, lang := range activeLanguages {
+		name, exists := names[lang.LanguageCode]
+		if canSuggest && domain.CanSuggestName(lang.LanguageCode) {
+			continue
+		}
+		if !exists || name == "" {
+			validationErr := domain.
+				NewValidationError("error.validation.names_required").
+				WithParams(map[string]string{"code": lang.LanguageCode})
+			delivery.Error(c, validationErr)
+			return validationErr
+		}
+	}
+
+	return nil
+}
+
+func toFieldChangeResponses(changes []domain.FieldChange, lang string) []dto.FieldChangeResponse {
+	responses := make([]dto.FieldChangeResponse, 0, len(changes))
+	for _, change := range changes {
+		responses = append(responses, dto.FieldChangeResponse{
+			Field:  change.Field,
+			Label:  translateOr("field."+change.Field, lang, change.Field),
+			Before: change.Before,
+			After:  change.After,
+		})
+	}
+	return responses
+}
+
+// translateOr translates the key, or returns fallback when it has no translation
+func translateOr(key, lang, fallback string) string {
+	if translated := i18n.Translate(key, lang, nil); translated != key {
+		return translated
+	}
+	return fallback
+}
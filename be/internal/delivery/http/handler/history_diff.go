@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"github.com/escalopa/family-tree/internal/delivery"
+	"github.com/escalopa/family-tree/internal/delivery/http/dto"
+	"github.com/escalopa/family-tree/internal/delivery/http/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// HistoryDiff compares a member between two of its history entries, with the
+// fields labeled in the interface language
+func (h *memberHandler) HistoryDiff(c *gin.Context) {
+	var uri dto.MemberIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	// A deleted member has history too, the use case checks its tree
+	treeID, ok := h.requireTreeAccess(c)
+	if !ok {
+		return
+	}
+
+	var query dto.HistoryDiffQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	diff, err := h.memberUseCase.HistoryDiff(c.Request.Context(), treeID, uri.MemberID, query.From, query.To, viewerOf(c))
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	lang := middleware.GetInterfaceLanguage(c)
+	preferredLang := middleware.GetPreferredLanguage(c)
+
+	response := dto.HistoryDiffResponse{
+		MemberID: diff.MemberID,
+		From:     diff.From,
+		To:       diff.To,
+		Fields:   toFieldChangeResponses(diff.Fields, lang),
+		Names:    make([]dto.NameChangeResponse, 0, len(diff.Names)),
+		Nicknames: dto.NicknameChangesResponse{
+			Added:   diff.Nicknames.Added,
+			Removed: diff.Nicknames.Removed,
+		},
+		Spouses: make([]dto.SpouseChangeResponse, 0, len(diff.Spouses)),
+	}
+	for _, name := range diff.Names {
+		response.Names = append(response.Names, dto.NameChangeResponse{
+			Language:      name.Language,
+			LanguageLabel: translateOr("language.name."+name.Language, lang, name.Language),
+			Before:        name.Before,
+			After:         name.After,
+		})
+	}
+	for _, spouse := range diff.Spouses {
+		response.Spouses = append(response.Spouses, dto.SpouseChangeResponse{
+			SpouseID:      spouse.SpouseID,
+			PartnerID:     spouse.PartnerID,
+			PartnerName:   extractName(spouse.PartnerNames, preferredLang),
+			PartnerGender: spouse.PartnerGender,
+			Change:        spouse.Change,
+			Fields:        toFieldChangeResponses(spouse.Fields, lang),
+		})
+	}
+
+	delivery.SuccessWithData(c, response)
+}
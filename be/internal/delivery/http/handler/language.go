@@ -118,7 +118,7 @@ func (h *LanguageHandler) ToggleActive(c *gin.Context) {
 		return
 	}
 
-	if err := h.languageUC.ToggleActive(c.Request.Context(), uri.Code, req.IsActive); err != nil {
+	if err := h.languageUC.ToggleActive(c.Request.Context(), uri.Code, req.IsActive, middleware.GetUserID(c)); err != nil {
 		delivery.Error(c, err)
 		return
 	}
@@ -0,0 +1,337 @@
+package handler
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/escalopa/family-tree/internal/delivery"
+	"github.com/escalopa/family-tree/internal/delivery/http/dto"
+	"github.com/escalopa/family-tree/internal/delivery/http/middleware"
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type mediaHandler struct {
+	mediaUseCase      MediaUseCase
+	memberUseCase     MemberUseCase
+	familyTreeUseCase FamilyTreeUseCase
+}
+
+func NewMediaHandler(mediaUseCase MediaUseCase, memberUseCase MemberUseCase, familyTreeUseCase FamilyTreeUseCase) *mediaHandler {
+	return &mediaHandler{
+		mediaUseCase:      mediaUseCase,
+		memberUseCase:     memberUseCase,
+		familyTreeUseCase: familyTreeUseCase,
+	}
+}
+
+func (h *mediaHandler) requireTreeAccess(c *gin.Context, treeID int) bool {
+	if err := h.familyTreeUseCase.EnsureAccess(c.Request.Context(), treeID, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return false
+	}
+	return true
+}
+
+func (h *mediaHandler) requireMemberInTree(c *gin.Context, treeID, memberID int) bool {
+	if !h.requireTreeAccess(c, treeID) {
+		return false
+	}
+	member, err := h.memberUseCase.Get(c.Request.Context(), memberID)
+	if err != nil {
+		delivery.Error(c, err)
+		return false
+	}
+	if member.TreeID != treeID {
+		delivery.Error(c, domain.NewNotFoundError("member"))
+		return false
+	}
+	return true
+}
+
+func (h *mediaHandler) Upload(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		delivery.Error(c, domain.NewValidationError("error.validation.missing_media_file"))
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		slog.Error("mediaHandler.Upload: read file", "error", err, "tree_id", uri.TreeID)
+		delivery.Error(c, domain.NewInternalError(err))
+		return
+	}
+
+	media := &domain.Media{
+		TreeID:     uri.TreeID,
+		UploadedBy: middleware.GetUserID(c),
+	}
+	if caption := c.PostForm("caption"); caption != "" {
+		media.Caption = &caption
+	}
+	if err := h.mediaUseCase.Upload(c.Request.Context(), media, data, header.Filename); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, toMediaResponse(media))
+}
+
+func (h *mediaHandler) Get(c *gin.Context) {
+	var uri dto.MediaIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	media, err := h.mediaUseCase.Get(c.Request.Context(), uri.TreeID, uri.MediaID, viewerOf(c))
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, toMediaResponse(media))
+}
+
+func (h *mediaHandler) GetFile(c *gin.Context) {
+	var uri dto.MediaIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	data, contentType, err := h.mediaUseCase.GetFile(c.Request.Context(), uri.TreeID, uri.MediaID, viewerOf(c))
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, data)
+}
+
+func (h *mediaHandler) List(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	items, err := h.mediaUseCase.List(c.Request.Context(), uri.TreeID, viewerOf(c))
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, toMediaListResponse(items))
+}
+
+func (h *mediaHandler) ListByMember(c *gin.Context) {
+	var treeURI dto.TreeIDUri
+	if err := c.ShouldBindUri(&treeURI); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	var uri dto.MemberIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	if !h.requireMemberInTree(c, treeURI.TreeID, uri.MemberID) {
+		return
+	}
+
+	items, err := h.mediaUseCase.ListByMember(c.Request.Context(), uri.MemberID, viewerOf(c))
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, toMediaListResponse(items))
+}
+
+func (h *mediaHandler) Update(c *gin.Context) {
+	var uri dto.MediaIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var req dto.MediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	media := &domain.Media{
+		MediaID:       uri.MediaID,
+		TreeID:        uri.TreeID,
+		Caption:       req.Caption,
+		Date:          resolveDate(req.DateCalendar, req.Date, req.DateHijri),
+		DateQualifier: req.DateQualifier,
+		DateEnd:       resolveDate(req.DateCalendar, req.DateEnd, req.DateEndHijri),
+		DateCalendar:  req.DateCalendar,
+		PlaceID:       req.PlaceID,
+	}
+	if err := h.mediaUseCase.Update(c.Request.Context(), media, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.media.updated", nil)
+}
+
+func (h *mediaHandler) Delete(c *gin.Context) {
+	var uri dto.MediaIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	if err := h.mediaUseCase.Delete(c.Request.Context(), uri.TreeID, uri.MediaID, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.media.deleted", nil)
+}
+
+func (h *mediaHandler) Tag(c *gin.Context) {
+	var uri dto.MediaTagUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var req dto.MediaTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	tag := domain.MediaTag{MemberID: uri.MemberID}
+	if req.Face != nil {
+		tag.Face = &domain.FaceRect{X: req.Face.X, Y: req.Face.Y, Width: req.Face.Width, Height: req.Face.Height}
+	}
+	if err := h.mediaUseCase.Tag(c.Request.Context(), uri.TreeID, uri.MediaID, tag, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.media.tagged", nil)
+}
+
+func (h *mediaHandler) Untag(c *gin.Context) {
+	var uri dto.MediaTagUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	if err := h.mediaUseCase.Untag(c.Request.Context(), uri.TreeID, uri.MediaID, uri.MemberID, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.media.untagged", nil)
+}
+
+func (h *mediaHandler) SetProfilePicture(c *gin.Context) {
+	var treeURI dto.TreeIDUri
+	if err := c.ShouldBindUri(&treeURI); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	var uri dto.MemberIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var req dto.ProfilePictureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireMemberInTree(c, treeURI.TreeID, uri.MemberID) {
+		return
+	}
+
+	if err := h.mediaUseCase.SetProfilePicture(c.Request.Context(), treeURI.TreeID, uri.MemberID, req.MediaID, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.member.picture_updated", nil)
+}
+
+func toMediaListResponse(items []*domain.Media) dto.MediaListResponse {
+	response := dto.MediaListResponse{Media: make([]dto.MediaResponse, 0, len(items))}
+	for _, media := range items {
+		response.Media = append(response.Media, toMediaResponse(media))
+	}
+	return response
+}
+
+func toMediaResponse(media *domain.Media) dto.MediaResponse {
+	tags := make([]dto.MediaTagResponse, 0, len(media.Tags))
+	for _, tag := range media.Tags {
+		response := dto.MediaTagResponse{MemberID: tag.MemberID}
+		if tag.Face != nil {
+			response.Face = &dto.FaceRect{X: tag.Face.X, Y: tag.Face.Y, Width: tag.Face.Width, Height: tag.Face.Height}
+		}
+		tags = append(tags, response)
+	}
+
+	return dto.MediaResponse{
+		MediaID:       media.MediaID,
+		TreeID:        media.TreeID,
+		Caption:       media.Caption,
+		Date:          dto.FromTimePtr(media.Date),
+		DateQualifier: media.DateQualifier,
+		DateEnd:       dto.FromTimePtr(media.DateEnd),
+		DateCalendar:  media.DateCalendar,
+		DateHijri:     dto.HijriFromTimePtr(media.Date),
+		DateEndHijri:  dto.HijriFromTimePtr(media.DateEnd),
+		PlaceID:       media.PlaceID,
+		UploadedBy:    media.UploadedBy,
+		Tags:          tags,
+		CreatedAt:     media.CreatedAt,
+		UpdatedAt:     media.UpdatedAt,
+	}
+}
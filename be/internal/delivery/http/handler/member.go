@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"io"
 	"log/slog"
 	"net/http"
@@ -57,27 +58,6 @@ func (h *memberHandler) requireMemberInTree(c *gin.Context, memberID int) (*doma
 	return member, treeID, true
 }
 
-func (h *memberHandler) validateNames(c *gin.Context, names map[string]string) error {
-	activeLanguages, err := h.languageUseCase.List(c.Request.Context(), true)
-	if err != nil {
-		delivery.Error(c, err)
-		return err
-	}
-
-	for _, lang := range activeLanguages {
-		name, exists := names[lang.LanguageCode]
-		if !exists || name == "" {
-			validationErr := domain.
-				NewValidationError("error.validation.names_required").
-				WithParams(map[string]string{"code": lang.LanguageCode})
-			delivery.Error(c, validationErr)
-			return validationErr
-		}
-	}
-
-	return nil
-}
-
 // CreateMember godoc
 // @Summary Create a new family member
 // @Description Creates a new member in the family tree (requires Admin role)
@@ -104,7 +84,7 @@ func (h *memberHandler) Create(c *gin.Context) {
 		return
 	}
 
-	if err := h.validateNames(c, req.Names); err != nil {
+	if err := validateNames(c, h.languageUseCase, req.Names); err != nil {
 		return
 	}
 	// Ensure nicknames is never nil, use empty array instead
@@ -114,15 +94,27 @@ func (h *memberHandler) Create(c *gin.Context) {
 	}
 
 	member := &domain.Member{
-		TreeID:      treeID,
-		Names:       req.Names,
-		Gender:      req.Gender,
-		DateOfBirth: req.DateOfBirth.ToTimePtr(),
-		DateOfDeath: req.DateOfDeath.ToTimePtr(),
-		FatherID:    req.FatherID,
-		MotherID:    req.MotherID,
-		Nicknames:   nicknames,
-		Profession:  req.Profession,
+		TreeID:               treeID,
+		Names:                req.Names,
+		NameParts:            toNameParts(req.NameParts),
+		Gender:               req.Gender,
+		DateOfBirth:          resolveDate(req.DateOfBirthCalendar, req.DateOfBirth, req.DateOfBirthHijri),
+		DateOfBirthQualifier: req.DateOfBirthQualifier,
+		DateOfBirthEnd:       resolveDate(req.DateOfBirthCalendar, req.DateOfBirthEnd, req.DateOfBirthEndHijri),
+		DateOfBirthCalendar:  req.DateOfBirthCalendar,
+		DateOfDeath:          resolveDate(req.DateOfDeathCalendar, req.DateOfDeath, req.DateOfDeathHijri),
+		DateOfDeathQualifier: req.DateOfDeathQualifier,
+		DateOfDeathEnd:       resolveDate(req.DateOfDeathCalendar, req.DateOfDeathEnd, req.DateOfDeathEndHijri),
+		DateOfDeathCalendar:  req.DateOfDeathCalendar,
+		BirthPlaceID:         req.BirthPlaceID,
+		DeathPlaceID:         req.DeathPlaceID,
+		BurialPlaceID:        req.BurialPlaceID,
+		FatherID:             req.FatherID,
+		MotherID:             req.MotherID,
+		Nicknames:            nicknames,
+		Profession:           req.Profession,
+		CustomFields:         req.CustomFields,
+		LivingStatus:         req.LivingStatus,
 	}
 
 	userID := middleware.GetUserID(c)
@@ -152,7 +144,7 @@ func (h *memberHandler) Update(c *gin.Context) {
 		return
 	}
 
-	if err := h.validateNames(c, req.Names); err != nil {
+	if err := validateNames(c, h.languageUseCase, req.Names); err != nil {
 		return
 	}
 
@@ -167,21 +159,37 @@ func (h *memberHandler) Update(c *gin.Context) {
 	}
 
 	member := &domain.Member{
-		MemberID:    uri.MemberID,
-		TreeID:      treeID,
-		Names:       req.Names,
-		Gender:      req.Gender,
-		Picture:     existingMember.Picture,
-		DateOfBirth: req.DateOfBirth.ToTimePtr(),
-		DateOfDeath: req.DateOfDeath.ToTimePtr(),
-		FatherID:    req.FatherID,
-		MotherID:    req.MotherID,
-		Nicknames:   nicknames,
-		Profession:  req.Profession,
-	}
-
-	userID := middleware.GetUserID(c)
-	if err := h.memberUseCase.Update(c.Request.Context(), member, req.Version, userID); err != nil {
+		MemberID:             uri.MemberID,
+		TreeID:               treeID,
+		Names:                req.Names,
+		NameParts:            toNameParts(req.NameParts),
+		Gender:               req.Gender,
+		Picture:              existingMember.Picture,
+		DateOfBirth:          resolveDate(req.DateOfBirthCalendar, req.DateOfBirth, req.DateOfBirthHijri),
+		DateOfBirthQualifier: req.DateOfBirthQualifier,
+		DateOfBirthEnd:       resolveDate(req.DateOfBirthCalendar, req.DateOfBirthEnd, req.DateOfBirthEndHijri),
+		DateOfBirthCalendar:  req.DateOfBirthCalendar,
+		DateOfDeath:          resolveDate(req.DateOfDeathCalendar, req.DateOfDeath, req.DateOfDeathHijri),
+		DateOfDeathQualifier: req.DateOfDeathQualifier,
+		DateOfDeathEnd:       resolveDate(req.DateOfDeathCalendar, req.DateOfDeathEnd, req.DateOfDeathEndHijri),
+		DateOfDeathCalendar:  req.DateOfDeathCalendar,
+		BirthPlaceID:         req.BirthPlaceID,
+		DeathPlaceID:         req.DeathPlaceID,
+		BurialPlaceID:        req.BurialPlaceID,
+		FatherID:             req.FatherID,
+		MotherID:             req.MotherID,
+		Nicknames:            nicknames,
+		Profession:           req.Profession,
+		CustomFields:         req.CustomFields,
+		LivingStatus:         req.LivingStatus,
+	}
+
+	if err := h.memberUseCase.Edit(c.Request.Context(), member, req.Version, req.Merge, viewerOf(c)); err != nil {
+		var conflict *domain.MemberConflict
+		if errors.As(err, &conflict) {
+			delivery.ErrorWithData(c, err, h.memberConflictResponse(c, conflict))
+			return
+		}
 		delivery.Error(c, err)
 		return
 	}
@@ -191,6 +199,51 @@ func (h *memberHandler) Update(c *gin.Context) {
 	delivery.SuccessWithData(c, response)
 }
 
+// memberConflictResponse shows an editor the member as it is now, what
+// changed since the version they edited and the fields left to resolve
+func (h *memberHandler) memberConflictResponse(c *gin.Context, conflict *domain.MemberConflict) dto.MemberConflictResponse {
+	lang := middleware.GetInterfaceLanguage(c)
+	preferredLang := middleware.GetPreferredLanguage(c)
+
+	response := dto.MemberConflictResponse{
+		Current:   h.memberDetailResponse(c, conflict.Current, nil, nil, nil),
+		Changes:   toFieldChangeResponses(conflict.Changes, lang),
+		History:   make([]dto.HistoryResponse, 0, len(conflict.History)),
+		Conflicts: make([]dto.MergeConflictResponse, 0, len(conflict.Conflicts)),
+	}
+	for _, h := range conflict.History {
+		response.History = append(response.History, dto.HistoryResponse{
+			HistoryID:     h.HistoryID,
+			MemberID:      h.MemberID,
+			MemberName:    extractName(h.MemberNames, preferredLang),
+			UserID:        h.UserID,
+			UserFullName:  h.UserFullName,
+			UserEmail:     h.UserEmail,
+			ChangedAt:     h.ChangedAt,
+			ChangeType:    h.ChangeType,
+			OldValues:     h.OldValues,
+			NewValues:     h.NewValues,
+			MemberVersion: h.MemberVersion,
+			ChangesetID:   h.ChangesetID,
+		})
+	}
+	for _, merge := range conflict.Conflicts {
+		// Keyed fields conflict per key, names per language
+		label := translateOr("field."+merge.Field, lang, merge.Field)
+		if field, key, keyed := strings.Cut(merge.Field, "."); keyed {
+			label = translateOr("field."+field, lang, field) + " (" + translateOr("language.name."+key, lang, key) + ")"
+		}
+		response.Conflicts = append(response.Conflicts, dto.MergeConflictResponse{
+			Field:   merge.Field,
+			Label:   label,
+			Base:    merge.Base,
+			Current: merge.Current,
+			Mine:    merge.Mine,
+		})
+	}
+	return response
+}
+
 func (h *memberHandler) Delete(c *gin.Context) {
 	var uri dto.MemberIDUri
 	if err := c.ShouldBindUri(&uri); err != nil {
@@ -219,6 +272,7 @@ func (h *memberHandler) Delete(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param member_id path int true "Member ID"
+// @Param as_of query string false "RFC 3339 time to read the member as it was then"
 // @Success 200 {object} dto.Response{data=dto.MemberResponse}
 // @Failure 400 {object} dto.Response
 // @Failure 401 {object} dto.Response
@@ -231,110 +285,172 @@ func (h *memberHandler) Get(c *gin.Context) {
 		return
 	}
 
+	var query dto.AsOfQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	viewer := viewerOf(c)
+	if query.AsOf != nil {
+		treeID, ok := h.requireTreeAccess(c)
+		if !ok {
+			return
+		}
+		past, err := h.memberUseCase.GetAsOf(c.Request.Context(), treeID, uri.MemberID, *query.AsOf, viewer)
+		if err != nil {
+			delivery.Error(c, err)
+			return
+		}
+		delivery.SuccessWithData(c, h.memberDetailResponse(c, past.Member, past.Parents, past.Children, past.Siblings))
+		return
+	}
+
 	member, _, ok := h.requireMemberInTree(c, uri.MemberID)
 	if !ok {
 		return
 	}
 
-	userRole := middleware.GetUserRole(c)
-	computed := h.memberUseCase.Compute(c.Request.Context(), member, userRole)
+	computed, err := h.memberUseCase.Compute(c.Request.Context(), member, viewer)
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	// Relatives that can't be loaded are left out rather than failing the member
+	parents, err := h.memberUseCase.ListParents(c.Request.Context(), member, viewer)
+	if err != nil {
+		parents = nil
+	}
+	children, err := h.memberUseCase.ListChildren(c.Request.Context(), uri.MemberID, viewer)
+	if err != nil {
+		children = nil
+	}
+	siblings, err := h.memberUseCase.ListSiblings(c.Request.Context(), uri.MemberID, viewer)
+	if err != nil {
+		siblings = nil
+	}
+
+	delivery.SuccessWithData(c, h.memberDetailResponse(c, computed, parents, children, siblings))
+}
 
+// memberDetailResponse builds the member response with its spouses and close family
+func (h *memberHandler) memberDetailResponse(c *gin.Context, computed *domain.MemberWithComputed, parents, children, siblings []*domain.Member) dto.MemberResponse {
 	preferredLang := middleware.GetPreferredLanguage(c)
-	memberID := uri.MemberID
 
 	spousesDTO := make([]dto.SpouseInfo, len(computed.Spouses))
 	for i, spouse := range computed.Spouses {
 		spousesDTO[i] = dto.SpouseInfo{
-			SpouseID:     spouse.SpouseID,
-			MemberID:     spouse.MemberID,
-			Name:         extractName(spouse.Names, preferredLang),
-			Names:        spouse.Names,
-			Gender:       spouse.Gender,
-			Picture:      spouse.Picture,
-			MarriageDate: dto.FromTimePtr(spouse.MarriageDate),
-			DivorceDate:  dto.FromTimePtr(spouse.DivorceDate),
-			MarriedYears: dto.CalculateMarriedYears(spouse.MarriageDate, spouse.DivorceDate),
+			SpouseID:              spouse.SpouseID,
+			MemberID:              spouse.MemberID,
+			Name:                  extractName(spouse.Names, preferredLang),
+			Names:                 spouse.Names,
+			Gender:                spouse.Gender,
+			Picture:               spouse.Picture,
+			MarriageDate:          dto.FromTimePtr(spouse.MarriageDate),
+			MarriageDateQualifier: spouse.MarriageDateQualifier,
+			MarriageDateEnd:       dto.FromTimePtr(spouse.MarriageDateEnd),
+			MarriageDateCalendar:  spouse.MarriageDateCalendar,
+			MarriageDateHijri:     dto.HijriFromTimePtr(spouse.MarriageDate),
+			MarriageDateEndHijri:  dto.HijriFromTimePtr(spouse.MarriageDateEnd),
+			DivorceDate:           dto.FromTimePtr(spouse.DivorceDate),
+			DivorceDateQualifier:  spouse.DivorceDateQualifier,
+			DivorceDateEnd:        dto.FromTimePtr(spouse.DivorceDateEnd),
+			DivorceDateCalendar:   spouse.DivorceDateCalendar,
+			DivorceDateHijri:      dto.HijriFromTimePtr(spouse.DivorceDate),
+			DivorceDateEndHijri:   dto.HijriFromTimePtr(spouse.DivorceDateEnd),
+			MarriagePlaceID:       spouse.MarriagePlaceID,
+			MarriedYears:          dto.CalculateMarriedYears(spouse.MarriageDate, spouse.DivorceDate),
 		}
 	}
 
 	var fatherInfo, motherInfo *dto.MemberInfo
-	if computed.FatherID != nil {
-		father, err := h.memberUseCase.Get(c.Request.Context(), *computed.FatherID)
-		if err == nil {
-			fatherInfo = &dto.MemberInfo{
-				MemberID: father.MemberID,
-				Name:     extractName(father.Names, preferredLang),
-				Picture:  father.Picture,
-			}
+	for _, parent := range parents {
+		info := &dto.MemberInfo{
+			MemberID: parent.MemberID,
+			Name:     extractName(parent.Names, preferredLang),
+			Picture:  parent.Picture,
 		}
-	}
-	if computed.MotherID != nil {
-		mother, err := h.memberUseCase.Get(c.Request.Context(), *computed.MotherID)
-		if err == nil {
-			motherInfo = &dto.MemberInfo{
-				MemberID: mother.MemberID,
-				Name:     extractName(mother.Names, preferredLang),
-				Picture:  mother.Picture,
-			}
+		if computed.FatherID != nil && *computed.FatherID == parent.MemberID {
+			fatherInfo = info
+		} else {
+			motherInfo = info
 		}
 	}
 
 	var childrenInfo []dto.MemberInfo
-	children, err := h.memberUseCase.ListChildren(c.Request.Context(), memberID)
-	if err == nil {
-		for _, child := range children {
-			childrenInfo = append(childrenInfo, dto.MemberInfo{
-				MemberID: child.MemberID,
-				Name:     extractName(child.Names, preferredLang),
-				Picture:  child.Picture,
-			})
-		}
+	for _, child := range children {
+		childrenInfo = append(childrenInfo, dto.MemberInfo{
+			MemberID: child.MemberID,
+			Name:     extractName(child.Names, preferredLang),
+			Picture:  child.Picture,
+		})
 	}
 
 	var siblingsInfo []dto.MemberInfo
-	siblings, err := h.memberUseCase.ListSiblings(c.Request.Context(), memberID)
-	if err == nil {
-		for _, sibling := range siblings {
-			siblingsInfo = append(siblingsInfo, dto.MemberInfo{
-				MemberID: sibling.MemberID,
-				Name:     extractName(sibling.Names, preferredLang),
-				Picture:  sibling.Picture,
-			})
-		}
+	for _, sibling := range siblings {
+		siblingsInfo = append(siblingsInfo, dto.MemberInfo{
+			MemberID: sibling.MemberID,
+			Name:     extractName(sibling.Names, preferredLang),
+			Picture:  sibling.Picture,
+		})
 	}
 
-	response := dto.MemberResponse{
-		MemberID:        computed.MemberID,
-		TreeID:          computed.TreeID,
-		Name:            extractName(computed.Names, preferredLang),
-		Names:           computed.Names,
-		FullName:        extractName(computed.FullNames, preferredLang),
-		FullNames:       computed.FullNames,
-		Gender:          computed.Gender,
-		Picture:         computed.Picture,
-		DateOfBirth:     dto.FromTimePtr(computed.DateOfBirth),
-		DateOfDeath:     dto.FromTimePtr(computed.DateOfDeath),
-		FatherID:        computed.FatherID,
-		MotherID:        computed.MotherID,
-		Father:          fatherInfo,
-		Mother:          motherInfo,
-		Nicknames:       computed.Nicknames,
-		Profession:      computed.Profession,
-		Version:         computed.Version,
-		Age:             computed.Age,
-		GenerationLevel: computed.GenerationLevel,
-		IsMarried:       computed.IsMarried,
-		Spouses:         spousesDTO,
-		Children:        childrenInfo,
-		Siblings:        siblingsInfo,
+	citationCounts, err := h.memberUseCase.CitationCounts(c.Request.Context(), computed.MemberID)
+	if err != nil {
+		citationCounts = map[string]int{}
+	}
+
+	return dto.MemberResponse{
+		MemberID:             computed.MemberID,
+		TreeID:               computed.TreeID,
+		Name:                 extractName(computed.Names, preferredLang),
+		Names:                computed.Names,
+		NameParts:            toNamePartsResponse(computed.NameParts),
+		SuggestedNames:       computed.SuggestedNames,
+		FullName:             extractName(computed.FullNames, preferredLang),
+		FullNames:            computed.FullNames,
+		Gender:               computed.Gender,
+		Picture:              computed.Picture,
+		DateOfBirth:          dto.FromTimePtr(computed.DateOfBirth),
+		DateOfBirthQualifier: computed.DateOfBirthQualifier,
+		DateOfBirthEnd:       dto.FromTimePtr(computed.DateOfBirthEnd),
+		DateOfBirthCalendar:  computed.DateOfBirthCalendar,
+		DateOfBirthHijri:     dto.HijriFromTimePtr(computed.DateOfBirth),
+		DateOfBirthEndHijri:  dto.HijriFromTimePtr(computed.DateOfBirthEnd),
+		DateOfDeath:          dto.FromTimePtr(computed.DateOfDeath),
+		DateOfDeathQualifier: computed.DateOfDeathQualifier,
+		DateOfDeathEnd:       dto.FromTimePtr(computed.DateOfDeathEnd),
+		DateOfDeathCalendar:  computed.DateOfDeathCalendar,
+		DateOfDeathHijri:     dto.HijriFromTimePtr(computed.DateOfDeath),
+		DateOfDeathEndHijri:  dto.HijriFromTimePtr(computed.DateOfDeathEnd),
+		BirthPlaceID:         computed.BirthPlaceID,
+		DeathPlaceID:         computed.DeathPlaceID,
+		BurialPlaceID:        computed.BurialPlaceID,
+		FatherID:             computed.FatherID,
+		MotherID:             computed.MotherID,
+		Father:               fatherInfo,
+		Mother:               motherInfo,
+		Nicknames:            computed.Nicknames,
+		Profession:           computed.Profession,
+		CustomFields:         computed.CustomFields,
+		LivingStatus:         computed.LivingStatus,
+		Version:              computed.Version,
+		Age:                  computed.Age,
+		AgeMin:               computed.AgeMin,
+		AgeMax:               computed.AgeMax,
+		GenerationLevel:      computed.GenerationLevel,
+		IsMarried:            computed.IsMarried,
+		Spouses:              spousesDTO,
+		Children:             childrenInfo,
+		Siblings:             siblingsInfo,
+		CitationCounts:       citationCounts,
 	}
-
-	delivery.SuccessWithData(c, response)
 }
 
 // SearchMembers godoc
 // @Summary Search family members
-// @Description Search for members by name, gender, or marital status (at least one filter required)
+// @Description Search for members by name, gender, marital status, or custom field value (at least one filter required)
 // @Tags members
 // @Accept json
 // @Produce json
@@ -343,6 +459,8 @@ func (h *memberHandler) Get(c *gin.Context) {
 // @Param english_name query string false "English name to search for"
 // @Param gender query string false "Gender filter (male/female)"
 // @Param married query int false "Marital status (0=unmarried, 1=married)"
+// @Param custom_value query string false "Text to search for in custom field values"
+// @Param custom_field query string false "Custom field key to limit the custom value search to"
 // @Param cursor query string false "Pagination cursor"
 // @Param limit query int false "Number of items to return (1-100)" default(20)
 // @Success 200 {object} dto.Response{data=dto.PaginatedMembersResponse}
@@ -369,11 +487,13 @@ func (h *memberHandler) List(c *gin.Context) {
 		EnglishName: query.EnglishName,
 		Gender:      query.Gender,
 		IsMarried:   query.Married,
+		CustomField: query.CustomField,
+		CustomValue: query.CustomValue,
 	}
 
 	isSearchEndpoint := strings.HasSuffix(c.FullPath(), "/members/search")
 	if isSearchEndpoint {
-		if query.Name == nil && query.ArabicName == nil && query.EnglishName == nil && query.Gender == nil && query.Married == nil {
+		if query.Name == nil && query.ArabicName == nil && query.EnglishName == nil && query.Gender == nil && query.Married == nil && query.CustomValue == nil {
 			delivery.Error(c, domain.NewValidationError("error.validation.at_least_one_filter_required"))
 			return
 		}
@@ -383,7 +503,7 @@ func (h *memberHandler) List(c *gin.Context) {
 		}
 	}
 
-	members, nextCursor, err := h.memberUseCase.List(c.Request.Context(), filter, query.Cursor, query.Limit)
+	members, nextCursor, err := h.memberUseCase.List(c.Request.Context(), filter, viewerOf(c), query.Cursor, query.Limit)
 	if err != nil {
 		delivery.Error(c, err)
 		return
@@ -394,15 +514,27 @@ func (h *memberHandler) List(c *gin.Context) {
 	var membersResponse []dto.MemberListItem
 	for _, m := range members {
 		membersResponse = append(membersResponse, dto.MemberListItem{
-			MemberID:    m.MemberID,
-			TreeID:      m.TreeID,
-			Name:        extractName(m.Names, preferredLang),
-			Names:       m.Names,
-			Gender:      m.Gender,
-			Picture:     m.Picture,
-			DateOfBirth: dto.FromTimePtr(m.DateOfBirth),
-			DateOfDeath: dto.FromTimePtr(m.DateOfDeath),
-			IsMarried:   m.IsMarried,
+			MemberID:             m.MemberID,
+			TreeID:               m.TreeID,
+			Name:                 extractName(m.Names, preferredLang),
+			Names:                m.Names,
+			SuggestedNames:       m.SuggestedNames,
+			Gender:               m.Gender,
+			Picture:              m.Picture,
+			DateOfBirth:          dto.FromTimePtr(m.DateOfBirth),
+			DateOfBirthQualifier: m.DateOfBirthQualifier,
+			DateOfBirthEnd:       dto.FromTimePtr(m.DateOfBirthEnd),
+			DateOfBirthCalendar:  m.DateOfBirthCalendar,
+			DateOfBirthHijri:     dto.HijriFromTimePtr(m.DateOfBirth),
+			DateOfBirthEndHijri:  dto.HijriFromTimePtr(m.DateOfBirthEnd),
+			DateOfDeath:          dto.FromTimePtr(m.DateOfDeath),
+			DateOfDeathQualifier: m.DateOfDeathQualifier,
+			DateOfDeathEnd:       dto.FromTimePtr(m.DateOfDeathEnd),
+			DateOfDeathCalendar:  m.DateOfDeathCalendar,
+			DateOfDeathHijri:     dto.HijriFromTimePtr(m.DateOfDeath),
+			DateOfDeathEndHijri:  dto.HijriFromTimePtr(m.DateOfDeathEnd),
+			IsMarried:            m.IsMarried,
+			CustomFields:         m.CustomFields,
 		})
 	}
 
@@ -436,7 +568,7 @@ func (h *memberHandler) ListHistory(c *gin.Context) {
 		return
 	}
 
-	history, nextCursor, err := h.memberUseCase.ListHistory(c.Request.Context(), query.MemberID, query.Cursor, query.Limit)
+	history, nextCursor, err := h.memberUseCase.ListHistory(c.Request.Context(), member, viewerOf(c), query.Cursor, query.Limit)
 	if err != nil {
 		delivery.Error(c, err)
 		return
@@ -458,6 +590,7 @@ func (h *memberHandler) ListHistory(c *gin.Context) {
 			OldValues:     h.OldValues,
 			NewValues:     h.NewValues,
 			MemberVersion: h.MemberVersion,
+			ChangesetID:   h.ChangesetID,
 		})
 	}
 
@@ -475,7 +608,9 @@ func (h *memberHandler) Rollback(c *gin.Context) {
 		delivery.Error(c, err)
 		return
 	}
-	if _, _, ok := h.requireMemberInTree(c, uri.MemberID); !ok {
+	// A deleted member can be rolled back too, the use case checks its tree
+	treeID, ok := h.requireTreeAccess(c)
+	if !ok {
 		return
 	}
 
@@ -486,7 +621,7 @@ func (h *memberHandler) Rollback(c *gin.Context) {
 	}
 
 	userID := middleware.GetUserID(c)
-	if err := h.memberUseCase.Rollback(c.Request.Context(), uri.MemberID, req.HistoryID, userID); err != nil {
+	if err := h.memberUseCase.Rollback(c.Request.Context(), treeID, uri.MemberID, req.HistoryID, userID); err != nil {
 		delivery.Error(c, err)
 		return
 	}
@@ -559,7 +694,7 @@ func (h *memberHandler) GetPicture(c *gin.Context) {
 		return
 	}
 
-	imageData, contentType, err := h.memberUseCase.GetPicture(c.Request.Context(), uri.MemberID)
+	imageData, contentType, err := h.memberUseCase.GetPicture(c.Request.Context(), uri.MemberID, viewerOf(c))
 	if err != nil {
 		delivery.Error(c, err)
 		return
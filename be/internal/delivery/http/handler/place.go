@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"github.com/escalopa/family-tree/internal/delivery"
+	"github.com/escalopa/family-tree/internal/delivery/http/dto"
+	"github.com/escalopa/family-tree/internal/delivery/http/middleware"
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type placeHandler struct {
+	placeUseCase      PlaceUseCase
+	familyTreeUseCase FamilyTreeUseCase
+}
+
+func NewPlaceHandler(placeUseCase PlaceUseCase, familyTreeUseCase FamilyTreeUseCase) *placeHandler {
+	return &placeHandler{placeUseCase: placeUseCase, familyTreeUseCase: familyTreeUseCase}
+}
+
+func (h *placeHandler) requireTreeAccess(c *gin.Context, treeID int) bool {
+	if err := h.familyTreeUseCase.EnsureAccess(c.Request.Context(), treeID, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return false
+	}
+	return true
+}
+
+func (h *placeHandler) Create(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var req dto.PlaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	place := &domain.Place{
+		TreeID:    uri.TreeID,
+		Names:     req.Names,
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+	}
+	if err := h.placeUseCase.Create(c.Request.Context(), place); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, toPlaceResponse(place, middleware.GetPreferredLanguage(c)))
+}
+
+func (h *placeHandler) Get(c *gin.Context) {
+	var uri dto.PlaceIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	place, err := h.placeUseCase.Get(c.Request.Context(), uri.TreeID, uri.PlaceID)
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, toPlaceResponse(place, middleware.GetPreferredLanguage(c)))
+}
+
+func (h *placeHandler) List(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	places, err := h.placeUseCase.List(c.Request.Context(), uri.TreeID)
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	preferredLang := middleware.GetPreferredLanguage(c)
+	response := dto.PlaceListResponse{Places: make([]dto.PlaceResponse, 0, len(places))}
+	for _, place := range places {
+		response.Places = append(response.Places, toPlaceResponse(place, preferredLang))
+	}
+	delivery.SuccessWithData(c, response)
+}
+
+func (h *placeHandler) Update(c *gin.Context) {
+	var uri dto.PlaceIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var req dto.PlaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	place := &domain.Place{
+		PlaceID:   uri.PlaceID,
+		TreeID:    uri.TreeID,
+		Names:     req.Names,
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+	}
+	if err := h.placeUseCase.Update(c.Request.Context(), place); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.place.updated", nil)
+}
+
+func (h *placeHandler) Delete(c *gin.Context) {
+	var uri dto.PlaceIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	if err := h.placeUseCase.Delete(c.Request.Context(), uri.TreeID, uri.PlaceID); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.place.deleted", nil)
+}
+
+func (h *placeHandler) GetMap(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var query dto.MapQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	filter := domain.MapFilter{
+		TreeID:        uri.TreeID,
+		MinGeneration: query.MinGeneration,
+		MaxGeneration: query.MaxGeneration,
+		From:          query.From,
+		To:            query.To,
+	}
+	features, err := h.placeUseCase.GetMap(c.Request.Context(), filter, viewerOf(c))
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	preferredLang := middleware.GetPreferredLanguage(c)
+	response := dto.GeoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: make([]dto.GeoJSONFeature, 0, len(features)),
+	}
+	for _, feature := range features {
+		response.Features = append(response.Features, dto.GeoJSONFeature{
+			Type: "Feature",
+			Geometry: dto.GeoJSONPoint{
+				Type:        "Point",
+				Coordinates: [2]float64{*feature.Place.Longitude, *feature.Place.Latitude},
+			},
+			Properties: dto.MapFeatureProperties{
+				EventType:  feature.EventType,
+				MemberID:   feature.MemberID,
+				MemberName: extractName(feature.MemberNames, preferredLang),
+				Generation: feature.Generation,
+				Date:       dto.FromTimePtr(feature.Date),
+				PlaceID:    feature.Place.PlaceID,
+				PlaceName:  extractName(feature.Place.Names, preferredLang),
+			},
+		})
+	}
+
+	delivery.SuccessWithData(c, response)
+}
+
+func toPlaceResponse(place *domain.Place, preferredLang string) dto.PlaceResponse {
+	return dto.PlaceResponse{
+		PlaceID:   place.PlaceID,
+		TreeID:    place.TreeID,
+		Name:      extractName(place.Names, preferredLang),
+		Names:     place.Names,
+		Latitude:  place.Latitude,
+		Longitude: place.Longitude,
+		CreatedAt: place.CreatedAt,
+		UpdatedAt: place.UpdatedAt,
+	}
+}
@@ -0,0 +1,352 @@
+package handler
+
+import (
+	"github.com/escalopa/family-tree/internal/delivery"
+	"github.com/escalopa/family-tree/internal/delivery/http/dto"
+	"github.com/escalopa/family-tree/internal/delivery/http/middleware"
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type proposalHandler struct {
+	proposalUseCase   ProposalUseCase
+	languageUseCase   LanguageUseCase
+	familyTreeUseCase FamilyTreeUseCase
+}
+
+func NewProposalHandler(proposalUseCase ProposalUseCase, languageUseCase LanguageUseCase, familyTreeUseCase FamilyTreeUseCase) *proposalHandler {
+	return &proposalHandler{
+		proposalUseCase:   proposalUseCase,
+		languageUseCase:   languageUseCase,
+		familyTreeUseCase: familyTreeUseCase,
+	}
+}
+
+func (h *proposalHandler) requireTreeAccess(c *gin.Context, treeID int) bool {
+	if err := h.familyTreeUseCase.EnsureAccess(c.Request.Context(), treeID, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return false
+	}
+	return true
+}
+
+func (h *proposalHandler) Submit(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var req dto.SubmitProposalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	proposal, ok := h.proposalFromPayload(c, uri.TreeID, req.ProposalPayload)
+	if !ok {
+		return
+	}
+	proposal.ProposerUserID = middleware.GetUserID(c)
+	proposal.Kind = req.Kind
+	proposal.MemberID = req.MemberID
+	proposal.SpouseID = req.SpouseID
+
+	if err := h.proposalUseCase.Submit(c.Request.Context(), proposal); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, toProposalResponse(c, &domain.ProposalReview{Proposal: proposal}))
+}
+
+func (h *proposalHandler) Revise(c *gin.Context) {
+	var uri dto.ProposalIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var req dto.ReviseProposalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	proposal, ok := h.proposalFromPayload(c, uri.TreeID, req.ProposalPayload)
+	if !ok {
+		return
+	}
+	proposal.ProposalID = uri.ProposalID
+
+	if err := h.proposalUseCase.Revise(c.Request.Context(), proposal, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, toProposalResponse(c, &domain.ProposalReview{Proposal: proposal}))
+}
+
+// List is the review queue of a tree, pending proposals by default
+func (h *proposalHandler) List(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var query dto.ProposalListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	status := domain.ProposalStatusPending
+	if query.Status != nil {
+		status = *query.Status
+	}
+	filter := domain.ProposalFilter{TreeID: uri.TreeID, Status: &status}
+	h.list(c, filter, query.PaginationQuery)
+}
+
+// ListMine lists the proposals the signed-in user made in any status
+func (h *proposalHandler) ListMine(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var query dto.ProposalListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	filter := domain.ProposalFilter{TreeID: uri.TreeID, Status: query.Status, ProposerUserID: &userID}
+	h.list(c, filter, query.PaginationQuery)
+}
+
+func (h *proposalHandler) list(c *gin.Context, filter domain.ProposalFilter, page dto.PaginationQuery) {
+	reviews, nextCursor, err := h.proposalUseCase.List(c.Request.Context(), filter, viewerOf(c), page.Cursor, page.Limit)
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	response := dto.PaginatedProposalsResponse{
+		Proposals:  make([]dto.ProposalResponse, 0, len(reviews)),
+		NextCursor: nextCursor,
+	}
+	for _, review := range reviews {
+		response.Proposals = append(response.Proposals, toProposalResponse(c, review))
+	}
+
+	delivery.SuccessWithData(c, response)
+}
+
+func (h *proposalHandler) Get(c *gin.Context) {
+	var uri dto.ProposalIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	review, err := h.proposalUseCase.Get(c.Request.Context(), uri.TreeID, uri.ProposalID, viewerOf(c))
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, toProposalResponse(c, review))
+}
+
+func (h *proposalHandler) Approve(c *gin.Context) {
+	var uri dto.ProposalIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var req dto.ApproveProposalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	proposal, err := h.proposalUseCase.Approve(c.Request.Context(), uri.TreeID, uri.ProposalID, middleware.GetUserID(c), req.Comment)
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, toProposalResponse(c, &domain.ProposalReview{Proposal: proposal}))
+}
+
+func (h *proposalHandler) Reject(c *gin.Context) {
+	var uri dto.ProposalIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var req dto.RejectProposalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	proposal, err := h.proposalUseCase.Reject(c.Request.Context(), uri.TreeID, uri.ProposalID, middleware.GetUserID(c), req.Reason)
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, toProposalResponse(c, &domain.ProposalReview{Proposal: proposal}))
+}
+
+func (h *proposalHandler) RequestChanges(c *gin.Context) {
+	var uri dto.ProposalIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var req dto.RequestProposalChangesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	proposal, err := h.proposalUseCase.RequestChanges(c.Request.Context(), uri.TreeID, uri.ProposalID, middleware.GetUserID(c), req.Comment)
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, toProposalResponse(c, &domain.ProposalReview{Proposal: proposal}))
+}
+
+// proposalFromPayload builds the proposed member or spouse relationship, the
+// use case keeps the one the proposal kind needs
+func (h *proposalHandler) proposalFromPayload(c *gin.Context, treeID int, payload dto.ProposalPayload) (*domain.Proposal, bool) {
+	proposal := &domain.Proposal{
+		TreeID:      treeID,
+		BaseVersion: payload.Version,
+		Comment:     payload.Comment,
+	}
+
+	if req := payload.Member; req != nil {
+		if err := validateNames(c, h.languageUseCase, req.Names); err != nil {
+			return nil, false
+		}
+		nicknames := req.Nicknames
+		if nicknames == nil {
+			nicknames = []string{}
+		}
+		proposal.Member = &domain.Member{
+			TreeID:               treeID,
+			Names:                req.Names,
+			NameParts:            toNameParts(req.NameParts),
+			Gender:               req.Gender,
+			DateOfBirth:          resolveDate(req.DateOfBirthCalendar, req.DateOfBirth, req.DateOfBirthHijri),
+			DateOfBirthQualifier: req.DateOfBirthQualifier,
+			DateOfBirthEnd:       resolveDate(req.DateOfBirthCalendar, req.DateOfBirthEnd, req.DateOfBirthEndHijri),
+			DateOfBirthCalendar:  req.DateOfBirthCalendar,
+			DateOfDeath:          resolveDate(req.DateOfDeathCalendar, req.DateOfDeath, req.DateOfDeathHijri),
+			DateOfDeathQualifier: req.DateOfDeathQualifier,
+			DateOfDeathEnd:       resolveDate(req.DateOfDeathCalendar, req.DateOfDeathEnd, req.DateOfDeathEndHijri),
+			DateOfDeathCalendar:  req.DateOfDeathCalendar,
+			BirthPlaceID:         req.BirthPlaceID,
+			DeathPlaceID:         req.DeathPlaceID,
+			BurialPlaceID:        req.BurialPlaceID,
+			FatherID:             req.FatherID,
+			MotherID:             req.MotherID,
+			Nicknames:            nicknames,
+			Profession:           req.Profession,
+			CustomFields:         req.CustomFields,
+			LivingStatus:         req.LivingStatus,
+		}
+	}
+
+	if payload.Spouse != nil || payload.FatherID != nil || payload.MotherID != nil {
+		proposal.Spouse = &domain.Spouse{}
+		if payload.FatherID != nil {
+			proposal.Spouse.FatherID = *payload.FatherID
+		}
+		if payload.MotherID != nil {
+			proposal.Spouse.MotherID = *payload.MotherID
+		}
+		if req := payload.Spouse; req != nil {
+			proposal.Spouse.MarriageDate = resolveDate(req.MarriageDateCalendar, req.MarriageDate, req.MarriageDateHijri)
+			proposal.Spouse.MarriageDateQualifier = req.MarriageDateQualifier
+			proposal.Spouse.MarriageDateEnd = resolveDate(req.MarriageDateCalendar, req.MarriageDateEnd, req.MarriageDateEndHijri)
+			proposal.Spouse.MarriageDateCalendar = req.MarriageDateCalendar
+			proposal.Spouse.DivorceDate = resolveDate(req.DivorceDateCalendar, req.DivorceDate, req.DivorceDateHijri)
+			proposal.Spouse.DivorceDateQualifier = req.DivorceDateQualifier
+			proposal.Spouse.DivorceDateEnd = resolveDate(req.DivorceDateCalendar, req.DivorceDateEnd, req.DivorceDateEndHijri)
+			proposal.Spouse.DivorceDateCalendar = req.DivorceDateCalendar
+			proposal.Spouse.MarriagePlaceID = req.MarriagePlaceID
+		}
+	}
+
+	return proposal, true
+}
+
+func toProposalResponse(c *gin.Context, review *domain.ProposalReview) dto.ProposalResponse {
+	proposal := review.Proposal
+	response := dto.ProposalResponse{
+		ProposalID:     proposal.ProposalID,
+		TreeID:         proposal.TreeID,
+		ProposerUserID: proposal.ProposerUserID,
+		ProposerName:   proposal.ProposerName,
+		Kind:           proposal.Kind,
+		MemberID:       proposal.MemberID,
+		SpouseID:       proposal.SpouseID,
+		Comment:        proposal.Comment,
+		Status:         proposal.Status,
+		Stale:          review.Stale,
+		Changes:        toFieldChangeResponses(review.Changes, middleware.GetInterfaceLanguage(c)),
+		ReviewerUserID: proposal.ReviewerUserID,
+		ReviewComment:  proposal.ReviewComment,
+		CreatedAt:      proposal.CreatedAt,
+		UpdatedAt:      proposal.UpdatedAt,
+		ReviewedAt:     proposal.ReviewedAt,
+	}
+	if proposal.Member != nil {
+		response.MemberName = extractName(proposal.Member.Names, middleware.GetPreferredLanguage(c))
+	}
+	return response
+}
@@ -0,0 +1,418 @@
+package handler
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/escalopa/family-tree/internal/delivery"
+	"github.com/escalopa/family-tree/internal/delivery/http/dto"
+	"github.com/escalopa/family-tree/internal/delivery/http/middleware"
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type sourceHandler struct {
+	sourceUseCase     SourceUseCase
+	familyTreeUseCase FamilyTreeUseCase
+}
+
+func NewSourceHandler(sourceUseCase SourceUseCase, familyTreeUseCase FamilyTreeUseCase) *sourceHandler {
+	return &sourceHandler{sourceUseCase: sourceUseCase, familyTreeUseCase: familyTreeUseCase}
+}
+
+func (h *sourceHandler) requireTreeAccess(c *gin.Context, treeID int) bool {
+	if err := h.familyTreeUseCase.EnsureAccess(c.Request.Context(), treeID, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return false
+	}
+	return true
+}
+
+func (h *sourceHandler) Create(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var req dto.SourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	source := &domain.Source{
+		TreeID:      uri.TreeID,
+		SourceType:  req.SourceType,
+		Title:       req.Title,
+		Author:      req.Author,
+		Description: req.Description,
+		CreatedBy:   middleware.GetUserID(c),
+	}
+	if err := h.sourceUseCase.CreateSource(c.Request.Context(), source); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, toSourceResponse(source))
+}
+
+func (h *sourceHandler) Get(c *gin.Context) {
+	var uri dto.SourceIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	source, err := h.sourceUseCase.GetSource(c.Request.Context(), uri.TreeID, uri.SourceID)
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, toSourceResponse(source))
+}
+
+func (h *sourceHandler) List(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	sources, err := h.sourceUseCase.ListSources(c.Request.Context(), uri.TreeID)
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	response := dto.SourceListResponse{Sources: make([]dto.SourceResponse, 0, len(sources))}
+	for _, source := range sources {
+		response.Sources = append(response.Sources, toSourceResponse(source))
+	}
+	delivery.SuccessWithData(c, response)
+}
+
+func (h *sourceHandler) Update(c *gin.Context) {
+	var uri dto.SourceIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var req dto.SourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	source := &domain.Source{
+		SourceID:    uri.SourceID,
+		TreeID:      uri.TreeID,
+		SourceType:  req.SourceType,
+		Title:       req.Title,
+		Author:      req.Author,
+		Description: req.Description,
+	}
+	if err := h.sourceUseCase.UpdateSource(c.Request.Context(), source); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.source.updated", nil)
+}
+
+func (h *sourceHandler) Delete(c *gin.Context) {
+	var uri dto.SourceIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	if err := h.sourceUseCase.DeleteSource(c.Request.Context(), uri.TreeID, uri.SourceID); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.source.deleted", nil)
+}
+
+func (h *sourceHandler) UploadAttachment(c *gin.Context) {
+	var uri dto.SourceIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		delivery.Error(c, domain.NewValidationError("error.validation.missing_attachment_file"))
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		slog.Error("sourceHandler.UploadAttachment: read file", "error", err, "source_id", uri.SourceID)
+		delivery.Error(c, domain.NewInternalError(err))
+		return
+	}
+
+	if _, err := h.sourceUseCase.UploadAttachment(c.Request.Context(), uri.TreeID, uri.SourceID, data, header.Filename); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.source.attachment_uploaded", nil)
+}
+
+func (h *sourceHandler) GetAttachment(c *gin.Context) {
+	var uri dto.SourceIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	data, contentType, err := h.sourceUseCase.GetAttachment(c.Request.Context(), uri.TreeID, uri.SourceID)
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, data)
+}
+
+func (h *sourceHandler) DeleteAttachment(c *gin.Context) {
+	var uri dto.SourceIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	if err := h.sourceUseCase.DeleteAttachment(c.Request.Context(), uri.TreeID, uri.SourceID); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.source.attachment_deleted", nil)
+}
+
+func (h *sourceHandler) ListSourceCitations(c *gin.Context) {
+	var uri dto.SourceIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	citations, err := h.sourceUseCase.ListCitationsBySource(c.Request.Context(), uri.TreeID, uri.SourceID)
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, toCitationListResponse(citations))
+}
+
+func (h *sourceHandler) ListCitations(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var query dto.CitationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	citations, err := h.sourceUseCase.ListCitationsBySubject(c.Request.Context(), uri.TreeID, query.SubjectType, query.SubjectID)
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, toCitationListResponse(citations))
+}
+
+func (h *sourceHandler) CreateCitation(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var req dto.CitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	citation := &domain.Citation{
+		SourceID:    req.SourceID,
+		SubjectType: req.SubjectType,
+		SubjectID:   req.SubjectID,
+		FieldName:   req.FieldName,
+		Confidence:  req.Confidence,
+		Detail:      req.Detail,
+		CreatedBy:   middleware.GetUserID(c),
+	}
+	if err := h.sourceUseCase.CreateCitation(c.Request.Context(), uri.TreeID, citation); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, toCitationResponse(citation))
+}
+
+func (h *sourceHandler) UpdateCitation(c *gin.Context) {
+	var uri dto.CitationIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var req dto.CitationUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	citation := &domain.Citation{
+		CitationID: uri.CitationID,
+		Confidence: req.Confidence,
+		Detail:     req.Detail,
+	}
+	if err := h.sourceUseCase.UpdateCitation(c.Request.Context(), uri.TreeID, citation); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.citation.updated", nil)
+}
+
+func (h *sourceHandler) DeleteCitation(c *gin.Context) {
+	var uri dto.CitationIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	if err := h.sourceUseCase.DeleteCitation(c.Request.Context(), uri.TreeID, uri.CitationID); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.citation.deleted", nil)
+}
+
+func (h *sourceHandler) ListUnsourcedFacts(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	if !h.requireTreeAccess(c, uri.TreeID) {
+		return
+	}
+
+	facts, err := h.sourceUseCase.ListUnsourcedFacts(c.Request.Context(), uri.TreeID)
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	response := dto.UnsourcedFactListResponse{Facts: make([]dto.UnsourcedFactResponse, 0, len(facts))}
+	for _, fact := range facts {
+		response.Facts = append(response.Facts, dto.UnsourcedFactResponse{
+			SubjectType: fact.SubjectType,
+			SubjectID:   fact.SubjectID,
+			FieldName:   fact.FieldName,
+			MemberIDs:   fact.MemberIDs,
+		})
+	}
+	delivery.SuccessWithData(c, response)
+}
+
+func toSourceResponse(source *domain.Source) dto.SourceResponse {
+	return dto.SourceResponse{
+		SourceID:      source.SourceID,
+		TreeID:        source.TreeID,
+		SourceType:    source.SourceType,
+		Title:         source.Title,
+		Author:        source.Author,
+		Description:   source.Description,
+		HasAttachment: source.AttachmentKey != nil && *source.AttachmentKey != "",
+		CreatedBy:     source.CreatedBy,
+		CreatedAt:     source.CreatedAt,
+		UpdatedAt:     source.UpdatedAt,
+	}
+}
+
+func toCitationListResponse(citations []*domain.Citation) dto.CitationListResponse {
+	response := dto.CitationListResponse{Citations: make([]dto.CitationResponse, 0, len(citations))}
+	for _, citation := range citations {
+		response.Citations = append(response.Citations, toCitationResponse(citation))
+	}
+	return response
+}
+
+func toCitationResponse(citation *domain.Citation) dto.CitationResponse {
+	return dto.CitationResponse{
+		CitationID:  citation.CitationID,
+		SourceID:    citation.SourceID,
+		SubjectType: citation.SubjectType,
+		SubjectID:   citation.SubjectID,
+		FieldName:   citation.FieldName,
+		Confidence:  citation.Confidence,
+		Detail:      citation.Detail,
+		CreatedBy:   citation.CreatedBy,
+		CreatedAt:   citation.CreatedAt,
+		UpdatedAt:   citation.UpdatedAt,
+	}
+}
@@ -78,10 +78,17 @@ func (h *spouseHandler) Create(c *gin.Context) {
 	}
 
 	spouse := &domain.Spouse{
-		FatherID:     req.FatherID,
-		MotherID:     req.MotherID,
-		MarriageDate: req.MarriageDate.ToTimePtr(),
-		DivorceDate:  req.DivorceDate.ToTimePtr(),
+		FatherID:              req.FatherID,
+		MotherID:              req.MotherID,
+		MarriageDate:          resolveDate(req.MarriageDateCalendar, req.MarriageDate, req.MarriageDateHijri),
+		MarriageDateQualifier: req.MarriageDateQualifier,
+		MarriageDateEnd:       resolveDate(req.MarriageDateCalendar, req.MarriageDateEnd, req.MarriageDateEndHijri),
+		MarriageDateCalendar:  req.MarriageDateCalendar,
+		DivorceDate:           resolveDate(req.DivorceDateCalendar, req.DivorceDate, req.DivorceDateHijri),
+		DivorceDateQualifier:  req.DivorceDateQualifier,
+		DivorceDateEnd:        resolveDate(req.DivorceDateCalendar, req.DivorceDateEnd, req.DivorceDateEndHijri),
+		DivorceDateCalendar:   req.DivorceDateCalendar,
+		MarriagePlaceID:       req.MarriagePlaceID,
 	}
 
 	if !h.requireMemberPairInTree(c, treeID, spouse.FatherID, spouse.MotherID) {
@@ -119,9 +126,16 @@ func (h *spouseHandler) Update(c *gin.Context) {
 	}
 
 	spouse := &domain.Spouse{
-		SpouseID:     uri.SpouseID,
-		MarriageDate: req.MarriageDate.ToTimePtr(),
-		DivorceDate:  req.DivorceDate.ToTimePtr(),
+		SpouseID:              uri.SpouseID,
+		MarriageDate:          resolveDate(req.MarriageDateCalendar, req.MarriageDate, req.MarriageDateHijri),
+		MarriageDateQualifier: req.MarriageDateQualifier,
+		MarriageDateEnd:       resolveDate(req.MarriageDateCalendar, req.MarriageDateEnd, req.MarriageDateEndHijri),
+		MarriageDateCalendar:  req.MarriageDateCalendar,
+		DivorceDate:           resolveDate(req.DivorceDateCalendar, req.DivorceDate, req.DivorceDateHijri),
+		DivorceDateQualifier:  req.DivorceDateQualifier,
+		DivorceDateEnd:        resolveDate(req.DivorceDateCalendar, req.DivorceDateEnd, req.DivorceDateEndHijri),
+		DivorceDateCalendar:   req.DivorceDateCalendar,
+		MarriagePlaceID:       req.MarriagePlaceID,
 	}
 
 	userID := middleware.GetUserID(c)
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"github.com/escalopa/family-tree/internal/delivery"
+	"github.com/escalopa/family-tree/internal/delivery/http/dto"
+	"github.com/escalopa/family-tree/internal/delivery/http/middleware"
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/escalopa/family-tree/internal/pkg/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+type timelineHandler struct {
+	timelineUseCase   TimelineUseCase
+	familyTreeUseCase FamilyTreeUseCase
+}
+
+func NewTimelineHandler(timelineUseCase TimelineUseCase, familyTreeUseCase FamilyTreeUseCase) *timelineHandler {
+	return &timelineHandler{timelineUseCase: timelineUseCase, familyTreeUseCase: familyTreeUseCase}
+}
+
+func (h *timelineHandler) List(c *gin.Context) {
+	var uri dto.TreeIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var query dto.TimelineQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	viewer := viewerOf(c)
+	if err := h.familyTreeUseCase.EnsureAccess(c.Request.Context(), uri.TreeID, userID); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	filter := domain.TimelineFilter{
+		TreeID:   uri.TreeID,
+		From:     query.From,
+		To:       query.To,
+		MemberID: query.MemberID,
+		Degree:   query.Degree,
+	}
+
+	entries, nextCursor, err := h.timelineUseCase.List(c.Request.Context(), filter, viewer, query.Cursor, query.Limit)
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	preferredLang := middleware.GetPreferredLanguage(c)
+	interfaceLang := middleware.GetInterfaceLanguage(c)
+
+	response := dto.PaginatedTimelineResponse{
+		Entries:    make([]dto.TimelineEntryResponse, 0, len(entries)),
+		NextCursor: nextCursor,
+	}
+	for _, entry := range entries {
+		memberName := extractName(entry.MemberNames, preferredLang)
+		params := map[string]string{"name": memberName}
+
+		item := dto.TimelineEntryResponse{
+			EventType:     entry.EventType,
+			Date:          dto.Date{Time: entry.Date},
+			DateQualifier: entry.DateQualifier,
+			DateEnd:       dto.FromTimePtr(entry.DateEnd),
+			DateCalendar:  entry.DateCalendar,
+			DateHijri:     dto.HijriFromTimePtr(&entry.Date),
+			DateEndHijri:  dto.HijriFromTimePtr(entry.DateEnd),
+			MemberID:      entry.MemberID,
+			MemberName:    memberName,
+			PartnerID:     entry.PartnerID,
+			FamilyUnitID:  entry.FamilyUnitID,
+			EventID:       entry.EventID,
+			CustomType:    entry.CustomType,
+			PlaceID:       entry.PlaceID,
+		}
+		if entry.PartnerID != nil {
+			partnerName := extractName(entry.PartnerNames, preferredLang)
+			item.PartnerName = &partnerName
+			params["partner"] = partnerName
+		}
+		if entry.CustomType != nil {
+			params["type"] = *entry.CustomType
+		}
+		item.Title = i18n.Translate("timeline."+entry.EventType, interfaceLang, params)
+
+		response.Entries = append(response.Entries, item)
+	}
+
+	delivery.SuccessWithData(c, response)
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"github.com/escalopa/family-tree/internal/delivery"
+	"github.com/escalopa/family-tree/internal/delivery/http/dto"
+	"github.com/escalopa/family-tree/internal/delivery/http/middleware"
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *memberHandler) ListNameSpellings(c *gin.Context) {
+	treeID, ok := h.requireTreeAccess(c)
+	if !ok {
+		return
+	}
+
+	spellings, err := h.memberUseCase.ListNameSpellings(c.Request.Context(), treeID)
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	response := make([]dto.NameSpellingResponse, 0, len(spellings))
+	for _, spelling := range spellings {
+		response = append(response, toNameSpellingResponse(spelling))
+	}
+
+	delivery.SuccessWithData(c, response)
+}
+
+func (h *memberHandler) SaveNameSpelling(c *gin.Context) {
+	var req dto.NameSpellingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	treeID, ok := h.requireTreeAccess(c)
+	if !ok {
+		return
+	}
+
+	spelling := &domain.NameSpelling{
+		TreeID:       treeID,
+		Arabic:       req.Arabic,
+		LanguageCode: req.LanguageCode,
+		Spelling:     req.Spelling,
+	}
+	if err := h.memberUseCase.SaveNameSpelling(c.Request.Context(), spelling); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, toNameSpellingResponse(spelling))
+}
+
+func (h *memberHandler) DeleteNameSpelling(c *gin.Context) {
+	var query dto.NameSpellingQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	treeID, ok := h.requireTreeAccess(c)
+	if !ok {
+		return
+	}
+
+	if err := h.memberUseCase.DeleteNameSpelling(c.Request.Context(), treeID, query.Arabic, query.LanguageCode); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.name_spelling.deleted", nil)
+}
+
+func (h *memberHandler) SuggestTreeNames(c *gin.Context) {
+	treeID, ok := h.requireTreeAccess(c)
+	if !ok {
+		return
+	}
+
+	updated, err := h.memberUseCase.SuggestTreeNames(c.Request.Context(), treeID, middleware.GetUserID(c))
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, dto.SuggestNamesResponse{UpdatedMembers: updated})
+}
+
+func (h *memberHandler) ConfirmName(c *gin.Context) {
+	var uri dto.MemberLanguageUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	if _, _, ok := h.requireMemberInTree(c, uri.MemberID); !ok {
+		return
+	}
+
+	if err := h.memberUseCase.ConfirmName(c.Request.Context(), uri.MemberID, uri.Code, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.member.name_confirmed", nil)
+}
+
+func toNameSpellingResponse(spelling *domain.NameSpelling) dto.NameSpellingResponse {
+	return dto.NameSpellingResponse{
+		Arabic:       spelling.Arabic,
+		LanguageCode: spelling.LanguageCode,
+		Spelling:     spelling.Spelling,
+		UpdatedAt:    spelling.UpdatedAt,
+	}
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"github.com/escalopa/family-tree/internal/delivery"
+	"github.com/escalopa/family-tree/internal/delivery/http/dto"
+	"github.com/escalopa/family-tree/internal/delivery/http/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *memberHandler) ListTrash(c *gin.Context) {
+	treeID, ok := h.requireTreeAccess(c)
+	if !ok {
+		return
+	}
+
+	trash, err := h.memberUseCase.ListTrash(c.Request.Context(), treeID)
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	preferredLang := middleware.GetPreferredLanguage(c)
+	response := dto.TrashResponse{
+		Members: make([]dto.TrashedMemberResponse, 0, len(trash.Members)),
+		Spouses: make([]dto.TrashedSpouseResponse, 0, len(trash.Spouses)),
+	}
+	for _, m := range trash.Members {
+		response.Members = append(response.Members, dto.TrashedMemberResponse{
+			MemberID:  m.MemberID,
+			Name:      extractName(m.Names, preferredLang),
+			Names:     m.Names,
+			Gender:    m.Gender,
+			FatherID:  m.FatherID,
+			MotherID:  m.MotherID,
+			DeletedAt: m.DeletedAt,
+		})
+	}
+	for _, s := range trash.Spouses {
+		response.Spouses = append(response.Spouses, dto.TrashedSpouseResponse{
+			SpouseID:     s.SpouseID,
+			FatherID:     s.FatherID,
+			MotherID:     s.MotherID,
+			MarriageDate: dto.FromTimePtr(s.MarriageDate),
+			DivorceDate:  dto.FromTimePtr(s.DivorceDate),
+			DeletedAt:    s.DeletedAt,
+		})
+	}
+
+	delivery.SuccessWithData(c, response)
+}
+
+func (h *memberHandler) RestoreMember(c *gin.Context) {
+	var uri dto.MemberIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	treeID, ok := h.requireTreeAccess(c)
+	if !ok {
+		return
+	}
+
+	if err := h.memberUseCase.RestoreMember(c.Request.Context(), treeID, uri.MemberID, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.trash.member_restored", nil)
+}
+
+func (h *memberHandler) RestoreSpouse(c *gin.Context) {
+	var uri dto.SpouseIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+	treeID, ok := h.requireTreeAccess(c)
+	if !ok {
+		return
+	}
+
+	if err := h.memberUseCase.RestoreSpouse(c.Request.Context(), treeID, uri.SpouseID, middleware.GetUserID(c)); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.Success(c, "success.trash.spouse_restored", nil)
+}
+
+func (h *memberHandler) PurgePictures(c *gin.Context) {
+	treeID, ok := h.requireTreeAccess(c)
+	if !ok {
+		return
+	}
+
+	deleted, err := h.memberUseCase.PurgePictures(c.Request.Context(), treeID)
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	delivery.SuccessWithData(c, dto.PurgePicturesResponse{DeletedPictures: deleted})
+}
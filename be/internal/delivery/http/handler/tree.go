@@ -31,7 +31,7 @@ func (h *treeHandler) GetTree(c *gin.Context) {
 	}
 
 	userID := middleware.GetUserID(c)
-	userRole := middleware.GetUserRole(c)
+	viewer := viewerOf(c)
 	if err := h.familyTreeUseCase.EnsureAccess(c.Request.Context(), uri.TreeID, userID); err != nil {
 		delivery.Error(c, err)
 		return
@@ -39,7 +39,7 @@ func (h *treeHandler) GetTree(c *gin.Context) {
 
 	// Check style
 	if query.Style == "list" {
-		members, err := h.treeUseCase.List(c.Request.Context(), uri.TreeID, query.RootID, userRole)
+		members, err := h.treeUseCase.List(c.Request.Context(), uri.TreeID, query.RootID, query.AsOf, viewer)
 		if err != nil {
 			delivery.Error(c, err)
 			return
@@ -51,13 +51,23 @@ func (h *treeHandler) GetTree(c *gin.Context) {
 		var response []dto.MemberListItem
 		for _, m := range members {
 			response = append(response, dto.MemberListItem{
-				MemberID:    m.MemberID,
-				Name:        extractName(m.Names, preferredLang),
-				Gender:      m.Gender,
-				Picture:     m.Picture,
-				DateOfBirth: dto.FromTimePtr(m.DateOfBirth),
-				DateOfDeath: dto.FromTimePtr(m.DateOfDeath),
-				IsMarried:   m.IsMarried,
+				MemberID:             m.MemberID,
+				Name:                 extractName(m.Names, preferredLang),
+				Gender:               m.Gender,
+				Picture:              m.Picture,
+				DateOfBirth:          dto.FromTimePtr(m.DateOfBirth),
+				DateOfBirthQualifier: m.DateOfBirthQualifier,
+				DateOfBirthEnd:       dto.FromTimePtr(m.DateOfBirthEnd),
+				DateOfBirthCalendar:  m.DateOfBirthCalendar,
+				DateOfBirthHijri:     dto.HijriFromTimePtr(m.DateOfBirth),
+				DateOfBirthEndHijri:  dto.HijriFromTimePtr(m.DateOfBirthEnd),
+				DateOfDeath:          dto.FromTimePtr(m.DateOfDeath),
+				DateOfDeathQualifier: m.DateOfDeathQualifier,
+				DateOfDeathEnd:       dto.FromTimePtr(m.DateOfDeathEnd),
+				DateOfDeathCalendar:  m.DateOfDeathCalendar,
+				DateOfDeathHijri:     dto.HijriFromTimePtr(m.DateOfDeath),
+				DateOfDeathEndHijri:  dto.HijriFromTimePtr(m.DateOfDeathEnd),
+				IsMarried:            m.IsMarried,
 			})
 		}
 
@@ -65,7 +75,7 @@ func (h *treeHandler) GetTree(c *gin.Context) {
 		return
 	}
 
-	tree, err := h.treeUseCase.Get(c.Request.Context(), uri.TreeID, query.RootID, userRole)
+	tree, err := h.treeUseCase.Get(c.Request.Context(), uri.TreeID, query.RootID, query.AsOf, viewer)
 	if err != nil {
 		delivery.Error(c, err)
 		return
@@ -94,13 +104,13 @@ func (h *treeHandler) GetRelation(c *gin.Context) {
 	}
 
 	userID := middleware.GetUserID(c)
-	userRole := middleware.GetUserRole(c)
+	viewer := viewerOf(c)
 	if err := h.familyTreeUseCase.EnsureAccess(c.Request.Context(), uri.TreeID, userID); err != nil {
 		delivery.Error(c, err)
 		return
 	}
 
-	tree, err := h.treeUseCase.GetRelation(c.Request.Context(), uri.TreeID, query.Member1ID, query.Member2ID, userRole)
+	tree, err := h.treeUseCase.GetRelation(c.Request.Context(), uri.TreeID, query.Member1ID, query.Member2ID, query.AsOf, viewer)
 	if err != nil {
 		delivery.Error(c, err)
 		return
@@ -122,14 +132,20 @@ func (h *treeHandler) GetGraph(c *gin.Context) {
 		return
 	}
 
+	var query dto.AsOfQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
 	userID := middleware.GetUserID(c)
-	userRole := middleware.GetUserRole(c)
+	viewer := viewerOf(c)
 	if err := h.familyTreeUseCase.EnsureAccess(c.Request.Context(), uri.TreeID, userID); err != nil {
 		delivery.Error(c, err)
 		return
 	}
 
-	graph, err := h.treeUseCase.GetGraph(c.Request.Context(), uri.TreeID, userRole)
+	graph, err := h.treeUseCase.GetGraph(c.Request.Context(), uri.TreeID, query.AsOf, viewer)
 	if err != nil {
 		delivery.Error(c, err)
 		return
@@ -153,13 +169,13 @@ func (h *treeHandler) GetRelationGraph(c *gin.Context) {
 	}
 
 	userID := middleware.GetUserID(c)
-	userRole := middleware.GetUserRole(c)
+	viewer := viewerOf(c)
 	if err := h.familyTreeUseCase.EnsureAccess(c.Request.Context(), uri.TreeID, userID); err != nil {
 		delivery.Error(c, err)
 		return
 	}
 
-	graph, err := h.treeUseCase.GetRelationGraph(c.Request.Context(), uri.TreeID, query.Member1ID, query.Member2ID, userRole)
+	graph, err := h.treeUseCase.GetRelationGraph(c.Request.Context(), uri.TreeID, query.Member1ID, query.Member2ID, query.AsOf, viewer)
 	if err != nil {
 		delivery.Error(c, err)
 		return
@@ -177,38 +193,71 @@ func (h *treeHandler) convertToTreeResponse(node *domain.MemberTreeNode, preferr
 	spousesDTO := make([]dto.SpouseInfo, len(node.Spouses))
 	for i, spouse := range node.Spouses {
 		spousesDTO[i] = dto.SpouseInfo{
-			SpouseID:     spouse.SpouseID,
-			MemberID:     spouse.MemberID,
-			Name:         extractName(spouse.Names, preferredLang),
-			Names:        spouse.Names,
-			Gender:       spouse.Gender,
-			Picture:      spouse.Picture,
-			MarriageDate: dto.FromTimePtr(spouse.MarriageDate),
-			DivorceDate:  dto.FromTimePtr(spouse.DivorceDate),
-			MarriedYears: dto.CalculateMarriedYears(spouse.MarriageDate, spouse.DivorceDate),
+			SpouseID:              spouse.SpouseID,
+			MemberID:              spouse.MemberID,
+			Name:                  extractName(spouse.Names, preferredLang),
+			Names:                 spouse.Names,
+			Gender:                spouse.Gender,
+			Picture:               spouse.Picture,
+			MarriageDate:          dto.FromTimePtr(spouse.MarriageDate),
+			MarriageDateQualifier: spouse.MarriageDateQualifier,
+			MarriageDateEnd:       dto.FromTimePtr(spouse.MarriageDateEnd),
+			MarriageDateCalendar:  spouse.MarriageDateCalendar,
+			MarriageDateHijri:     dto.HijriFromTimePtr(spouse.MarriageDate),
+			MarriageDateEndHijri:  dto.HijriFromTimePtr(spouse.MarriageDateEnd),
+			DivorceDate:           dto.FromTimePtr(spouse.DivorceDate),
+			DivorceDateQualifier:  spouse.DivorceDateQualifier,
+			DivorceDateEnd:        dto.FromTimePtr(spouse.DivorceDateEnd),
+			DivorceDateCalendar:   spouse.DivorceDateCalendar,
+			DivorceDateHijri:      dto.HijriFromTimePtr(spouse.DivorceDate),
+			DivorceDateEndHijri:   dto.HijriFromTimePtr(spouse.DivorceDateEnd),
+			MarriagePlaceID:       spouse.MarriagePlaceID,
+			MarriedYears:          dto.CalculateMarriedYears(spouse.MarriageDate, spouse.DivorceDate),
+			IsRedacted:            spouse.IsRedacted,
 		}
 	}
 
 	response := &dto.TreeNodeResponse{
 		Member: dto.MemberResponse{
-			MemberID:        node.MemberID,
-			Name:            extractName(node.Names, preferredLang),
-			Names:           node.Names,
-			FullName:        extractName(node.FullNames, preferredLang),
-			FullNames:       node.FullNames,
-			Gender:          node.Gender,
-			Picture:         node.Picture,
-			DateOfBirth:     dto.FromTimePtr(node.DateOfBirth),
-			DateOfDeath:     dto.FromTimePtr(node.DateOfDeath),
-			FatherID:        node.FatherID,
-			MotherID:        node.MotherID,
-			Nicknames:       node.Nicknames,
-			Profession:      node.Profession,
-			Version:         node.Version,
-			Age:             node.Age,
-			GenerationLevel: node.GenerationLevel,
-			IsMarried:       node.IsMarried,
-			Spouses:         spousesDTO,
+			MemberID:             node.MemberID,
+			Name:                 extractName(node.Names, preferredLang),
+			Names:                node.Names,
+			NameParts:            toNamePartsResponse(node.NameParts),
+			SuggestedNames:       node.SuggestedNames,
+			FullName:             extractName(node.FullNames, preferredLang),
+			FullNames:            node.FullNames,
+			Gender:               node.Gender,
+			Picture:              node.Picture,
+			DateOfBirth:          dto.FromTimePtr(node.DateOfBirth),
+			DateOfBirthQualifier: node.DateOfBirthQualifier,
+			DateOfBirthEnd:       dto.FromTimePtr(node.DateOfBirthEnd),
+			DateOfBirthCalendar:  node.DateOfBirthCalendar,
+			DateOfBirthHijri:     dto.HijriFromTimePtr(node.DateOfBirth),
+			DateOfBirthEndHijri:  dto.HijriFromTimePtr(node.DateOfBirthEnd),
+			DateOfDeath:          dto.FromTimePtr(node.DateOfDeath),
+			DateOfDeathQualifier: node.DateOfDeathQualifier,
+			DateOfDeathEnd:       dto.FromTimePtr(node.DateOfDeathEnd),
+			DateOfDeathCalendar:  node.DateOfDeathCalendar,
+			DateOfDeathHijri:     dto.HijriFromTimePtr(node.DateOfDeath),
+			DateOfDeathEndHijri:  dto.HijriFromTimePtr(node.DateOfDeathEnd),
+			BirthPlaceID:         node.BirthPlaceID,
+			DeathPlaceID:         node.DeathPlaceID,
+			BurialPlaceID:        node.BurialPlaceID,
+			FatherID:             node.FatherID,
+			MotherID:             node.MotherID,
+			Nicknames:            node.Nicknames,
+			Profession:           node.Profession,
+			CustomFields:         node.CustomFields,
+			LivingStatus:         node.LivingStatus,
+			IsLiving:             node.IsLiving,
+			IsRedacted:           node.IsRedacted,
+			Version:              node.Version,
+			Age:                  node.Age,
+			AgeMin:               node.AgeMin,
+			AgeMax:               node.AgeMax,
+			GenerationLevel:      node.GenerationLevel,
+			IsMarried:            node.IsMarried,
+			Spouses:              spousesDTO,
 		},
 		IsInPath: node.IsInPath,
 	}
@@ -237,24 +286,43 @@ func (h *treeHandler) convertToGraphResponse(graph *domain.FamilyGraph, preferre
 	for _, person := range graph.People {
 		response.People = append(response.People, dto.FamilyGraphPersonResponse{
 			Member: dto.MemberResponse{
-				MemberID:        person.MemberID,
-				TreeID:          person.TreeID,
-				Name:            extractName(person.Names, preferredLang),
-				Names:           person.Names,
-				FullName:        extractName(person.FullNames, preferredLang),
-				FullNames:       person.FullNames,
-				Gender:          person.Gender,
-				Picture:         person.Picture,
-				DateOfBirth:     dto.FromTimePtr(person.DateOfBirth),
-				DateOfDeath:     dto.FromTimePtr(person.DateOfDeath),
-				FatherID:        person.FatherID,
-				MotherID:        person.MotherID,
-				Nicknames:       person.Nicknames,
-				Profession:      person.Profession,
-				Version:         person.Version,
-				Age:             person.Age,
-				GenerationLevel: person.GenerationLevel,
-				IsMarried:       person.IsMarried,
+				MemberID:             person.MemberID,
+				TreeID:               person.TreeID,
+				Name:                 extractName(person.Names, preferredLang),
+				Names:                person.Names,
+				NameParts:            toNamePartsResponse(person.NameParts),
+				SuggestedNames:       person.SuggestedNames,
+				FullName:             extractName(person.FullNames, preferredLang),
+				FullNames:            person.FullNames,
+				Gender:               person.Gender,
+				Picture:              person.Picture,
+				DateOfBirth:          dto.FromTimePtr(person.DateOfBirth),
+				DateOfBirthQualifier: person.DateOfBirthQualifier,
+				DateOfBirthEnd:       dto.FromTimePtr(person.DateOfBirthEnd),
+				DateOfBirthCalendar:  person.DateOfBirthCalendar,
+				DateOfBirthHijri:     dto.HijriFromTimePtr(person.DateOfBirth),
+				DateOfBirthEndHijri:  dto.HijriFromTimePtr(person.DateOfBirthEnd),
+				DateOfDeath:          dto.FromTimePtr(person.DateOfDeath),
+				DateOfDeathQualifier: person.DateOfDeathQualifier,
+				DateOfDeathEnd:       dto.FromTimePtr(person.DateOfDeathEnd),
+				DateOfDeathCalendar:  person.DateOfDeathCalendar,
+				DateOfDeathHijri:     dto.HijriFromTimePtr(person.DateOfDeath),
+				DateOfDeathEndHijri:  dto.HijriFromTimePtr(person.DateOfDeathEnd),
+				BirthPlaceID:         person.BirthPlaceID,
+				DeathPlaceID:         person.DeathPlaceID,
+				BurialPlaceID:        person.BurialPlaceID,
+				FatherID:             person.FatherID,
+				MotherID:             person.MotherID,
+				Nicknames:            person.Nicknames,
+				Profession:           person.Profession,
+				CustomFields:         person.CustomFields,
+				LivingStatus:         person.LivingStatus,
+				Version:              person.Version,
+				Age:                  person.Age,
+				AgeMin:               person.AgeMin,
+				AgeMax:               person.AgeMax,
+				GenerationLevel:      person.GenerationLevel,
+				IsMarried:            person.IsMarried,
 			},
 			ParentFamilyUnitIDs:  person.ParentFamilyUnitIDs,
 			PartnerFamilyUnitIDs: person.PartnerFamilyUnitIDs,
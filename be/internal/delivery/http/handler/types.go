@@ -10,7 +10,7 @@ import (
 type LanguageUseCase interface {
 	Get(ctx context.Context, code string) (*domain.Language, error)
 	List(ctx context.Context, activeOnly bool) ([]*domain.Language, error)
-	ToggleActive(ctx context.Context, code string, isActive bool) error
+	ToggleActive(ctx context.Context, code string, isActive bool, userID int) error
 	UpdatePreference(ctx context.Context, pref *domain.UserLanguagePreference) error
 	UpdateDisplayOrder(ctx context.Context, orders map[string]int) error
 }
@@ -28,7 +28,7 @@ type AuthUseCase interface {
 	GetURL(ctx context.Context, provider string) (string, error)
 	HandleCallback(ctx context.Context, provider, code, state string) (*domain.User, *domain.AuthTokens, error)
 	RefreshTokens(ctx context.Context, refreshToken string) (*domain.AuthTokens, error)
-	Logout(ctx context.Context, sessionID string) error
+	Logout(ctx context.Context, sessionID string, userID int) error
 	LogoutAll(ctx context.Context, userID int) error
 	ValidateSession(ctx context.Context, sessionID string) (*domain.Session, error)
 	ListProviders(ctx context.Context) []string
@@ -42,22 +42,46 @@ type UserUseCase interface {
 	ListLeaderboard(ctx context.Context, limit int) ([]*domain.UserScore, error)
 	ListScoreHistory(ctx context.Context, userID int, cursor *string, limit int) ([]*domain.ScoreHistory, *string, error)
 	ListChanges(ctx context.Context, userID int, cursor *string, limit int) ([]*domain.HistoryWithUser, *string, error)
+	ListRoleHistory(ctx context.Context, userID int, cursor *string, limit int) ([]*domain.RoleHistory, *string, error)
+}
+
+type AuditUseCase interface {
+	List(ctx context.Context, filter domain.AuditFilter, viewer domain.Viewer, cursor *string, limit int) ([]*domain.AuditEvent, *string, error)
 }
 
 type MemberUseCase interface {
 	Create(ctx context.Context, member *domain.Member, userID int) error
-	Update(ctx context.Context, member *domain.Member, expectedVersion, userID int) error
+	Edit(ctx context.Context, member *domain.Member, expectedVersion int, merge bool, viewer domain.Viewer) error
 	Delete(ctx context.Context, memberID, userID int) error
 	Get(ctx context.Context, memberID int) (*domain.Member, error)
-	ListChildren(ctx context.Context, parentID int) ([]*domain.Member, error)
-	ListSiblings(ctx context.Context, memberID int) ([]*domain.Member, error)
-	List(ctx context.Context, filter domain.MemberFilter, cursor *string, limit int) ([]*domain.Member, *string, error)
-	ListHistory(ctx context.Context, memberID int, cursor *string, limit int) ([]*domain.HistoryWithUser, *string, error)
-	Rollback(ctx context.Context, memberID, historyID, userID int) error
+	ListParents(ctx context.Context, member *domain.Member, viewer domain.Viewer) ([]*domain.Member, error)
+	ListChildren(ctx context.Context, parentID int, viewer domain.Viewer) ([]*domain.Member, error)
+	ListSiblings(ctx context.Context, memberID int, viewer domain.Viewer) ([]*domain.Member, error)
+	CitationCounts(ctx context.Context, memberID int) (map[string]int, error)
+	GetBiographies(ctx context.Context, memberID int) (map[string]string, error)
+	UpdateBiography(ctx context.Context, memberID int, languageCode, biography string, expectedVersion, userID int) error
+	GetNotes(ctx context.Context, memberID int) (*string, error)
+	UpdateNotes(ctx context.Context, memberID int, notes string, expectedVersion, userID int) error
+	List(ctx context.Context, filter domain.MemberFilter, viewer domain.Viewer, cursor *string, limit int) ([]*domain.Member, *string, error)
+	ListHistory(ctx context.Context, member *domain.Member, viewer domain.Viewer, cursor *string, limit int) ([]*domain.HistoryWithUser, *string, error)
+	HistoryDiff(ctx context.Context, treeID, memberID, fromID, toID int, viewer domain.Viewer) (*domain.MemberDiff, error)
+	Rollback(ctx context.Context, treeID, memberID, historyID, userID int) error
 	UploadPicture(ctx context.Context, memberID int, data []byte, filename string, userID int) (string, error)
 	DeletePicture(ctx context.Context, memberID int, userID int) error
-	GetPicture(ctx context.Context, memberID int) ([]byte, string, error)
-	Compute(ctx context.Context, member *domain.Member, userRole int) *domain.MemberWithComputed
+	GetPicture(ctx context.Context, memberID int, viewer domain.Viewer) ([]byte, string, error)
+	Compute(ctx context.Context, member *domain.Member, viewer domain.Viewer) (*domain.MemberWithComputed, error)
+	GetAsOf(ctx context.Context, treeID, memberID int, asOf time.Time, viewer domain.Viewer) (*domain.MemberAsOf, error)
+	ConfirmName(ctx context.Context, memberID int, languageCode string, userID int) error
+	SuggestTreeNames(ctx context.Context, treeID, userID int) (int, error)
+	ListNameSpellings(ctx context.Context, treeID int) ([]*domain.NameSpelling, error)
+	SaveNameSpelling(ctx context.Context, spelling *domain.NameSpelling) error
+	DeleteNameSpelling(ctx context.Context, treeID int, arabic, languageCode string) error
+	ListTrash(ctx context.Context, treeID int) (*domain.Trash, error)
+	RestoreMember(ctx context.Context, treeID, memberID, userID int) error
+	RestoreSpouse(ctx context.Context, treeID, spouseID, userID int) error
+	PurgePictures(ctx context.Context, treeID int) (int, error)
+	RestoreAsOf(ctx context.Context, treeID int, rootID *int, asOf time.Time, userID int) (*domain.AsOfRestore, error)
+	RevertChangeset(ctx context.Context, treeID int, changesetID string, userID int) (*domain.Changeset, *domain.AsOfRestore, error)
 }
 
 type FamilyTreeUseCase interface {
@@ -65,13 +89,23 @@ type FamilyTreeUseCase interface {
 	List(ctx context.Context, userID int) ([]*domain.FamilyTree, error)
 	Get(ctx context.Context, treeID, userID int) (*domain.FamilyTree, error)
 	EnsureAccess(ctx context.Context, treeID, userID int) error
-	Invite(ctx context.Context, treeID, inviterUserID int, inviteeEmail string, message *string, expiresAt *time.Time) (*domain.FamilyTreeInvitation, error)
+	EnsurePermission(ctx context.Context, treeID, userID int, permission string) error
+	UpdateNameSettings(ctx context.Context, treeID, userID int, settings *domain.NameSettings) error
+	GetPrivacyPolicy(ctx context.Context, treeID, userID int) (*domain.PrivacyPolicy, error)
+	UpdatePrivacyPolicy(ctx context.Context, treeID, userID int, policy *domain.PrivacyPolicy) error
+	ListMemberships(ctx context.Context, treeID, userID int) ([]*domain.FamilyTreeMembership, error)
+	UpdateMembershipRole(ctx context.Context, treeID, userID, memberUserID int, role string) error
+	RemoveMembership(ctx context.Context, treeID, userID, memberUserID int) error
+	Leave(ctx context.Context, treeID, userID int) error
+	TransferOwnership(ctx context.Context, treeID, userID, newOwnerUserID int) error
+	Invite(ctx context.Context, treeID, inviterUserID int, inviteeEmail, role string, message *string, expiresAt *time.Time) (*domain.FamilyTreeInvitation, error)
 	ListTreeInvitations(ctx context.Context, treeID, userID int) ([]*domain.FamilyTreeInvitation, error)
 	ListMyInvitations(ctx context.Context, userID int) ([]*domain.FamilyTreeInvitation, error)
 	AcceptInvitation(ctx context.Context, invitationID, userID int) error
 	DeclineInvitation(ctx context.Context, invitationID, userID int) error
-	CreateShareLink(ctx context.Context, treeID, userID int, expiresAt *time.Time, maxVisits *int) (*domain.FamilyTreeShareLink, error)
+	CreateShareLink(ctx context.Context, treeID, userID int, expiresAt *time.Time, maxVisits *int, livingPolicy string) (*domain.FamilyTreeShareLink, error)
 	ListShareLinks(ctx context.Context, treeID, userID int) ([]*domain.FamilyTreeShareLink, error)
+	UpdateShareLinkPolicy(ctx context.Context, treeID, shareID, userID int, livingPolicy string) error
 	RevokeShareLink(ctx context.Context, treeID, shareID, userID int) error
 	ConsumeShareLink(ctx context.Context, token string) (*domain.FamilyTreeShareLink, error)
 }
@@ -83,10 +117,105 @@ type SpouseUseCase interface {
 	Delete(ctx context.Context, spouseID, userID int) error
 }
 
+type FamilyUnitUseCase interface {
+	Get(ctx context.Context, treeID, familyUnitID int) (*domain.FamilyUnit, error)
+	Create(ctx context.Context, unit *domain.FamilyUnit, userID int) error
+	Update(ctx context.Context, unit *domain.FamilyUnit, userID int) error
+	Delete(ctx context.Context, treeID, familyUnitID, userID int) error
+	AddChild(ctx context.Context, treeID, familyUnitID, childID int, relationType string, userID int) error
+	RemoveChild(ctx context.Context, treeID, familyUnitID, childID, userID int) error
+}
+
+type ProposalUseCase interface {
+	Submit(ctx context.Context, proposal *domain.Proposal) error
+	Revise(ctx context.Context, revised *domain.Proposal, userID int) error
+	List(ctx context.Context, filter domain.ProposalFilter, viewer domain.Viewer, cursor *string, limit int) ([]*domain.ProposalReview, *string, error)
+	Get(ctx context.Context, treeID, proposalID int, viewer domain.Viewer) (*domain.ProposalReview, error)
+	Approve(ctx context.Context, treeID, proposalID, reviewerID int, comment *string) (*domain.Proposal, error)
+	Reject(ctx context.Context, treeID, proposalID, reviewerID int, reason string) (*domain.Proposal, error)
+	RequestChanges(ctx context.Context, treeID, proposalID, reviewerID int, comment string) (*domain.Proposal, error)
+}
+
 type TreeUseCase interface {
-	Get(ctx context.Context, treeID int, rootID *int, userRole int) (*domain.MemberTreeNode, error)
-	List(ctx context.Context, treeID int, rootID *int, userRole int) ([]*domain.MemberWithComputed, error)
-	GetRelation(ctx context.Context, treeID, member1ID, member2ID int, userRole int) (*domain.MemberTreeNode, error)
-	GetGraph(ctx context.Context, treeID int, userRole int) (*domain.FamilyGraph, error)
-	GetRelationGraph(ctx context.Context, treeID, member1ID, member2ID int, userRole int) (*domain.FamilyGraph, error)
+	Get(ctx context.Context, treeID int, rootID *int, asOf *time.Time, viewer domain.Viewer) (*domain.MemberTreeNode, error)
+	GetPublic(ctx context.Context, treeID int, livingPolicy string) (*domain.MemberTreeNode, error)
+	List(ctx context.Context, treeID int, rootID *int, asOf *time.Time, viewer domain.Viewer) ([]*domain.MemberWithComputed, error)
+	GetRelation(ctx context.Context, treeID, member1ID, member2ID int, asOf *time.Time, viewer domain.Viewer) (*domain.MemberTreeNode, error)
+	GetGraph(ctx context.Context, treeID int, asOf *time.Time, viewer domain.Viewer) (*domain.FamilyGraph, error)
+	GetRelationGraph(ctx context.Context, treeID, member1ID, member2ID int, asOf *time.Time, viewer domain.Viewer) (*domain.FamilyGraph, error)
+}
+
+type TimelineUseCase interface {
+	List(ctx context.Context, filter domain.TimelineFilter, viewer domain.Viewer, cursor *string, limit int) ([]*domain.TimelineEntry, *string, error)
+}
+
+type ChangesetUseCase interface {
+	Create(ctx context.Context, changeset *domain.Changeset) error
+	List(ctx context.Context, treeID int, cursor *string, limit int) ([]*domain.Changeset, *string, error)
+}
+
+type ActivityUseCase interface {
+	List(ctx context.Context, filter domain.ActivityFilter, viewer domain.Viewer, cursor *string, limit int) ([]*domain.HistoryWithUser, *string, error)
+}
+
+type CustomFieldUseCase interface {
+	Create(ctx context.Context, field *domain.CustomField) error
+	Get(ctx context.Context, treeID, fieldID int) (*domain.CustomField, error)
+	List(ctx context.Context, treeID int) ([]*domain.CustomField, error)
+	Update(ctx context.Context, field *domain.CustomField) error
+	Delete(ctx context.Context, treeID, fieldID int) error
+}
+
+type PlaceUseCase interface {
+	Create(ctx context.Context, place *domain.Place) error
+	Get(ctx context.Context, treeID, placeID int) (*domain.Place, error)
+	List(ctx context.Context, treeID int) ([]*domain.Place, error)
+	Update(ctx context.Context, place *domain.Place) error
+	Delete(ctx context.Context, treeID, placeID int) error
+	GetMap(ctx context.Context, filter domain.MapFilter, viewer domain.Viewer) ([]*domain.MapFeature, error)
+}
+
+type EventUseCase interface {
+	Create(ctx context.Context, event *domain.MemberEvent, userID int) error
+	Get(ctx context.Context, memberID, eventID int, viewer domain.Viewer) (*domain.MemberEvent, error)
+	List(ctx context.Context, memberID int, viewer domain.Viewer) ([]*domain.MemberEvent, error)
+	Update(ctx context.Context, event *domain.MemberEvent, userID int) error
+	Delete(ctx context.Context, memberID, eventID, userID int) error
+}
+
+type MediaUseCase interface {
+	Upload(ctx context.Context, media *domain.Media, data []byte, filename string) error
+	Get(ctx context.Context, treeID, mediaID int, viewer domain.Viewer) (*domain.Media, error)
+	GetFile(ctx context.Context, treeID, mediaID int, viewer domain.Viewer) ([]byte, string, error)
+	List(ctx context.Context, treeID int, viewer domain.Viewer) ([]*domain.Media, error)
+	ListByMember(ctx context.Context, memberID int, viewer domain.Viewer) ([]*domain.Media, error)
+	Update(ctx context.Context, media *domain.Media, userID int) error
+	Delete(ctx context.Context, treeID, mediaID, userID int) error
+	Tag(ctx context.Context, treeID, mediaID int, tag domain.MediaTag, userID int) error
+	Untag(ctx context.Context, treeID, mediaID, memberID, userID int) error
+	SetProfilePicture(ctx context.Context, treeID, memberID, mediaID, userID int) error
+}
+
+type SourceUseCase interface {
+	CreateSource(ctx context.Context, source *domain.Source) error
+	GetSource(ctx context.Context, treeID, sourceID int) (*domain.Source, error)
+	ListSources(ctx context.Context, treeID int) ([]*domain.Source, error)
+	UpdateSource(ctx context.Context, source *domain.Source) error
+	DeleteSource(ctx context.Context, treeID, sourceID int) error
+	UploadAttachment(ctx context.Context, treeID, sourceID int, data []byte, filename string) (string, error)
+	GetAttachment(ctx context.Context, treeID, sourceID int) ([]byte, string, error)
+	DeleteAttachment(ctx context.Context, treeID, sourceID int) error
+	CreateCitation(ctx context.Context, treeID int, citation *domain.Citation) error
+	UpdateCitation(ctx context.Context, treeID int, citation *domain.Citation) error
+	DeleteCitation(ctx context.Context, treeID, citationID int) error
+	ListCitationsBySource(ctx context.Context, treeID, sourceID int) ([]*domain.Citation, error)
+	ListCitationsBySubject(ctx context.Context, treeID int, subjectType string, subjectID int) ([]*domain.Citation, error)
+	ListUnsourcedFacts(ctx context.Context, treeID int) ([]*domain.UnsourcedFact, error)
+}
+
+type CalendarUseCase interface {
+	CreateFeed(ctx context.Context, treeID, userID int) (*domain.FamilyTreeCalendarFeed, error)
+	ListFeeds(ctx context.Context, treeID, userID int) ([]*domain.FamilyTreeCalendarFeed, error)
+	RevokeFeed(ctx context.Context, treeID, feedID, userID int) error
+	GetFeedContent(ctx context.Context, token string) (*domain.CalendarFeedContent, error)
 }
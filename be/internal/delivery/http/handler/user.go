@@ -270,6 +270,7 @@ func (h *userHandler) ListChanges(c *gin.Context) {
 			OldValues:     h.OldValues,
 			NewValues:     h.NewValues,
 			MemberVersion: h.MemberVersion,
+			ChangesetID:   h.ChangesetID,
 		})
 	}
 
@@ -280,3 +281,42 @@ func (h *userHandler) ListChanges(c *gin.Context) {
 
 	delivery.SuccessWithData(c, response)
 }
+
+func (h *userHandler) ListRoleHistory(c *gin.Context) {
+	var uri dto.UserIDUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	var query dto.PaginationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	history, nextCursor, err := h.userUseCase.ListRoleHistory(c.Request.Context(), uri.UserID, query.Cursor, query.Limit)
+	if err != nil {
+		delivery.Error(c, err)
+		return
+	}
+
+	response := dto.PaginatedRoleHistoryResponse{
+		History:    make([]dto.RoleHistoryResponse, 0, len(history)),
+		NextCursor: nextCursor,
+	}
+	for _, h := range history {
+		response.History = append(response.History, dto.RoleHistoryResponse{
+			HistoryID:     h.HistoryID,
+			UserID:        h.UserID,
+			OldRoleID:     h.OldRoleID,
+			NewRoleID:     h.NewRoleID,
+			ChangedBy:     h.ChangedBy,
+			ChangedByName: h.ChangedByName,
+			ChangedAt:     h.ChangedAt,
+			ActionType:    h.ActionType,
+		})
+	}
+
+	delivery.SuccessWithData(c, response)
+}
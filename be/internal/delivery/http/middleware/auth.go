@@ -15,6 +15,7 @@ const (
 	keyIsActive          = "is_active"
 	keySessionID         = "session_id"
 	keyPreferredLanguage = "preferred_language"
+	keyTreeRole          = "tree_role"
 )
 
 type AuthMiddleware struct {
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"strconv"
+
+	"github.com/escalopa/family-tree/internal/delivery"
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const headerChangesetID = "X-Changeset-ID"
+
+type ChangesetMiddleware struct {
+	changesetUseCase ChangesetUseCase
+}
+
+func NewChangesetMiddleware(changesetUseCase ChangesetUseCase) *ChangesetMiddleware {
+	return &ChangesetMiddleware{changesetUseCase: changesetUseCase}
+}
+
+// Stamp puts the history and scores a request writes in a changeset, the
+// explicit one named by X-Changeset-ID or a new one for the request alone
+func (m *ChangesetMiddleware) Stamp() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		changesetID := uuid.New().String()
+		if header := c.GetHeader(headerChangesetID); header != "" {
+			id, err := uuid.Parse(header)
+			if err != nil {
+				delivery.Error(c, domain.NewValidationError("error.changeset.invalid_id"))
+				c.Abort()
+				return
+			}
+			treeID, err := strconv.Atoi(c.Param("tree_id"))
+			if err != nil {
+				delivery.Error(c, domain.NewValidationError("error.changeset.tree_required"))
+				c.Abort()
+				return
+			}
+			if err := m.changesetUseCase.Open(c.Request.Context(), treeID, id.String(), GetUserID(c)); err != nil {
+				delivery.Error(c, err)
+				c.Abort()
+				return
+			}
+			changesetID = id.String()
+		}
+		c.Header(headerChangesetID, changesetID)
+
+		ctx := domain.ContextWithChangeset(c.Request.Context(), changesetID)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"github.com/escalopa/family-tree/internal/domain"
 	"github.com/escalopa/family-tree/internal/pkg/i18n"
 	"github.com/gin-gonic/gin"
 )
@@ -8,6 +9,7 @@ import (
 const (
 	keyInterfaceLanguage = "interface_language"
 	headerAcceptLanguage = "Accept-Language"
+	headerCalendar       = "X-Calendar"
 )
 
 func LanguageMiddleware() gin.HandlerFunc {
@@ -17,6 +19,18 @@ func LanguageMiddleware() gin.HandlerFunc {
 		lang = i18n.NormalizeLanguage(lang)
 
 		c.Set(keyInterfaceLanguage, lang)
+
+		// Responses carry every date in both calendars, the header tells the
+		// client which one to show: Hijri for Arabic unless asked otherwise
+		calendar := c.GetHeader(headerCalendar)
+		if calendar != domain.CalendarGregorian && calendar != domain.CalendarHijri {
+			calendar = domain.CalendarGregorian
+			if lang == "ar" {
+				calendar = domain.CalendarHijri
+			}
+		}
+		c.Header(headerCalendar, calendar)
+
 		c.Next()
 	}
 }
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"regexp"
+
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const headerRequestID = "X-Request-ID"
+
+// requestIDPattern keeps client supplied request ids short and printable
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+// RequestInfo gives every request an id, reusing the client's X-Request-ID
+// when it is sane, and puts the id, client IP and user agent in the request
+// context for the audit log
+func RequestInfo() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(headerRequestID)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = uuid.New().String()
+		}
+		c.Header(headerRequestID, requestID)
+
+		ctx := domain.ContextWithRequestInfo(c.Request.Context(), domain.RequestInfo{
+			IPAddress: c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+			RequestID: requestID,
+		})
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"strconv"
+
+	"github.com/escalopa/family-tree/internal/delivery"
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type TreeRoleMiddleware struct {
+	treeRoleUseCase TreeRoleUseCase
+}
+
+func NewTreeRoleMiddleware(treeRoleUseCase TreeRoleUseCase) *TreeRoleMiddleware {
+	return &TreeRoleMiddleware{treeRoleUseCase: treeRoleUseCase}
+}
+
+// Load looks up the caller's role in the tree of the route once per request.
+// A tree the caller isn't a member of is not found, whatever the caller's
+// global role.
+func (m *TreeRoleMiddleware) Load() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		param := c.Param("tree_id")
+		if param == "" {
+			c.Next()
+			return
+		}
+		treeID, err := strconv.Atoi(param)
+		if err != nil {
+			delivery.Error(c, domain.NewNotFoundError("family_tree"))
+			c.Abort()
+			return
+		}
+		role, err := m.treeRoleUseCase.Role(c.Request.Context(), treeID, GetUserID(c))
+		if err != nil {
+			delivery.Error(c, err)
+			c.Abort()
+			return
+		}
+		c.Set(keyTreeRole, role)
+		c.Next()
+	}
+}
+
+// RequireTreePermission lets through members whose role in the tree grants
+// the permission, it runs after Load
+func RequireTreePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !domain.TreeRoleAllows(GetTreeRole(c), permission) {
+			delivery.Error(c, domain.NewForbiddenError("error.family_tree.permission_denied"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func GetTreeRole(c *gin.Context) string {
+	role, exists := c.Get(keyTreeRole)
+	if !exists {
+		return ""
+	}
+	if r, ok := role.(string); ok {
+		return r
+	}
+	return ""
+}
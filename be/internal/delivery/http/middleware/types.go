@@ -24,6 +24,14 @@ type AuthUseCase interface {
 	ValidateSession(ctx context.Context, sessionID string) (*domain.Session, error)
 }
 
+type ChangesetUseCase interface {
+	Open(ctx context.Context, treeID int, changesetID string, userID int) error
+}
+
+type TreeRoleUseCase interface {
+	Role(ctx context.Context, treeID, userID int) (string, error)
+}
+
 type UserRepository interface {
 	Get(ctx context.Context, userID int) (*domain.User, error)
 }
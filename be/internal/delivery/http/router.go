@@ -13,10 +13,24 @@ type Router struct {
 	userHandler               UserHandler
 	memberHandler             MemberHandler
 	spouseHandler             SpouseHandler
+	familyUnitHandler         FamilyUnitHandler
+	proposalHandler           ProposalHandler
+	auditHandler              AuditHandler
 	treeHandler               TreeHandler
 	familyTreeHandler         FamilyTreeHandler
+	timelineHandler           TimelineHandler
+	activityHandler           ActivityHandler
+	changesetHandler          ChangesetHandler
+	calendarHandler           CalendarHandler
+	placeHandler              PlaceHandler
+	eventHandler              EventHandler
+	mediaHandler              MediaHandler
+	sourceHandler             SourceHandler
+	customFieldHandler        CustomFieldHandler
 	languageHandler           LanguageHandler
 	authMiddleware            AuthMiddleware
+	changesetMiddleware       ChangesetMiddleware
+	treeRoleMiddleware        TreeRoleMiddleware
 	allowedOrigins            []string
 	enableHSTS                bool
 	authRateLimitMiddleware   RateLimitMiddleware
@@ -29,10 +43,24 @@ func NewRouter(
 	userHandler UserHandler,
 	memberHandler MemberHandler,
 	spouseHandler SpouseHandler,
+	familyUnitHandler FamilyUnitHandler,
+	proposalHandler ProposalHandler,
+	auditHandler AuditHandler,
 	treeHandler TreeHandler,
 	familyTreeHandler FamilyTreeHandler,
+	timelineHandler TimelineHandler,
+	activityHandler ActivityHandler,
+	changesetHandler ChangesetHandler,
+	calendarHandler CalendarHandler,
+	placeHandler PlaceHandler,
+	eventHandler EventHandler,
+	mediaHandler MediaHandler,
+	sourceHandler SourceHandler,
+	customFieldHandler CustomFieldHandler,
 	languageHandler LanguageHandler,
 	authMiddleware AuthMiddleware,
+	changesetMiddleware ChangesetMiddleware,
+	treeRoleMiddleware TreeRoleMiddleware,
 	allowedOrigins []string,
 	enableHSTS bool,
 	authRateLimitMiddleware RateLimitMiddleware,
@@ -44,10 +72,24 @@ func NewRouter(
 		userHandler:               userHandler,
 		memberHandler:             memberHandler,
 		spouseHandler:             spouseHandler,
+		familyUnitHandler:         familyUnitHandler,
+		proposalHandler:           proposalHandler,
+		auditHandler:              auditHandler,
 		treeHandler:               treeHandler,
 		familyTreeHandler:         familyTreeHandler,
+		timelineHandler:           timelineHandler,
+		activityHandler:           activityHandler,
+		changesetHandler:          changesetHandler,
+		calendarHandler:           calendarHandler,
+		placeHandler:              placeHandler,
+		eventHandler:              eventHandler,
+		mediaHandler:              mediaHandler,
+		sourceHandler:             sourceHandler,
+		customFieldHandler:        customFieldHandler,
 		languageHandler:           languageHandler,
 		authMiddleware:            authMiddleware,
+		changesetMiddleware:       changesetMiddleware,
+		treeRoleMiddleware:        treeRoleMiddleware,
 		allowedOrigins:            allowedOrigins,
 		enableHSTS:                enableHSTS,
 		authRateLimitMiddleware:   authRateLimitMiddleware,
@@ -57,6 +99,7 @@ func NewRouter(
 }
 
 func (r *Router) Setup(engine *gin.Engine) {
+	engine.Use(middleware.RequestInfo())
 	engine.Use(middleware.SecurityHeaders(r.enableHSTS))
 	engine.Use(middleware.CORS(r.allowedOrigins))
 	engine.Use(middleware.LanguageMiddleware())
@@ -68,6 +111,7 @@ func (r *Router) Setup(engine *gin.Engine) {
 	})
 
 	engine.GET("/public/trees/:token", r.familyTreeHandler.GetPublicTree)
+	engine.GET("/public/calendars/:token", r.calendarHandler.GetPublicCalendar)
 
 	auth := engine.Group("/auth")
 	auth.Use(r.authRateLimitMiddleware.RateLimit())
@@ -97,13 +141,20 @@ func (r *Router) Setup(engine *gin.Engine) {
 			userGroup.GET("/leaderboard", r.userHandler.ListLeaderboard)
 			userGroup.GET("/score/:user_id", r.userHandler.ListScoreHistory)
 			userGroup.GET("/members/:user_id", middleware.RequireRole(domain.RoleAdmin), r.userHandler.ListChanges)
+			userGroup.GET("/:user_id/role-history", middleware.RequireRole(domain.RoleSuperAdmin), r.userHandler.ListRoleHistory)
 			userGroup.GET("/:user_id", r.userHandler.Get)
 
 			userGroup.PUT("/:user_id", middleware.RequireRole(domain.RoleSuperAdmin), r.userHandler.Update)
 		}
 
+		auditGroup := api.Group("/audit-events")
+		auditGroup.Use(middleware.RequireActive())
+		{
+			auditGroup.GET("", r.auditHandler.List)
+		}
+
 		familyTreeGroup := api.Group("/family-trees")
-		familyTreeGroup.Use(middleware.RequireActive())
+		familyTreeGroup.Use(middleware.RequireActive(), r.treeRoleMiddleware.Load(), r.changesetMiddleware.Stamp())
 		{
 			familyTreeGroup.GET("", r.familyTreeHandler.List)
 			familyTreeGroup.POST("", r.familyTreeHandler.Create)
@@ -111,29 +162,117 @@ func (r *Router) Setup(engine *gin.Engine) {
 			familyTreeGroup.POST("/invitations/:invitation_id/accept", r.familyTreeHandler.AcceptInvitation)
 			familyTreeGroup.POST("/invitations/:invitation_id/decline", r.familyTreeHandler.DeclineInvitation)
 			familyTreeGroup.GET("/:tree_id", r.familyTreeHandler.Get)
+			familyTreeGroup.PUT("/:tree_id/name-settings", middleware.RequireTreePermission(domain.TreePermissionManage), r.familyTreeHandler.UpdateNameSettings)
+			familyTreeGroup.GET("/:tree_id/privacy-policy", r.familyTreeHandler.GetPrivacyPolicy)
+			familyTreeGroup.PUT("/:tree_id/privacy-policy", middleware.RequireTreePermission(domain.TreePermissionManage), r.familyTreeHandler.UpdatePrivacyPolicy)
+			familyTreeGroup.DELETE("/:tree_id/privacy-policy", middleware.RequireTreePermission(domain.TreePermissionManage), r.familyTreeHandler.ResetPrivacyPolicy)
 			familyTreeGroup.GET("/:tree_id/tree", r.treeHandler.GetTree)
 			familyTreeGroup.GET("/:tree_id/tree/graph", r.treeHandler.GetGraph)
 			familyTreeGroup.GET("/:tree_id/tree/relation", r.treeHandler.GetRelation)
 			familyTreeGroup.GET("/:tree_id/tree/graph/relation", r.treeHandler.GetRelationGraph)
+			familyTreeGroup.GET("/:tree_id/timeline", r.timelineHandler.List)
+			familyTreeGroup.GET("/:tree_id/activity", r.activityHandler.List)
+			familyTreeGroup.GET("/:tree_id/map", r.placeHandler.GetMap)
+			familyTreeGroup.GET("/:tree_id/custom-fields", r.customFieldHandler.List)
+			familyTreeGroup.GET("/:tree_id/custom-fields/:field_id", r.customFieldHandler.Get)
+			familyTreeGroup.POST("/:tree_id/custom-fields", middleware.RequireTreePermission(domain.TreePermissionManage), r.customFieldHandler.Create)
+			familyTreeGroup.PUT("/:tree_id/custom-fields/:field_id", middleware.RequireTreePermission(domain.TreePermissionManage), r.customFieldHandler.Update)
+			familyTreeGroup.DELETE("/:tree_id/custom-fields/:field_id", middleware.RequireTreePermission(domain.TreePermissionManage), r.customFieldHandler.Delete)
+			familyTreeGroup.GET("/:tree_id/places", r.placeHandler.List)
+			familyTreeGroup.GET("/:tree_id/places/:place_id", r.placeHandler.Get)
+			familyTreeGroup.POST("/:tree_id/places", middleware.RequireTreePermission(domain.TreePermissionEdit), r.placeHandler.Create)
+			familyTreeGroup.PUT("/:tree_id/places/:place_id", middleware.RequireTreePermission(domain.TreePermissionEdit), r.placeHandler.Update)
+			familyTreeGroup.DELETE("/:tree_id/places/:place_id", middleware.RequireTreePermission(domain.TreePermissionEdit), r.placeHandler.Delete)
+			familyTreeGroup.GET("/:tree_id/media", r.mediaHandler.List)
+			familyTreeGroup.GET("/:tree_id/media/:media_id", r.mediaHandler.Get)
+			familyTreeGroup.GET("/:tree_id/media/:media_id/file", r.mediaHandler.GetFile)
+			familyTreeGroup.POST("/:tree_id/media", r.uploadRateLimitMiddleware.RateLimit(), middleware.RequireTreePermission(domain.TreePermissionEdit), r.mediaHandler.Upload)
+			familyTreeGroup.PUT("/:tree_id/media/:media_id", middleware.RequireTreePermission(domain.TreePermissionEdit), r.mediaHandler.Update)
+			familyTreeGroup.DELETE("/:tree_id/media/:media_id", middleware.RequireTreePermission(domain.TreePermissionEdit), r.mediaHandler.Delete)
+			familyTreeGroup.PUT("/:tree_id/media/:media_id/tags/:member_id", middleware.RequireTreePermission(domain.TreePermissionEdit), r.mediaHandler.Tag)
+			familyTreeGroup.DELETE("/:tree_id/media/:media_id/tags/:member_id", middleware.RequireTreePermission(domain.TreePermissionEdit), r.mediaHandler.Untag)
+			familyTreeGroup.GET("/:tree_id/sources", r.sourceHandler.List)
+			familyTreeGroup.GET("/:tree_id/sources/:source_id", r.sourceHandler.Get)
+			familyTreeGroup.GET("/:tree_id/sources/:source_id/attachment", r.sourceHandler.GetAttachment)
+			familyTreeGroup.GET("/:tree_id/sources/:source_id/citations", r.sourceHandler.ListSourceCitations)
+			familyTreeGroup.POST("/:tree_id/sources", middleware.RequireTreePermission(domain.TreePermissionEdit), r.sourceHandler.Create)
+			familyTreeGroup.PUT("/:tree_id/sources/:source_id", middleware.RequireTreePermission(domain.TreePermissionEdit), r.sourceHandler.Update)
+			familyTreeGroup.DELETE("/:tree_id/sources/:source_id", middleware.RequireTreePermission(domain.TreePermissionEdit), r.sourceHandler.Delete)
+			familyTreeGroup.POST("/:tree_id/sources/:source_id/attachment", r.uploadRateLimitMiddleware.RateLimit(), middleware.RequireTreePermission(domain.TreePermissionEdit), r.sourceHandler.UploadAttachment)
+			familyTreeGroup.DELETE("/:tree_id/sources/:source_id/attachment", middleware.RequireTreePermission(domain.TreePermissionEdit), r.sourceHandler.DeleteAttachment)
+			familyTreeGroup.GET("/:tree_id/citations", r.sourceHandler.ListCitations)
+			familyTreeGroup.POST("/:tree_id/citations", middleware.RequireTreePermission(domain.TreePermissionEdit), r.sourceHandler.CreateCitation)
+			familyTreeGroup.PUT("/:tree_id/citations/:citation_id", middleware.RequireTreePermission(domain.TreePermissionEdit), r.sourceHandler.UpdateCitation)
+			familyTreeGroup.DELETE("/:tree_id/citations/:citation_id", middleware.RequireTreePermission(domain.TreePermissionEdit), r.sourceHandler.DeleteCitation)
+			familyTreeGroup.GET("/:tree_id/data-quality/unsourced-facts", middleware.RequireTreePermission(domain.TreePermissionEdit), r.sourceHandler.ListUnsourcedFacts)
 			familyTreeGroup.GET("/:tree_id/members", r.memberHandler.List)
 			familyTreeGroup.GET("/:tree_id/members/search", r.memberHandler.List)
-			familyTreeGroup.GET("/:tree_id/members/history", middleware.RequireRole(domain.RoleSuperAdmin), r.memberHandler.ListHistory)
+			familyTreeGroup.GET("/:tree_id/members/history", middleware.RequireTreePermission(domain.TreePermissionEdit), r.memberHandler.ListHistory)
 			familyTreeGroup.GET("/:tree_id/members/:member_id", r.memberHandler.Get)
-			familyTreeGroup.GET("/:tree_id/members/:member_id/picture", middleware.RequireRole(domain.RoleAdmin), r.memberHandler.GetPicture)
-			familyTreeGroup.POST("/:tree_id/members", middleware.RequireRole(domain.RoleAdmin), r.memberHandler.Create)
-			familyTreeGroup.POST("/:tree_id/members/:member_id/rollback", middleware.RequireRole(domain.RoleSuperAdmin), r.memberHandler.Rollback)
-			familyTreeGroup.PUT("/:tree_id/members/:member_id", middleware.RequireRole(domain.RoleAdmin), r.memberHandler.Update)
-			familyTreeGroup.DELETE("/:tree_id/members/:member_id", middleware.RequireRole(domain.RoleSuperAdmin), r.memberHandler.Delete)
-			familyTreeGroup.POST("/:tree_id/members/:member_id/picture", r.uploadRateLimitMiddleware.RateLimit(), middleware.RequireRole(domain.RoleAdmin), r.memberHandler.UploadPicture)
-			familyTreeGroup.DELETE("/:tree_id/members/:member_id/picture", middleware.RequireRole(domain.RoleAdmin), r.memberHandler.DeletePicture)
-			familyTreeGroup.POST("/:tree_id/spouses", middleware.RequireRole(domain.RoleAdmin), r.spouseHandler.Create)
-			familyTreeGroup.PUT("/:tree_id/spouses/:spouse_id", middleware.RequireRole(domain.RoleAdmin), r.spouseHandler.Update)
-			familyTreeGroup.DELETE("/:tree_id/spouses/:spouse_id", middleware.RequireRole(domain.RoleAdmin), r.spouseHandler.Delete)
-			familyTreeGroup.POST("/:tree_id/invitations", r.familyTreeHandler.Invite)
-			familyTreeGroup.GET("/:tree_id/invitations", r.familyTreeHandler.ListInvitations)
-			familyTreeGroup.POST("/:tree_id/share-links", r.familyTreeHandler.CreateShareLink)
-			familyTreeGroup.GET("/:tree_id/share-links", r.familyTreeHandler.ListShareLinks)
-			familyTreeGroup.DELETE("/:tree_id/share-links/:share_id", r.familyTreeHandler.RevokeShareLink)
+			familyTreeGroup.GET("/:tree_id/members/:member_id/picture", middleware.RequireTreePermission(domain.TreePermissionEdit), r.memberHandler.GetPicture)
+			familyTreeGroup.POST("/:tree_id/members", middleware.RequireTreePermission(domain.TreePermissionEdit), r.memberHandler.Create)
+			familyTreeGroup.GET("/:tree_id/members/:member_id/history/diff", middleware.RequireTreePermission(domain.TreePermissionEdit), r.memberHandler.HistoryDiff)
+			familyTreeGroup.POST("/:tree_id/members/:member_id/rollback", middleware.RequireTreePermission(domain.TreePermissionDelete), r.memberHandler.Rollback)
+			familyTreeGroup.PUT("/:tree_id/members/:member_id", middleware.RequireTreePermission(domain.TreePermissionEdit), r.memberHandler.Update)
+			familyTreeGroup.DELETE("/:tree_id/members/:member_id", middleware.RequireTreePermission(domain.TreePermissionDelete), r.memberHandler.Delete)
+			familyTreeGroup.POST("/:tree_id/members/:member_id/picture", r.uploadRateLimitMiddleware.RateLimit(), middleware.RequireTreePermission(domain.TreePermissionEdit), r.memberHandler.UploadPicture)
+			familyTreeGroup.DELETE("/:tree_id/members/:member_id/picture", middleware.RequireTreePermission(domain.TreePermissionEdit), r.memberHandler.DeletePicture)
+			familyTreeGroup.PUT("/:tree_id/members/:member_id/picture", middleware.RequireTreePermission(domain.TreePermissionEdit), r.mediaHandler.SetProfilePicture)
+			familyTreeGroup.GET("/:tree_id/members/:member_id/media", r.mediaHandler.ListByMember)
+			familyTreeGroup.GET("/:tree_id/members/:member_id/biography", r.memberHandler.GetBiography)
+			familyTreeGroup.PUT("/:tree_id/members/:member_id/biography/:code", middleware.RequireTreePermission(domain.TreePermissionEdit), r.memberHandler.UpdateBiography)
+			familyTreeGroup.GET("/:tree_id/members/:member_id/notes", middleware.RequireTreePermission(domain.TreePermissionEdit), r.memberHandler.GetNotes)
+			familyTreeGroup.PUT("/:tree_id/members/:member_id/notes", middleware.RequireTreePermission(domain.TreePermissionEdit), r.memberHandler.UpdateNotes)
+			familyTreeGroup.POST("/:tree_id/members/:member_id/names/:code/confirm", middleware.RequireTreePermission(domain.TreePermissionEdit), r.memberHandler.ConfirmName)
+			familyTreeGroup.POST("/:tree_id/members/suggest-names", middleware.RequireTreePermission(domain.TreePermissionEdit), r.memberHandler.SuggestTreeNames)
+			familyTreeGroup.GET("/:tree_id/name-spellings", r.memberHandler.ListNameSpellings)
+			familyTreeGroup.PUT("/:tree_id/name-spellings", middleware.RequireTreePermission(domain.TreePermissionEdit), r.memberHandler.SaveNameSpelling)
+			familyTreeGroup.DELETE("/:tree_id/name-spellings", middleware.RequireTreePermission(domain.TreePermissionEdit), r.memberHandler.DeleteNameSpelling)
+			familyTreeGroup.GET("/:tree_id/trash", middleware.RequireTreePermission(domain.TreePermissionDelete), r.memberHandler.ListTrash)
+			familyTreeGroup.POST("/:tree_id/trash/members/:member_id/restore", middleware.RequireTreePermission(domain.TreePermissionDelete), r.memberHandler.RestoreMember)
+			familyTreeGroup.POST("/:tree_id/trash/spouses/:spouse_id/restore", middleware.RequireTreePermission(domain.TreePermissionDelete), r.memberHandler.RestoreSpouse)
+			familyTreeGroup.DELETE("/:tree_id/trash/pictures", middleware.RequireTreePermission(domain.TreePermissionDelete), r.memberHandler.PurgePictures)
+			familyTreeGroup.POST("/:tree_id/restore", middleware.RequireTreePermission(domain.TreePermissionDelete), r.memberHandler.RestoreAsOf)
+			familyTreeGroup.GET("/:tree_id/changesets", r.changesetHandler.List)
+			familyTreeGroup.POST("/:tree_id/changesets", middleware.RequireTreePermission(domain.TreePermissionEdit), r.changesetHandler.Create)
+			familyTreeGroup.POST("/:tree_id/changesets/:changeset_id/revert", middleware.RequireTreePermission(domain.TreePermissionDelete), r.changesetHandler.Revert)
+			familyTreeGroup.GET("/:tree_id/members/:member_id/events", r.eventHandler.List)
+			familyTreeGroup.GET("/:tree_id/members/:member_id/events/:event_id", r.eventHandler.Get)
+			familyTreeGroup.POST("/:tree_id/members/:member_id/events", middleware.RequireTreePermission(domain.TreePermissionEdit), r.eventHandler.Create)
+			familyTreeGroup.PUT("/:tree_id/members/:member_id/events/:event_id", middleware.RequireTreePermission(domain.TreePermissionEdit), r.eventHandler.Update)
+			familyTreeGroup.DELETE("/:tree_id/members/:member_id/events/:event_id", middleware.RequireTreePermission(domain.TreePermissionEdit), r.eventHandler.Delete)
+			familyTreeGroup.POST("/:tree_id/spouses", middleware.RequireTreePermission(domain.TreePermissionEdit), r.spouseHandler.Create)
+			familyTreeGroup.PUT("/:tree_id/spouses/:spouse_id", middleware.RequireTreePermission(domain.TreePermissionEdit), r.spouseHandler.Update)
+			familyTreeGroup.DELETE("/:tree_id/spouses/:spouse_id", middleware.RequireTreePermission(domain.TreePermissionEdit), r.spouseHandler.Delete)
+			familyTreeGroup.GET("/:tree_id/family-units/:family_unit_id", r.familyUnitHandler.Get)
+			familyTreeGroup.POST("/:tree_id/family-units", middleware.RequireTreePermission(domain.TreePermissionEdit), r.familyUnitHandler.Create)
+			familyTreeGroup.PUT("/:tree_id/family-units/:family_unit_id", middleware.RequireTreePermission(domain.TreePermissionEdit), r.familyUnitHandler.Update)
+			familyTreeGroup.PUT("/:tree_id/family-units/:family_unit_id/status", middleware.RequireTreePermission(domain.TreePermissionEdit), r.familyUnitHandler.UpdateStatus)
+			familyTreeGroup.DELETE("/:tree_id/family-units/:family_unit_id", middleware.RequireTreePermission(domain.TreePermissionEdit), r.familyUnitHandler.Delete)
+			familyTreeGroup.PUT("/:tree_id/family-units/:family_unit_id/children/:child_id", middleware.RequireTreePermission(domain.TreePermissionEdit), r.familyUnitHandler.AddChild)
+			familyTreeGroup.DELETE("/:tree_id/family-units/:family_unit_id/children/:child_id", middleware.RequireTreePermission(domain.TreePermissionEdit), r.familyUnitHandler.RemoveChild)
+			familyTreeGroup.POST("/:tree_id/proposals", r.proposalHandler.Submit)
+			familyTreeGroup.GET("/:tree_id/proposals", middleware.RequireTreePermission(domain.TreePermissionEdit), r.proposalHandler.List)
+			familyTreeGroup.GET("/:tree_id/proposals/mine", r.proposalHandler.ListMine)
+			familyTreeGroup.GET("/:tree_id/proposals/:proposal_id", r.proposalHandler.Get)
+			familyTreeGroup.PUT("/:tree_id/proposals/:proposal_id", r.proposalHandler.Revise)
+			familyTreeGroup.POST("/:tree_id/proposals/:proposal_id/approve", middleware.RequireTreePermission(domain.TreePermissionEdit), r.proposalHandler.Approve)
+			familyTreeGroup.POST("/:tree_id/proposals/:proposal_id/reject", middleware.RequireTreePermission(domain.TreePermissionEdit), r.proposalHandler.Reject)
+			familyTreeGroup.POST("/:tree_id/proposals/:proposal_id/request-changes", middleware.RequireTreePermission(domain.TreePermissionEdit), r.proposalHandler.RequestChanges)
+			familyTreeGroup.GET("/:tree_id/memberships", r.familyTreeHandler.ListMemberships)
+			familyTreeGroup.PUT("/:tree_id/memberships/:user_id", middleware.RequireTreePermission(domain.TreePermissionManage), r.familyTreeHandler.UpdateMembership)
+			familyTreeGroup.DELETE("/:tree_id/memberships/:user_id", middleware.RequireTreePermission(domain.TreePermissionManage), r.familyTreeHandler.RemoveMembership)
+			familyTreeGroup.POST("/:tree_id/leave", r.familyTreeHandler.Leave)
+			familyTreeGroup.POST("/:tree_id/transfer-ownership", middleware.RequireTreePermission(domain.TreePermissionManage), r.familyTreeHandler.TransferOwnership)
+			familyTreeGroup.POST("/:tree_id/invitations", middleware.RequireTreePermission(domain.TreePermissionShare), r.familyTreeHandler.Invite)
+			familyTreeGroup.GET("/:tree_id/invitations", middleware.RequireTreePermission(domain.TreePermissionShare), r.familyTreeHandler.ListInvitations)
+			familyTreeGroup.POST("/:tree_id/share-links", middleware.RequireTreePermission(domain.TreePermissionShare), r.familyTreeHandler.CreateShareLink)
+			familyTreeGroup.GET("/:tree_id/share-links", middleware.RequireTreePermission(domain.TreePermissionShare), r.familyTreeHandler.ListShareLinks)
+			familyTreeGroup.PATCH("/:tree_id/share-links/:share_id", middleware.RequireTreePermission(domain.TreePermissionShare), r.familyTreeHandler.UpdateShareLink)
+			familyTreeGroup.DELETE("/:tree_id/share-links/:share_id", middleware.RequireTreePermission(domain.TreePermissionShare), r.familyTreeHandler.RevokeShareLink)
+			familyTreeGroup.POST("/:tree_id/calendar-feeds", r.calendarHandler.CreateFeed)
+			familyTreeGroup.GET("/:tree_id/calendar-feeds", r.calendarHandler.ListFeeds)
+			familyTreeGroup.DELETE("/:tree_id/calendar-feeds/:feed_id", r.calendarHandler.RevokeFeed)
 		}
 
 		languageGroup := api.Group("/languages")
@@ -18,6 +18,11 @@ type UserHandler interface {
 	ListLeaderboard(c *gin.Context)
 	ListScoreHistory(c *gin.Context)
 	ListChanges(c *gin.Context)
+	ListRoleHistory(c *gin.Context)
+}
+
+type AuditHandler interface {
+	List(c *gin.Context)
 }
 
 type MemberHandler interface {
@@ -27,10 +32,25 @@ type MemberHandler interface {
 	Update(c *gin.Context)
 	Delete(c *gin.Context)
 	ListHistory(c *gin.Context)
+	HistoryDiff(c *gin.Context)
 	Rollback(c *gin.Context)
 	UploadPicture(c *gin.Context)
 	DeletePicture(c *gin.Context)
 	GetPicture(c *gin.Context)
+	GetBiography(c *gin.Context)
+	UpdateBiography(c *gin.Context)
+	GetNotes(c *gin.Context)
+	UpdateNotes(c *gin.Context)
+	ConfirmName(c *gin.Context)
+	SuggestTreeNames(c *gin.Context)
+	ListNameSpellings(c *gin.Context)
+	SaveNameSpelling(c *gin.Context)
+	DeleteNameSpelling(c *gin.Context)
+	ListTrash(c *gin.Context)
+	RestoreMember(c *gin.Context)
+	RestoreSpouse(c *gin.Context)
+	PurgePictures(c *gin.Context)
+	RestoreAsOf(c *gin.Context)
 }
 
 type SpouseHandler interface {
@@ -39,6 +59,27 @@ type SpouseHandler interface {
 	Delete(c *gin.Context)
 }
 
+type FamilyUnitHandler interface {
+	Get(c *gin.Context)
+	Create(c *gin.Context)
+	Update(c *gin.Context)
+	UpdateStatus(c *gin.Context)
+	Delete(c *gin.Context)
+	AddChild(c *gin.Context)
+	RemoveChild(c *gin.Context)
+}
+
+type ProposalHandler interface {
+	Submit(c *gin.Context)
+	Revise(c *gin.Context)
+	List(c *gin.Context)
+	ListMine(c *gin.Context)
+	Get(c *gin.Context)
+	Approve(c *gin.Context)
+	Reject(c *gin.Context)
+	RequestChanges(c *gin.Context)
+}
+
 type TreeHandler interface {
 	GetTree(c *gin.Context)
 	GetRelation(c *gin.Context)
@@ -50,6 +91,15 @@ type FamilyTreeHandler interface {
 	Create(c *gin.Context)
 	List(c *gin.Context)
 	Get(c *gin.Context)
+	UpdateNameSettings(c *gin.Context)
+	GetPrivacyPolicy(c *gin.Context)
+	UpdatePrivacyPolicy(c *gin.Context)
+	ResetPrivacyPolicy(c *gin.Context)
+	ListMemberships(c *gin.Context)
+	UpdateMembership(c *gin.Context)
+	RemoveMembership(c *gin.Context)
+	Leave(c *gin.Context)
+	TransferOwnership(c *gin.Context)
 	ListMyInvitations(c *gin.Context)
 	Invite(c *gin.Context)
 	ListInvitations(c *gin.Context)
@@ -57,10 +107,87 @@ type FamilyTreeHandler interface {
 	DeclineInvitation(c *gin.Context)
 	CreateShareLink(c *gin.Context)
 	ListShareLinks(c *gin.Context)
+	UpdateShareLink(c *gin.Context)
 	RevokeShareLink(c *gin.Context)
 	GetPublicTree(c *gin.Context)
 }
 
+type TimelineHandler interface {
+	List(c *gin.Context)
+}
+
+type ChangesetHandler interface {
+	Create(c *gin.Context)
+	List(c *gin.Context)
+	Revert(c *gin.Context)
+}
+
+type ActivityHandler interface {
+	List(c *gin.Context)
+}
+
+type CustomFieldHandler interface {
+	Create(c *gin.Context)
+	Get(c *gin.Context)
+	List(c *gin.Context)
+	Update(c *gin.Context)
+	Delete(c *gin.Context)
+}
+
+type PlaceHandler interface {
+	Create(c *gin.Context)
+	Get(c *gin.Context)
+	List(c *gin.Context)
+	Update(c *gin.Context)
+	Delete(c *gin.Context)
+	GetMap(c *gin.Context)
+}
+
+type EventHandler interface {
+	Create(c *gin.Context)
+	Get(c *gin.Context)
+	List(c *gin.Context)
+	Update(c *gin.Context)
+	Delete(c *gin.Context)
+}
+
+type MediaHandler interface {
+	Upload(c *gin.Context)
+	Get(c *gin.Context)
+	GetFile(c *gin.Context)
+	List(c *gin.Context)
+	ListByMember(c *gin.Context)
+	Update(c *gin.Context)
+	Delete(c *gin.Context)
+	Tag(c *gin.Context)
+	Untag(c *gin.Context)
+	SetProfilePicture(c *gin.Context)
+}
+
+type SourceHandler interface {
+	Create(c *gin.Context)
+	Get(c *gin.Context)
+	List(c *gin.Context)
+	Update(c *gin.Context)
+	Delete(c *gin.Context)
+	UploadAttachment(c *gin.Context)
+	GetAttachment(c *gin.Context)
+	DeleteAttachment(c *gin.Context)
+	ListSourceCitations(c *gin.Context)
+	ListCitations(c *gin.Context)
+	CreateCitation(c *gin.Context)
+	UpdateCitation(c *gin.Context)
+	DeleteCitation(c *gin.Context)
+	ListUnsourcedFacts(c *gin.Context)
+}
+
+type CalendarHandler interface {
+	CreateFeed(c *gin.Context)
+	ListFeeds(c *gin.Context)
+	RevokeFeed(c *gin.Context)
+	GetPublicCalendar(c *gin.Context)
+}
+
 type LanguageHandler interface {
 	Get(c *gin.Context)
 	List(c *gin.Context)
@@ -74,6 +201,14 @@ type AuthMiddleware interface {
 	Authenticate() gin.HandlerFunc
 }
 
+type ChangesetMiddleware interface {
+	Stamp() gin.HandlerFunc
+}
+
+type TreeRoleMiddleware interface {
+	Load() gin.HandlerFunc
+}
+
 type RateLimitMiddleware interface {
 	RateLimit() gin.HandlerFunc
 }
@@ -19,6 +19,12 @@ func getInterfaceLanguage(c *gin.Context) string {
 }
 
 func Error(c *gin.Context, err error) {
+	ErrorWithData(c, err, nil)
+}
+
+// ErrorWithData responds with the error along with the data a client needs
+// to recover from it
+func ErrorWithData(c *gin.Context, err error, data any) {
 	interfaceLang := getInterfaceLanguage(c)
 
 	if validationErrs, ok := err.(validator.ValidationErrors); ok {
@@ -41,6 +47,7 @@ func Error(c *gin.Context, err error) {
 
 		c.JSON(domainErr.HTTPStatusCode(), dto.Response{
 			Success:   false,
+			Data:      data,
 			Error:     translatedMsg,
 			ErrorCode: domainErr.Code.String(),
 		})
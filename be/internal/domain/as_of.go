@@ -0,0 +1,520 @@
+package domain
+
+import (
+	"encoding/json"
+	"slices"
+	"sort"
+	"time"
+)
+
+// AsOfChangeTypes are the history entries that change the members and
+// spouse relationships of a tree, the ones undone to see it in the past
+var AsOfChangeTypes = []string{
+	ChangeTypeInsert, ChangeTypeUpdate, ChangeTypeDelete, ChangeTypeRestore,
+	ChangeTypeAddPicture, ChangeTypeDeletePicture,
+	ChangeTypeAddSpouse, ChangeTypeRemoveSpouse, ChangeTypeUpdateSpouse, ChangeTypeRestoreSpouse,
+}
+
+// MemberAsOf is a member as it was at a point in time along with the close
+// family it had then
+type MemberAsOf struct {
+	Member   *MemberWithComputed
+	Parents  []*Member
+	Children []*Member
+	Siblings []*Member
+}
+
+// TreeState is the members and spouse relationships of a tree at a point in time
+type TreeState struct {
+	TreeID  int
+	AsOf    time.Time
+	Members []*Member
+	Spouses []*Spouse
+}
+
+// RewindHistory rebuilds a tree as it was at asOf from its current members,
+// its spouse relationships including deleted ones, and the history recorded
+// after asOf in the order it was written. Entries are undone newest first: an
+// insert or restore removes the member, an update or delete puts back the old
+// snapshot. Members without history are taken as they are now.
+func RewindHistory(treeID int, members []*Member, spouses []*Spouse, history []*History, asOf time.Time) *TreeState {
+	memberByID := make(map[int]*Member, len(members))
+	for _, member := range members {
+		memberByID[member.MemberID] = member
+	}
+
+	// A spouse relationship deleted along with a partner has no history of
+	// its own, its delete time says whether it still existed at asOf
+	spouseByID := make(map[int]*Spouse, len(spouses))
+	for _, spouse := range spouses {
+		if spouse.DeletedAt == nil || spouse.DeletedAt.After(asOf) {
+			live := *spouse
+			live.DeletedAt = nil
+			spouseByID[spouse.SpouseID] = &live
+		}
+	}
+
+	for _, entry := range slices.Backward(history) {
+		if !entry.ChangedAt.After(asOf) {
+			continue
+		}
+		switch entry.ChangeType {
+		case ChangeTypeInsert, ChangeTypeRestore:
+			delete(memberByID, entry.MemberID)
+		case ChangeTypeUpdate, ChangeTypeDelete:
+			var member Member
+			if err := json.Unmarshal(entry.OldValues, &member); err != nil || len(member.Names) == 0 {
+				continue
+			}
+			member.MemberID = entry.MemberID
+			member.TreeID = treeID
+			member.DeletedAt = nil
+			if member.Nicknames == nil {
+				member.Nicknames = []string{}
+			}
+			memberByID[entry.MemberID] = &member
+		case ChangeTypeAddPicture, ChangeTypeDeletePicture:
+			current, ok := memberByID[entry.MemberID]
+			if !ok {
+				continue
+			}
+			var old struct {
+				Picture *string `json:"picture"`
+			}
+			if err := json.Unmarshal(entry.OldValues, &old); err != nil {
+				continue
+			}
+			member := *current
+			member.Picture = old.Picture
+			memberByID[entry.MemberID] = &member
+		case ChangeTypeAddSpouse, ChangeTypeRestoreSpouse:
+			var spouse Spouse
+			if err := json.Unmarshal(entry.NewValues, &spouse); err != nil {
+				continue
+			}
+			delete(spouseByID, spouse.SpouseID)
+		case ChangeTypeRemoveSpouse, ChangeTypeUpdateSpouse:
+			var spouse Spouse
+			if err := json.Unmarshal(entry.OldValues, &spouse); err != nil || spouse.SpouseID == 0 {
+				continue
+			}
+			spouse.DeletedAt = nil
+			spouseByID[spouse.SpouseID] = &spouse
+		}
+	}
+
+	state := &TreeState{
+		TreeID:  treeID,
+		AsOf:    asOf,
+		Members: make([]*Member, 0, len(memberByID)),
+		Spouses: make([]*Spouse, 0, len(spouseByID)),
+	}
+	for _, member := range memberByID {
+		state.Members = append(state.Members, member)
+	}
+	sort.Slice(state.Members, func(i, j int) bool {
+		return state.Members[i].MemberID < state.Members[j].MemberID
+	})
+
+	for _, spouse := range spouseByID {
+		_, hasFather := memberByID[spouse.FatherID]
+		_, hasMother := memberByID[spouse.MotherID]
+		if hasFather && hasMother {
+			state.Spouses = append(state.Spouses, spouse)
+		}
+	}
+	sort.Slice(state.Spouses, func(i, j int) bool {
+		left, right := state.Spouses[i], state.Spouses[j]
+		if left.MarriageDate == nil || right.MarriageDate == nil || left.MarriageDate.Equal(*right.MarriageDate) {
+			if (left.MarriageDate == nil) != (right.MarriageDate == nil) {
+				return right.MarriageDate == nil
+			}
+			return left.SpouseID < right.SpouseID
+		}
+		return left.MarriageDate.Before(*right.MarriageDate)
+	})
+
+	return state
+}
+
+// Member returns the member as it was, if it existed
+func (s *TreeState) Member(memberID int) (*Member, bool) {
+	for _, member := range s.Members {
+		if member.MemberID == memberID {
+			return member, true
+		}
+	}
+	return nil, false
+}
+
+// Children returns the children the member had, oldest first
+func (s *TreeState) Children(memberID int) []*Member {
+	return s.family(func(member *Member) bool {
+		return sameID(member.FatherID, &memberID) || sameID(member.MotherID, &memberID)
+	})
+}
+
+// Siblings returns the members that shared a parent with the member, oldest first
+func (s *TreeState) Siblings(memberID int) []*Member {
+	of, ok := s.Member(memberID)
+	if !ok {
+		return nil
+	}
+	return s.family(func(member *Member) bool {
+		return member.MemberID != memberID && (sameID(member.FatherID, of.FatherID) || sameID(member.MotherID, of.MotherID))
+	})
+}
+
+func (s *TreeState) family(match func(member *Member) bool) []*Member {
+	var members []*Member
+	for _, member := range s.Members {
+		if match(member) {
+			members = append(members, member)
+		}
+	}
+	sort.SliceStable(members, func(i, j int) bool {
+		left, right := members[i].DateOfBirth, members[j].DateOfBirth
+		if left == nil || right == nil {
+			return left != nil && right == nil
+		}
+		return left.Before(*right)
+	})
+	return members
+}
+
+// sameID reports whether both IDs are set and equal
+func sameID(left, right *int) bool {
+	return left != nil && right != nil && *left == *right
+}
+
+// SpousesOf returns the spouse relationships the member had with the
+// partner's name, gender and picture filled in
+func (s *TreeState) SpousesOf(memberID int) []SpouseWithMemberInfo {
+	spouses := s.SpouseMap()[memberID]
+	for i := range spouses {
+		if partner, ok := s.Member(spouses[i].MemberID); ok {
+			spouses[i].Names = partner.Names
+			spouses[i].Gender = partner.Gender
+			spouses[i].Picture = partner.Picture
+		}
+	}
+	return spouses
+}
+
+// SpouseMap indexes the spouse relationships by both partners, the way the
+// tree views read them
+func (s *TreeState) SpouseMap() map[int][]SpouseWithMemberInfo {
+	spouseMap := make(map[int][]SpouseWithMemberInfo)
+	for _, spouse := range s.Spouses {
+		spouseMap[spouse.FatherID] = append(spouseMap[spouse.FatherID], spouse.WithMember(spouse.MotherID))
+		spouseMap[spouse.MotherID] = append(spouseMap[spouse.MotherID], spouse.WithMember(spouse.FatherID))
+	}
+	return spouseMap
+}
+
+// FamilyUnits rebuilds the family units of the state from its spouse
+// relationships and parent links. Units that still exist keep their ID and
+// the non biological children linked to them, the ones that don't get a
+// negative ID. Manual units are kept with the partners and children that
+// existed, their own history isn't replayed.
+func (s *TreeState) FamilyUnits(current []*FamilyUnit) []*FamilyUnit {
+	unitBySpouse := make(map[int]*FamilyUnit)
+	unitByParents := make(map[[2]int]*FamilyUnit)
+	var manual []*FamilyUnit
+	for _, unit := range current {
+		switch {
+		case unit.Source == FamilyUnitSourceManual:
+			manual = append(manual, unit)
+		case unit.SourceSpouseID != nil:
+			unitBySpouse[*unit.SourceSpouseID] = unit
+		case unit.Source == FamilyUnitSourceParents:
+			unitByParents[parentsKey(unit.PartnerIDs...)] = unit
+		}
+	}
+
+	exists := make(map[int]bool, len(s.Members))
+	for _, member := range s.Members {
+		exists[member.MemberID] = true
+	}
+
+	nextID := -1
+	units := make([]*FamilyUnit, 0, len(s.Spouses)+len(manual))
+	unitOfPair := make(map[[2]int]*FamilyUnit)
+	newUnit := func(template *FamilyUnit) *FamilyUnit {
+		unit := &FamilyUnit{TreeID: s.TreeID, ChildRelations: map[int]string{}}
+		if template != nil {
+			unit.FamilyUnitID = template.FamilyUnitID
+			for childID, relation := range template.ChildRelations {
+				if relation != ChildRelationBiological && exists[childID] {
+					unit.ChildIDs = append(unit.ChildIDs, childID)
+					unit.ChildRelations[childID] = relation
+				}
+			}
+		} else {
+			unit.FamilyUnitID = nextID
+			nextID--
+		}
+		units = append(units, unit)
+		return unit
+	}
+
+	for _, spouse := range s.Spouses {
+		unit := newUnit(unitBySpouse[spouse.SpouseID])
+		spouseID := spouse.SpouseID
+		unit.Source = FamilyUnitSourceSpouse
+		unit.SourceSpouseID = &spouseID
+		unit.RelationshipType = RelationshipTypeMarriage
+		unit.Status = FamilyStatusActive
+		if spouse.DivorceDate != nil {
+			unit.Status = FamilyStatusDivorced
+		}
+		unit.StartDate = spouse.MarriageDate
+		unit.EndDate = spouse.DivorceDate
+		unit.PartnerIDs = []int{spouse.FatherID, spouse.MotherID}
+		unitOfPair[parentsKey(spouse.FatherID, spouse.MotherID)] = unit
+	}
+
+	for _, member := range s.Members {
+		if member.FatherID == nil && member.MotherID == nil {
+			continue
+		}
+		var parentIDs []int
+		for _, parentID := range []*int{member.FatherID, member.MotherID} {
+			if parentID != nil {
+				parentIDs = append(parentIDs, *parentID)
+			}
+		}
+		pair := parentsKey(parentIDs...)
+		unit, ok := unitOfPair[pair]
+		if !ok {
+			unit = newUnit(unitByParents[pair])
+			unit.Source = FamilyUnitSourceParents
+			unit.RelationshipType = RelationshipTypeUnknown
+			unit.Status = FamilyStatusUnknown
+			unit.PartnerIDs = parentIDs
+			unitOfPair[pair] = unit
+		}
+		if !slices.Contains(unit.ChildIDs, member.MemberID) {
+			unit.ChildIDs = append(unit.ChildIDs, member.MemberID)
+		}
+		unit.ChildRelations[member.MemberID] = ChildRelationBiological
+	}
+
+	for _, template := range manual {
+		unit := *template
+		unit.PartnerIDs = slices.DeleteFunc(slices.Clone(template.PartnerIDs), func(id int) bool { return !exists[id] })
+		unit.ChildIDs = slices.DeleteFunc(slices.Clone(template.ChildIDs), func(id int) bool { return !exists[id] })
+		if len(unit.PartnerIDs) > 0 {
+			units = append(units, &unit)
+		}
+	}
+
+	for _, unit := range units {
+		sort.Ints(unit.ChildIDs)
+	}
+	return units
+}
+
+// parentsKey identifies a couple or a single parent whatever the order of the IDs
+func parentsKey(partnerIDs ...int) [2]int {
+	key := [2]int{}
+	copy(key[:], partnerIDs)
+	if key[1] != 0 && key[1] < key[0] {
+		key[0], key[1] = key[1], key[0]
+	}
+	return key
+}
+
+// RestorePlan is what has to change for a tree, or the descendants of one of
+// its members, to be as it was in a past state
+type RestorePlan struct {
+	Restore        []*Member // deleted since, parents first
+	Update         []*Member // edited since, as they were, parents first
+	Delete         []*Member // added since, children first
+	RestoreSpouses []*Spouse // removed since
+	UpdateSpouses  []*Spouse // edited since, as they were, applied after the restores
+	RemoveSpouses  []*Spouse // added since
+}
+
+// AsOfRestore is what restoring a tree to a point in time changed and the
+// changes it had to skip
+type AsOfRestore struct {
+	MembersRestored int
+	MembersUpdated  int
+	MembersDeleted  int
+	SpousesRestored int
+	SpousesUpdated  int
+	SpousesRemoved  int
+	Skipped         []AsOfRestoreSkip
+}
+
+// AsOfRestoreSkip is a member or spouse relationship that couldn't be put
+// back, with the error that stopped it
+type AsOfRestoreSkip struct {
+	MemberID *int
+	SpouseID *int
+	Err      *DomainError
+}
+
+// PlanRestore compares the state with the tree as it is now, its live
+// members and all its spouse relationships including deleted ones. A root
+// limits the plan to the root and its descendants then or now, and to the
+// spouse relationships they are part of.
+func (s *TreeState) PlanRestore(current []*Member, spouses []*Spouse, rootID *int) *RestorePlan {
+	var inScope func(memberID int) bool
+	if rootID == nil {
+		inScope = func(int) bool { return true }
+	} else {
+		descendants := DescendantsOf(*rootID, s.Members, current)
+		inScope = func(memberID int) bool { return descendants[memberID] }
+	}
+	spouseInScope := func(spouse *Spouse) bool {
+		return inScope(spouse.FatherID) || inScope(spouse.MotherID)
+	}
+	return s.planRestore(current, spouses, inScope, spouseInScope)
+}
+
+// PlanRevert compares the state with the tree as it is now like PlanRestore,
+// limited to the members and spouse relationships a changeset touched
+func (s *TreeState) PlanRevert(current []*Member, spouses []*Spouse, memberIDs, spouseIDs map[int]bool) *RestorePlan {
+	return s.planRestore(current, spouses,
+		func(memberID int) bool { return memberIDs[memberID] },
+		func(spouse *Spouse) bool { return spouseIDs[spouse.SpouseID] },
+	)
+}
+
+func (s *TreeState) planRestore(current []*Member, spouses []*Spouse, inScope func(memberID int) bool, spouseInScope func(spouse *Spouse) bool) *RestorePlan {
+	currentByID := make(map[int]*Member, len(current))
+	for _, member := range current {
+		currentByID[member.MemberID] = member
+	}
+	pastByID := make(map[int]*Member, len(s.Members))
+	for _, member := range s.Members {
+		pastByID[member.MemberID] = member
+	}
+
+	plan := &RestorePlan{}
+	for _, member := range s.Members {
+		if !inScope(member.MemberID) {
+			continue
+		}
+		now, ok := currentByID[member.MemberID]
+		switch {
+		case !ok:
+			plan.Restore = append(plan.Restore, member)
+		case now.Version != member.Version || !samePicture(now.Picture, member.Picture):
+			plan.Update = append(plan.Update, member)
+		}
+	}
+	for _, member := range current {
+		if _, ok := pastByID[member.MemberID]; !ok && inScope(member.MemberID) {
+			plan.Delete = append(plan.Delete, member)
+		}
+	}
+
+	depth := generationDepths(s.Members, current)
+	byDepth := func(members []*Member, parentsFirst bool) {
+		sort.SliceStable(members, func(i, j int) bool {
+			if parentsFirst {
+				return depth[members[i].MemberID] < depth[members[j].MemberID]
+			}
+			return depth[members[i].MemberID] > depth[members[j].MemberID]
+		})
+	}
+	byDepth(plan.Restore, true)
+	byDepth(plan.Update, true)
+	byDepth(plan.Delete, false)
+
+	pastSpouses := make(map[int]*Spouse, len(s.Spouses))
+	for _, spouse := range s.Spouses {
+		pastSpouses[spouse.SpouseID] = spouse
+	}
+	liveSpouses := make(map[int]*Spouse, len(spouses))
+	deletedSpouses := make(map[int]*Spouse)
+	for _, spouse := range spouses {
+		if spouse.DeletedAt == nil {
+			liveSpouses[spouse.SpouseID] = spouse
+		} else {
+			deletedSpouses[spouse.SpouseID] = spouse
+		}
+	}
+	for _, spouse := range s.Spouses {
+		if !spouseInScope(spouse) {
+			continue
+		}
+		now, ok := liveSpouses[spouse.SpouseID]
+		switch {
+		case !ok:
+			plan.RestoreSpouses = append(plan.RestoreSpouses, spouse)
+			// A relationship comes back as it was deleted, edits made before
+			// that but after the state are undone next
+			if deleted, ok := deletedSpouses[spouse.SpouseID]; ok && !deleted.SameValues(spouse) {
+				plan.UpdateSpouses = append(plan.UpdateSpouses, spouse)
+			}
+		case !now.SameValues(spouse):
+			plan.UpdateSpouses = append(plan.UpdateSpouses, spouse)
+		}
+	}
+	for _, spouse := range spouses {
+		if _, ok := pastSpouses[spouse.SpouseID]; !ok && spouse.DeletedAt == nil && spouseInScope(spouse) {
+			plan.RemoveSpouses = append(plan.RemoveSpouses, spouse)
+		}
+	}
+
+	return plan
+}
+
+// generationDepths numbers the generations of the members through the parent
+// links of any of the member lists, a member without known parents is 0
+func generationDepths(memberLists ...[]*Member) map[int]int {
+	parents := make(map[int][]int)
+	for _, members := range memberLists {
+		for _, member := range members {
+			for _, parentID := range []*int{member.FatherID, member.MotherID} {
+				if parentID != nil && !slices.Contains(parents[member.MemberID], *parentID) {
+					parents[member.MemberID] = append(parents[member.MemberID], *parentID)
+				}
+			}
+		}
+	}
+
+	depth := make(map[int]int)
+	visiting := make(map[int]bool)
+	var walk func(memberID int) int
+	walk = func(memberID int) int {
+		if d, ok := depth[memberID]; ok {
+			return d
+		}
+		if visiting[memberID] {
+			return 0
+		}
+		visiting[memberID] = true
+		d := 0
+		for _, parentID := range parents[memberID] {
+			d = max(d, walk(parentID)+1)
+		}
+		depth[memberID] = d
+		return d
+	}
+	for memberID := range parents {
+		walk(memberID)
+	}
+	return depth
+}
+
+func samePicture(left, right *string) bool {
+	value := func(picture *string) string {
+		if picture == nil {
+			return ""
+		}
+		return *picture
+	}
+	return value(left) == value(right)
+}
+
+func sameTime(left, right *time.Time) bool {
+	if left == nil || right == nil {
+		return left == nil && right == nil
+	}
+	return left.Equal(*right)
+}
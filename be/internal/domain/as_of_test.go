@@ -0,0 +1,180 @@
+package domain
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+)
+
+// changesetTree is a tree after a changeset that edited member 1, added its
+// child 2, deleted member 4 with its marriage 11 and married 1 to 3 as 10
+type changesetTree struct {
+	current []*Member
+	spouses []*Spouse
+	history []*History
+	before  time.Time
+}
+
+func newChangesetTree() changesetTree {
+	start := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+	values := func(value any) json.RawMessage {
+		data, _ := json.Marshal(value)
+		return data
+	}
+	parentID := 1
+
+	deletedAt := at(3)
+	return changesetTree{
+		current: []*Member{
+			{MemberID: 1, TreeID: 1, Version: 2, Names: map[string]string{"en": "New"}},
+			{MemberID: 2, TreeID: 1, Version: 1, Names: map[string]string{"en": "Child"}, FatherID: &parentID},
+			{MemberID: 3, TreeID: 1, Version: 1, Names: map[string]string{"en": "Three"}},
+		},
+		spouses: []*Spouse{
+			{SpouseID: 10, FatherID: 1, MotherID: 3},
+			{SpouseID: 11, FatherID: 4, MotherID: 3, DeletedAt: &deletedAt},
+		},
+		history: []*History{
+			{HistoryID: 1, MemberID: 1, ChangedAt: at(1), ChangeType: ChangeTypeUpdate,
+				OldValues: values(Member{Version: 1, Names: map[string]string{"en": "Old"}})},
+			{HistoryID: 2, MemberID: 2, ChangedAt: at(2), ChangeType: ChangeTypeInsert},
+			{HistoryID: 3, MemberID: 4, ChangedAt: at(3), ChangeType: ChangeTypeDelete,
+				OldValues: values(Member{Version: 1, Names: map[string]string{"en": "Four"}})},
+			{HistoryID: 4, MemberID: 1, ChangedAt: at(4), ChangeType: ChangeTypeAddSpouse,
+				NewValues: values(Spouse{SpouseID: 10, FatherID: 1, MotherID: 3})},
+		},
+		before: start,
+	}
+}
+
+func memberIDs(members []*Member) []int {
+	ids := []int{}
+	for _, member := range members {
+		ids = append(ids, member.MemberID)
+	}
+	return ids
+}
+
+func spouseIDs(spouses []*Spouse) []int {
+	ids := []int{}
+	for _, spouse := range spouses {
+		ids = append(ids, spouse.SpouseID)
+	}
+	return ids
+}
+
+func TestRewindHistory(t *testing.T) {
+	tree := newChangesetTree()
+
+	tests := []struct {
+		name        string
+		asOf        time.Time
+		wantMembers []int
+		wantSpouses []int
+		wantName    string
+	}{
+		{"before the changeset", tree.before, []int{1, 3, 4}, []int{11}, "Old"},
+		{"after the update", tree.before.Add(1500 * time.Millisecond), []int{1, 3, 4}, []int{11}, "New"},
+		{"after the delete", tree.before.Add(3 * time.Second), []int{1, 2, 3}, []int{}, "New"},
+		{"now", tree.before.Add(time.Minute), []int{1, 2, 3}, []int{10}, "New"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := RewindHistory(1, tree.current, tree.spouses, tree.history, tt.asOf)
+
+			if got := memberIDs(state.Members); !slices.Equal(got, tt.wantMembers) {
+				t.Errorf("members = %v, want %v", got, tt.wantMembers)
+			}
+			if got := spouseIDs(state.Spouses); !slices.Equal(got, tt.wantSpouses) {
+				t.Errorf("spouses = %v, want %v", got, tt.wantSpouses)
+			}
+			if member, ok := state.Member(1); !ok || member.Names["en"] != tt.wantName {
+				t.Errorf("member 1 = %+v, want named %s", member, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestPlanRevert(t *testing.T) {
+	tree := newChangesetTree()
+	state := RewindHistory(1, tree.current, tree.spouses, tree.history, tree.before)
+
+	tests := []struct {
+		name              string
+		memberIDs         map[int]bool
+		spouseIDs         map[int]bool
+		wantRestore       []int
+		wantUpdate        []int
+		wantDelete        []int
+		wantRestoreSpouse []int
+		wantRemoveSpouse  []int
+	}{
+		{
+			name:              "whole changeset",
+			memberIDs:         map[int]bool{1: true, 2: true, 4: true},
+			spouseIDs:         map[int]bool{10: true, 11: true},
+			wantRestore:       []int{4},
+			wantUpdate:        []int{1},
+			wantDelete:        []int{2},
+			wantRestoreSpouse: []int{11},
+			wantRemoveSpouse:  []int{10},
+		},
+		{
+			name:              "untouched members stay",
+			memberIDs:         map[int]bool{1: true},
+			spouseIDs:         map[int]bool{},
+			wantRestore:       []int{},
+			wantUpdate:        []int{1},
+			wantDelete:        []int{},
+			wantRestoreSpouse: []int{},
+			wantRemoveSpouse:  []int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := state.PlanRevert(tree.current, tree.spouses, tt.memberIDs, tt.spouseIDs)
+
+			for _, check := range []struct {
+				part      string
+				got, want []int
+			}{
+				{"restore", memberIDs(plan.Restore), tt.wantRestore},
+				{"update", memberIDs(plan.Update), tt.wantUpdate},
+				{"delete", memberIDs(plan.Delete), tt.wantDelete},
+				{"restore spouses", spouseIDs(plan.RestoreSpouses), tt.wantRestoreSpouse},
+				{"remove spouses", spouseIDs(plan.RemoveSpouses), tt.wantRemoveSpouse},
+			} {
+				if !slices.Equal(check.got, check.want) {
+					t.Errorf("%s = %v, want %v", check.part, check.got, check.want)
+				}
+			}
+			if len(plan.UpdateSpouses) != 0 {
+				t.Errorf("update spouses = %v, want none", spouseIDs(plan.UpdateSpouses))
+			}
+		})
+	}
+}
+
+func TestPlanRevertParentsFirst(t *testing.T) {
+	parentID, childID := 1, 2
+	state := &TreeState{TreeID: 1, Members: []*Member{
+		{MemberID: childID, Version: 1, FatherID: &parentID},
+		{MemberID: parentID, Version: 1},
+		{MemberID: 3, Version: 1, FatherID: &childID},
+	}}
+	scope := map[int]bool{1: true, 2: true, 3: true}
+
+	plan := state.PlanRevert(nil, nil, scope, nil)
+	if got := memberIDs(plan.Restore); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("restore = %v, want parents first [1 2 3]", got)
+	}
+
+	plan = (&TreeState{TreeID: 1}).PlanRevert(state.Members, nil, scope, nil)
+	if got := memberIDs(plan.Delete); !slices.Equal(got, []int{3, 2, 1}) {
+		t.Errorf("delete = %v, want children first [3 2 1]", got)
+	}
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditActionLogin       = "auth.login"
+	AuditActionLoginFailed = "auth.login_failed"
+	AuditActionLogout      = "auth.logout"
+	AuditActionLogoutAll   = "auth.logout_all"
+
+	AuditActionInvitationSent     = "invitation.sent"
+	AuditActionInvitationAccepted = "invitation.accepted"
+	AuditActionInvitationDeclined = "invitation.declined"
+
+	AuditActionMembershipRoleChanged = "membership.role_changed"
+	AuditActionMembershipRemoved     = "membership.removed"
+	AuditActionMembershipLeft        = "membership.left"
+	AuditActionOwnershipTransferred  = "membership.ownership_transferred"
+
+	AuditActionShareLinkCreated = "share_link.created"
+	AuditActionShareLinkUpdated = "share_link.updated"
+	AuditActionShareLinkRevoked = "share_link.revoked"
+	AuditActionShareLinkVisited = "share_link.visited"
+
+	AuditActionLanguageToggled = "language.toggled"
+
+	AuditActionUserActivated   = "user.activated"
+	AuditActionUserDeactivated = "user.deactivated"
+	AuditActionUserRoleChanged = "user.role_changed"
+)
+
+const (
+	AuditTargetUser       = "user"
+	AuditTargetInvitation = "invitation"
+	AuditTargetMembership = "membership"
+	AuditTargetShareLink  = "share_link"
+	AuditTargetLanguage   = "language"
+)
+
+// AuditEvent is a security relevant action, the request it came from is
+// recorded with it
+type AuditEvent struct {
+	AuditID     int64
+	ActorUserID *int // nil for anonymous visitors and failed logins
+	ActorName   *string
+	Action      string
+	TargetType  string
+	TargetID    *string
+	TreeID      *int
+	Details     json.RawMessage
+	IPAddress   string
+	UserAgent   string
+	RequestID   string
+	CreatedAt   time.Time
+}
+
+type AuditFilter struct {
+	TreeID      *int
+	ActorUserID *int
+	Action      *string
+	TargetType  *string
+	TargetID    *string
+	From        *time.Time
+	To          *time.Time
+}
+
+// RequestInfo is where a request came from, it travels in the request context
+// so use cases can record it
+type RequestInfo struct {
+	IPAddress string
+	UserAgent string
+	RequestID string
+}
+
+type requestInfoKey struct{}
+
+func ContextWithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFromContext returns the request info of ctx, empty outside a request
+func RequestInfoFromContext(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "strings"
+
+// History and score field names for the long-form texts of a member. A
+// biography is kept per language, so its field carries the language code
+// the same way the client shows names
+const (
+	NotesField           = "notes"
+	biographyFieldPrefix = "biography_"
+)
+
+func BiographyField(languageCode string) string {
+	return biographyFieldPrefix + languageCode
+}
+
+// BiographyLanguage returns the language of a biography field name
+func BiographyLanguage(field string) (string, bool) {
+	languageCode, ok := strings.CutPrefix(field, biographyFieldPrefix)
+	return languageCode, ok && languageCode != ""
+}
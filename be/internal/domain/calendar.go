@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+const (
+	CalendarEventBirthday            = "birthday"
+	CalendarEventRemembrance         = "remembrance"
+	CalendarEventMarriageAnniversary = "marriage_anniversary"
+)
+
+type CalendarEvent struct {
+	UID          string            `json:"uid"`
+	EventType    string            `json:"event_type"`
+	Date         time.Time         `json:"date"`
+	MemberID     int               `json:"member_id"`
+	MemberNames  map[string]string `json:"member_names"`
+	PartnerID    *int              `json:"partner_id,omitempty"`
+	PartnerNames map[string]string `json:"partner_names,omitempty"`
+}
+
+// CalendarFeedContent is everything needed to render a feed for its subscriber
+type CalendarFeedContent struct {
+	Feed     *FamilyTreeCalendarFeed
+	TreeName string
+	Language string
+	Events   []*CalendarEvent
+}
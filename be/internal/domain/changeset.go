@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Changeset groups the history and score rows written by one request or by an
+// explicit batch of requests. The summary fields are computed from its rows.
+type Changeset struct {
+	ChangesetID  string     `json:"changeset_id"`
+	TreeID       int        `json:"tree_id"`
+	UserID       int        `json:"user_id"`
+	UserFullName string     `json:"user_full_name"`
+	Description  *string    `json:"description,omitempty"`
+	ChangeTypes  []string   `json:"change_types"`
+	MemberIDs    []int      `json:"member_ids"`
+	EntryCount   int        `json:"entry_count"`
+	Points       int        `json:"points"`
+	CreatedAt    time.Time  `json:"created_at"`
+	LastChangeAt *time.Time `json:"last_change_at,omitempty"`
+	RevertedAt   *time.Time `json:"reverted_at,omitempty"`
+	RevertedBy   *int       `json:"reverted_by,omitempty"`
+	RevertedIn   *string    `json:"reverted_in,omitempty"`
+}
+
+type changesetKey struct{}
+
+// ContextWithChangeset makes the history and score rows written with ctx part
+// of the changeset
+func ContextWithChangeset(ctx context.Context, changesetID string) context.Context {
+	return context.WithValue(ctx, changesetKey{}, changesetID)
+}
+
+// ChangesetFromContext returns the changeset of ctx, nil outside a request
+func ChangesetFromContext(ctx context.Context) *string {
+	changesetID, ok := ctx.Value(changesetKey{}).(string)
+	if !ok || changesetID == "" {
+		return nil
+	}
+	return &changesetID
+}
@@ -18,4 +18,6 @@ type MemberFilter struct {
 	EnglishName *string
 	Gender      *string
 	IsMarried   *bool
+	CustomField *string // field_key the custom value search is limited to
+	CustomValue *string
 }
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"regexp"
+	"slices"
+	"time"
+)
+
+const (
+	CustomFieldTypeText   = "text"
+	CustomFieldTypeNumber = "number"
+	CustomFieldTypeDate   = "date"
+	CustomFieldTypeEnum   = "enum"
+	CustomFieldTypeMember = "member"
+
+	customFieldPrefix = "custom_"
+)
+
+var customFieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// CustomField is a tree specific member attribute defined by the tree owner.
+// The key is fixed once created and names the value in member data
+type CustomField struct {
+	FieldID      int               `json:"field_id"`
+	TreeID       int               `json:"tree_id"`
+	Key          string            `json:"key"`
+	Names        map[string]string `json:"names"` // language_code -> name
+	Type         string            `json:"type"`
+	Options      []string          `json:"options"` // allowed values of an enum field
+	IsRequired   bool              `json:"is_required"`
+	DisplayOrder int               `json:"display_order"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+func IsValidCustomFieldKey(key string) bool {
+	return customFieldKeyPattern.MatchString(key)
+}
+
+func IsValidCustomFieldType(fieldType string) bool {
+	switch fieldType {
+	case CustomFieldTypeText, CustomFieldTypeNumber, CustomFieldTypeDate, CustomFieldTypeEnum, CustomFieldTypeMember:
+		return true
+	}
+	return false
+}
+
+func (f *CustomField) HasOption(value string) bool {
+	return slices.Contains(f.Options, value)
+}
+
+// CustomFieldName is the history and score field name of a custom value
+func CustomFieldName(key string) string {
+	return customFieldPrefix + key
+}
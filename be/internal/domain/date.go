@@ -0,0 +1,172 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/escalopa/family-tree/internal/pkg/hijri"
+)
+
+const (
+	DateQualifierExact   = "exact"
+	DateQualifierAbout   = "about"
+	DateQualifierBefore  = "before"
+	DateQualifierAfter   = "after"
+	DateQualifierBetween = "between"
+	DateQualifierYear    = "year"
+	DateQualifierMonth   = "month"
+
+	CalendarGregorian = "gregorian"
+	CalendarHijri     = "hijri"
+
+	// DateAboutMarginYears is how far either side of the given date an "about" date may fall
+	DateAboutMarginYears = 5
+)
+
+// DateRange is the span of days a genealogical date may fall on, a nil bound is open-ended
+type DateRange struct {
+	Earliest *time.Time
+	Latest   *time.Time
+}
+
+// NormalizeCalendar validates the calendar a date was entered in, an unknown
+// date is always recorded as gregorian
+func NormalizeCalendar(calendar string, date *time.Time) (string, error) {
+	if date == nil {
+		return CalendarGregorian, nil
+	}
+	switch calendar {
+	case "", CalendarGregorian:
+		return CalendarGregorian, nil
+	case CalendarHijri:
+		return CalendarHijri, nil
+	default:
+		return "", NewValidationError("error.date.invalid_calendar")
+	}
+}
+
+// NormalizeDate validates a qualified date and fills the stored range: the
+// date itself is the start used for sorting, end is only kept when the
+// qualifier describes a closed span. Dates are always Gregorian, the calendar
+// only decides which year or month a year or month qualifier spans
+func NormalizeDate(date *time.Time, qualifier string, end *time.Time, calendar string) (*time.Time, string, *time.Time, error) {
+	if qualifier == "" {
+		qualifier = DateQualifierExact
+	}
+	if date == nil {
+		if end != nil {
+			return nil, "", nil, NewValidationError("error.date.start_required")
+		}
+		return nil, DateQualifierExact, nil, nil
+	}
+
+	start := truncateDay(*date)
+	switch qualifier {
+	case DateQualifierExact, DateQualifierAbout, DateQualifierBefore, DateQualifierAfter:
+		return &start, qualifier, nil, nil
+	case DateQualifierYear, DateQualifierMonth:
+		if calendar == CalendarHijri {
+			return hijriSpan(start, qualifier)
+		}
+		return gregorianSpan(start, qualifier)
+	case DateQualifierBetween:
+		if end == nil {
+			return nil, "", nil, NewValidationError("error.date.end_required")
+		}
+		last := truncateDay(*end)
+		if last.Before(start) {
+			return nil, "", nil, NewValidationError("error.date.invalid_range")
+		}
+		return &start, qualifier, &last, nil
+	default:
+		return nil, "", nil, NewValidationError("error.date.invalid_qualifier")
+	}
+}
+
+// gregorianSpan returns the Gregorian year or month containing the day
+func gregorianSpan(day time.Time, qualifier string) (*time.Time, string, *time.Time, error) {
+	start := time.Date(day.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(day.Year(), time.December, 31, 0, 0, 0, 0, time.UTC)
+	if qualifier == DateQualifierMonth {
+		start = time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+		end = start.AddDate(0, 1, -1)
+	}
+	return &start, qualifier, &end, nil
+}
+
+// hijriSpan returns the Hijri year or month containing the day
+func hijriSpan(day time.Time, qualifier string) (*time.Time, string, *time.Time, error) {
+	date, ok := hijri.FromGregorian(day)
+	if !ok {
+		return nil, "", nil, NewValidationError("error.date.invalid_hijri")
+	}
+
+	first, last := hijri.StartOfYear(date.Year), hijri.EndOfYear(date.Year)
+	if qualifier == DateQualifierMonth {
+		first, last = hijri.Date{Year: date.Year, Month: date.Month, Day: 1}, date.EndOfMonth()
+	}
+	start, end := first.ToGregorian(), last.ToGregorian()
+	return &start, qualifier, &end, nil
+}
+
+// NewDateRange returns the range a stored date covers, or nil when the date is unknown
+func NewDateRange(date *time.Time, qualifier string, end *time.Time) *DateRange {
+	if date == nil {
+		return nil
+	}
+	start := truncateDay(*date)
+
+	switch qualifier {
+	case DateQualifierAbout:
+		earliest := start.AddDate(-DateAboutMarginYears, 0, 0)
+		latest := start.AddDate(DateAboutMarginYears, 0, 0)
+		return &DateRange{Earliest: &earliest, Latest: &latest}
+	case DateQualifierBefore:
+		latest := start.AddDate(0, 0, -1)
+		return &DateRange{Latest: &latest}
+	case DateQualifierAfter:
+		earliest := start.AddDate(0, 0, 1)
+		return &DateRange{Earliest: &earliest}
+	case DateQualifierBetween, DateQualifierYear, DateQualifierMonth:
+		if end == nil {
+			return &DateRange{Earliest: &start}
+		}
+		latest := truncateDay(*end)
+		return &DateRange{Earliest: &start, Latest: &latest}
+	default:
+		return &DateRange{Earliest: &start, Latest: &start}
+	}
+}
+
+// IsExact reports whether the range pins down a single day, an unknown date is never exact
+func (r *DateRange) IsExact() bool {
+	return r != nil && r.Earliest != nil && r.Latest != nil && r.Earliest.Equal(*r.Latest)
+}
+
+// CanBeBefore reports whether some day in r may fall strictly before some day in other
+func (r *DateRange) CanBeBefore(other *DateRange) bool {
+	if r.Earliest == nil || other.Latest == nil {
+		return true
+	}
+	return r.Earliest.Before(*other.Latest)
+}
+
+// IsDefinitelyBefore reports whether every day in r falls strictly before every day in other
+func (r *DateRange) IsDefinitelyBefore(other *DateRange) bool {
+	if r.Latest == nil || other.Earliest == nil {
+		return false
+	}
+	return r.Latest.Before(*other.Earliest)
+}
+
+// YearsBetween returns the number of whole years elapsed from one day to another
+func YearsBetween(from, to time.Time) int {
+	years := to.Year() - from.Year()
+	if to.Month() < from.Month() || (to.Month() == from.Month() && to.Day() < from.Day()) {
+		years--
+	}
+	return years
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
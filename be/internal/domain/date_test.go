@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// sameDay reports whether two optional days are both unset or the same day
+func sameDay(got, want *time.Time) bool {
+	if got == nil || want == nil {
+		return got == want
+	}
+	return got.Equal(*want)
+}
+
+func TestNormalizeDate(t *testing.T) {
+	evening := time.Date(1990, time.March, 14, 18, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		date          *time.Time
+		qualifier     string
+		end           *time.Time
+		calendar      string
+		wantDate      *time.Time
+		wantQualifier string
+		wantEnd       *time.Time
+		wantErr       string
+	}{
+		{name: "unknown date", wantQualifier: DateQualifierExact},
+		{name: "end without date", end: date(1990, time.March, 14), wantErr: "error.date.start_required"},
+		{name: "exact by default", date: &evening, wantDate: date(1990, time.March, 14), wantQualifier: DateQualifierExact},
+		{name: "about drops end", date: date(1990, time.March, 14), qualifier: DateQualifierAbout, end: date(1991, time.March, 14),
+			wantDate: date(1990, time.March, 14), wantQualifier: DateQualifierAbout},
+		{name: "gregorian year", date: date(1990, time.March, 14), qualifier: DateQualifierYear,
+			wantDate: date(1990, time.January, 1), wantQualifier: DateQualifierYear, wantEnd: date(1990, time.December, 31)},
+		{name: "gregorian leap month", date: date(2020, time.February, 10), qualifier: DateQualifierMonth,
+			wantDate: date(2020, time.February, 1), wantQualifier: DateQualifierMonth, wantEnd: date(2020, time.February, 29)},
+		{name: "hijri year", date: date(2024, time.January, 1), qualifier: DateQualifierYear, calendar: CalendarHijri,
+			wantDate: date(2023, time.July, 19), wantQualifier: DateQualifierYear, wantEnd: date(2024, time.July, 7)},
+		{name: "hijri month", date: date(2000, time.January, 1), qualifier: DateQualifierMonth, calendar: CalendarHijri,
+			wantDate: date(1999, time.December, 9), wantQualifier: DateQualifierMonth, wantEnd: date(2000, time.January, 7)},
+		{name: "before the hijri epoch", date: date(600, time.January, 1), qualifier: DateQualifierYear, calendar: CalendarHijri,
+			wantErr: "error.date.invalid_hijri"},
+		{name: "between", date: date(1990, time.March, 14), qualifier: DateQualifierBetween, end: date(1992, time.June, 1),
+			wantDate: date(1990, time.March, 14), wantQualifier: DateQualifierBetween, wantEnd: date(1992, time.June, 1)},
+		{name: "between without end", date: date(1990, time.March, 14), qualifier: DateQualifierBetween, wantErr: "error.date.end_required"},
+		{name: "between reversed", date: date(1992, time.June, 1), qualifier: DateQualifierBetween, end: date(1990, time.March, 14),
+			wantErr: "error.date.invalid_range"},
+		{name: "unknown qualifier", date: date(1990, time.March, 14), qualifier: "circa", wantErr: "error.date.invalid_qualifier"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotDate, gotQualifier, gotEnd, err := NormalizeDate(tt.date, tt.qualifier, tt.end, tt.calendar)
+			if tt.wantErr != "" {
+				var domainErr *DomainError
+				if !errors.As(err, &domainErr) || domainErr.TranslationKey != tt.wantErr {
+					t.Fatalf("error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if !sameDay(gotDate, tt.wantDate) {
+				t.Errorf("date = %v, want %v", gotDate, tt.wantDate)
+			}
+			if gotQualifier != tt.wantQualifier {
+				t.Errorf("qualifier = %q, want %q", gotQualifier, tt.wantQualifier)
+			}
+			if !sameDay(gotEnd, tt.wantEnd) {
+				t.Errorf("end = %v, want %v", gotEnd, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestNewDateRange(t *testing.T) {
+	day := date(1990, time.March, 14)
+
+	tests := []struct {
+		name         string
+		date         *time.Time
+		qualifier    string
+		end          *time.Time
+		wantNil      bool
+		wantEarliest *time.Time
+		wantLatest   *time.Time
+		wantExact    bool
+	}{
+		{name: "unknown", wantNil: true},
+		{name: "exact", date: day, qualifier: DateQualifierExact, wantEarliest: day, wantLatest: day, wantExact: true},
+		{name: "about", date: day, qualifier: DateQualifierAbout, wantEarliest: date(1985, time.March, 14), wantLatest: date(1995, time.March, 14)},
+		{name: "before", date: day, qualifier: DateQualifierBefore, wantLatest: date(1990, time.March, 13)},
+		{name: "after", date: day, qualifier: DateQualifierAfter, wantEarliest: date(1990, time.March, 15)},
+		{name: "between", date: day, qualifier: DateQualifierBetween, end: date(1991, time.May, 2), wantEarliest: day, wantLatest: date(1991, time.May, 2)},
+		{name: "year without end", date: date(1990, time.January, 1), qualifier: DateQualifierYear, wantEarliest: date(1990, time.January, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewDateRange(tt.date, tt.qualifier, tt.end)
+			if tt.wantNil {
+				if got != nil {
+					t.Fatalf("range = %+v, want nil", got)
+				}
+				return
+			}
+			if !sameDay(got.Earliest, tt.wantEarliest) {
+				t.Errorf("earliest = %v, want %v", got.Earliest, tt.wantEarliest)
+			}
+			if !sameDay(got.Latest, tt.wantLatest) {
+				t.Errorf("latest = %v, want %v", got.Latest, tt.wantLatest)
+			}
+			if got.IsExact() != tt.wantExact {
+				t.Errorf("IsExact() = %v, want %v", got.IsExact(), tt.wantExact)
+			}
+		})
+	}
+}
+
+func TestDateRangeOrder(t *testing.T) {
+	exact := NewDateRange(date(1990, time.March, 14), DateQualifierExact, nil)
+	about := NewDateRange(date(1992, time.March, 14), DateQualifierAbout, nil)
+	later := NewDateRange(date(2000, time.January, 1), DateQualifierExact, nil)
+	open := NewDateRange(date(1995, time.January, 1), DateQualifierAfter, nil)
+
+	tests := []struct {
+		name           string
+		left, right    *DateRange
+		wantCan        bool
+		wantDefinitely bool
+	}{
+		{"exact before later", exact, later, true, true},
+		{"later before exact", later, exact, false, false},
+		{"overlapping", about, exact, true, false},
+		{"open ended", open, exact, false, false},
+		{"before open ended", exact, open, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.left.CanBeBefore(tt.right); got != tt.wantCan {
+				t.Errorf("CanBeBefore() = %v, want %v", got, tt.wantCan)
+			}
+			if got := tt.left.IsDefinitelyBefore(tt.right); got != tt.wantDefinitely {
+				t.Errorf("IsDefinitelyBefore() = %v, want %v", got, tt.wantDefinitely)
+			}
+		})
+	}
+}
+
+func TestYearsBetween(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to *time.Time
+		want     int
+	}{
+		{"day before birthday", date(1990, time.March, 14), date(2020, time.March, 13), 29},
+		{"on birthday", date(1990, time.March, 14), date(2020, time.March, 14), 30},
+		{"leap day birth", date(2000, time.February, 29), date(2021, time.February, 28), 20},
+		{"leap day birth in march", date(2000, time.February, 29), date(2021, time.March, 1), 21},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := YearsBetween(*tt.from, *tt.to); got != tt.want {
+				t.Errorf("YearsBetween() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"sort"
+)
+
+// FieldChange is a field that differs between two versions of a member or
+// spouse relationship, the values are JSON and null when unset
+type FieldChange struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// MemberDiffSkip are the member fields that aren't edited directly and are
+// left out of diffs
+var MemberDiffSkip = []string{"member_id", "tree_id", "version", "deleted_at", "is_married", "picture", "suggested_names"}
+
+// SpouseDiffSkip are the spouse relationship fields left out of diffs
+var SpouseDiffSkip = []string{"spouse_id", "deleted_at"}
+
+// DiffMembers compares two versions of a member as the viewer sees them, both
+// are stripped by the rules the member before the change falls under so the
+// diff can't show a hidden value on either side. A nil privacy strips nothing.
+func DiffMembers(before, after *Member, privacy *Privacy) []FieldChange {
+	if privacy != nil {
+		member := before
+		if member == nil {
+			member = after
+		}
+		before, after = privacy.stripped(before, member), privacy.stripped(after, member)
+	}
+	return DiffFields(before, after, MemberDiffSkip...)
+}
+
+// DiffFields compares the JSON fields of two values of the same type, a nil
+// value has no fields. Unset and empty values are the same, fields listed in
+// skip are left out.
+func DiffFields(before, after any, skip ...string) []FieldChange {
+	beforeFields, afterFields := jsonFields(before), jsonFields(after)
+
+	keys := make([]string, 0, len(beforeFields)+len(afterFields))
+	for key := range beforeFields {
+		keys = append(keys, key)
+	}
+	for key := range afterFields {
+		if _, ok := beforeFields[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []FieldChange
+	for _, key := range keys {
+		if slices.Contains(skip, key) {
+			continue
+		}
+		beforeValue, afterValue := beforeFields[key], afterFields[key]
+		if sameJSON(beforeValue, afterValue) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: key, Before: orNull(beforeValue), After: orNull(afterValue)})
+	}
+	return changes
+}
+
+func jsonFields(value any) map[string]json.RawMessage {
+	fields := map[string]json.RawMessage{}
+	if value == nil || reflect.ValueOf(value).Kind() == reflect.Pointer && reflect.ValueOf(value).IsNil() {
+		return fields
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fields
+	}
+	_ = json.Unmarshal(data, &fields)
+	return fields
+}
+
+func sameJSON(left, right json.RawMessage) bool {
+	var leftValue, rightValue any
+	_ = json.Unmarshal(left, &leftValue)
+	_ = json.Unmarshal(right, &rightValue)
+	if isEmptyJSON(leftValue) && isEmptyJSON(rightValue) {
+		return true
+	}
+	return reflect.DeepEqual(leftValue, rightValue)
+}
+
+func isEmptyJSON(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
+
+func orNull(value json.RawMessage) json.RawMessage {
+	if len(bytes.TrimSpace(value)) == 0 {
+		return json.RawMessage("null")
+	}
+	return value
+}
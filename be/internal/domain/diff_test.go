@@ -0,0 +1,62 @@
+package domain
+
+import "testing"
+
+// withChanges changes the member's profession and nicknames
+func withChanges(member *Member) *Member {
+	profession := "Doctor"
+	member.Profession = &profession
+	member.Nicknames = []string{"Doc"}
+	return member
+}
+
+func TestDiffMembers(t *testing.T) {
+	hidden := PrivacyPolicy{Rules: []PrivacyRule{
+		{Field: PrivacyFieldProfession, Visibility: VisibilityHidden, Gender: "F"},
+		{Field: PrivacyFieldNames, Visibility: VisibilityHidden, Gender: "F"},
+	}}
+	viewer := NewPrivacy(hidden, Viewer{UserID: 1, TreeRole: TreeRoleViewer}, nil)
+
+	tests := []struct {
+		name    string
+		before  *Member
+		after   *Member
+		privacy *Privacy
+		want    []string
+	}{
+		{"no privacy", testMember(1, "F"), withChanges(testMember(1, "F")), nil, []string{"nicknames", "profession"}},
+		{"visible member", testMember(1, "M"), withChanges(testMember(1, "M")), viewer, []string{"nicknames", "profession"}},
+		{"hidden field", testMember(1, "F"), withChanges(testMember(1, "F")), viewer, []string{"nicknames"}},
+		{"new member", nil, testMember(1, "F"), viewer, []string{"custom_fields", "date_of_birth", "date_of_death", "gender", "nicknames", "birth_place_id"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := DiffMembers(tt.before, tt.after, tt.privacy)
+
+			got := map[string]bool{}
+			for _, change := range changes {
+				got[change.Field] = true
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("fields = %v, want %v", changes, tt.want)
+			}
+			for _, field := range tt.want {
+				if !got[field] {
+					t.Errorf("field %q missing from %v", field, changes)
+				}
+			}
+		})
+	}
+}
+
+func TestDiffMembersKeepsMembers(t *testing.T) {
+	before, after := testMember(1, "F"), withChanges(testMember(1, "F"))
+	privacy := NewPrivacy(PrivacyPolicy{Rules: []PrivacyRule{{Field: PrivacyFieldProfession, Visibility: VisibilityHidden}}}, Viewer{TreeRole: TreeRolePublic}, nil)
+
+	DiffMembers(before, after, privacy)
+
+	if before.Profession == nil || after.Profession == nil {
+		t.Errorf("DiffMembers stripped the members it was given")
+	}
+}
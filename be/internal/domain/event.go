@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+const (
+	EventTypeEducation       = "education"
+	EventTypeMigration       = "migration"
+	EventTypeMilitaryService = "military_service"
+	EventTypeHajj            = "hajj"
+	EventTypeResidence       = "residence"
+	EventTypeReligious       = "religious"
+	EventTypeCustom          = "custom"
+)
+
+// IsValidEventType reports whether the type is one of the known event types
+func IsValidEventType(eventType string) bool {
+	switch eventType {
+	case EventTypeEducation, EventTypeMigration, EventTypeMilitaryService, EventTypeHajj,
+		EventTypeResidence, EventTypeReligious, EventTypeCustom:
+		return true
+	}
+	return false
+}
+
+type MemberEvent struct {
+	EventID        int        `json:"event_id"`
+	MemberID       int        `json:"member_id"`
+	EventType      string     `json:"event_type"`
+	CustomType     *string    `json:"custom_type"`
+	Date           *time.Time `json:"date"`
+	DateQualifier  string     `json:"date_qualifier"`
+	DateEnd        *time.Time `json:"date_end"`
+	DateCalendar   string     `json:"date_calendar"`
+	PlaceID        *int       `json:"place_id"`
+	Description    *string    `json:"description"`
+	ParticipantIDs []int      `json:"participant_ids"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func (e *MemberEvent) DateRange() *DateRange {
+	return NewDateRange(e.Date, e.DateQualifier, e.DateEnd)
+}
@@ -1,17 +1,89 @@
 package domain
 
-import "time"
+import (
+	"slices"
+	"time"
+)
+
+const (
+	RelationshipTypeMarriage    = "marriage"
+	RelationshipTypePartnership = "partnership"
+	RelationshipTypeUnknown     = "unknown"
+
+	FamilyStatusActive    = "active"
+	FamilyStatusDivorced  = "divorced"
+	FamilyStatusSeparated = "separated"
+	FamilyStatusWidowed   = "widowed"
+	FamilyStatusUnknown   = "unknown"
+
+	ChildRelationBiological = "biological"
+	ChildRelationAdopted    = "adopted"
+	ChildRelationStep       = "step"
+	ChildRelationFoster     = "foster"
+	ChildRelationUnknown    = "unknown"
+
+	// FamilyUnitSourceSpouse units mirror a spouse record, FamilyUnitSourceParents
+	// units are built from the father and mother of their children and
+	// FamilyUnitSourceManual units are created through the family unit API
+	FamilyUnitSourceSpouse  = "spouse"
+	FamilyUnitSourceParents = "parents"
+	FamilyUnitSourceManual  = "manual"
+)
 
 type FamilyUnit struct {
-	FamilyUnitID     int        `json:"family_unit_id"`
-	TreeID           int        `json:"tree_id"`
-	RelationshipType string     `json:"relationship_type"`
-	Status           string     `json:"status"`
-	StartDate        *time.Time `json:"start_date"`
-	EndDate          *time.Time `json:"end_date"`
-	PartnerIDs       []int      `json:"partner_ids"`
-	ChildIDs         []int      `json:"child_ids"`
-	ChildRelations   map[int]string
+	FamilyUnitID     int            `json:"family_unit_id"`
+	TreeID           int            `json:"tree_id"`
+	RelationshipType string         `json:"relationship_type"`
+	Status           string         `json:"status"`
+	StartDate        *time.Time     `json:"start_date"`
+	EndDate          *time.Time     `json:"end_date"`
+	Source           string         `json:"source"`
+	SourceSpouseID   *int           `json:"source_spouse_id,omitempty"`
+	PartnerIDs       []int          `json:"partner_ids"`
+	ChildIDs         []int          `json:"child_ids"`
+	ChildRelations   map[int]string `json:"child_relations,omitempty"`
+}
+
+// FamilyUnitChild is the link of a child to a family unit, as recorded in history
+type FamilyUnitChild struct {
+	FamilyUnitID int    `json:"family_unit_id"`
+	ChildID      int    `json:"child_id"`
+	RelationType string `json:"relation_type"`
+}
+
+func IsValidRelationshipType(relationshipType string) bool {
+	switch relationshipType {
+	case RelationshipTypeMarriage, RelationshipTypePartnership, RelationshipTypeUnknown:
+		return true
+	}
+	return false
+}
+
+func IsValidFamilyStatus(status string) bool {
+	switch status {
+	case FamilyStatusActive, FamilyStatusDivorced, FamilyStatusSeparated, FamilyStatusWidowed, FamilyStatusUnknown:
+		return true
+	}
+	return false
+}
+
+func IsValidChildRelation(relationType string) bool {
+	switch relationType {
+	case ChildRelationBiological, ChildRelationAdopted, ChildRelationStep, ChildRelationFoster, ChildRelationUnknown:
+		return true
+	}
+	return false
+}
+
+// HasPartner reports whether the member is one of the unit's partners
+func (u *FamilyUnit) HasPartner(memberID int) bool {
+	return slices.Contains(u.PartnerIDs, memberID)
+}
+
+// IsDissolved reports whether the partnership has ended by divorce or death,
+// a separated couple is still married
+func (u *FamilyUnit) IsDissolved() bool {
+	return u.Status == FamilyStatusDivorced || u.Status == FamilyStatusWidowed
 }
 
 type FamilyGraphPerson struct {
@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 const (
 	TreeRoleOwner  = "owner"
@@ -13,17 +16,60 @@ const (
 	InvitationStatusRevoked  = "revoked"
 )
 
+// What a role in a tree allows. Access to a tree is decided by the caller's
+// role in it alone, every member may read it. Global roles run the site, its
+// users, languages and audit log: a global admin or super admin gets nothing
+// from them inside a tree and needs a membership like everyone else, acting
+// with its role. Only a tree's privacy rules may still tell global roles apart.
+const (
+	TreePermissionRead   = "read"   // see the tree and propose changes
+	TreePermissionEdit   = "edit"   // add, change and remove members, relationships and their records
+	TreePermissionDelete = "delete" // delete members, empty the trash and undo history
+	TreePermissionShare  = "share"  // manage invitations and share links
+	TreePermissionManage = "manage" // change the tree's settings, privacy policy and custom fields
+)
+
+var treeRolePermissions = map[string][]string{
+	TreeRoleOwner:  {TreePermissionRead, TreePermissionEdit, TreePermissionDelete, TreePermissionShare, TreePermissionManage},
+	TreeRoleEditor: {TreePermissionRead, TreePermissionEdit},
+	TreeRoleViewer: {TreePermissionRead},
+}
+
+// TreeRoleAllows reports whether the role in a tree grants the permission
+func TreeRoleAllows(role, permission string) bool {
+	return slices.Contains(treeRolePermissions[role], permission)
+}
+
 type FamilyTree struct {
-	TreeID      int       `json:"tree_id"`
-	Name        string    `json:"name"`
-	Description *string   `json:"description"`
-	OwnerUserID int       `json:"owner_user_id"`
-	OwnerName   string    `json:"owner_name,omitempty"`
-	OwnerEmail  string    `json:"owner_email,omitempty"`
-	UserRole    string    `json:"user_role,omitempty"`
-	MemberCount int       `json:"member_count"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	TreeID       int          `json:"tree_id"`
+	Name         string       `json:"name"`
+	Description  *string      `json:"description"`
+	OwnerUserID  int          `json:"owner_user_id"`
+	OwnerName    string       `json:"owner_name,omitempty"`
+	OwnerEmail   string       `json:"owner_email,omitempty"`
+	UserRole     string       `json:"user_role,omitempty"`
+	MemberCount  int          `json:"member_count"`
+	NameSettings NameSettings `json:"name_settings"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+// FamilyTreeMembership is a user's access to a tree
+type FamilyTreeMembership struct {
+	TreeID   int       `json:"tree_id"`
+	UserID   int       `json:"user_id"`
+	FullName string    `json:"full_name"`
+	Email    string    `json:"email"`
+	Avatar   *string   `json:"avatar"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// RevokedAccess counts what a user made to share a tree that stopped working
+// when they lost access to it
+type RevokedAccess struct {
+	ShareLinks    int `json:"share_links"`
+	CalendarFeeds int `json:"calendar_feeds"`
 }
 
 type FamilyTreeInvitation struct {
@@ -34,6 +80,7 @@ type FamilyTreeInvitation struct {
 	InviterName   string     `json:"inviter_name,omitempty"`
 	InviteeUserID *int       `json:"invitee_user_id"`
 	InviteeEmail  string     `json:"invitee_email"`
+	Role          string     `json:"role"`
 	Status        string     `json:"status"`
 	Message       *string    `json:"message"`
 	CreatedAt     time.Time  `json:"created_at"`
@@ -51,4 +98,16 @@ type FamilyTreeShareLink struct {
 	MaxVisits  *int       `json:"max_visits"`
 	VisitCount int        `json:"visit_count"`
 	RevokedAt  *time.Time `json:"revoked_at"`
+	// LivingPolicy is what the link shows of living members
+	LivingPolicy string `json:"living_policy"`
+}
+
+type FamilyTreeCalendarFeed struct {
+	FeedID         int        `json:"feed_id"`
+	TreeID         int        `json:"tree_id"`
+	UserID         int        `json:"user_id"`
+	Token          string     `json:"token"`
+	CreatedAt      time.Time  `json:"created_at"`
+	LastAccessedAt *time.Time `json:"last_accessed_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
 }
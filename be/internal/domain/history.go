@@ -9,11 +9,28 @@ const (
 	ChangeTypeInsert        = "INSERT"
 	ChangeTypeUpdate        = "UPDATE"
 	ChangeTypeDelete        = "DELETE"
+	ChangeTypeRestore       = "RESTORE"
 	ChangeTypeAddSpouse     = "ADD_SPOUSE"
 	ChangeTypeRemoveSpouse  = "REMOVE_SPOUSE"
 	ChangeTypeUpdateSpouse  = "UPDATE_SPOUSE"
+	ChangeTypeRestoreSpouse = "RESTORE_SPOUSE"
 	ChangeTypeAddPicture    = "ADD_PICTURE"
 	ChangeTypeDeletePicture = "DELETE_PICTURE"
+	ChangeTypeAddEvent      = "ADD_EVENT"
+	ChangeTypeUpdateEvent   = "UPDATE_EVENT"
+	ChangeTypeDeleteEvent   = "DELETE_EVENT"
+	ChangeTypeTagMedia      = "TAG_MEDIA"
+	ChangeTypeUntagMedia    = "UNTAG_MEDIA"
+	ChangeTypeUpdateMedia   = "UPDATE_MEDIA"
+	ChangeTypeDeleteMedia   = "DELETE_MEDIA"
+	ChangeTypeUpdateBio     = "UPDATE_BIOGRAPHY"
+	ChangeTypeUpdateNotes   = "UPDATE_NOTES"
+
+	ChangeTypeAddFamilyUnit    = "ADD_FAMILY_UNIT"
+	ChangeTypeUpdateFamilyUnit = "UPDATE_FAMILY_UNIT"
+	ChangeTypeDeleteFamilyUnit = "DELETE_FAMILY_UNIT"
+	ChangeTypeAddChild         = "ADD_CHILD"
+	ChangeTypeRemoveChild      = "REMOVE_CHILD"
 )
 
 type History struct {
@@ -25,6 +42,7 @@ type History struct {
 	OldValues     json.RawMessage `json:"old_values"`
 	NewValues     json.RawMessage `json:"new_values"`
 	MemberVersion int             `json:"member_version"`
+	ChangesetID   *string         `json:"changeset_id,omitempty"`
 }
 
 type HistoryWithUser struct {
@@ -33,3 +51,16 @@ type HistoryWithUser struct {
 	UserFullName string            `json:"user_full_name"`
 	UserEmail    string            `json:"user_email"`
 }
+
+// ActivityFilter narrows the changes made in a tree. MemberID roots a subtree
+// whose members are resolved into MemberIDs, nil keeps every member
+type ActivityFilter struct {
+	TreeID      int
+	UserID      *int
+	ChangeTypes []string
+	MemberID    *int
+	MemberIDs   []int
+	ChangesetID *string
+	From        *time.Time
+	To          *time.Time
+}
@@ -0,0 +1,226 @@
+package domain
+
+import (
+	"encoding/json"
+	"slices"
+	"sort"
+)
+
+const (
+	SpouseChangeAdded   = "added"
+	SpouseChangeRemoved = "removed"
+	SpouseChangeUpdated = "updated"
+)
+
+// MemberVersion is a member and its spouse relationships as they were right
+// after one of its history entries
+type MemberVersion struct {
+	HistoryID int
+	Member    *Member // nil when no snapshot of the member was recorded yet
+	Deleted   bool
+	Spouses   map[int]*Spouse // spouse_id -> relationship
+}
+
+// ReplayMemberHistory rebuilds the version of a member after the last of its
+// history entries, given oldest first. Member snapshots replace the member,
+// picture entries change its picture and spouse entries the relationships it
+// has. Other entries don't change what the version holds.
+func ReplayMemberHistory(history []*History) *MemberVersion {
+	version := &MemberVersion{Spouses: make(map[int]*Spouse)}
+	for _, entry := range history {
+		version.HistoryID = entry.HistoryID
+		switch entry.ChangeType {
+		case ChangeTypeInsert, ChangeTypeUpdate, ChangeTypeRestore:
+			var member Member
+			if err := json.Unmarshal(entry.NewValues, &member); err != nil {
+				continue
+			}
+			member.MemberID = entry.MemberID
+			member.DeletedAt = nil
+			version.Member = &member
+			version.Deleted = false
+		case ChangeTypeDelete:
+			if version.Member == nil {
+				var member Member
+				if err := json.Unmarshal(entry.OldValues, &member); err == nil {
+					member.MemberID = entry.MemberID
+					member.DeletedAt = nil
+					version.Member = &member
+				}
+			}
+			version.Deleted = true
+		case ChangeTypeAddPicture, ChangeTypeDeletePicture:
+			if version.Member == nil {
+				continue
+			}
+			var values struct {
+				Picture *string `json:"picture"`
+			}
+			if err := json.Unmarshal(entry.NewValues, &values); err != nil {
+				continue
+			}
+			member := *version.Member
+			member.Picture = values.Picture
+			version.Member = &member
+		case ChangeTypeAddSpouse, ChangeTypeUpdateSpouse, ChangeTypeRestoreSpouse:
+			var spouse Spouse
+			if err := json.Unmarshal(entry.NewValues, &spouse); err != nil || spouse.SpouseID == 0 {
+				continue
+			}
+			spouse.DeletedAt = nil
+			version.Spouses[spouse.SpouseID] = &spouse
+		case ChangeTypeRemoveSpouse:
+			var spouse Spouse
+			if err := json.Unmarshal(entry.OldValues, &spouse); err != nil {
+				continue
+			}
+			delete(version.Spouses, spouse.SpouseID)
+		}
+	}
+	return version
+}
+
+// NameChange is a name in one language that differs between two versions,
+// nil when the member had no name in the language
+type NameChange struct {
+	Language string  `json:"language"`
+	Before   *string `json:"before"`
+	After    *string `json:"after"`
+}
+
+// NicknameChanges are the nicknames one version has and the other hasn't
+type NicknameChanges struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+// SpouseChange is a spouse relationship added, removed or updated between two
+// versions with the fields that differ
+type SpouseChange struct {
+	SpouseID      int               `json:"spouse_id"`
+	PartnerID     int               `json:"partner_id"`
+	PartnerNames  map[string]string `json:"partner_names,omitempty"`
+	PartnerGender string            `json:"partner_gender,omitempty"`
+	Change        string            `json:"change"`
+	Fields        []FieldChange     `json:"fields"`
+}
+
+// MemberDiff is what changed on a member from one version to another
+type MemberDiff struct {
+	MemberID  int             `json:"member_id"`
+	From      int             `json:"from"`
+	To        int             `json:"to"`
+	Fields    []FieldChange   `json:"fields"`
+	Names     []NameChange    `json:"names"`
+	Nicknames NicknameChanges `json:"nicknames"`
+	Spouses   []SpouseChange  `json:"spouses"`
+}
+
+// DiffMemberVersions compares two versions of a member. Names are compared
+// per language and nicknames as a set, the other member fields as in
+// DiffFields. A change in whether the member was deleted is the deleted field.
+func DiffMemberVersions(memberID int, from, to *MemberVersion) *MemberDiff {
+	diff := &MemberDiff{
+		MemberID:  memberID,
+		From:      from.HistoryID,
+		To:        to.HistoryID,
+		Fields:    []FieldChange{},
+		Names:     []NameChange{},
+		Nicknames: NicknameChanges{Added: []string{}, Removed: []string{}},
+		Spouses:   []SpouseChange{},
+	}
+
+	if from.Deleted != to.Deleted {
+		before, _ := json.Marshal(from.Deleted)
+		after, _ := json.Marshal(to.Deleted)
+		diff.Fields = append(diff.Fields, FieldChange{Field: "deleted", Before: before, After: after})
+	}
+	diff.Fields = append(diff.Fields, DiffFields(from.Member, to.Member, slices.Concat(MemberDiffSkip, []string{"names", "nicknames"})...)...)
+
+	var beforeNames, afterNames map[string]string
+	var beforeNicknames, afterNicknames []string
+	if from.Member != nil {
+		beforeNames, beforeNicknames = from.Member.Names, from.Member.Nicknames
+	}
+	if to.Member != nil {
+		afterNames, afterNicknames = to.Member.Names, to.Member.Nicknames
+	}
+	diff.Names = diffNames(beforeNames, afterNames)
+
+	for _, nickname := range afterNicknames {
+		if !slices.Contains(beforeNicknames, nickname) {
+			diff.Nicknames.Added = append(diff.Nicknames.Added, nickname)
+		}
+	}
+	for _, nickname := range beforeNicknames {
+		if !slices.Contains(afterNicknames, nickname) {
+			diff.Nicknames.Removed = append(diff.Nicknames.Removed, nickname)
+		}
+	}
+
+	spouseIDs := make([]int, 0, len(from.Spouses)+len(to.Spouses))
+	for spouseID := range from.Spouses {
+		spouseIDs = append(spouseIDs, spouseID)
+	}
+	for spouseID := range to.Spouses {
+		if _, ok := from.Spouses[spouseID]; !ok {
+			spouseIDs = append(spouseIDs, spouseID)
+		}
+	}
+	sort.Ints(spouseIDs)
+
+	for _, spouseID := range spouseIDs {
+		before, after := from.Spouses[spouseID], to.Spouses[spouseID]
+		change := SpouseChange{SpouseID: spouseID, Change: SpouseChangeUpdated}
+		switch {
+		case before == nil:
+			change.Change = SpouseChangeAdded
+			change.Fields = DiffFields(nil, after, SpouseDiffSkip...)
+			change.PartnerID = after.PartnerOf(memberID)
+		case after == nil:
+			change.Change = SpouseChangeRemoved
+			change.Fields = DiffFields(before, nil, SpouseDiffSkip...)
+			change.PartnerID = before.PartnerOf(memberID)
+		default:
+			change.Fields = DiffFields(before, after, SpouseDiffSkip...)
+			if len(change.Fields) == 0 {
+				continue
+			}
+			change.PartnerID = after.PartnerOf(memberID)
+		}
+		diff.Spouses = append(diff.Spouses, change)
+	}
+
+	return diff
+}
+
+func diffNames(before, after map[string]string) []NameChange {
+	languages := make([]string, 0, len(before)+len(after))
+	for language := range before {
+		languages = append(languages, language)
+	}
+	for language := range after {
+		if _, ok := before[language]; !ok {
+			languages = append(languages, language)
+		}
+	}
+	sort.Strings(languages)
+
+	changes := []NameChange{}
+	for _, language := range languages {
+		beforeName, hadName := before[language]
+		afterName, hasName := after[language]
+		if hadName && hasName && beforeName == afterName {
+			continue
+		}
+		change := NameChange{Language: language}
+		if hadName {
+			change.Before = &beforeName
+		}
+		if hasName {
+			change.After = &afterName
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
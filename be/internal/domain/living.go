@@ -0,0 +1,144 @@
+package domain
+
+import "time"
+
+const (
+	LivingStatusLiving   = "living"
+	LivingStatusDeceased = "deceased"
+
+	// LivingPolicyRedact shows living members as "Living" with no details
+	LivingPolicyRedact = "redact"
+	// LivingPolicyNames keeps the names of living members and drops the rest
+	LivingPolicyNames = "names"
+	LivingPolicyShow  = "show"
+
+	// LivingYears is how long after their birth a member without a death date
+	// is taken to be alive
+	LivingYears = 110
+)
+
+func IsValidLivingStatus(status string) bool {
+	return status == "" || status == LivingStatusLiving || status == LivingStatusDeceased
+}
+
+func IsValidLivingPolicy(policy string) bool {
+	switch policy {
+	case LivingPolicyRedact, LivingPolicyNames, LivingPolicyShow:
+		return true
+	}
+	return false
+}
+
+// LivingMembers returns the IDs of the members taken to be alive. The status
+// set on a member wins, a death date means deceased, and a member without
+// one is alive when born within LivingYears or, with no birth date, when a
+// descendant is alive
+func LivingMembers(members []*Member, now time.Time) map[int]bool {
+	children := make(map[int][]*Member)
+	for _, member := range members {
+		if member.FatherID != nil {
+			children[*member.FatherID] = append(children[*member.FatherID], member)
+		}
+		if member.MotherID != nil {
+			children[*member.MotherID] = append(children[*member.MotherID], member)
+		}
+	}
+
+	cutoff := now.AddDate(-LivingYears, 0, 0)
+	memo := make(map[int]bool)
+	visiting := make(map[int]bool)
+
+	var isLiving func(member *Member) bool
+	isLiving = func(member *Member) bool {
+		if living, ok := memo[member.MemberID]; ok {
+			return living
+		}
+		if visiting[member.MemberID] {
+			return false
+		}
+		visiting[member.MemberID] = true
+
+		living := false
+		switch {
+		case member.LivingStatus == LivingStatusLiving:
+			living = true
+		case member.LivingStatus == LivingStatusDeceased, member.DateOfDeath != nil:
+		case member.DateOfBirth != nil:
+			// the latest possible birth decides, a range may reach into the window
+			birth := member.DateOfBirth
+			if member.DateOfBirthEnd != nil {
+				birth = member.DateOfBirthEnd
+			}
+			living = birth.After(cutoff)
+		default:
+			for _, child := range children[member.MemberID] {
+				if isLiving(child) {
+					living = true
+					break
+				}
+			}
+		}
+
+		memo[member.MemberID] = living
+		return living
+	}
+
+	result := make(map[int]bool)
+	for _, member := range members {
+		if isLiving(member) {
+			result[member.MemberID] = true
+		}
+	}
+	return result
+}
+
+// ApplyLivingPolicy marks the living members of a tree and strips what the
+// policy hides about them, a marriage is hidden when either partner is alive
+func ApplyLivingPolicy(node *MemberTreeNode, living map[int]bool, policy string) {
+	if node == nil {
+		return
+	}
+
+	node.IsLiving = living[node.MemberID]
+	if node.IsLiving && policy != LivingPolicyShow {
+		redactMember(&node.MemberWithComputed, policy)
+	}
+
+	for i := range node.Spouses {
+		spouse := &node.Spouses[i]
+		if policy == LivingPolicyShow || (!node.IsLiving && !living[spouse.MemberID]) {
+			continue
+		}
+		spouse.MarriageDate, spouse.MarriageDateEnd = nil, nil
+		spouse.DivorceDate, spouse.DivorceDateEnd = nil, nil
+		spouse.MarriagePlaceID = nil
+		if living[spouse.MemberID] {
+			spouse.Picture = nil
+			if policy == LivingPolicyRedact {
+				spouse.Names = nil
+				spouse.IsRedacted = true
+			}
+		}
+	}
+
+	for _, child := range node.Children {
+		ApplyLivingPolicy(child, living, policy)
+	}
+}
+
+func redactMember(member *MemberWithComputed, policy string) {
+	if policy == LivingPolicyRedact {
+		member.Names = nil
+		member.NameParts = nil
+		member.FullNames = nil
+		member.Nicknames = nil
+		member.IsRedacted = true
+	}
+	member.Picture = nil
+	member.DateOfBirth, member.DateOfBirthEnd = nil, nil
+	member.DateOfDeath, member.DateOfDeathEnd = nil, nil
+	member.BirthPlaceID, member.DeathPlaceID, member.BurialPlaceID = nil, nil, nil
+	member.Profession = nil
+	member.CustomFields = nil
+	member.Age, member.AgeMin, member.AgeMax = nil, nil, nil
+}
@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+type Media struct {
+	MediaID       int        `json:"media_id"`
+	TreeID        int        `json:"tree_id"`
+	StorageKey    string     `json:"storage_key"`
+	Caption       *string    `json:"caption"`
+	Date          *time.Time `json:"date"`
+	DateQualifier string     `json:"date_qualifier"`
+	DateEnd       *time.Time `json:"date_end"`
+	DateCalendar  string     `json:"date_calendar"`
+	PlaceID       *int       `json:"place_id"`
+	UploadedBy    int        `json:"uploaded_by"`
+	Tags          []MediaTag `json:"tags"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// MediaTag marks a member in a photo, Face is nil when the member is tagged
+// without pointing at them
+type MediaTag struct {
+	MemberID int       `json:"member_id"`
+	Face     *FaceRect `json:"face"`
+}
+
+// FaceRect is a rectangle given as fractions of the image width and height
+type FaceRect struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// Valid reports whether the rectangle has a size and lies inside the image
+func (f *FaceRect) Valid() bool {
+	return f.X >= 0 && f.Y >= 0 && f.Width > 0 && f.Height > 0 &&
+		f.X+f.Width <= 1 && f.Y+f.Height <= 1
+}
+
+func (m *Media) HasTag(memberID int) bool {
+	for _, tag := range m.Tags {
+		if tag.MemberID == memberID {
+			return true
+		}
+	}
+	return false
+}
@@ -5,29 +5,54 @@ import (
 )
 
 type Member struct {
-	MemberID    int               `json:"member_id"`
-	TreeID      int               `json:"tree_id"`
-	Names       map[string]string `json:"names"` // language_code -> name
-	Gender      string            `json:"gender"`
-	Picture     *string           `json:"picture"`
-	DateOfBirth *time.Time        `json:"date_of_birth"`
-	DateOfDeath *time.Time        `json:"date_of_death"`
-	FatherID    *int              `json:"father_id"`
-	MotherID    *int              `json:"mother_id"`
-	Nicknames   []string          `json:"nicknames"`
-	Profession  *string           `json:"profession"`
-	Version     int               `json:"version"`
-	DeletedAt   *time.Time        `json:"deleted_at"`
-	IsMarried   bool              `json:"is_married"`
+	MemberID             int                  `json:"member_id"`
+	TreeID               int                  `json:"tree_id"`
+	Names                map[string]string    `json:"names"`           // language_code -> name
+	NameParts            map[string]NameParts `json:"name_parts"`      // language_code -> kunya, laqab and family name
+	SuggestedNames       []string             `json:"suggested_names"` // language codes of names not yet confirmed by an editor
+	Gender               string               `json:"gender"`
+	Picture              *string              `json:"picture"`
+	DateOfBirth          *time.Time           `json:"date_of_birth"`
+	DateOfBirthQualifier string               `json:"date_of_birth_qualifier"`
+	DateOfBirthEnd       *time.Time           `json:"date_of_birth_end"`
+	DateOfBirthCalendar  string               `json:"date_of_birth_calendar"`
+	DateOfDeath          *time.Time           `json:"date_of_death"`
+	DateOfDeathQualifier string               `json:"date_of_death_qualifier"`
+	DateOfDeathEnd       *time.Time           `json:"date_of_death_end"`
+	DateOfDeathCalendar  string               `json:"date_of_death_calendar"`
+	BirthPlaceID         *int                 `json:"birth_place_id"`
+	DeathPlaceID         *int                 `json:"death_place_id"`
+	BurialPlaceID        *int                 `json:"burial_place_id"`
+	FatherID             *int                 `json:"father_id"`
+	MotherID             *int                 `json:"mother_id"`
+	Nicknames            []string             `json:"nicknames"`
+	Profession           *string              `json:"profession"`
+	CustomFields         map[string]string    `json:"custom_fields"` // field_key -> value
+	LivingStatus         string               `json:"living_status"` // living or deceased when set by an editor, empty to infer it
+	Version              int                  `json:"version"`
+	DeletedAt            *time.Time           `json:"deleted_at"`
+	IsMarried            bool                 `json:"is_married"`
+}
+
+func (m *Member) BirthRange() *DateRange {
+	return NewDateRange(m.DateOfBirth, m.DateOfBirthQualifier, m.DateOfBirthEnd)
+}
+
+func (m *Member) DeathRange() *DateRange {
+	return NewDateRange(m.DateOfDeath, m.DateOfDeathQualifier, m.DateOfDeathEnd)
 }
 
 type MemberWithComputed struct {
 	Member
 	FullNames       map[string]string      `json:"full_names"` // language_code -> full_name
 	Age             *int                   `json:"age"`
+	AgeMin          *int                   `json:"age_min,omitempty"`
+	AgeMax          *int                   `json:"age_max,omitempty"`
 	GenerationLevel int                    `json:"generation_level"`
 	IsMarried       bool                   `json:"is_married"`
 	Spouses         []SpouseWithMemberInfo `json:"spouses,omitempty"`
+	IsLiving        bool                   `json:"is_living"`
+	IsRedacted      bool                   `json:"is_redacted,omitempty"` // details hidden by a living policy
 }
 
 type MemberTreeNode struct {
@@ -35,3 +60,32 @@ type MemberTreeNode struct {
 	Children []*MemberTreeNode `json:"children,omitempty"`
 	IsInPath bool              `json:"is_in_path,omitempty"`
 }
+
+// DescendantsOf returns the root and everyone descending from it through the
+// parent links of any of the member lists
+func DescendantsOf(rootID int, memberLists ...[]*Member) map[int]bool {
+	children := make(map[int][]int)
+	for _, members := range memberLists {
+		for _, member := range members {
+			for _, parentID := range []*int{member.FatherID, member.MotherID} {
+				if parentID != nil {
+					children[*parentID] = append(children[*parentID], member.MemberID)
+				}
+			}
+		}
+	}
+
+	descendants := map[int]bool{rootID: true}
+	queue := []int{rootID}
+	for len(queue) > 0 {
+		memberID := queue[0]
+		queue = queue[1:]
+		for _, childID := range children[memberID] {
+			if !descendants[childID] {
+				descendants[childID] = true
+				queue = append(queue, childID)
+			}
+		}
+	}
+	return descendants
+}
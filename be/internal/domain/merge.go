@@ -0,0 +1,210 @@
+package domain
+
+import (
+	"encoding/json"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// mergeKeyedFields are the member fields merged key by key, so edits to
+// different languages or custom fields don't conflict
+var mergeKeyedFields = []string{"names", "name_parts", "custom_fields"}
+
+// MergeConflict is a field both the edit and the server changed from the
+// version the edit started at, each to a different value. Keyed fields are
+// reported per key as field.key.
+type MergeConflict struct {
+	Field   string          `json:"field"`
+	Base    json.RawMessage `json:"base"`
+	Current json.RawMessage `json:"current"`
+	Mine    json.RawMessage `json:"mine"`
+}
+
+// MemberConflict is an edit made on an older version of a member. It carries
+// the member as it is now and what changed since the edited version, with
+// the fields that couldn't be merged when the edit asked for a merge.
+type MemberConflict struct {
+	Current   *MemberWithComputed
+	Base      *Member // nil when the edited version wasn't recorded
+	Changes   []FieldChange
+	History   []*HistoryWithUser
+	Conflicts []MergeConflict
+
+	err *DomainError
+}
+
+func NewMemberConflict(conflicts []MergeConflict) *MemberConflict {
+	err := NewVersionConflictError()
+	if len(conflicts) > 0 {
+		fields := make([]string, len(conflicts))
+		for i, conflict := range conflicts {
+			fields[i] = conflict.Field
+		}
+		err.TranslationKey = "error.member.merge_conflict"
+		err.Params = map[string]string{"fields": strings.Join(fields, ", ")}
+	}
+	return &MemberConflict{Conflicts: conflicts, err: err}
+}
+
+func (c *MemberConflict) Error() string {
+	return c.err.Error()
+}
+
+func (c *MemberConflict) Unwrap() error {
+	return c.err
+}
+
+// MergeMember merges an edit made on the base version of a member with the
+// member as it is now. A field changed on one side only takes that side's
+// value and a field changed the same way on both sides is kept. Omitted name
+// parts and custom fields leave them unchanged. Without a base every field
+// the edit and the server disagree on is a conflict. Fields that aren't
+// edited directly keep their current values.
+func MergeMember(base, current, mine *Member) (*Member, []MergeConflict) {
+	edit := *mine
+	if edit.NameParts == nil && base != nil {
+		edit.NameParts = base.NameParts
+	}
+	if edit.CustomFields == nil && base != nil {
+		edit.CustomFields = base.CustomFields
+	}
+
+	baseFields, currentFields, mineFields := jsonFields(base), jsonFields(current), jsonFields(&edit)
+	known := base != nil
+
+	keys := make([]string, 0, len(currentFields))
+	for key := range currentFields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	merged := make(map[string]json.RawMessage, len(currentFields))
+	var conflicts []MergeConflict
+	for _, key := range keys {
+		if slices.Contains(MemberDiffSkip, key) {
+			merged[key] = currentFields[key]
+			continue
+		}
+
+		switch {
+		case key == "nicknames":
+			merged[key] = mergeNicknames(baseFields[key], currentFields[key], mineFields[key], known)
+		case slices.Contains(mergeKeyedFields, key):
+			value, keyConflicts := mergeKeyed(key, baseFields[key], currentFields[key], mineFields[key], known)
+			merged[key] = value
+			conflicts = append(conflicts, keyConflicts...)
+		default:
+			value, ok := mergeValue(baseFields[key], currentFields[key], mineFields[key], known)
+			merged[key] = value
+			if !ok {
+				conflicts = append(conflicts, MergeConflict{
+					Field:   key,
+					Base:    orNull(baseFields[key]),
+					Current: orNull(currentFields[key]),
+					Mine:    orNull(mineFields[key]),
+				})
+			}
+		}
+	}
+
+	result := &Member{}
+	data, _ := json.Marshal(merged)
+	_ = json.Unmarshal(data, result)
+	result.MemberID = current.MemberID
+	result.TreeID = current.TreeID
+	result.Version = current.Version
+	if result.Nicknames == nil {
+		result.Nicknames = []string{}
+	}
+	return result, conflicts
+}
+
+// mergeValue merges one value, it keeps the current value and reports false
+// when both sides changed it differently
+func mergeValue(base, current, mine json.RawMessage, known bool) (json.RawMessage, bool) {
+	switch {
+	case sameJSON(mine, current):
+		return current, true
+	case known && sameJSON(mine, base):
+		return current, true
+	case known && sameJSON(current, base):
+		return mine, true
+	}
+	return current, false
+}
+
+func mergeKeyed(field string, base, current, mine json.RawMessage, known bool) (json.RawMessage, []MergeConflict) {
+	var baseValues, currentValues, mineValues map[string]json.RawMessage
+	_ = json.Unmarshal(base, &baseValues)
+	_ = json.Unmarshal(current, &currentValues)
+	_ = json.Unmarshal(mine, &mineValues)
+
+	keys := make([]string, 0, len(currentValues)+len(mineValues))
+	for key := range currentValues {
+		keys = append(keys, key)
+	}
+	for key := range mineValues {
+		if _, ok := currentValues[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	merged := make(map[string]json.RawMessage, len(keys))
+	var conflicts []MergeConflict
+	for _, key := range keys {
+		value, ok := mergeValue(baseValues[key], currentValues[key], mineValues[key], known)
+		if !ok {
+			conflicts = append(conflicts, MergeConflict{
+				Field:   field + "." + key,
+				Base:    orNull(baseValues[key]),
+				Current: orNull(currentValues[key]),
+				Mine:    orNull(mineValues[key]),
+			})
+		}
+		if len(value) > 0 && !sameJSON(value, nil) {
+			merged[key] = value
+		}
+	}
+
+	data, _ := json.Marshal(merged)
+	return data, conflicts
+}
+
+// mergeNicknames keeps the current nicknames with the ones the edit added and
+// without the ones it removed, nicknames never conflict
+func mergeNicknames(base, current, mine json.RawMessage, known bool) json.RawMessage {
+	var baseNicknames, currentNicknames, mineNicknames []string
+	_ = json.Unmarshal(base, &baseNicknames)
+	_ = json.Unmarshal(current, &currentNicknames)
+	_ = json.Unmarshal(mine, &mineNicknames)
+
+	merged := []string{}
+	for _, nickname := range currentNicknames {
+		if !known || !slices.Contains(baseNicknames, nickname) || slices.Contains(mineNicknames, nickname) {
+			merged = append(merged, nickname)
+		}
+	}
+	for _, nickname := range mineNicknames {
+		if !slices.Contains(merged, nickname) && (!known || !slices.Contains(baseNicknames, nickname)) {
+			merged = append(merged, nickname)
+		}
+	}
+
+	data, _ := json.Marshal(merged)
+	return data
+}
+
+// mergedValue returns the value of a field of the member, field.key for a
+// key of a keyed field
+func mergedValue(member *Member, field string) json.RawMessage {
+	fields := jsonFields(member)
+	name, key, keyed := strings.Cut(field, ".")
+	if !keyed {
+		return orNull(fields[name])
+	}
+	var values map[string]json.RawMessage
+	_ = json.Unmarshal(fields[name], &values)
+	return orNull(values[key])
+}
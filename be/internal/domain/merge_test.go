@@ -0,0 +1,178 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func mergeBase() *Member {
+	profession := "Teacher"
+	picture := "picture.jpg"
+	return &Member{
+		MemberID:     1,
+		TreeID:       2,
+		Version:      3,
+		Names:        map[string]string{"en": "Ali", "ar": "علي"},
+		Gender:       "M",
+		Picture:      &picture,
+		Profession:   &profession,
+		Nicknames:    []string{"Abu Omar", "Sheikh"},
+		CustomFields: map[string]string{"clan": "North"},
+	}
+}
+
+func setProfession(profession string) func(*Member) {
+	return func(m *Member) { m.Profession = &profession }
+}
+
+func TestMergeMember(t *testing.T) {
+	tests := []struct {
+		name          string
+		noBase        bool
+		current       func(*Member)
+		mine          func(*Member)
+		check         func(t *testing.T, merged *Member)
+		wantConflicts []string
+	}{
+		{
+			name: "changed by the edit only",
+			mine: setProfession("Doctor"),
+			check: func(t *testing.T, merged *Member) {
+				if *merged.Profession != "Doctor" {
+					t.Errorf("profession = %q, want Doctor", *merged.Profession)
+				}
+			},
+		},
+		{
+			name:    "changed on the server only",
+			current: setProfession("Doctor"),
+			check: func(t *testing.T, merged *Member) {
+				if *merged.Profession != "Doctor" {
+					t.Errorf("profession = %q, want Doctor", *merged.Profession)
+				}
+			},
+		},
+		{
+			name:    "changed the same way",
+			current: setProfession("Doctor"),
+			mine:    setProfession("Doctor"),
+		},
+		{
+			name:          "changed differently",
+			current:       setProfession("Doctor"),
+			mine:          setProfession("Engineer"),
+			wantConflicts: []string{"profession"},
+			check: func(t *testing.T, merged *Member) {
+				if *merged.Profession != "Doctor" {
+					t.Errorf("profession = %q, want the current Doctor", *merged.Profession)
+				}
+			},
+		},
+		{
+			name:    "names in different languages",
+			current: func(m *Member) { m.Names = map[string]string{"en": "Aly", "ar": "علي"} },
+			mine:    func(m *Member) { m.Names = map[string]string{"en": "Ali", "ar": "عليّ"} },
+			check: func(t *testing.T, merged *Member) {
+				if merged.Names["en"] != "Aly" || merged.Names["ar"] != "عليّ" {
+					t.Errorf("names = %v, want both changes", merged.Names)
+				}
+			},
+		},
+		{
+			name:          "name in the same language",
+			current:       func(m *Member) { m.Names = map[string]string{"en": "Aly", "ar": "علي"} },
+			mine:          func(m *Member) { m.Names = map[string]string{"en": "Alee", "ar": "علي"} },
+			wantConflicts: []string{"names.en"},
+		},
+		{
+			name:    "nicknames added and removed",
+			current: func(m *Member) { m.Nicknames = append(m.Nicknames, "Hajji") },
+			mine:    func(m *Member) { m.Nicknames = []string{"Sheikh"} },
+			check: func(t *testing.T, merged *Member) {
+				if !slices.Equal(merged.Nicknames, []string{"Sheikh", "Hajji"}) {
+					t.Errorf("nicknames = %v, want [Sheikh Hajji]", merged.Nicknames)
+				}
+			},
+		},
+		{
+			name:    "custom fields omitted",
+			current: func(m *Member) { m.CustomFields = map[string]string{"clan": "South"} },
+			mine:    func(m *Member) { m.CustomFields = nil },
+			check: func(t *testing.T, merged *Member) {
+				if merged.CustomFields["clan"] != "South" {
+					t.Errorf("custom fields = %v, want the current clan", merged.CustomFields)
+				}
+			},
+		},
+		{
+			name:          "no base",
+			noBase:        true,
+			current:       setProfession("Doctor"),
+			mine:          setProfession("Engineer"),
+			wantConflicts: []string{"profession"},
+		},
+		{
+			name: "fields not edited directly",
+			current: func(m *Member) {
+				m.Version = 5
+				picture := "new.jpg"
+				m.Picture = &picture
+			},
+			mine: func(m *Member) { m.Picture = nil },
+			check: func(t *testing.T, merged *Member) {
+				if merged.Version != 5 || merged.Picture == nil || *merged.Picture != "new.jpg" {
+					t.Errorf("version %d and picture %v, want the current ones", merged.Version, merged.Picture)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, current, mine := mergeBase(), mergeBase(), mergeBase()
+			if tt.current != nil {
+				tt.current(current)
+			}
+			if tt.mine != nil {
+				tt.mine(mine)
+			}
+			if tt.noBase {
+				base = nil
+			}
+
+			merged, conflicts := MergeMember(base, current, mine)
+
+			var fields []string
+			for _, conflict := range conflicts {
+				fields = append(fields, conflict.Field)
+			}
+			if !slices.Equal(fields, tt.wantConflicts) {
+				t.Errorf("conflicts = %v, want %v", fields, tt.wantConflicts)
+			}
+			if tt.check != nil {
+				tt.check(t, merged)
+			}
+		})
+	}
+}
+
+func TestMergeMemberKeepsEdit(t *testing.T) {
+	base, current, mine := mergeBase(), mergeBase(), mergeBase()
+	mine.CustomFields = nil
+
+	MergeMember(base, current, mine)
+
+	if mine.CustomFields != nil {
+		t.Errorf("MergeMember changed the edit, custom fields = %v", mine.CustomFields)
+	}
+}
+
+func TestNewMemberConflict(t *testing.T) {
+	conflict := NewMemberConflict([]MergeConflict{{Field: "profession"}, {Field: "names.en"}})
+	if conflict.err.TranslationKey != "error.member.merge_conflict" || conflict.err.Params["fields"] != "profession, names.en" {
+		t.Errorf("error = %v, want the merge conflict naming both fields", conflict.err)
+	}
+	if !IsDomainError(conflict, ErrCodeVersionConflict) {
+		t.Errorf("error code isn't a version conflict")
+	}
+}
@@ -0,0 +1,249 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	// NameFormatLineage joins the given names of the father chain, the format
+	// for languages without rules
+	NameFormatLineage    = "lineage"
+	NameFormatNasab      = "nasab"
+	NameFormatPatronymic = "patronymic"
+	NameFormatWestern    = "western"
+
+	// MaxLineageDepth bounds the fathers walked for a full name
+	MaxLineageDepth = 100
+)
+
+var defaultNameFormats = map[string]string{
+	"ar": NameFormatNasab,
+	"ru": NameFormatPatronymic,
+	"en": NameFormatWestern,
+}
+
+// NameParts are the parts of a name besides the given name, kept per language
+type NameParts struct {
+	Kunya  string `json:"kunya,omitempty"`  // teknonym, e.g. أبو محمد
+	Laqab  string `json:"laqab,omitempty"`  // epithet or honorific
+	Family string `json:"family,omitempty"` // tribal nisba or family name
+}
+
+func (p NameParts) IsEmpty() bool {
+	return p.Kunya == "" && p.Laqab == "" && p.Family == ""
+}
+
+// NameSettings are the full-name rules of a tree
+type NameSettings struct {
+	Formats         map[string]string `json:"formats,omitempty"`          // language_code -> name format
+	NasabDepth      int               `json:"nasab_depth,omitempty"`      // fathers named in a nasab, 0 names them all
+	NasabConnectors bool              `json:"nasab_connectors,omitempty"` // put بن/بنت between the names of a nasab
+}
+
+func IsValidNameFormat(format string) bool {
+	switch format {
+	case NameFormatLineage, NameFormatNasab, NameFormatPatronymic, NameFormatWestern:
+		return true
+	}
+	return false
+}
+
+// Format returns the name format of a language: the tree's choice, else the
+// default of the language, else the father chain
+func (s NameSettings) Format(languageCode string) string {
+	if format, ok := s.Formats[languageCode]; ok {
+		return format
+	}
+	if format, ok := defaultNameFormats[languageCode]; ok {
+		return format
+	}
+	return NameFormatLineage
+}
+
+// FormatFullName builds the full name of lineage[0] in one language, lineage
+// holds the member followed by its father, grandfather and so on
+func FormatFullName(settings NameSettings, languageCode string, lineage []*Member) string {
+	if len(lineage) == 0 {
+		return ""
+	}
+
+	var words []string
+	switch settings.Format(languageCode) {
+	case NameFormatNasab:
+		words = nasabWords(settings, languageCode, lineage)
+	case NameFormatPatronymic:
+		words = patronymicWords(languageCode, lineage)
+	case NameFormatWestern:
+		words = []string{lineage[0].Names[languageCode], familyName(languageCode, lineage)}
+	default:
+		for _, member := range lineage {
+			words = append(words, member.Names[languageCode])
+		}
+	}
+
+	return joinNameWords(words)
+}
+
+// nasabWords orders an Arabic name as kunya, ism, nasab, laqab and nisba. The
+// nasab stops at the first father without a name in the language so no
+// generation is skipped silently
+func nasabWords(settings NameSettings, languageCode string, lineage []*Member) []string {
+	member := lineage[0]
+	parts := member.NameParts[languageCode]
+	words := []string{parts.Kunya, member.Names[languageCode]}
+
+	fathers := lineage[1:]
+	if settings.NasabDepth > 0 && len(fathers) > settings.NasabDepth {
+		fathers = fathers[:settings.NasabDepth]
+	}
+
+	child := member
+	for _, father := range fathers {
+		name := strings.TrimSpace(father.Names[languageCode])
+		if name == "" {
+			break
+		}
+		if settings.NasabConnectors {
+			words = append(words, nasabConnector(languageCode, child.Gender))
+		}
+		words = append(words, name)
+		child = father
+	}
+
+	return append(words, parts.Laqab, familyName(languageCode, lineage))
+}
+
+func nasabConnector(languageCode, gender string) string {
+	if languageCode == "ar" {
+		if gender == "F" {
+			return "بنت"
+		}
+		return "بن"
+	}
+	if gender == "F" {
+		return "bint"
+	}
+	return "ibn"
+}
+
+// patronymicWords orders a Russian name as given name, patronymic and family
+// name, a daughter takes the feminine form of a family name she inherits
+func patronymicWords(languageCode string, lineage []*Member) []string {
+	member := lineage[0]
+	words := []string{member.Names[languageCode]}
+	if len(lineage) > 1 {
+		words = append(words, patronymic(lineage[1].Names[languageCode], member.Gender))
+	}
+
+	family := member.NameParts[languageCode].Family
+	if family == "" {
+		family = familyName(languageCode, lineage[1:])
+		if member.Gender == "F" {
+			family = feminineSurname(family)
+		}
+	}
+	return append(words, family)
+}
+
+// irregularPatronymics are the masculine and feminine patronymics the
+// endings rules get wrong
+var irregularPatronymics = map[string][2]string{
+	"Лев":    {"Львович", "Львовна"},
+	"Павел":  {"Павлович", "Павловна"},
+	"Пётр":   {"Петрович", "Петровна"},
+	"Петр":   {"Петрович", "Петровна"},
+	"Михаил": {"Михайлович", "Михайловна"},
+	"Илья":   {"Ильич", "Ильинична"},
+	"Кузьма": {"Кузьмич", "Кузьминична"},
+	"Фома":   {"Фомич", "Фоминична"},
+	"Лука":   {"Лукич", "Лукинична"},
+}
+
+// patronymic derives a Russian patronymic from the father's given name, names
+// not written in Cyrillic have none
+func patronymic(fatherName, gender string) string {
+	name := strings.TrimSpace(fatherName)
+	runes := []rune(name)
+	if len(runes) < 2 || !unicode.Is(unicode.Cyrillic, runes[len(runes)-1]) {
+		return ""
+	}
+
+	female := 0
+	if gender == "F" {
+		female = 1
+	}
+	if forms, ok := irregularPatronymics[name]; ok {
+		return forms[female]
+	}
+
+	endings := func(male, feminine string) string {
+		if female == 1 {
+			return feminine
+		}
+		return male
+	}
+
+	lower := []rune(strings.ToLower(name))
+	last := lower[len(lower)-1]
+	stem := string(runes[:len(runes)-1])
+	switch {
+	case strings.HasSuffix(string(lower), "ий"):
+		// Дмитрий -> Дмитриевич but Юрий -> Юрьевич, the soft sign follows a single consonant
+		stem = string(runes[:len(runes)-2])
+		if len(lower) >= 4 && !isRussianVowel(lower[len(lower)-4]) {
+			return stem + endings("иевич", "иевна")
+		}
+		return stem + endings("ьевич", "ьевна")
+	case last == 'й' || last == 'ь':
+		return stem + endings("евич", "евна")
+	case last == 'а' || last == 'я':
+		return stem + endings("ич", "ична")
+	case isRussianVowel(last):
+		return name + endings("вич", "вна")
+	default:
+		return name + endings("ович", "овна")
+	}
+}
+
+func isRussianVowel(r rune) bool {
+	return strings.ContainsRune("аеёиоуыэюя", r)
+}
+
+// feminineSurname turns the common masculine Russian family names feminine
+func feminineSurname(surname string) string {
+	for _, suffix := range []string{"ский", "цкий"} {
+		if strings.HasSuffix(surname, suffix) {
+			return strings.TrimSuffix(surname, "ий") + "ая"
+		}
+	}
+	if strings.HasSuffix(surname, "ой") {
+		return strings.TrimSuffix(surname, "ой") + "ая"
+	}
+	for _, suffix := range []string{"ов", "ев", "ёв", "ин", "ын"} {
+		if strings.HasSuffix(surname, suffix) {
+			return surname + "а"
+		}
+	}
+	return surname
+}
+
+// familyName is the nearest family name or nisba along the lineage
+func familyName(languageCode string, lineage []*Member) string {
+	for _, member := range lineage {
+		if family := strings.TrimSpace(member.NameParts[languageCode].Family); family != "" {
+			return family
+		}
+	}
+	return ""
+}
+
+func joinNameWords(words []string) string {
+	kept := make([]string, 0, len(words))
+	for _, word := range words {
+		if word = strings.TrimSpace(word); word != "" {
+			kept = append(kept, word)
+		}
+	}
+	return strings.Join(kept, " ")
+}
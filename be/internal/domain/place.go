@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+const (
+	MapEventBirth = "birth"
+	MapEventDeath = "death"
+)
+
+type Place struct {
+	PlaceID   int               `json:"place_id"`
+	TreeID    int               `json:"tree_id"`
+	Names     map[string]string `json:"names"` // language_code -> name
+	Latitude  *float64          `json:"latitude"`
+	Longitude *float64          `json:"longitude"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// HasCoordinates reports whether the place can be put on a map
+func (p *Place) HasCoordinates() bool {
+	return p.Latitude != nil && p.Longitude != nil
+}
+
+type MapFilter struct {
+	TreeID        int
+	MinGeneration *int
+	MaxGeneration *int
+	From          *time.Time
+	To            *time.Time
+}
+
+// MapFeature is a member's birth or death at a place with coordinates
+type MapFeature struct {
+	EventType   string
+	MemberID    int
+	MemberNames map[string]string
+	Generation  int
+	Date        *time.Time
+	Place       *Place
+}
@@ -0,0 +1,346 @@
+package domain
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+const (
+	PrivacyFieldNames        = "names"
+	PrivacyFieldDates        = "dates" // birth and death dates, ages and event dates
+	PrivacyFieldPicture      = "picture"
+	PrivacyFieldPlaces       = "places"
+	PrivacyFieldProfession   = "profession"
+	PrivacyFieldNicknames    = "nicknames"
+	PrivacyFieldCustomFields = "custom_fields"
+
+	VisibilityVisible = "visible"
+	// VisibilityNoYear keeps the day and month of dates, other fields treat
+	// it as hidden
+	VisibilityNoYear = "no_year"
+	VisibilityHidden = "hidden"
+
+	// TreeRolePublic is the tree role of viewers outside the tree, such as
+	// visitors of a share link
+	TreeRolePublic = "public"
+)
+
+var privacyFields = []string{
+	PrivacyFieldNames, PrivacyFieldDates, PrivacyFieldPicture, PrivacyFieldPlaces,
+	PrivacyFieldProfession, PrivacyFieldNicknames, PrivacyFieldCustomFields,
+}
+
+// visibilityRank orders visibilities from the most to the least shown
+var visibilityRank = map[string]int{
+	VisibilityVisible: 0,
+	VisibilityNoYear:  1,
+	VisibilityHidden:  2,
+}
+
+// Viewer is who a member is shown to
+type Viewer struct {
+	UserID   int    // 0 for anonymous viewers
+	Role     int    // global role
+	TreeRole string // role in the member's tree, TreeRolePublic outside it
+}
+
+// PrivacyRule limits a field of the members it matches for the viewers it
+// matches, empty conditions match everything
+type PrivacyRule struct {
+	Field      string `json:"field"`
+	Visibility string `json:"visibility"`
+
+	// viewers
+	TreeRoles []string `json:"tree_roles,omitempty"` // owner, editor, viewer or public
+	BelowRole int      `json:"below_role,omitempty"` // global roles below this one
+
+	// members
+	Gender    string `json:"gender,omitempty"`
+	Living    *bool  `json:"living,omitempty"`
+	MemberIDs []int  `json:"member_ids,omitempty"`
+}
+
+// PrivacyPolicy is the field visibility of a tree, when several rules match
+// the most restrictive one wins
+type PrivacyPolicy struct {
+	Rules []PrivacyRule `json:"rules"`
+}
+
+// DefaultPrivacyPolicy hides the year of a woman's dates below super admin
+// and her picture below admin
+func DefaultPrivacyPolicy() PrivacyPolicy {
+	return PrivacyPolicy{Rules: []PrivacyRule{
+		{Field: PrivacyFieldDates, Visibility: VisibilityNoYear, BelowRole: RoleSuperAdmin, Gender: "F"},
+		{Field: PrivacyFieldPicture, Visibility: VisibilityHidden, BelowRole: RoleAdmin, Gender: "F"},
+	}}
+}
+
+func (p PrivacyPolicy) Validate() error {
+	for _, rule := range p.Rules {
+		if !slices.Contains(privacyFields, rule.Field) {
+			return NewValidationError("error.privacy.invalid_field").WithParams(map[string]string{"field": rule.Field})
+		}
+		if _, ok := visibilityRank[rule.Visibility]; !ok {
+			return NewValidationError("error.privacy.invalid_visibility")
+		}
+		if rule.Visibility == VisibilityNoYear && rule.Field != PrivacyFieldDates {
+			return NewValidationError("error.privacy.no_year_without_dates")
+		}
+		for _, role := range rule.TreeRoles {
+			switch role {
+			case TreeRoleOwner, TreeRoleEditor, TreeRoleViewer, TreeRolePublic:
+			default:
+				return NewValidationError("error.privacy.invalid_tree_role")
+			}
+		}
+		if rule.Gender != "" && rule.Gender != "M" && rule.Gender != "F" {
+			return NewValidationError("error.validation.invalid_gender")
+		}
+	}
+	return nil
+}
+
+// UsesLiving reports whether a rule depends on members being alive, which
+// takes the whole tree to find out
+func (p PrivacyPolicy) UsesLiving() bool {
+	return slices.ContainsFunc(p.Rules, func(rule PrivacyRule) bool { return rule.Living != nil })
+}
+
+// Privacy applies a policy for one viewer
+type Privacy struct {
+	policy PrivacyPolicy
+	viewer Viewer
+	living map[int]bool
+}
+
+// NewPrivacy binds a policy to a viewer, living holds the members taken to be
+// alive and is only read by rules on living
+func NewPrivacy(policy PrivacyPolicy, viewer Viewer, living map[int]bool) *Privacy {
+	return &Privacy{policy: policy, viewer: viewer, living: living}
+}
+
+// Visibility is how much of a field of the member the viewer may see
+func (p *Privacy) Visibility(field string, member *Member) string {
+	visibility := VisibilityVisible
+	for _, rule := range p.policy.Rules {
+		if rule.Field != field || !p.matches(rule, member) {
+			continue
+		}
+		if visibilityRank[rule.Visibility] > visibilityRank[visibility] {
+			visibility = rule.Visibility
+		}
+	}
+	return visibility
+}
+
+// CanSee reports whether the viewer may see all of a field of the member
+func (p *Privacy) CanSee(field string, member *Member) bool {
+	return p.Visibility(field, member) == VisibilityVisible
+}
+
+func (p *Privacy) matches(rule PrivacyRule, member *Member) bool {
+	if len(rule.TreeRoles) > 0 && !slices.Contains(rule.TreeRoles, p.viewer.TreeRole) {
+		return false
+	}
+	if rule.BelowRole > 0 && p.viewer.Role >= rule.BelowRole {
+		return false
+	}
+	if rule.Gender != "" && rule.Gender != member.Gender {
+		return false
+	}
+	if rule.Living != nil && *rule.Living != p.living[member.MemberID] {
+		return false
+	}
+	if len(rule.MemberIDs) > 0 && !slices.Contains(rule.MemberIDs, member.MemberID) {
+		return false
+	}
+	return true
+}
+
+// ApplyMember strips the fields the viewer may not see
+func (p *Privacy) ApplyMember(member *Member) {
+	original := *member
+	p.applyMember(member, &original)
+}
+
+// ApplyVersion strips a recorded version of the member the way the member
+// itself is stripped
+func (p *Privacy) ApplyVersion(version *MemberVersion, member *Member) {
+	if version.Member == nil {
+		return
+	}
+	snapshot := *version.Member
+	p.applyMember(&snapshot, member)
+	version.Member = &snapshot
+}
+
+// stripped returns a copy of version stripped by the rules member falls under
+func (p *Privacy) stripped(version, member *Member) *Member {
+	if version == nil {
+		return nil
+	}
+	copied := *version
+	p.applyMember(&copied, member)
+	return &copied
+}
+
+// applyMember strips the fields of member the viewer may not see of original
+func (p *Privacy) applyMember(member, original *Member) {
+	if !p.CanSee(PrivacyFieldNames, original) {
+		member.Names = nil
+		member.NameParts = nil
+	}
+	switch p.Visibility(PrivacyFieldDates, original) {
+	case VisibilityNoYear:
+		member.DateOfBirth, member.DateOfBirthEnd = hideYear(member.DateOfBirth), hideYear(member.DateOfBirthEnd)
+		member.DateOfDeath, member.DateOfDeathEnd = hideYear(member.DateOfDeath), hideYear(member.DateOfDeathEnd)
+	case VisibilityHidden:
+		member.DateOfBirth, member.DateOfBirthEnd = nil, nil
+		member.DateOfDeath, member.DateOfDeathEnd = nil, nil
+	}
+	if !p.CanSee(PrivacyFieldPicture, original) {
+		member.Picture = nil
+	}
+	if !p.CanSee(PrivacyFieldPlaces, original) {
+		member.BirthPlaceID, member.DeathPlaceID, member.BurialPlaceID = nil, nil, nil
+	}
+	if !p.CanSee(PrivacyFieldProfession, original) {
+		member.Profession = nil
+	}
+	if !p.CanSee(PrivacyFieldNicknames, original) {
+		member.Nicknames = nil
+	}
+	if !p.CanSee(PrivacyFieldCustomFields, original) {
+		member.CustomFields = nil
+	}
+}
+
+// ApplyComputed strips a member with its computed fields, ages give the year
+// of birth away and are only kept with the dates in full
+func (p *Privacy) ApplyComputed(member *MemberWithComputed) {
+	original := member.Member
+	p.ApplyMember(&member.Member)
+
+	if !p.CanSee(PrivacyFieldNames, &original) {
+		member.FullNames = nil
+	}
+	if !p.CanSee(PrivacyFieldDates, &original) {
+		member.Age, member.AgeMin, member.AgeMax = nil, nil, nil
+	}
+}
+
+// ApplySpouse strips what a spouse entry shows of the spouse it points to
+func (p *Privacy) ApplySpouse(info *SpouseWithMemberInfo) {
+	spouse := &Member{MemberID: info.MemberID, Gender: info.Gender}
+	if !p.CanSee(PrivacyFieldNames, spouse) {
+		info.Names = nil
+	}
+	if !p.CanSee(PrivacyFieldPicture, spouse) {
+		info.Picture = nil
+	}
+}
+
+// ApplyEvent strips an event of the member the way the member's own dates
+// and places are
+func (p *Privacy) ApplyEvent(event *MemberEvent, member *Member) {
+	switch p.Visibility(PrivacyFieldDates, member) {
+	case VisibilityNoYear:
+		event.Date, event.DateEnd = hideYear(event.Date), hideYear(event.DateEnd)
+	case VisibilityHidden:
+		event.Date, event.DateEnd = nil, nil
+	}
+	if !p.CanSee(PrivacyFieldPlaces, member) {
+		event.PlaceID = nil
+	}
+}
+
+// ApplyConflict strips what a version conflict on the member shows of the
+// edited version and the changes made since, the edit's own values are kept
+func (p *Privacy) ApplyConflict(conflict *MemberConflict, member *Member) {
+	current := *member
+	p.applyMember(&current, member)
+
+	var base *Member
+	if conflict.Base != nil {
+		stripped := *conflict.Base
+		p.applyMember(&stripped, member)
+		base = &stripped
+		conflict.Base = base
+	}
+
+	for i := range conflict.Conflicts {
+		conflict.Conflicts[i].Current = mergedValue(&current, conflict.Conflicts[i].Field)
+		if base != nil {
+			conflict.Conflicts[i].Base = mergedValue(base, conflict.Conflicts[i].Field)
+		}
+	}
+	for _, history := range conflict.History {
+		p.ApplyHistory(history, member)
+	}
+}
+
+// historyKeys are the keys of recorded old and new values that each privacy
+// field covers, for members as well as their events
+var historyKeys = map[string][]string{
+	PrivacyFieldNames:        {"names", "name_parts", "full_names"},
+	PrivacyFieldDates:        {"date_of_birth", "date_of_birth_end", "date_of_death", "date_of_death_end", "date", "date_end"},
+	PrivacyFieldPicture:      {"picture"},
+	PrivacyFieldPlaces:       {"birth_place_id", "death_place_id", "burial_place_id", "place_id"},
+	PrivacyFieldProfession:   {"profession"},
+	PrivacyFieldNicknames:    {"nicknames"},
+	PrivacyFieldCustomFields: {"custom_fields"},
+}
+
+// ApplyHistory strips a recorded change of the member the way the member
+// itself is stripped
+func (p *Privacy) ApplyHistory(history *HistoryWithUser, member *Member) {
+	if !p.CanSee(PrivacyFieldNames, member) {
+		history.MemberNames = nil
+	}
+	history.OldValues = p.applyValues(history.OldValues, member)
+	history.NewValues = p.applyValues(history.NewValues, member)
+}
+
+func (p *Privacy) applyValues(values json.RawMessage, member *Member) json.RawMessage {
+	var fields map[string]json.RawMessage
+	if len(values) == 0 || json.Unmarshal(values, &fields) != nil {
+		return values
+	}
+
+	for field, keys := range historyKeys {
+		visibility := p.Visibility(field, member)
+		if visibility == VisibilityVisible {
+			continue
+		}
+		for _, key := range keys {
+			value, ok := fields[key]
+			if !ok {
+				continue
+			}
+			if field == PrivacyFieldDates && visibility == VisibilityNoYear {
+				var date *time.Time
+				if json.Unmarshal(value, &date) == nil {
+					fields[key], _ = json.Marshal(hideYear(date))
+					continue
+				}
+			}
+			fields[key] = json.RawMessage("null")
+		}
+	}
+
+	stripped, err := json.Marshal(fields)
+	if err != nil {
+		return values
+	}
+	return stripped
+}
+
+// hideYear keeps the day and month of a date but drops its year
+func hideYear(date *time.Time) *time.Time {
+	if date == nil {
+		return nil
+	}
+	hidden := time.Date(1, date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return &hidden
+}
@@ -0,0 +1,289 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) *time.Time {
+	t := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return &t
+}
+
+func testMember(id int, gender string) *Member {
+	picture := "picture.jpg"
+	profession := "Teacher"
+	birthPlace := 7
+	return &Member{
+		MemberID:     id,
+		Gender:       gender,
+		Names:        map[string]string{"en": "Name"},
+		DateOfBirth:  date(1990, time.March, 14),
+		DateOfDeath:  date(2020, time.June, 2),
+		Picture:      &picture,
+		Profession:   &profession,
+		BirthPlaceID: &birthPlace,
+		Nicknames:    []string{"Nick"},
+		CustomFields: map[string]string{"clan": "North"},
+	}
+}
+
+func TestDefaultPrivacyPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		gender      string
+		role        int
+		wantDates   string
+		wantPicture string
+	}{
+		{"man for guest", "M", RoleGuest, VisibilityVisible, VisibilityVisible},
+		{"woman for guest", "F", RoleGuest, VisibilityNoYear, VisibilityHidden},
+		{"woman for no role", "F", RoleNone, VisibilityNoYear, VisibilityHidden},
+		{"woman for admin", "F", RoleAdmin, VisibilityNoYear, VisibilityVisible},
+		{"woman for super admin", "F", RoleSuperAdmin, VisibilityVisible, VisibilityVisible},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			privacy := NewPrivacy(DefaultPrivacyPolicy(), Viewer{UserID: 1, Role: tt.role, TreeRole: TreeRoleViewer}, nil)
+			member := testMember(1, tt.gender)
+
+			if got := privacy.Visibility(PrivacyFieldDates, member); got != tt.wantDates {
+				t.Errorf("dates = %q, want %q", got, tt.wantDates)
+			}
+			if got := privacy.Visibility(PrivacyFieldPicture, member); got != tt.wantPicture {
+				t.Errorf("picture = %q, want %q", got, tt.wantPicture)
+			}
+			for _, field := range []string{PrivacyFieldNames, PrivacyFieldPlaces, PrivacyFieldProfession, PrivacyFieldNicknames, PrivacyFieldCustomFields} {
+				if got := privacy.Visibility(field, member); got != VisibilityVisible {
+					t.Errorf("%s = %q, want visible", field, got)
+				}
+			}
+		})
+	}
+}
+
+func TestPrivacyRuleConditions(t *testing.T) {
+	yes, no := true, false
+
+	tests := []struct {
+		name   string
+		rule   PrivacyRule
+		viewer Viewer
+		member *Member
+		living map[int]bool
+		want   string
+	}{
+		{
+			name:   "tree role matches",
+			rule:   PrivacyRule{Field: PrivacyFieldNames, Visibility: VisibilityHidden, TreeRoles: []string{TreeRolePublic}},
+			viewer: Viewer{Role: RoleGuest, TreeRole: TreeRolePublic},
+			member: testMember(1, "M"),
+			want:   VisibilityHidden,
+		},
+		{
+			name:   "tree role does not match",
+			rule:   PrivacyRule{Field: PrivacyFieldNames, Visibility: VisibilityHidden, TreeRoles: []string{TreeRolePublic}},
+			viewer: Viewer{UserID: 1, Role: RoleGuest, TreeRole: TreeRoleEditor},
+			member: testMember(1, "M"),
+			want:   VisibilityVisible,
+		},
+		{
+			name:   "below role matches lower roles only",
+			rule:   PrivacyRule{Field: PrivacyFieldNames, Visibility: VisibilityHidden, BelowRole: RoleAdmin},
+			viewer: Viewer{UserID: 1, Role: RoleAdmin, TreeRole: TreeRoleViewer},
+			member: testMember(1, "M"),
+			want:   VisibilityVisible,
+		},
+		{
+			name:   "living rule matches a living member",
+			rule:   PrivacyRule{Field: PrivacyFieldPlaces, Visibility: VisibilityHidden, Living: &yes},
+			viewer: Viewer{UserID: 1, Role: RoleSuperAdmin, TreeRole: TreeRoleOwner},
+			member: testMember(1, "M"),
+			living: map[int]bool{1: true},
+			want:   VisibilityHidden,
+		},
+		{
+			name:   "living rule skips the deceased",
+			rule:   PrivacyRule{Field: PrivacyFieldPlaces, Visibility: VisibilityHidden, Living: &yes},
+			viewer: Viewer{UserID: 1, Role: RoleSuperAdmin, TreeRole: TreeRoleOwner},
+			member: testMember(2, "M"),
+			living: map[int]bool{1: true},
+			want:   VisibilityVisible,
+		},
+		{
+			name:   "deceased rule matches the deceased",
+			rule:   PrivacyRule{Field: PrivacyFieldPlaces, Visibility: VisibilityHidden, Living: &no},
+			viewer: Viewer{UserID: 1, Role: RoleGuest, TreeRole: TreeRoleViewer},
+			member: testMember(2, "M"),
+			living: map[int]bool{1: true},
+			want:   VisibilityHidden,
+		},
+		{
+			name:   "member rule matches listed members",
+			rule:   PrivacyRule{Field: PrivacyFieldProfession, Visibility: VisibilityHidden, MemberIDs: []int{3, 4}},
+			viewer: Viewer{UserID: 1, Role: RoleSuperAdmin, TreeRole: TreeRoleOwner},
+			member: testMember(4, "F"),
+			want:   VisibilityHidden,
+		},
+		{
+			name:   "member rule skips other members",
+			rule:   PrivacyRule{Field: PrivacyFieldProfession, Visibility: VisibilityHidden, MemberIDs: []int{3, 4}},
+			viewer: Viewer{UserID: 1, Role: RoleSuperAdmin, TreeRole: TreeRoleOwner},
+			member: testMember(5, "F"),
+			want:   VisibilityVisible,
+		},
+		{
+			name:   "all conditions must match",
+			rule:   PrivacyRule{Field: PrivacyFieldNames, Visibility: VisibilityHidden, TreeRoles: []string{TreeRoleViewer}, Gender: "F"},
+			viewer: Viewer{UserID: 1, Role: RoleGuest, TreeRole: TreeRoleViewer},
+			member: testMember(1, "M"),
+			want:   VisibilityVisible,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			privacy := NewPrivacy(PrivacyPolicy{Rules: []PrivacyRule{tt.rule}}, tt.viewer, tt.living)
+			if got := privacy.Visibility(tt.rule.Field, tt.member); got != tt.want {
+				t.Errorf("Visibility() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrivacyStrictestRuleWins(t *testing.T) {
+	policy := PrivacyPolicy{Rules: []PrivacyRule{
+		{Field: PrivacyFieldDates, Visibility: VisibilityHidden, TreeRoles: []string{TreeRolePublic}},
+		{Field: PrivacyFieldDates, Visibility: VisibilityNoYear},
+		{Field: PrivacyFieldDates, Visibility: VisibilityVisible, Gender: "M"},
+	}}
+	member := testMember(1, "M")
+
+	if got := NewPrivacy(policy, Viewer{UserID: 1, TreeRole: TreeRoleOwner}, nil).Visibility(PrivacyFieldDates, member); got != VisibilityNoYear {
+		t.Errorf("owner sees dates %q, want %q", got, VisibilityNoYear)
+	}
+	if got := NewPrivacy(policy, Viewer{TreeRole: TreeRolePublic}, nil).Visibility(PrivacyFieldDates, member); got != VisibilityHidden {
+		t.Errorf("public sees dates %q, want %q", got, VisibilityHidden)
+	}
+}
+
+func TestPrivacyApplyMember(t *testing.T) {
+	policy := PrivacyPolicy{Rules: []PrivacyRule{
+		{Field: PrivacyFieldNames, Visibility: VisibilityHidden},
+		{Field: PrivacyFieldDates, Visibility: VisibilityNoYear},
+		{Field: PrivacyFieldPicture, Visibility: VisibilityHidden},
+		{Field: PrivacyFieldPlaces, Visibility: VisibilityHidden},
+		{Field: PrivacyFieldProfession, Visibility: VisibilityHidden},
+		{Field: PrivacyFieldNicknames, Visibility: VisibilityHidden},
+		{Field: PrivacyFieldCustomFields, Visibility: VisibilityHidden},
+	}}
+	member := testMember(1, "M")
+
+	NewPrivacy(policy, Viewer{TreeRole: TreeRolePublic}, nil).ApplyMember(member)
+
+	if member.Names != nil || member.Picture != nil || member.BirthPlaceID != nil || member.Profession != nil ||
+		member.Nicknames != nil || member.CustomFields != nil {
+		t.Errorf("hidden fields kept: %+v", member)
+	}
+	if member.DateOfBirth == nil || member.DateOfBirth.Year() != 1 ||
+		member.DateOfBirth.Month() != time.March || member.DateOfBirth.Day() != 14 {
+		t.Errorf("DateOfBirth = %v, want March 14 without its year", member.DateOfBirth)
+	}
+	if member.DateOfBirthEnd != nil {
+		t.Errorf("DateOfBirthEnd = %v, want nil", member.DateOfBirthEnd)
+	}
+}
+
+func TestPrivacyApplyMemberHiddenDates(t *testing.T) {
+	policy := PrivacyPolicy{Rules: []PrivacyRule{{Field: PrivacyFieldDates, Visibility: VisibilityHidden}}}
+	member := testMember(1, "F")
+
+	NewPrivacy(policy, Viewer{TreeRole: TreeRolePublic}, nil).ApplyMember(member)
+
+	if member.DateOfBirth != nil || member.DateOfDeath != nil {
+		t.Errorf("dates = %v, %v, want nil", member.DateOfBirth, member.DateOfDeath)
+	}
+	if member.Names == nil || member.Picture == nil {
+		t.Error("fields without a rule were stripped")
+	}
+}
+
+func TestPrivacyApplyComputedClearsAge(t *testing.T) {
+	age := 30
+	computed := &MemberWithComputed{
+		Member:    *testMember(1, "F"),
+		FullNames: map[string]string{"en": "Full Name"},
+		Age:       &age,
+	}
+
+	NewPrivacy(DefaultPrivacyPolicy(), Viewer{UserID: 1, Role: RoleAdmin, TreeRole: TreeRoleEditor}, nil).ApplyComputed(computed)
+
+	if computed.Age != nil {
+		t.Errorf("Age = %d, want nil once the year is hidden", *computed.Age)
+	}
+	if computed.FullNames == nil || computed.Picture == nil {
+		t.Error("admin lost the full names or picture")
+	}
+
+	computed.Age = &age
+	NewPrivacy(DefaultPrivacyPolicy(), Viewer{UserID: 1, Role: RoleSuperAdmin, TreeRole: TreeRoleEditor}, nil).ApplyComputed(computed)
+	if computed.Age == nil {
+		t.Error("super admin lost the age")
+	}
+}
+
+func TestPrivacyApplySpouseAndEvent(t *testing.T) {
+	privacy := NewPrivacy(DefaultPrivacyPolicy(), Viewer{UserID: 1, Role: RoleGuest, TreeRole: TreeRoleViewer}, nil)
+
+	picture := "wife.jpg"
+	wife := SpouseWithMemberInfo{MemberID: 2, Gender: "F", Names: map[string]string{"en": "Wife"}, Picture: &picture}
+	privacy.ApplySpouse(&wife)
+	if wife.Picture != nil || wife.Names == nil {
+		t.Errorf("spouse = %+v, want names without picture", wife)
+	}
+
+	event := &MemberEvent{Date: date(2001, time.May, 5)}
+	privacy.ApplyEvent(event, testMember(2, "F"))
+	if event.Date == nil || event.Date.Year() != 1 || event.Date.Day() != 5 {
+		t.Errorf("event date = %v, want May 5 without its year", event.Date)
+	}
+}
+
+func TestPrivacyPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    PrivacyRule
+		wantErr bool
+	}{
+		{"valid", PrivacyRule{Field: PrivacyFieldDates, Visibility: VisibilityNoYear, TreeRoles: []string{TreeRolePublic}, Gender: "F"}, false},
+		{"unknown field", PrivacyRule{Field: "email", Visibility: VisibilityHidden}, true},
+		{"unknown visibility", PrivacyRule{Field: PrivacyFieldNames, Visibility: "blurred"}, true},
+		{"no year outside dates", PrivacyRule{Field: PrivacyFieldNames, Visibility: VisibilityNoYear}, true},
+		{"unknown tree role", PrivacyRule{Field: PrivacyFieldNames, Visibility: VisibilityHidden, TreeRoles: []string{"admin"}}, true},
+		{"unknown gender", PrivacyRule{Field: PrivacyFieldNames, Visibility: VisibilityHidden, Gender: "X"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := PrivacyPolicy{Rules: []PrivacyRule{tt.rule}}.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !IsDomainError(err, ErrCodeInvalidInput) {
+				t.Errorf("Validate() error = %v, want a validation error", err)
+			}
+		})
+	}
+}
+
+func TestPrivacyPolicyUsesLiving(t *testing.T) {
+	living := true
+	if DefaultPrivacyPolicy().UsesLiving() {
+		t.Error("default policy should not depend on living members")
+	}
+	policy := PrivacyPolicy{Rules: []PrivacyRule{{Field: PrivacyFieldNames, Visibility: VisibilityHidden, Living: &living}}}
+	if !policy.UsesLiving() {
+		t.Error("policy with a living rule should report it")
+	}
+}
@@ -0,0 +1,59 @@
+package domain
+
+import "time"
+
+const (
+	ProposalKindCreateMember = "create_member"
+	ProposalKindUpdateMember = "update_member"
+	ProposalKindAddSpouse    = "add_spouse"
+	ProposalKindUpdateSpouse = "update_spouse"
+	ProposalKindRemoveSpouse = "remove_spouse"
+
+	ProposalStatusPending          = "pending"
+	ProposalStatusChangesRequested = "changes_requested"
+	ProposalStatusApproved         = "approved"
+	ProposalStatusRejected         = "rejected"
+)
+
+// Proposal is an edit suggested by a tree member that an editor approves,
+// rejects or sends back for changes
+type Proposal struct {
+	ProposalID     int
+	TreeID         int
+	ProposerUserID int
+	ProposerName   string
+	Kind           string
+	MemberID       *int    // member updated, or created once approved
+	SpouseID       *int    // spouse relationship updated or removed
+	Member         *Member // proposed member for member proposals
+	Spouse         *Spouse // proposed relationship for added and updated spouses
+	BaseVersion    *int    // member version the proposer edited
+	BaseSpouse     *Spouse // relationship as the proposer saw it
+	Comment        *string
+	Status         string
+	ReviewerUserID *int
+	ReviewComment  *string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	ReviewedAt     *time.Time
+}
+
+// IsOpen reports whether the proposal still waits on its proposer or an editor
+func (p *Proposal) IsOpen() bool {
+	return p.Status == ProposalStatusPending || p.Status == ProposalStatusChangesRequested
+}
+
+// ProposalReview is a proposal with its changes against the tree as it is now.
+// A stale proposal was made against a member version or relationship that has
+// been edited since.
+type ProposalReview struct {
+	Proposal *Proposal
+	Changes  []FieldChange
+	Stale    bool
+}
+
+type ProposalFilter struct {
+	TreeID         int
+	Status         *string
+	ProposerUserID *int
+}
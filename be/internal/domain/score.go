@@ -16,6 +16,18 @@ const (
 	PointsSpouse      = 3
 	PointsNicknames   = 1
 	PointsProfession  = 1
+
+	// Life events
+	PointsEvent            = 2
+	PointsEventDate        = 2
+	PointsEventPlace       = 2
+	PointsEventDescription = 1
+
+	// Long-form text
+	PointsBiography = 3
+
+	// Tree specific fields
+	PointsCustomField = 1
 )
 
 type Score struct {
@@ -0,0 +1,84 @@
+package domain
+
+import "time"
+
+const (
+	SourceTypeDocument   = "document"
+	SourceTypeInterview  = "interview"
+	SourceTypeGravestone = "gravestone"
+	SourceTypeRegistry   = "registry"
+	SourceTypeOther      = "other"
+)
+
+const (
+	CitationSubjectMember = "member"
+	CitationSubjectSpouse = "spouse"
+	CitationSubjectEvent  = "event"
+)
+
+const (
+	ConfidenceLow    = "low"
+	ConfidenceMedium = "medium"
+	ConfidenceHigh   = "high"
+)
+
+// citableFields lists the facts a citation may point at per subject type,
+// the event field stands for the event having happened at all
+var citableFields = map[string][]string{
+	CitationSubjectMember: {
+		"names", "gender", "nicknames", "profession", "father_id", "mother_id",
+		"date_of_birth", "date_of_death", "birth_place_id", "death_place_id", "burial_place_id",
+	},
+	CitationSubjectSpouse: {"marriage_date", "divorce_date", "marriage_place_id"},
+	CitationSubjectEvent:  {"event", "date", "place_id", "description"},
+}
+
+// IsCitableField reports whether a citation can point at the field of the subject type
+func IsCitableField(subjectType, fieldName string) bool {
+	for _, field := range citableFields[subjectType] {
+		if field == fieldName {
+			return true
+		}
+	}
+	return false
+}
+
+type Source struct {
+	SourceID      int       `json:"source_id"`
+	TreeID        int       `json:"tree_id"`
+	SourceType    string    `json:"source_type"`
+	Title         string    `json:"title"`
+	Author        *string   `json:"author"`
+	Description   *string   `json:"description"`
+	AttachmentKey *string   `json:"attachment_key"`
+	CreatedBy     int       `json:"created_by"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type Citation struct {
+	CitationID  int       `json:"citation_id"`
+	SourceID    int       `json:"source_id"`
+	SubjectType string    `json:"subject_type"`
+	SubjectID   int       `json:"subject_id"`
+	FieldName   string    `json:"field_name"`
+	Confidence  string    `json:"confidence"`
+	Detail      *string   `json:"detail"`
+	CreatedBy   int       `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// FactRef names one fact of a member, spouse relation or event
+type FactRef struct {
+	SubjectType string `json:"subject_type"`
+	SubjectID   int    `json:"subject_id"`
+	FieldName   string `json:"field_name"`
+}
+
+// UnsourcedFact is a filled fact without any citation, MemberIDs are the
+// members the fact belongs to
+type UnsourcedFact struct {
+	FactRef
+	MemberIDs []int `json:"member_ids"`
+}
@@ -3,20 +3,85 @@ package domain
 import "time"
 
 type Spouse struct {
-	SpouseID     int        `json:"spouse_id"`
-	FatherID     int        `json:"father_id"`
-	MotherID     int        `json:"mother_id"`
-	MarriageDate *time.Time `json:"marriage_date"`
-	DivorceDate  *time.Time `json:"divorce_date"`
-	DeletedAt    *time.Time `json:"deleted_at,omitempty"`
+	SpouseID              int        `json:"spouse_id"`
+	FatherID              int        `json:"father_id"`
+	MotherID              int        `json:"mother_id"`
+	MarriageDate          *time.Time `json:"marriage_date"`
+	MarriageDateQualifier string     `json:"marriage_date_qualifier"`
+	MarriageDateEnd       *time.Time `json:"marriage_date_end"`
+	MarriageDateCalendar  string     `json:"marriage_date_calendar"`
+	DivorceDate           *time.Time `json:"divorce_date"`
+	DivorceDateQualifier  string     `json:"divorce_date_qualifier"`
+	DivorceDateEnd        *time.Time `json:"divorce_date_end"`
+	DivorceDateCalendar   string     `json:"divorce_date_calendar"`
+	MarriagePlaceID       *int       `json:"marriage_place_id"`
+	DeletedAt             *time.Time `json:"deleted_at,omitempty"`
+}
+
+func (s *Spouse) MarriageRange() *DateRange {
+	return NewDateRange(s.MarriageDate, s.MarriageDateQualifier, s.MarriageDateEnd)
+}
+
+func (s *Spouse) DivorceRange() *DateRange {
+	return NewDateRange(s.DivorceDate, s.DivorceDateQualifier, s.DivorceDateEnd)
 }
 
 type SpouseWithMemberInfo struct {
-	SpouseID     int               `json:"spouse_id"`
-	MemberID     int               `json:"member_id"`
-	Names        map[string]string `json:"names"`
-	Gender       string            `json:"gender"`
-	Picture      *string           `json:"picture"`
-	MarriageDate *time.Time        `json:"marriage_date"`
-	DivorceDate  *time.Time        `json:"divorce_date"`
+	SpouseID              int               `json:"spouse_id"`
+	MemberID              int               `json:"member_id"`
+	Names                 map[string]string `json:"names"`
+	Gender                string            `json:"gender"`
+	Picture               *string           `json:"picture"`
+	MarriageDate          *time.Time        `json:"marriage_date"`
+	MarriageDateQualifier string            `json:"marriage_date_qualifier"`
+	MarriageDateEnd       *time.Time        `json:"marriage_date_end"`
+	MarriageDateCalendar  string            `json:"marriage_date_calendar"`
+	DivorceDate           *time.Time        `json:"divorce_date"`
+	DivorceDateQualifier  string            `json:"divorce_date_qualifier"`
+	DivorceDateEnd        *time.Time        `json:"divorce_date_end"`
+	DivorceDateCalendar   string            `json:"divorce_date_calendar"`
+	MarriagePlaceID       *int              `json:"marriage_place_id"`
+	IsRedacted            bool              `json:"is_redacted,omitempty"`
+}
+
+func (s *SpouseWithMemberInfo) MarriageRange() *DateRange {
+	return NewDateRange(s.MarriageDate, s.MarriageDateQualifier, s.MarriageDateEnd)
+}
+
+// WithMember describes the relationship from the side of the other partner
+// SameValues reports whether both relationships have the same dates and place
+func (s *Spouse) SameValues(other *Spouse) bool {
+	return sameTime(s.MarriageDate, other.MarriageDate) &&
+		s.MarriageDateQualifier == other.MarriageDateQualifier &&
+		sameTime(s.MarriageDateEnd, other.MarriageDateEnd) &&
+		s.MarriageDateCalendar == other.MarriageDateCalendar &&
+		sameTime(s.DivorceDate, other.DivorceDate) &&
+		s.DivorceDateQualifier == other.DivorceDateQualifier &&
+		sameTime(s.DivorceDateEnd, other.DivorceDateEnd) &&
+		s.DivorceDateCalendar == other.DivorceDateCalendar &&
+		(s.MarriagePlaceID == nil && other.MarriagePlaceID == nil || sameID(s.MarriagePlaceID, other.MarriagePlaceID))
+}
+
+func (s *Spouse) WithMember(memberID int) SpouseWithMemberInfo {
+	return SpouseWithMemberInfo{
+		SpouseID:              s.SpouseID,
+		MemberID:              memberID,
+		MarriageDate:          s.MarriageDate,
+		MarriageDateQualifier: s.MarriageDateQualifier,
+		MarriageDateEnd:       s.MarriageDateEnd,
+		MarriageDateCalendar:  s.MarriageDateCalendar,
+		DivorceDate:           s.DivorceDate,
+		DivorceDateQualifier:  s.DivorceDateQualifier,
+		DivorceDateEnd:        s.DivorceDateEnd,
+		DivorceDateCalendar:   s.DivorceDateCalendar,
+		MarriagePlaceID:       s.MarriagePlaceID,
+	}
+}
+
+// PartnerOf returns the other partner of the relationship
+func (s *Spouse) PartnerOf(memberID int) int {
+	if s.FatherID == memberID {
+		return s.MotherID
+	}
+	return s.FatherID
 }
@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+const (
+	TimelineEventBirth      = "birth"
+	TimelineEventDeath      = "death"
+	TimelineEventMarriage   = "marriage"
+	TimelineEventDivorce    = "divorce"
+	TimelineEventSeparation = "separation"
+	TimelineEventWidowed    = "widowed"
+)
+
+type TimelineFilter struct {
+	TreeID   int
+	From     *time.Time
+	To       *time.Time
+	MemberID *int
+	Degree   int
+}
+
+type TimelineEntry struct {
+	EventType     string            `json:"event_type"`
+	Date          time.Time         `json:"date"`
+	DateQualifier string            `json:"date_qualifier"`
+	DateEnd       *time.Time        `json:"date_end,omitempty"`
+	DateCalendar  string            `json:"date_calendar"`
+	MemberID      int               `json:"member_id"`
+	MemberNames   map[string]string `json:"member_names"`
+	PartnerID     *int              `json:"partner_id,omitempty"`
+	PartnerNames  map[string]string `json:"partner_names,omitempty"`
+	FamilyUnitID  *int              `json:"family_unit_id,omitempty"`
+	EventID       *int              `json:"event_id,omitempty"`
+	CustomType    *string           `json:"custom_type,omitempty"`
+	PlaceID       *int              `json:"place_id,omitempty"`
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// TransliterationSource is the language missing names are suggested from
+const TransliterationSource = "ar"
+
+// NameScripts are the languages whose missing names are suggested, with the
+// script they are written in
+var NameScripts = map[string]string{
+	"en": "latin",
+	"ru": "cyrillic",
+}
+
+// CanSuggestName reports whether a missing name in the language is filled in
+// from the Arabic name
+func CanSuggestName(languageCode string) bool {
+	_, ok := NameScripts[languageCode]
+	return ok
+}
+
+// NameSpelling is the spelling a tree uses for an Arabic name or word
+type NameSpelling struct {
+	TreeID       int       `json:"tree_id"`
+	Arabic       string    `json:"arabic"`
+	LanguageCode string    `json:"language_code"`
+	Spelling     string    `json:"spelling"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// TrashedMember is a soft-deleted member, its names are the ones it had when
+// it was deleted
+type TrashedMember struct {
+	MemberID  int               `json:"member_id"`
+	TreeID    int               `json:"tree_id"`
+	Names     map[string]string `json:"names"`
+	Gender    string            `json:"gender"`
+	FatherID  *int              `json:"father_id"`
+	MotherID  *int              `json:"mother_id"`
+	DeletedAt time.Time         `json:"deleted_at"`
+}
+
+// Trash is what was deleted from a tree and can still be restored
+type Trash struct {
+	Members []*TrashedMember `json:"members"`
+	Spouses []*Spouse        `json:"spouses"`
+}
@@ -23,3 +23,15 @@ type UserFilter struct {
 	RoleID   *int
 	IsActive *bool
 }
+
+// RoleHistory is a grant or revoke of a user's global role
+type RoleHistory struct {
+	HistoryID     int
+	UserID        int
+	OldRoleID     *int
+	NewRoleID     *int
+	ChangedBy     int
+	ChangedByName string
+	ChangedAt     time.Time
+	ActionType    string
+}
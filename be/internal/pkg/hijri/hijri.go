@@ -0,0 +1,104 @@
+// Package hijri converts between the Gregorian calendar and the tabular
+// Islamic calendar (civil epoch, 30-year cycle with the Kuwaiti leap years).
+// Observed or Umm al-Qura dates may differ from it by a day or two.
+package hijri
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// epochJDN is the Julian day number of 1 Muharram 1 AH (16 July 622 Julian)
+	epochJDN = 1948440
+	// unixEpochJDN is the Julian day number of 1 January 1970
+	unixEpochJDN = 2440588
+
+	layout = "%04d-%02d-%02d"
+)
+
+type Date struct {
+	Year  int
+	Month int
+	Day   int
+}
+
+// Parse reads a Hijri date written as YYYY-MM-DD
+func Parse(value string) (Date, error) {
+	var d Date
+	var rest string
+	n, _ := fmt.Sscanf(value, "%d-%d-%d%s", &d.Year, &d.Month, &d.Day, &rest)
+	if n != 3 || len(value) != 10 {
+		return Date{}, fmt.Errorf("invalid hijri date %q, expected YYYY-MM-DD", value)
+	}
+	if !d.Valid() {
+		return Date{}, fmt.Errorf("hijri date %q does not exist", value)
+	}
+	return d, nil
+}
+
+// IsLeap reports whether the year has 355 days, the extra day closing Dhu al-Hijjah
+func IsLeap(year int) bool {
+	return (14+11*year)%30 < 11
+}
+
+// DaysInMonth returns the length of a month: odd months have 30 days, even
+// months 29, except Dhu al-Hijjah in a leap year
+func DaysInMonth(year, month int) int {
+	if month%2 == 1 || (month == 12 && IsLeap(year)) {
+		return 30
+	}
+	return 29
+}
+
+func (d Date) Valid() bool {
+	return d.Year >= 1 && d.Month >= 1 && d.Month <= 12 && d.Day >= 1 && d.Day <= DaysInMonth(d.Year, d.Month)
+}
+
+func (d Date) String() string {
+	return fmt.Sprintf(layout, d.Year, d.Month, d.Day)
+}
+
+// ToGregorian returns the Gregorian day, at midnight UTC, the Hijri date falls on
+func (d Date) ToGregorian() time.Time {
+	return time.Unix(int64(d.julianDay()-unixEpochJDN)*86400, 0).UTC()
+}
+
+// FromGregorian returns the Hijri date of a Gregorian day; ok is false for
+// days before the Hijri epoch
+func FromGregorian(t time.Time) (Date, bool) {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	jdn := int(day.Unix()/86400) + unixEpochJDN
+	if jdn < epochJDN {
+		return Date{}, false
+	}
+
+	year := (30*(jdn-epochJDN) + 10646) / 10631
+	month := 1
+	for month < 12 && jdn >= (Date{Year: year, Month: month + 1, Day: 1}).julianDay() {
+		month++
+	}
+	first := Date{Year: year, Month: month, Day: 1}.julianDay()
+	return Date{Year: year, Month: month, Day: jdn - first + 1}, true
+}
+
+// StartOfYear returns 1 Muharram of the year
+func StartOfYear(year int) Date {
+	return Date{Year: year, Month: 1, Day: 1}
+}
+
+// EndOfYear returns the last day of Dhu al-Hijjah of the year
+func EndOfYear(year int) Date {
+	return Date{Year: year, Month: 12, Day: DaysInMonth(year, 12)}
+}
+
+// EndOfMonth returns the last day of the date's month
+func (d Date) EndOfMonth() Date {
+	return Date{Year: d.Year, Month: d.Month, Day: DaysInMonth(d.Year, d.Month)}
+}
+
+func (d Date) julianDay() int {
+	// months alternate 30 and 29 days, so the days before month m are ceil(29.5*(m-1))
+	monthDays := (59*(d.Month-1) + 1) / 2
+	return d.Day + monthDays + (d.Year-1)*354 + (3+11*d.Year)/30 + epochJDN - 1
+}
@@ -0,0 +1,115 @@
+package hijri
+
+import (
+	"testing"
+	"time"
+)
+
+func TestToGregorian(t *testing.T) {
+	tests := []struct {
+		name string
+		date Date
+		want time.Time
+	}{
+		{"epoch", Date{Year: 1, Month: 1, Day: 1}, time.Date(622, time.July, 19, 0, 0, 0, 0, time.UTC)},
+		{"new year 1445", Date{Year: 1445, Month: 1, Day: 1}, time.Date(2023, time.July, 19, 0, 0, 0, 0, time.UTC)},
+		{"ramadan 1444", Date{Year: 1444, Month: 9, Day: 1}, time.Date(2023, time.March, 23, 0, 0, 0, 0, time.UTC)},
+		{"leap day", Date{Year: 1445, Month: 12, Day: 30}, time.Date(2024, time.July, 7, 0, 0, 0, 0, time.UTC)},
+		{"after the leap day", Date{Year: 1446, Month: 1, Day: 1}, time.Date(2024, time.July, 8, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.date.ToGregorian(); !got.Equal(tt.want) {
+				t.Errorf("%s.ToGregorian() = %s, want %s", tt.date, got.Format(time.DateOnly), tt.want.Format(time.DateOnly))
+			}
+		})
+	}
+}
+
+func TestFromGregorian(t *testing.T) {
+	tests := []struct {
+		name   string
+		day    time.Time
+		want   Date
+		wantOK bool
+	}{
+		{"epoch", time.Date(622, time.July, 19, 0, 0, 0, 0, time.UTC), Date{Year: 1, Month: 1, Day: 1}, true},
+		{"day before the epoch", time.Date(622, time.July, 18, 0, 0, 0, 0, time.UTC), Date{}, false},
+		{"millennium", time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC), Date{Year: 1420, Month: 9, Day: 24}, true},
+		{"time of day ignored", time.Date(2024, time.January, 1, 23, 59, 0, 0, time.UTC), Date{Year: 1445, Month: 6, Day: 19}, true},
+		{"leap day", time.Date(2024, time.July, 7, 0, 0, 0, 0, time.UTC), Date{Year: 1445, Month: 12, Day: 30}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := FromGregorian(tt.day)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("FromGregorian(%s) = %s, %v, want %s, %v", tt.day.Format(time.DateOnly), got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	// every day of a full 30-year cycle converts back to itself
+	start := Date{Year: 1441, Month: 1, Day: 1}.ToGregorian()
+	end := Date{Year: 1471, Month: 1, Day: 1}.ToGregorian()
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		date, ok := FromGregorian(day)
+		if !ok || !date.Valid() {
+			t.Fatalf("FromGregorian(%s) = %s, %v", day.Format(time.DateOnly), date, ok)
+		}
+		if back := date.ToGregorian(); !back.Equal(day) {
+			t.Fatalf("%s converts back to %s, want %s", date, back.Format(time.DateOnly), day.Format(time.DateOnly))
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Date
+		wantErr bool
+	}{
+		{"1445-06-19", Date{Year: 1445, Month: 6, Day: 19}, false},
+		{"1445-12-30", Date{Year: 1445, Month: 12, Day: 30}, false},
+		{"1444-12-30", Date{}, true},
+		{"1445-02-30", Date{}, true},
+		{"1445-13-01", Date{}, true},
+		{"1445-6-19", Date{}, true},
+		{"1445-06-19x", Date{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := Parse(tt.value)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("Parse(%q) = %s, %v, want %s, error %v", tt.value, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestYearLength(t *testing.T) {
+	tests := []struct {
+		year int
+		leap bool
+	}{
+		{1442, true},
+		{1444, false},
+		{1445, true},
+		{1446, false},
+	}
+
+	for _, tt := range tests {
+		days := EndOfYear(tt.year).ToGregorian().Sub(StartOfYear(tt.year).ToGregorian()).Hours()/24 + 1
+		want := 354.0
+		if tt.leap {
+			want = 355
+		}
+		if IsLeap(tt.year) != tt.leap || days != want {
+			t.Errorf("year %d: IsLeap = %v with %v days, want %v with %v", tt.year, IsLeap(tt.year), days, tt.leap, want)
+		}
+	}
+}
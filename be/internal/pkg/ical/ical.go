@@ -0,0 +1,111 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	ContentType = "text/calendar; charset=utf-8"
+
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405Z"
+	maxLineOctets  = 75
+)
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+)
+
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Date        time.Time
+	Yearly      bool
+}
+
+type Calendar struct {
+	ProdID  string
+	Name    string
+	Events  []Event
+	Stamped time.Time
+}
+
+// Marshal renders the calendar as an RFC 5545 document with all-day events
+func (c *Calendar) Marshal() []byte {
+	stamp := c.Stamped
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+
+	var buf bytes.Buffer
+	writeLine(&buf, "BEGIN:VCALENDAR")
+	writeLine(&buf, "VERSION:2.0")
+	writeLine(&buf, "PRODID:"+c.ProdID)
+	writeLine(&buf, "CALSCALE:GREGORIAN")
+	writeLine(&buf, "METHOD:PUBLISH")
+	if c.Name != "" {
+		writeLine(&buf, "X-WR-CALNAME:"+escapeText(c.Name))
+	}
+
+	for _, event := range c.Events {
+		start := time.Date(event.Date.Year(), event.Date.Month(), event.Date.Day(), 0, 0, 0, 0, time.UTC)
+
+		writeLine(&buf, "BEGIN:VEVENT")
+		writeLine(&buf, "UID:"+event.UID)
+		writeLine(&buf, "DTSTAMP:"+stamp.UTC().Format(dateTimeLayout))
+		writeLine(&buf, "DTSTART;VALUE=DATE:"+start.Format(dateLayout))
+		writeLine(&buf, "DTEND;VALUE=DATE:"+start.AddDate(0, 0, 1).Format(dateLayout))
+		if event.Yearly {
+			writeLine(&buf, "RRULE:"+yearlyRule(start))
+		}
+		writeLine(&buf, "SUMMARY:"+escapeText(event.Summary))
+		if event.Description != "" {
+			writeLine(&buf, "DESCRIPTION:"+escapeText(event.Description))
+		}
+		writeLine(&buf, "TRANSP:TRANSPARENT")
+		writeLine(&buf, "END:VEVENT")
+	}
+
+	writeLine(&buf, "END:VCALENDAR")
+	return buf.Bytes()
+}
+
+// yearlyRule moves February 29 to the last day of February so the event
+// still shows up in common years, which a plain yearly rule would skip
+func yearlyRule(start time.Time) string {
+	if start.Month() == time.February && start.Day() == 29 {
+		return "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=-1"
+	}
+	return "FREQ=YEARLY"
+}
+
+func escapeText(value string) string {
+	return textEscaper.Replace(value)
+}
+
+// writeLine folds content lines longer than 75 octets without splitting a
+// multi-byte character, then terminates them with CRLF
+func writeLine(buf *bytes.Buffer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		// continuation lines lose one octet to the leading space
+		limit = maxLineOctets - 1
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
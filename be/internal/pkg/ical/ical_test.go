@@ -0,0 +1,107 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestYearlyRule(t *testing.T) {
+	tests := []struct {
+		name string
+		date time.Time
+		want string
+	}{
+		{"common day", time.Date(1990, time.March, 14, 0, 0, 0, 0, time.UTC), "FREQ=YEARLY"},
+		{"february 28", time.Date(1990, time.February, 28, 0, 0, 0, 0, time.UTC), "FREQ=YEARLY"},
+		{"leap day", time.Date(2000, time.February, 29, 0, 0, 0, 0, time.UTC), "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := yearlyRule(tt.date); got != tt.want {
+				t.Errorf("yearlyRule() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"short", "SUMMARY:Birthday"},
+		{"exactly 75 octets", "SUMMARY:" + strings.Repeat("a", 67)},
+		{"long ascii", "DESCRIPTION:" + strings.Repeat("abcdefghij", 20)},
+		{"long arabic", "SUMMARY:" + strings.Repeat("عيد ميلاد محمد ", 10)},
+		{"long mixed", "SUMMARY:a" + strings.Repeat("день рождения ", 10)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writeLine(&buf, tt.line)
+			out := buf.String()
+
+			if !strings.HasSuffix(out, "\r\n") {
+				t.Fatalf("line isn't terminated with CRLF: %q", out)
+			}
+			for i, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+				if len(line) > maxLineOctets {
+					t.Errorf("line %d is %d octets long", i, len(line))
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d splits a character: %q", i, line)
+				}
+				if i > 0 && !strings.HasPrefix(line, " ") {
+					t.Errorf("continuation line %d doesn't start with a space", i)
+				}
+			}
+			if unfolded := strings.ReplaceAll(strings.TrimSuffix(out, "\r\n"), "\r\n ", ""); unfolded != tt.line {
+				t.Errorf("unfolded = %q, want %q", unfolded, tt.line)
+			}
+		})
+	}
+}
+
+func TestEscapeText(t *testing.T) {
+	if got, want := escapeText("a\\b;c,d\r\ne\nf"), `a\\b\;c\,d\ne\nf`; got != want {
+		t.Errorf("escapeText() = %q, want %q", got, want)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	calendar := &Calendar{
+		ProdID:  "-//Family Tree//Calendar//EN",
+		Name:    "Family, birthdays",
+		Stamped: time.Date(2026, time.October, 19, 10, 30, 0, 0, time.UTC),
+		Events: []Event{
+			{UID: "birthday-1", Summary: "Ali's birthday", Date: time.Date(2000, time.February, 29, 15, 0, 0, 0, time.UTC), Yearly: true},
+			{UID: "death-2", Summary: "In memory of Omar", Description: "1950; 2020", Date: time.Date(2020, time.December, 31, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	out := string(calendar.Marshal())
+
+	for _, line := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"X-WR-CALNAME:Family\\, birthdays\r\n",
+		"DTSTAMP:20261019T103000Z\r\n",
+		"DTSTART;VALUE=DATE:20000229\r\nDTEND;VALUE=DATE:20000301\r\nRRULE:FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=-1\r\n",
+		"DTSTART;VALUE=DATE:20201231\r\nDTEND;VALUE=DATE:20210101\r\nSUMMARY:In memory of Omar\r\nDESCRIPTION:1950\\; 2020\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, line) {
+			t.Errorf("calendar is missing %q:\n%s", line, out)
+		}
+	}
+	if got := strings.Count(out, "BEGIN:VEVENT"); got != 2 {
+		t.Errorf("events = %d, want 2", got)
+	}
+	if got := strings.Count(out, "RRULE"); got != 1 {
+		t.Errorf("recurring events = %d, want 1", got)
+	}
+}
@@ -0,0 +1,105 @@
+// Package markdown cleans user written Markdown before it is stored. Clients
+// render it with raw HTML disabled, this package makes sure the stored text
+// stays safe for renderers that allow it.
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	// autolinkPattern matches the <https://...> and <mailto:...> forms, the
+	// only angle bracket syntax kept as is
+	autolinkPattern = regexp.MustCompile(`(?i)<(?:https?|mailto):[^<>\s]+>`)
+
+	// inlineLinkPattern matches inline link and image destinations, allowing
+	// one level of balanced parentheses inside the destination
+	inlineLinkPattern = regexp.MustCompile(`\]\(((?:[^()]|\([^()]*\))*)\)`)
+
+	// referencePattern matches reference definitions, whose destination may
+	// start on the next line
+	referencePattern = regexp.MustCompile(`(?m)^[ ]{0,3}\[[^\]]+\]:[ \t]*(?:\n[ \t]*)?(.*)$`)
+
+	// schemePattern matches a URL scheme, anything else before the first
+	// colon makes the link relative
+	schemePattern = regexp.MustCompile(`^[a-z][a-z0-9+.-]*$`)
+
+	// safeSchemes are the only schemes links may use
+	safeSchemes = map[string]bool{"http": true, "https": true, "mailto": true}
+)
+
+// Sanitize normalizes line endings, drops control characters, escapes raw
+// HTML and neutralizes unsafe link targets
+func Sanitize(input string) string {
+	text := strings.ReplaceAll(input, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	text = strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' {
+			return r
+		}
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, text)
+
+	text = escapeHTML(text)
+	text = inlineLinkPattern.ReplaceAllStringFunc(text, func(link string) string {
+		if safeLink(inlineLinkPattern.FindStringSubmatch(link)[1]) {
+			return link
+		}
+		return "](#)"
+	})
+	text = referencePattern.ReplaceAllStringFunc(text, func(definition string) string {
+		if safeLink(referencePattern.FindStringSubmatch(definition)[1]) {
+			return definition
+		}
+		return ""
+	})
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// escapeHTML escapes every '<' that does not open an autolink, which is
+// enough to turn tags and comments into plain text
+func escapeHTML(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+
+	last := 0
+	for _, loc := range autolinkPattern.FindAllStringIndex(text, -1) {
+		b.WriteString(strings.ReplaceAll(text[last:loc[0]], "<", "&lt;"))
+		b.WriteString(text[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	b.WriteString(strings.ReplaceAll(text[last:], "<", "&lt;"))
+	return b.String()
+}
+
+// safeLink reports whether a link destination is relative or uses one of the
+// safe schemes. The destination is read the way a browser ends up reading it,
+// entities and backslash escapes decoded and whitespace and control characters
+// dropped, so none of them can hide a scheme.
+func safeLink(destination string) bool {
+	destination = html.UnescapeString(destination)
+	destination = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || unicode.IsControl(r) || r == '\\' {
+			return -1
+		}
+		return r
+	}, destination)
+	destination = strings.ToLower(strings.TrimLeft(destination, "<"))
+
+	scheme, _, found := strings.Cut(destination, ":")
+	if !found || !schemePattern.MatchString(scheme) {
+		return true
+	}
+	return safeSchemes[scheme]
+}
@@ -0,0 +1,60 @@
+package markdown
+
+import "testing"
+
+func TestSanitizeLinks(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"https link", "[a](https://example.com)", "[a](https://example.com)"},
+		{"mailto link", "[a](mailto:me@example.com)", "[a](mailto:me@example.com)"},
+		{"relative link", "[a](/trees/1 \"Tree: one\")", "[a](/trees/1 \"Tree: one\")"},
+		{"fragment", "[a](#top)", "[a](#top)"},
+		{"javascript", "[a](javascript:alert(1))", "[a](#)"},
+		{"upper case", "[a](JavaScript:alert(1))", "[a](#)"},
+		{"angle brackets", "[a](<javascript:alert(1)>)", "[a](#)"},
+		{"decimal entity", "[a](&#106;avascript:alert(1))", "[a](#)"},
+		{"hex entity", "[a](&#x6A;avascript:alert(1))", "[a](#)"},
+		{"named entity", "[a](javascript&colon;alert(1))", "[a](#)"},
+		{"tab entity", "[a](java&Tab;script:alert(1))", "[a](#)"},
+		{"backslash escape", "[a](javascript\\:alert(1))", "[a](#)"},
+		{"control character", "[a](java\x01script:alert(1))", "[a](#)"},
+		{"image data", "![a](data:image/svg+xml;base64,PHN2Zz4=)", "![a](#)"},
+		{"unknown scheme", "[a](ftp://example.com)", "[a](#)"},
+		{"reference", "[a]: https://example.com", "[a]: https://example.com"},
+		{"unsafe reference", "text\n[a]: javascript:alert(1)", "text"},
+		{"reference entity", "[a]: &#x6A;avascript:alert(1)", ""},
+		{"reference next line", "[a]:\n  vbscript:msgbox(1)\ntext", "text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sanitize(tt.input); got != tt.want {
+				t.Errorf("Sanitize(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeHTML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"tag", "<script>alert(1)</script>", "&lt;script>alert(1)&lt;/script>"},
+		{"autolink", "<https://example.com>", "<https://example.com>"},
+		{"unsafe autolink", "<javascript:alert(1)>", "&lt;javascript:alert(1)>"},
+		{"line endings", "a\r\nb\rc  \n", "a\nb\nc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sanitize(tt.input); got != tt.want {
+				t.Errorf("Sanitize(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,272 @@
+// Package translit writes Arabic personal names in Latin or Cyrillic letters.
+// Names are usually written without short vowels, so the rules can only guess
+// them: common names come from a dictionary and everything else is a
+// suggestion for a human to confirm.
+package translit
+
+import (
+	"strings"
+	"unicode"
+)
+
+type Script string
+
+const (
+	Latin    Script = "latin"
+	Cyrillic Script = "cyrillic"
+)
+
+type table struct {
+	letters map[rune]string
+	// vowels written for fatha, damma and kasra, the first is also guessed
+	// between two consonants
+	a, u, i string
+	// w and y are the consonant readings of و and ي
+	w, y    string
+	article string
+	abd     string
+}
+
+var tables = map[Script]*table{
+	Latin: {
+		letters: map[rune]string{
+			'ب': "b", 'ت': "t", 'ث': "th", 'ج': "j", 'ح': "h", 'خ': "kh", 'د': "d", 'ذ': "dh",
+			'ر': "r", 'ز': "z", 'س': "s", 'ش': "sh", 'ص': "s", 'ض': "d", 'ط': "t", 'ظ': "z",
+			'ع': "", 'غ': "gh", 'ف': "f", 'ق': "q", 'ك': "k", 'ل': "l", 'م': "m", 'ن': "n",
+			'ه': "h", 'ء': "", 'ؤ': "", 'ئ': "",
+		},
+		a: "a", u: "u", i: "i",
+		w: "w", y: "y",
+		article: "al-",
+		abd:     "Abdul",
+	},
+	Cyrillic: {
+		letters: map[rune]string{
+			'ب': "б", 'ت': "т", 'ث': "с", 'ج': "дж", 'ح': "х", 'خ': "х", 'د': "д", 'ذ': "з",
+			'ر': "р", 'ز': "з", 'س': "с", 'ش': "ш", 'ص': "с", 'ض': "д", 'ط': "т", 'ظ': "з",
+			'ع': "", 'غ': "г", 'ف': "ф", 'ق': "к", 'ك': "к", 'ل': "л", 'م': "м", 'ن': "н",
+			'ه': "х", 'ء': "", 'ؤ': "", 'ئ': "",
+		},
+		a: "а", u: "у", i: "и",
+		w: "в", y: "й",
+		article: "аль-",
+		abd:     "Абдул",
+	},
+}
+
+// common are the usual spellings of frequent names, which the letter rules
+// cannot vowel correctly
+var common = map[string]map[Script]string{
+	"محمد":       {Latin: "Muhammad", Cyrillic: "Мухаммад"},
+	"أحمد":       {Latin: "Ahmad", Cyrillic: "Ахмад"},
+	"احمد":       {Latin: "Ahmad", Cyrillic: "Ахмад"},
+	"محمود":      {Latin: "Mahmoud", Cyrillic: "Махмуд"},
+	"مصطفى":      {Latin: "Mustafa", Cyrillic: "Мустафа"},
+	"علي":        {Latin: "Ali", Cyrillic: "Али"},
+	"عمر":        {Latin: "Omar", Cyrillic: "Умар"},
+	"عثمان":      {Latin: "Uthman", Cyrillic: "Усман"},
+	"حسن":        {Latin: "Hassan", Cyrillic: "Хасан"},
+	"حسين":       {Latin: "Hussein", Cyrillic: "Хусейн"},
+	"خالد":       {Latin: "Khalid", Cyrillic: "Халид"},
+	"إبراهيم":    {Latin: "Ibrahim", Cyrillic: "Ибрахим"},
+	"ابراهيم":    {Latin: "Ibrahim", Cyrillic: "Ибрахим"},
+	"إسماعيل":    {Latin: "Ismail", Cyrillic: "Исмаил"},
+	"يوسف":       {Latin: "Yusuf", Cyrillic: "Юсуф"},
+	"يعقوب":      {Latin: "Yaqub", Cyrillic: "Якуб"},
+	"يحيى":       {Latin: "Yahya", Cyrillic: "Яхья"},
+	"موسى":       {Latin: "Musa", Cyrillic: "Муса"},
+	"عيسى":       {Latin: "Isa", Cyrillic: "Иса"},
+	"سليمان":     {Latin: "Sulaiman", Cyrillic: "Сулейман"},
+	"داود":       {Latin: "Dawud", Cyrillic: "Дауд"},
+	"صالح":       {Latin: "Salih", Cyrillic: "Салих"},
+	"سعيد":       {Latin: "Said", Cyrillic: "Саид"},
+	"سعد":        {Latin: "Saad", Cyrillic: "Саад"},
+	"عبدالله":    {Latin: "Abdullah", Cyrillic: "Абдуллах"},
+	"عبد الله":   {Latin: "Abdullah", Cyrillic: "Абдуллах"},
+	"عبدالرحمن":  {Latin: "Abdulrahman", Cyrillic: "Абдуррахман"},
+	"عبد الرحمن": {Latin: "Abdulrahman", Cyrillic: "Абдуррахман"},
+	"جعفر":       {Latin: "Jafar", Cyrillic: "Джафар"},
+	"طارق":       {Latin: "Tariq", Cyrillic: "Тарик"},
+	"زياد":       {Latin: "Ziad", Cyrillic: "Зияд"},
+	"ياسر":       {Latin: "Yasser", Cyrillic: "Ясир"},
+	"فيصل":       {Latin: "Faisal", Cyrillic: "Фейсал"},
+	"هاشم":       {Latin: "Hashim", Cyrillic: "Хашим"},
+	"فاطمة":      {Latin: "Fatima", Cyrillic: "Фатима"},
+	"عائشة":      {Latin: "Aisha", Cyrillic: "Аиша"},
+	"خديجة":      {Latin: "Khadija", Cyrillic: "Хадиджа"},
+	"مريم":       {Latin: "Maryam", Cyrillic: "Марьям"},
+	"زينب":       {Latin: "Zainab", Cyrillic: "Зайнаб"},
+	"سارة":       {Latin: "Sara", Cyrillic: "Сара"},
+	"ليلى":       {Latin: "Layla", Cyrillic: "Лейла"},
+	"نور":        {Latin: "Nour", Cyrillic: "Нур"},
+	"آمنة":       {Latin: "Amina", Cyrillic: "Амина"},
+	"أمينة":      {Latin: "Amina", Cyrillic: "Амина"},
+	"حليمة":      {Latin: "Halima", Cyrillic: "Халима"},
+	"رقية":       {Latin: "Ruqayya", Cyrillic: "Рукайя"},
+	"هدى":        {Latin: "Huda", Cyrillic: "Худа"},
+	"بن":         {Latin: "ibn", Cyrillic: "ибн"},
+	"ابن":        {Latin: "ibn", Cyrillic: "ибн"},
+	"بنت":        {Latin: "bint", Cyrillic: "бинт"},
+	"أبو":        {Latin: "Abu", Cyrillic: "Абу"},
+	"ابو":        {Latin: "Abu", Cyrillic: "Абу"},
+	"أم":         {Latin: "Umm", Cyrillic: "Умм"},
+	"ام":         {Latin: "Umm", Cyrillic: "Умм"},
+}
+
+const (
+	fatha  = 'َ'
+	damma  = 'ُ'
+	kasra  = 'ِ'
+	sukun  = 'ْ'
+	shadda = 'ّ'
+	tatwil = 'ـ'
+)
+
+// IsArabic reports whether the text has Arabic letters to transliterate
+func IsArabic(text string) bool {
+	for _, r := range text {
+		if unicode.Is(unicode.Arabic, r) && unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// Transliterate writes an Arabic name in the script, overrides maps whole
+// names or single words to the spelling to use instead of the rules
+func Transliterate(name string, script Script, overrides map[string]string) string {
+	t, ok := tables[script]
+	if !ok {
+		return ""
+	}
+
+	name = strings.Join(strings.Fields(name), " ")
+	if spelling, ok := overrides[name]; ok {
+		return spelling
+	}
+	if spelling, ok := common[name][script]; ok {
+		return spelling
+	}
+
+	words := joinAbd(strings.Fields(name))
+	for i, word := range words {
+		if spelling, ok := overrides[word]; ok {
+			words[i] = spelling
+			continue
+		}
+		if spelling, ok := common[word][script]; ok {
+			words[i] = spelling
+			continue
+		}
+		words[i] = t.word(word)
+	}
+	return strings.Join(words, " ")
+}
+
+// joinAbd joins عبد to the name after it, عبد الكريم is one name like
+// عبدالكريم
+func joinAbd(words []string) []string {
+	joined := make([]string, 0, len(words))
+	for i := 0; i < len(words); i++ {
+		if words[i] == "عبد" && i+1 < len(words) {
+			joined = append(joined, words[i]+words[i+1])
+			i++
+			continue
+		}
+		joined = append(joined, words[i])
+	}
+	return joined
+}
+
+func (t *table) word(word string) string {
+	runes := []rune(strings.ReplaceAll(word, string(tatwil), ""))
+
+	switch {
+	case len(runes) > 3 && string(runes[:3]) == "عبد":
+		// عبد joins the following name, عبدالكريم is Abdulkarim
+		rest := runes[3:]
+		if len(rest) > 2 && string(rest[:2]) == "ال" {
+			rest = rest[2:]
+		}
+		return t.abd + t.spell(rest)
+	case len(runes) > 3 && string(runes[:2]) == "ال":
+		return capitalize(t.article) + capitalize(t.spell(runes[2:]))
+	}
+	return capitalize(t.spell(runes))
+}
+
+// spell writes the letters of a word, guessing a short vowel between two
+// consonants unless a sukun says there is none
+func (t *table) spell(runes []rune) string {
+	// Unicode puts a short vowel before the shadda on the same letter, the
+	// letter is doubled before its vowel is written
+	for i := 0; i+1 < len(runes); i++ {
+		if (runes[i] == fatha || runes[i] == damma || runes[i] == kasra) && runes[i+1] == shadda {
+			runes[i], runes[i+1] = shadda, runes[i]
+		}
+	}
+
+	var out strings.Builder
+	prevConsonant := false
+	last := ""
+	consonant := func(s string) {
+		if prevConsonant {
+			out.WriteString(t.a)
+		}
+		out.WriteString(s)
+		last = s
+		prevConsonant = true
+	}
+	vowel := func(s string) {
+		out.WriteString(s)
+		last = ""
+		prevConsonant = false
+	}
+
+	for _, r := range runes {
+		switch r {
+		case fatha:
+			vowel(t.a)
+		case damma:
+			vowel(t.u)
+		case kasra:
+			vowel(t.i)
+		case sukun:
+			prevConsonant = false
+		case shadda:
+			out.WriteString(last)
+		case 'ا', 'أ', 'آ', 'ى':
+			vowel(t.a)
+		case 'إ':
+			vowel(t.i)
+		case 'ة':
+			vowel(t.a)
+		case 'و':
+			if !prevConsonant {
+				consonant(t.w)
+			} else {
+				vowel(t.u)
+			}
+		case 'ي':
+			if !prevConsonant {
+				consonant(t.y)
+			} else {
+				vowel(t.i)
+			}
+		default:
+			if letter, ok := t.letters[r]; ok {
+				consonant(letter)
+			}
+		}
+	}
+	return out.String()
+}
+
+func capitalize(word string) string {
+	runes := []rune(word)
+	if len(runes) == 0 {
+		return word
+	}
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
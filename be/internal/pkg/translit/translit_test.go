@@ -0,0 +1,78 @@
+package translit
+
+import "testing"
+
+func TestTransliterate(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		overrides map[string]string
+		latin     string
+		cyrillic  string
+	}{
+		{name: "common name", input: "محمد", latin: "Muhammad", cyrillic: "Мухаммад"},
+		{name: "letter rules", input: "كريم", latin: "Karim", cyrillic: "Карим"},
+		{name: "consonant waw", input: "وليد", latin: "Walid", cyrillic: "Валид"},
+		{name: "article", input: "الشامي", latin: "Al-Shami", cyrillic: "Аль-Шами"},
+		{name: "abd joined", input: "عبدالكريم", latin: "Abdulkarim", cyrillic: "Абдулкарим"},
+		{name: "abd apart", input: "عبد الكريم", latin: "Abdulkarim", cyrillic: "Абдулкарим"},
+		{name: "abd apart in a full name", input: "محمد عبد الله", latin: "Muhammad Abdullah", cyrillic: "Мухаммад Абдуллах"},
+		{name: "short vowels and shadda", input: "مُحَمَّد", latin: "Muhammad", cyrillic: "Мухаммад"},
+		{name: "sukun", input: "سلْمى", latin: "Salma", cyrillic: "Салма"},
+		{name: "tatwil", input: "نـــادر", latin: "Nadar", cyrillic: "Надар"},
+		{name: "lineage", input: "أحمد بن علي", latin: "Ahmad ibn Ali", cyrillic: "Ахмад ибн Али"},
+		{name: "extra spaces", input: "  علي   حسن ", latin: "Ali Hassan", cyrillic: "Али Хасан"},
+		{
+			name:      "whole name override",
+			input:     "علي حسن",
+			overrides: map[string]string{"علي حسن": "Aly Hasan"},
+			latin:     "Aly Hasan",
+			cyrillic:  "Aly Hasan",
+		},
+		{
+			name:      "word override",
+			input:     "نادر حسن",
+			overrides: map[string]string{"نادر": "Nader"},
+			latin:     "Nader Hassan",
+			cyrillic:  "Nader Хасан",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Transliterate(tt.input, Latin, tt.overrides); got != tt.latin {
+				t.Errorf("latin = %q, want %q", got, tt.latin)
+			}
+			if got := Transliterate(tt.input, Cyrillic, tt.overrides); got != tt.cyrillic {
+				t.Errorf("cyrillic = %q, want %q", got, tt.cyrillic)
+			}
+		})
+	}
+}
+
+func TestTransliterateUnknownScript(t *testing.T) {
+	if got := Transliterate("محمد", Script("greek"), nil); got != "" {
+		t.Errorf("Transliterate() = %q, want empty", got)
+	}
+}
+
+func TestIsArabic(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"محمد", true},
+		{"Ali محمد", true},
+		{"Ali", false},
+		{"١٢٣", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := IsArabic(tt.text); got != tt.want {
+				t.Errorf("IsArabic(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
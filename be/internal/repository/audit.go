@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AuditRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAuditRepository(db *pgxpool.Pool) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func (r *AuditRepository) Create(ctx context.Context, event *domain.AuditEvent) error {
+	query := `
+		INSERT INTO audit_events (actor_user_id, action, target_type, target_id, tree_id, details, ip_address, user_agent, request_id)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''), NULLIF($9, ''))
+		RETURNING audit_id, created_at
+	`
+	var details []byte
+	if len(event.Details) > 0 {
+		details = event.Details
+	}
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query,
+		event.ActorUserID, event.Action, event.TargetType, event.TargetID, event.TreeID, details,
+		event.IPAddress, event.UserAgent, event.RequestID,
+	).Scan(&event.AuditID, &event.CreatedAt)
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	return nil
+}
+
+// List returns the events matching the filter, newest first
+func (r *AuditRepository) List(ctx context.Context, filter domain.AuditFilter, cursor *string, limit int) ([]*domain.AuditEvent, *string, error) {
+	query := `
+		SELECT a.audit_id, a.actor_user_id, u.full_name, a.action, a.target_type, a.target_id, a.tree_id,
+		       a.details, COALESCE(a.ip_address, ''), COALESCE(a.user_agent, ''), COALESCE(a.request_id, ''),
+		       a.created_at
+		FROM audit_events a
+		LEFT JOIN users u ON u.user_id = a.actor_user_id
+		WHERE (($1::text IS NULL) OR a.audit_id < $1::bigint)
+		  AND (($2::int IS NULL) OR a.tree_id = $2)
+		  AND (($3::int IS NULL) OR a.actor_user_id = $3)
+		  AND (($4::text IS NULL) OR a.action = $4)
+		  AND (($5::text IS NULL) OR a.target_type = $5)
+		  AND (($6::text IS NULL) OR a.target_id = $6)
+		  AND (($7::timestamp IS NULL) OR a.created_at >= $7)
+		  AND (($8::timestamp IS NULL) OR a.created_at < $8)
+		ORDER BY a.audit_id DESC
+		LIMIT $9
+	`
+	rows, err := r.db.Query(ctx, query, cursor, filter.TreeID, filter.ActorUserID, filter.Action,
+		filter.TargetType, filter.TargetID, filter.From, filter.To, limit)
+	if err != nil {
+		return nil, nil, domain.NewDatabaseError(err)
+	}
+	defer rows.Close()
+
+	var events []*domain.AuditEvent
+	for rows.Next() {
+		event := &domain.AuditEvent{}
+		var details []byte
+		err := rows.Scan(
+			&event.AuditID, &event.ActorUserID, &event.ActorName, &event.Action, &event.TargetType,
+			&event.TargetID, &event.TreeID, &details, &event.IPAddress, &event.UserAgent,
+			&event.RequestID, &event.CreatedAt,
+		)
+		if err != nil {
+			return nil, nil, domain.NewDatabaseError(err)
+		}
+		event.Details = details
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, domain.NewDatabaseError(err)
+	}
+
+	var nextCursor *string
+	if len(events) == limit {
+		lastID := strconv.FormatInt(events[len(events)-1].AuditID, 10)
+		nextCursor = &lastID
+	}
+	return events, nextCursor, nil
+}
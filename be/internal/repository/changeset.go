@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ChangesetRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewChangesetRepository(db *pgxpool.Pool) *ChangesetRepository {
+	return &ChangesetRepository{db: db}
+}
+
+// Create opens an explicit changeset for later requests to write in
+func (r *ChangesetRepository) Create(ctx context.Context, changeset *domain.Changeset) error {
+	query := `
+		INSERT INTO changesets (tree_id, user_id, description)
+		VALUES ($1, $2, $3)
+		RETURNING changeset_id, created_at
+	`
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query,
+		changeset.TreeID, changeset.UserID, changeset.Description,
+	).Scan(&changeset.ChangesetID, &changeset.CreatedAt)
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	return nil
+}
+
+func (r *ChangesetRepository) Get(ctx context.Context, treeID int, changesetID string) (*domain.Changeset, error) {
+	changesets, _, err := r.list(ctx, treeID, &changesetID, nil, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(changesets) == 0 {
+		return nil, domain.NewNotFoundError("changeset")
+	}
+	return changesets[0], nil
+}
+
+// List returns the changesets of a tree with their summaries, newest first.
+// The cursor carries the time and id of the last changeset since changesets
+// can start at the same time.
+func (r *ChangesetRepository) List(ctx context.Context, treeID int, cursor *string, limit int) ([]*domain.Changeset, *string, error) {
+	return r.list(ctx, treeID, nil, cursor, limit)
+}
+
+// list summarizes the changesets from the history rows that carry their id,
+// joined with the rows of explicit and reverted changesets
+func (r *ChangesetRepository) list(ctx context.Context, treeID int, changesetID, cursor *string, limit int) ([]*domain.Changeset, *string, error) {
+	var cursorAt *time.Time
+	var cursorID *string
+	if cursor != nil && *cursor != "" {
+		at, id, ok := parseChangesetCursor(*cursor)
+		if !ok {
+			return nil, nil, domain.NewValidationError("error.validation.invalid_cursor")
+		}
+		cursorAt, cursorID = &at, &id
+	}
+
+	query := `
+		WITH entries AS (
+			SELECT h.changeset_id, MIN(h.user_id) AS user_id,
+			       array_agg(DISTINCT h.change_type ORDER BY h.change_type) AS change_types,
+			       array_agg(DISTINCT h.member_id ORDER BY h.member_id) AS member_ids,
+			       COUNT(*) AS entry_count,
+			       MIN(h.changed_at) AS first_change_at,
+			       MAX(h.changed_at) AS last_change_at
+			FROM members_history h
+			JOIN members m ON m.member_id = h.member_id
+			WHERE m.tree_id = $1
+			  AND h.changeset_id IS NOT NULL
+			  AND (($2::uuid IS NULL) OR h.changeset_id = $2)
+			GROUP BY h.changeset_id
+		), recorded AS (
+			SELECT changeset_id, user_id, description, created_at, reverted_at, reverted_by, reverted_in
+			FROM changesets
+			WHERE tree_id = $1
+			  AND (($2::uuid IS NULL) OR changeset_id = $2)
+		), summaries AS (
+			SELECT COALESCE(e.changeset_id, c.changeset_id) AS changeset_id,
+			       COALESCE(c.user_id, e.user_id) AS user_id,
+			       c.description,
+			       COALESCE(e.change_types, '{}') AS change_types,
+			       COALESCE(e.member_ids, '{}') AS member_ids,
+			       COALESCE(e.entry_count, 0) AS entry_count,
+			       COALESCE(c.created_at, e.first_change_at) AS created_at,
+			       e.last_change_at, c.reverted_at, c.reverted_by, c.reverted_in
+			FROM entries e
+			FULL JOIN recorded c ON c.changeset_id = e.changeset_id
+		)
+		SELECT s.changeset_id, s.user_id, u.full_name, s.description, s.change_types, s.member_ids, s.entry_count,
+		       COALESCE((SELECT SUM(us.points) FROM user_scores us WHERE us.changeset_id = s.changeset_id), 0),
+		       s.created_at, s.last_change_at, s.reverted_at, s.reverted_by, s.reverted_in
+		FROM summaries s
+		JOIN users u ON u.user_id = s.user_id
+		WHERE (($3::timestamp IS NULL) OR (s.created_at, s.changeset_id) < ($3, $4::uuid))
+		ORDER BY s.created_at DESC, s.changeset_id DESC
+		LIMIT $5
+	`
+
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, treeID, changesetID, cursorAt, cursorID, limit)
+	if err != nil {
+		return nil, nil, domain.NewDatabaseError(err)
+	}
+	defer rows.Close()
+
+	changesets := []*domain.Changeset{}
+	for rows.Next() {
+		changeset := &domain.Changeset{TreeID: treeID}
+		if err := rows.Scan(
+			&changeset.ChangesetID, &changeset.UserID, &changeset.UserFullName, &changeset.Description,
+			&changeset.ChangeTypes, &changeset.MemberIDs, &changeset.EntryCount, &changeset.Points,
+			&changeset.CreatedAt, &changeset.LastChangeAt,
+			&changeset.RevertedAt, &changeset.RevertedBy, &changeset.RevertedIn,
+		); err != nil {
+			return nil, nil, domain.NewDatabaseError(err)
+		}
+		changesets = append(changesets, changeset)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, domain.NewDatabaseError(err)
+	}
+
+	var nextCursor *string
+	if len(changesets) == limit && limit > 0 {
+		last := changesets[len(changesets)-1]
+		next := last.CreatedAt.Format(time.RFC3339Nano) + "_" + last.ChangesetID
+		nextCursor = &next
+	}
+
+	return changesets, nextCursor, nil
+}
+
+func parseChangesetCursor(cursor string) (time.Time, string, bool) {
+	at, id, found := strings.Cut(cursor, "_")
+	if !found {
+		return time.Time{}, "", false
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil {
+		return time.Time{}, "", false
+	}
+	changesetID, err := uuid.Parse(id)
+	if err != nil {
+		return time.Time{}, "", false
+	}
+	return createdAt, changesetID.String(), true
+}
+
+// MarkReverted records that the changeset was reverted, a changeset reverted
+// in the meantime is a conflict. In a transaction the row stays locked until
+// it ends, so a concurrent revert waits for it and then conflicts.
+func (r *ChangesetRepository) MarkReverted(ctx context.Context, changeset *domain.Changeset) error {
+	query := `
+		INSERT INTO changesets (changeset_id, tree_id, user_id, created_at, reverted_at, reverted_by, reverted_in)
+		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP, $5, $6)
+		ON CONFLICT (changeset_id) DO UPDATE
+		SET reverted_at = EXCLUDED.reverted_at,
+		    reverted_by = EXCLUDED.reverted_by,
+		    reverted_in = EXCLUDED.reverted_in
+		WHERE changesets.reverted_at IS NULL
+		RETURNING reverted_at
+	`
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query,
+		changeset.ChangesetID, changeset.TreeID, changeset.UserID, changeset.CreatedAt,
+		changeset.RevertedBy, changeset.RevertedIn,
+	).Scan(&changeset.RevertedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.NewConflictError("error.changeset.already_reverted", nil)
+	}
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CitationRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCitationRepository(db *pgxpool.Pool) *CitationRepository {
+	return &CitationRepository{db: db}
+}
+
+const selectCitationColumns = `
+		SELECT citation_id, source_id, subject_type, subject_id, field_name, confidence, detail,
+		       created_by, created_at, updated_at
+		FROM citations
+`
+
+func scanCitation(row pgx.Row, citation *domain.Citation) error {
+	return row.Scan(
+		&citation.CitationID, &citation.SourceID, &citation.SubjectType, &citation.SubjectID, &citation.FieldName, &citation.Confidence, &citation.Detail,
+		&citation.CreatedBy, &citation.CreatedAt, &citation.UpdatedAt,
+	)
+}
+
+func (r *CitationRepository) Create(ctx context.Context, citation *domain.Citation) error {
+	query := `
+		INSERT INTO citations (source_id, subject_type, subject_id, field_name, confidence, detail, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING citation_id, created_at, updated_at
+	`
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query,
+		citation.SourceID, citation.SubjectType, citation.SubjectID, citation.FieldName, citation.Confidence, citation.Detail, citation.CreatedBy,
+	).Scan(&citation.CitationID, &citation.CreatedAt, &citation.UpdatedAt)
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	return nil
+}
+
+func (r *CitationRepository) Get(ctx context.Context, citationID int) (*domain.Citation, error) {
+	query := selectCitationColumns + `
+		WHERE citation_id = $1
+	`
+	citation := &domain.Citation{}
+	err := scanCitation(getQuerier(ctx, r.db).QueryRow(ctx, query, citationID), citation)
+	if errors.Is(err, pgx.ErrNoRows) {
+		slog.Warn("CitationRepository.Get: citation not found", "citation_id", citationID)
+		return nil, domain.NewNotFoundError("citation")
+	}
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return citation, nil
+}
+
+func (r *CitationRepository) ListBySourceID(ctx context.Context, sourceID int) ([]*domain.Citation, error) {
+	query := selectCitationColumns + `
+		WHERE source_id = $1
+		ORDER BY citation_id
+	`
+	return r.list(ctx, query, sourceID)
+}
+
+func (r *CitationRepository) ListBySubject(ctx context.Context, subjectType string, subjectID int) ([]*domain.Citation, error) {
+	query := selectCitationColumns + `
+		WHERE subject_type = $1 AND subject_id = $2
+		ORDER BY field_name, citation_id
+	`
+	return r.list(ctx, query, subjectType, subjectID)
+}
+
+func (r *CitationRepository) list(ctx context.Context, query string, args ...any) ([]*domain.Citation, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	defer rows.Close()
+
+	var citations []*domain.Citation
+	for rows.Next() {
+		citation := &domain.Citation{}
+		if err := scanCitation(rows, citation); err != nil {
+			return nil, domain.NewDatabaseError(err)
+		}
+		citations = append(citations, citation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return citations, nil
+}
+
+func (r *CitationRepository) Update(ctx context.Context, citation *domain.Citation) error {
+	query := `
+		UPDATE citations
+		SET confidence = $1, detail = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE citation_id = $3
+		RETURNING updated_at
+	`
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, citation.Confidence, citation.Detail, citation.CitationID).Scan(&citation.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.NewNotFoundError("citation")
+	}
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	return nil
+}
+
+func (r *CitationRepository) Delete(ctx context.Context, citationID int) error {
+	result, err := getQuerier(ctx, r.db).Exec(ctx, `DELETE FROM citations WHERE citation_id = $1`, citationID)
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.NewNotFoundError("citation")
+	}
+	return nil
+}
+
+// CountByMemberFields counts the citations of each field of a member
+func (r *CitationRepository) CountByMemberFields(ctx context.Context, memberID int) (map[string]int, error) {
+	query := `
+		SELECT field_name, COUNT(*)
+		FROM citations
+		WHERE subject_type = $1 AND subject_id = $2
+		GROUP BY field_name
+	`
+	rows, err := r.db.Query(ctx, query, domain.CitationSubjectMember, memberID)
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var fieldName string
+		var count int
+		if err := rows.Scan(&fieldName, &count); err != nil {
+			return nil, domain.NewDatabaseError(err)
+		}
+		counts[fieldName] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return counts, nil
+}
+
+// ListCitedFacts returns every fact that has at least one citation from a source of the tree
+func (r *CitationRepository) ListCitedFacts(ctx context.Context, treeID int) ([]domain.FactRef, error) {
+	query := `
+		SELECT DISTINCT c.subject_type, c.subject_id, c.field_name
+		FROM citations c
+		JOIN sources s ON s.source_id = c.source_id
+		WHERE s.tree_id = $1
+	`
+	rows, err := r.db.Query(ctx, query, treeID)
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	defer rows.Close()
+
+	var facts []domain.FactRef
+	for rows.Next() {
+		var fact domain.FactRef
+		if err := rows.Scan(&fact.SubjectType, &fact.SubjectID, &fact.FieldName); err != nil {
+			return nil, domain.NewDatabaseError(err)
+		}
+		facts = append(facts, fact)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return facts, nil
+}
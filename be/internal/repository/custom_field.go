@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CustomFieldRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCustomFieldRepository(db *pgxpool.Pool) *CustomFieldRepository {
+	return &CustomFieldRepository{db: db}
+}
+
+const selectCustomFieldColumns = `
+		SELECT f.field_id, f.tree_id, f.field_key, f.field_type, f.options, f.is_required, f.display_order,
+		       f.created_at, f.updated_at,
+		       COALESCE(
+			       (SELECT jsonb_object_agg(fn.language_code, fn.name)
+			        FROM tree_custom_field_names fn
+			        WHERE fn.field_id = f.field_id),
+			       '{}'::jsonb
+		       ) AS names
+		FROM tree_custom_fields f
+`
+
+func scanCustomField(row pgx.Row, field *domain.CustomField) error {
+	return row.Scan(
+		&field.FieldID, &field.TreeID, &field.Key, &field.Type, &field.Options, &field.IsRequired, &field.DisplayOrder,
+		&field.CreatedAt, &field.UpdatedAt,
+		&field.Names,
+	)
+}
+
+func (r *CustomFieldRepository) Create(ctx context.Context, field *domain.CustomField) error {
+	return doWithQuerier(ctx, r.db, func(txCtx context.Context) error {
+		querier := getQuerier(txCtx, r.db)
+
+		query := `
+			INSERT INTO tree_custom_fields (tree_id, field_key, field_type, options, is_required, display_order)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING field_id, created_at, updated_at
+		`
+		err := querier.QueryRow(txCtx, query,
+			field.TreeID, field.Key, field.Type, field.Options, field.IsRequired, field.DisplayOrder,
+		).Scan(&field.FieldID, &field.CreatedAt, &field.UpdatedAt)
+		if err != nil {
+			return domain.NewDatabaseError(err)
+		}
+
+		return r.replaceNames(txCtx, querier, field)
+	})
+}
+
+func (r *CustomFieldRepository) Get(ctx context.Context, fieldID int) (*domain.CustomField, error) {
+	query := selectCustomFieldColumns + `
+		WHERE f.field_id = $1
+	`
+	field := &domain.CustomField{}
+	err := scanCustomField(getQuerier(ctx, r.db).QueryRow(ctx, query, fieldID), field)
+	if errors.Is(err, pgx.ErrNoRows) {
+		slog.Warn("CustomFieldRepository.Get: custom field not found", "field_id", fieldID)
+		return nil, domain.NewNotFoundError("custom_field")
+	}
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return field, nil
+}
+
+func (r *CustomFieldRepository) ListByTreeID(ctx context.Context, treeID int) ([]*domain.CustomField, error) {
+	query := selectCustomFieldColumns + `
+		WHERE f.tree_id = $1
+		ORDER BY f.display_order, f.field_id
+	`
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, treeID)
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	defer rows.Close()
+
+	var fields []*domain.CustomField
+	for rows.Next() {
+		field := &domain.CustomField{}
+		if err := scanCustomField(rows, field); err != nil {
+			return nil, domain.NewDatabaseError(err)
+		}
+		fields = append(fields, field)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return fields, nil
+}
+
+// Update changes everything but the key and type, which existing values
+// depend on
+func (r *CustomFieldRepository) Update(ctx context.Context, field *domain.CustomField) error {
+	return doWithQuerier(ctx, r.db, func(txCtx context.Context) error {
+		querier := getQuerier(txCtx, r.db)
+
+		query := `
+			UPDATE tree_custom_fields
+			SET options = $1, is_required = $2, display_order = $3, updated_at = CURRENT_TIMESTAMP
+			WHERE field_id = $4
+			RETURNING updated_at
+		`
+		err := querier.QueryRow(txCtx, query, field.Options, field.IsRequired, field.DisplayOrder, field.FieldID).
+			Scan(&field.UpdatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.NewNotFoundError("custom_field")
+		}
+		if err != nil {
+			return domain.NewDatabaseError(err)
+		}
+
+		return r.replaceNames(txCtx, querier, field)
+	})
+}
+
+// Delete removes the field together with the values members have for it
+func (r *CustomFieldRepository) Delete(ctx context.Context, fieldID int) error {
+	result, err := getQuerier(ctx, r.db).Exec(ctx, `DELETE FROM tree_custom_fields WHERE field_id = $1`, fieldID)
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.NewNotFoundError("custom_field")
+	}
+	return nil
+}
+
+// HasValuesOutside reports whether a member holds a value of the field that
+// is not one of the given options
+func (r *CustomFieldRepository) HasValuesOutside(ctx context.Context, fieldID int, options []string) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM member_custom_values cv
+			JOIN members m ON m.member_id = cv.member_id AND m.deleted_at IS NULL
+			WHERE cv.field_id = $1 AND NOT (cv.value = ANY($2))
+		)
+	`
+	var exists bool
+	if err := getQuerier(ctx, r.db).QueryRow(ctx, query, fieldID, options).Scan(&exists); err != nil {
+		return false, domain.NewDatabaseError(err)
+	}
+	return exists, nil
+}
+
+func (r *CustomFieldRepository) replaceNames(ctx context.Context, querier Querier, field *domain.CustomField) error {
+	batch := &pgx.Batch{}
+	batch.Queue(`DELETE FROM tree_custom_field_names WHERE field_id = $1`, field.FieldID)
+	for langCode, name := range field.Names {
+		batch.Queue(`INSERT INTO tree_custom_field_names (field_id, language_code, name) VALUES ($1, $2, $3)`, field.FieldID, langCode, name)
+	}
+
+	br := querier.SendBatch(ctx, batch)
+	if err := br.Close(); err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type EventRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewEventRepository(db *pgxpool.Pool) *EventRepository {
+	return &EventRepository{db: db}
+}
+
+const selectEventColumns = `
+		SELECT e.event_id, e.member_id, e.event_type, e.custom_type,
+		       e.event_date, e.event_date_qualifier, e.event_date_end, e.event_date_calendar,
+		       e.place_id, e.description, e.created_at, e.updated_at,
+		       COALESCE(
+			       (SELECT array_agg(p.member_id ORDER BY p.member_id)
+			        FROM member_event_participants p
+			        WHERE p.event_id = e.event_id),
+			       '{}'
+		       ) AS participant_ids
+		FROM member_events e
+`
+
+func scanEvent(row pgx.Row, event *domain.MemberEvent) error {
+	return row.Scan(
+		&event.EventID, &event.MemberID, &event.EventType, &event.CustomType,
+		&event.Date, &event.DateQualifier, &event.DateEnd, &event.DateCalendar,
+		&event.PlaceID, &event.Description, &event.CreatedAt, &event.UpdatedAt,
+		&event.ParticipantIDs,
+	)
+}
+
+func (r *EventRepository) Create(ctx context.Context, event *domain.MemberEvent) error {
+	return doWithQuerier(ctx, r.db, func(txCtx context.Context) error {
+		querier := getQuerier(txCtx, r.db)
+
+		query := `
+			INSERT INTO member_events (member_id, event_type, custom_type,
+			                           event_date, event_date_qualifier, event_date_end, event_date_calendar,
+			                           place_id, description)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			RETURNING event_id, created_at, updated_at
+		`
+		err := querier.QueryRow(txCtx, query,
+			event.MemberID, event.EventType, event.CustomType,
+			event.Date, event.DateQualifier, event.DateEnd, event.DateCalendar,
+			event.PlaceID, event.Description,
+		).Scan(&event.EventID, &event.CreatedAt, &event.UpdatedAt)
+		if err != nil {
+			return domain.NewDatabaseError(err)
+		}
+
+		return r.replaceParticipants(txCtx, querier, event)
+	})
+}
+
+func (r *EventRepository) Get(ctx context.Context, eventID int) (*domain.MemberEvent, error) {
+	query := selectEventColumns + `
+		WHERE e.event_id = $1 AND e.deleted_at IS NULL
+	`
+	event := &domain.MemberEvent{}
+	err := scanEvent(getQuerier(ctx, r.db).QueryRow(ctx, query, eventID), event)
+	if errors.Is(err, pgx.ErrNoRows) {
+		slog.Warn("EventRepository.Get: event not found", "event_id", eventID)
+		return nil, domain.NewNotFoundError("event")
+	}
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return event, nil
+}
+
+func (r *EventRepository) ListByMemberID(ctx context.Context, memberID int) ([]*domain.MemberEvent, error) {
+	query := selectEventColumns + `
+		WHERE e.member_id = $1 AND e.deleted_at IS NULL
+		ORDER BY e.event_date ASC NULLS LAST, e.event_id ASC
+	`
+	return r.list(ctx, query, memberID)
+}
+
+func (r *EventRepository) ListByTreeID(ctx context.Context, treeID int) ([]*domain.MemberEvent, error) {
+	query := selectEventColumns + `
+		JOIN members m ON m.member_id = e.member_id
+		WHERE m.tree_id = $1 AND m.deleted_at IS NULL AND e.deleted_at IS NULL
+		ORDER BY e.event_id ASC
+	`
+	return r.list(ctx, query, treeID)
+}
+
+func (r *EventRepository) list(ctx context.Context, query string, args ...any) ([]*domain.MemberEvent, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	defer rows.Close()
+
+	var events []*domain.MemberEvent
+	for rows.Next() {
+		event := &domain.MemberEvent{}
+		if err := scanEvent(rows, event); err != nil {
+			return nil, domain.NewDatabaseError(err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return events, nil
+}
+
+func (r *EventRepository) Update(ctx context.Context, event *domain.MemberEvent) error {
+	return doWithQuerier(ctx, r.db, func(txCtx context.Context) error {
+		querier := getQuerier(txCtx, r.db)
+
+		query := `
+			UPDATE member_events
+			SET event_type = $1, custom_type = $2,
+			    event_date = $3, event_date_qualifier = $4, event_date_end = $5, event_date_calendar = $6,
+			    place_id = $7, description = $8, updated_at = CURRENT_TIMESTAMP
+			WHERE event_id = $9 AND deleted_at IS NULL
+			RETURNING updated_at
+		`
+		err := querier.QueryRow(txCtx, query,
+			event.EventType, event.CustomType,
+			event.Date, event.DateQualifier, event.DateEnd, event.DateCalendar,
+			event.PlaceID, event.Description, event.EventID,
+		).Scan(&event.UpdatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.NewNotFoundError("event")
+		}
+		if err != nil {
+			return domain.NewDatabaseError(err)
+		}
+
+		return r.replaceParticipants(txCtx, querier, event)
+	})
+}
+
+func (r *EventRepository) Delete(ctx context.Context, eventID int) error {
+	querier := getQuerier(ctx, r.db)
+	query := `
+		UPDATE member_events
+		SET deleted_at = CURRENT_TIMESTAMP
+		WHERE event_id = $1 AND deleted_at IS NULL
+	`
+	result, err := querier.Exec(ctx, query, eventID)
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.NewNotFoundError("event")
+	}
+	return nil
+}
+
+func (r *EventRepository) replaceParticipants(ctx context.Context, querier Querier, event *domain.MemberEvent) error {
+	batch := &pgx.Batch{}
+	batch.Queue(`DELETE FROM member_event_participants WHERE event_id = $1`, event.EventID)
+	for _, memberID := range event.ParticipantIDs {
+		batch.Queue(`INSERT INTO member_event_participants (event_id, member_id) VALUES ($1, $2)`, event.EventID, memberID)
+	}
+
+	br := querier.SendBatch(ctx, batch)
+	if err := br.Close(); err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	return nil
+}
@@ -2,8 +2,10 @@ package repository
 
 import (
 	"context"
+	"errors"
 
 	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -15,8 +17,7 @@ func NewFamilyGraphRepository(db *pgxpool.Pool) *FamilyGraphRepository {
 	return &FamilyGraphRepository{db: db}
 }
 
-func (r *FamilyGraphRepository) ListFamilyUnitsByTreeID(ctx context.Context, treeID int) ([]*domain.FamilyUnit, error) {
-	query := `
+const selectFamilyUnitColumns = `
 		SELECT
 			fu.family_unit_id,
 			fu.tree_id,
@@ -24,6 +25,12 @@ func (r *FamilyGraphRepository) ListFamilyUnitsByTreeID(ctx context.Context, tre
 			fu.status,
 			fu.start_date,
 			fu.end_date,
+			CASE
+				WHEN fu.source_spouse_id IS NOT NULL THEN 'spouse'
+				WHEN fu.legacy_father_id IS NOT NULL OR fu.legacy_mother_id IS NOT NULL THEN 'parents'
+				ELSE 'manual'
+			END AS source,
+			fu.source_spouse_id,
 			COALESCE(
 				array_agg(DISTINCT fup.person_id ORDER BY fup.person_id)
 					FILTER (WHERE fup.person_id IS NOT NULL),
@@ -37,15 +44,79 @@ func (r *FamilyGraphRepository) ListFamilyUnitsByTreeID(ctx context.Context, tre
 		FROM family_units fu
 		LEFT JOIN family_unit_partners fup ON fup.family_unit_id = fu.family_unit_id
 		LEFT JOIN family_unit_children fuc ON fuc.family_unit_id = fu.family_unit_id
+`
+
+const groupFamilyUnitColumns = `
+		GROUP BY fu.family_unit_id, fu.tree_id, fu.relationship_type, fu.status, fu.start_date, fu.end_date,
+		         fu.source_spouse_id, fu.legacy_father_id, fu.legacy_mother_id
+`
+
+func scanFamilyUnit(row pgx.Row, unit *domain.FamilyUnit) error {
+	return row.Scan(
+		&unit.FamilyUnitID,
+		&unit.TreeID,
+		&unit.RelationshipType,
+		&unit.Status,
+		&unit.StartDate,
+		&unit.EndDate,
+		&unit.Source,
+		&unit.SourceSpouseID,
+		&unit.PartnerIDs,
+		&unit.ChildIDs,
+	)
+}
+
+func (r *FamilyGraphRepository) GetFamilyUnit(ctx context.Context, familyUnitID int) (*domain.FamilyUnit, error) {
+	query := selectFamilyUnitColumns + `
+		WHERE fu.family_unit_id = $1
+		  AND fu.deleted_at IS NULL
+	` + groupFamilyUnitColumns
+
+	unit := &domain.FamilyUnit{}
+	err := scanFamilyUnit(r.db.QueryRow(ctx, query, familyUnitID), unit)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.NewNotFoundError("family_unit")
+	}
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+
+	if err := r.loadChildRelations(ctx, []*domain.FamilyUnit{unit}); err != nil {
+		return nil, err
+	}
+	return unit, nil
+}
+
+func (r *FamilyGraphRepository) ListFamilyUnitsByTreeID(ctx context.Context, treeID int) ([]*domain.FamilyUnit, error) {
+	query := selectFamilyUnitColumns + `
 		WHERE fu.tree_id = $1
 		  AND fu.deleted_at IS NULL
-		GROUP BY fu.family_unit_id, fu.tree_id, fu.relationship_type, fu.status, fu.start_date, fu.end_date
+	` + groupFamilyUnitColumns + `
 		ORDER BY
 			COALESCE(fu.start_date, DATE '9999-12-31'),
 			fu.family_unit_id
 	`
+	return r.listFamilyUnits(ctx, query, treeID)
+}
 
-	rows, err := r.db.Query(ctx, query, treeID)
+// ListFamilyUnitsByPartnerID lists the units the member is a partner in
+func (r *FamilyGraphRepository) ListFamilyUnitsByPartnerID(ctx context.Context, memberID int) ([]*domain.FamilyUnit, error) {
+	query := selectFamilyUnitColumns + `
+		WHERE fu.deleted_at IS NULL
+		  AND EXISTS (
+			SELECT 1 FROM family_unit_partners p
+			WHERE p.family_unit_id = fu.family_unit_id AND p.person_id = $1
+		  )
+	` + groupFamilyUnitColumns + `
+		ORDER BY
+			COALESCE(fu.start_date, DATE '9999-12-31'),
+			fu.family_unit_id
+	`
+	return r.listFamilyUnits(ctx, query, memberID)
+}
+
+func (r *FamilyGraphRepository) listFamilyUnits(ctx context.Context, query string, args ...any) ([]*domain.FamilyUnit, error) {
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, domain.NewDatabaseError(err)
 	}
@@ -54,60 +125,146 @@ func (r *FamilyGraphRepository) ListFamilyUnitsByTreeID(ctx context.Context, tre
 	units := make([]*domain.FamilyUnit, 0)
 	for rows.Next() {
 		unit := &domain.FamilyUnit{}
-		if err := rows.Scan(
-			&unit.FamilyUnitID,
-			&unit.TreeID,
-			&unit.RelationshipType,
-			&unit.Status,
-			&unit.StartDate,
-			&unit.EndDate,
-			&unit.PartnerIDs,
-			&unit.ChildIDs,
-		); err != nil {
+		if err := scanFamilyUnit(rows, unit); err != nil {
 			return nil, domain.NewDatabaseError(err)
 		}
-		unit.ChildRelations = make(map[int]string)
 		units = append(units, unit)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, domain.NewDatabaseError(err)
 	}
 
-	if len(units) == 0 {
-		return units, nil
+	if err := r.loadChildRelations(ctx, units); err != nil {
+		return nil, err
 	}
+	return units, nil
+}
 
-	relationQuery := `
-		SELECT family_unit_id, child_person_id, relation_type
-		FROM family_unit_children
-		WHERE family_unit_id = ANY($1)
-	`
+func (r *FamilyGraphRepository) loadChildRelations(ctx context.Context, units []*domain.FamilyUnit) error {
 	unitIDs := make([]int, 0, len(units))
 	unitByID := make(map[int]*domain.FamilyUnit, len(units))
 	for _, unit := range units {
+		unit.ChildRelations = make(map[int]string)
 		unitIDs = append(unitIDs, unit.FamilyUnitID)
 		unitByID[unit.FamilyUnitID] = unit
 	}
+	if len(unitIDs) == 0 {
+		return nil
+	}
 
-	relationRows, err := r.db.Query(ctx, relationQuery, unitIDs)
+	query := `
+		SELECT family_unit_id, child_person_id, relation_type
+		FROM family_unit_children
+		WHERE family_unit_id = ANY($1)
+	`
+	rows, err := r.db.Query(ctx, query, unitIDs)
 	if err != nil {
-		return nil, domain.NewDatabaseError(err)
+		return domain.NewDatabaseError(err)
 	}
-	defer relationRows.Close()
+	defer rows.Close()
 
-	for relationRows.Next() {
+	for rows.Next() {
 		var unitID, childID int
 		var relationType string
-		if err := relationRows.Scan(&unitID, &childID, &relationType); err != nil {
-			return nil, domain.NewDatabaseError(err)
+		if err := rows.Scan(&unitID, &childID, &relationType); err != nil {
+			return domain.NewDatabaseError(err)
 		}
 		if unit := unitByID[unitID]; unit != nil {
 			unit.ChildRelations[childID] = relationType
 		}
 	}
-	if err := relationRows.Err(); err != nil {
-		return nil, domain.NewDatabaseError(err)
+	if err := rows.Err(); err != nil {
+		return domain.NewDatabaseError(err)
 	}
+	return nil
+}
 
-	return units, nil
+// CreateFamilyUnit stores a manual unit, partners are ordered as given
+func (r *FamilyGraphRepository) CreateFamilyUnit(ctx context.Context, unit *domain.FamilyUnit) error {
+	return doWithQuerier(ctx, r.db, func(txCtx context.Context) error {
+		querier := getQuerier(txCtx, r.db)
+
+		query := `
+			INSERT INTO family_units (tree_id, relationship_type, status, start_date, end_date)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING family_unit_id
+		`
+		err := querier.QueryRow(txCtx, query, unit.TreeID, unit.RelationshipType, unit.Status, unit.StartDate, unit.EndDate).
+			Scan(&unit.FamilyUnitID)
+		if err != nil {
+			return domain.NewDatabaseError(err)
+		}
+
+		batch := &pgx.Batch{}
+		for i, partnerID := range unit.PartnerIDs {
+			batch.Queue(`
+				INSERT INTO family_unit_partners (family_unit_id, person_id, partner_order)
+				VALUES ($1, $2, $3)
+			`, unit.FamilyUnitID, partnerID, i+1)
+		}
+		if err := querier.SendBatch(txCtx, batch).Close(); err != nil {
+			return domain.NewDatabaseError(err)
+		}
+
+		unit.Source = domain.FamilyUnitSourceManual
+		return nil
+	})
+}
+
+func (r *FamilyGraphRepository) UpdateFamilyUnit(ctx context.Context, unit *domain.FamilyUnit) error {
+	query := `
+		UPDATE family_units
+		SET relationship_type = $1, status = $2, start_date = $3, end_date = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE family_unit_id = $5 AND deleted_at IS NULL
+	`
+	result, err := getQuerier(ctx, r.db).Exec(ctx, query, unit.RelationshipType, unit.Status, unit.StartDate, unit.EndDate, unit.FamilyUnitID)
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.NewNotFoundError("family_unit")
+	}
+	return nil
+}
+
+func (r *FamilyGraphRepository) DeleteFamilyUnit(ctx context.Context, familyUnitID int) error {
+	query := `
+		UPDATE family_units
+		SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE family_unit_id = $1 AND deleted_at IS NULL
+	`
+	result, err := getQuerier(ctx, r.db).Exec(ctx, query, familyUnitID)
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.NewNotFoundError("family_unit")
+	}
+	return nil
+}
+
+func (r *FamilyGraphRepository) AddFamilyUnitChild(ctx context.Context, familyUnitID, childID int, relationType string) error {
+	query := `
+		INSERT INTO family_unit_children (family_unit_id, child_person_id, relation_type)
+		VALUES ($1, $2, $3)
+	`
+	if _, err := getQuerier(ctx, r.db).Exec(ctx, query, familyUnitID, childID, relationType); err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	return nil
+}
+
+func (r *FamilyGraphRepository) RemoveFamilyUnitChild(ctx context.Context, familyUnitID, childID int) error {
+	query := `
+		DELETE FROM family_unit_children
+		WHERE family_unit_id = $1 AND child_person_id = $2
+	`
+	result, err := getQuerier(ctx, r.db).Exec(ctx, query, familyUnitID, childID)
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.NewNotFoundError("family_unit_child")
+	}
+	return nil
 }
@@ -51,7 +51,7 @@ func (r *FamilyTreeRepository) ListForUser(ctx context.Context, userID int) ([]*
 		SELECT ft.tree_id, ft.name, ft.description, ft.owner_user_id,
 		       owner.full_name, owner.email, ftm.role,
 		       COUNT(m.member_id) FILTER (WHERE m.deleted_at IS NULL) AS member_count,
-		       ft.created_at, ft.updated_at
+		       ft.name_settings, ft.created_at, ft.updated_at
 		FROM family_trees ft
 		JOIN family_tree_memberships ftm ON ftm.tree_id = ft.tree_id
 		JOIN users owner ON owner.user_id = ft.owner_user_id
@@ -78,6 +78,7 @@ func (r *FamilyTreeRepository) ListForUser(ctx context.Context, userID int) ([]*
 			&tree.OwnerEmail,
 			&tree.UserRole,
 			&tree.MemberCount,
+			&tree.NameSettings,
 			&tree.CreatedAt,
 			&tree.UpdatedAt,
 		); err != nil {
@@ -96,7 +97,7 @@ func (r *FamilyTreeRepository) GetForUser(ctx context.Context, treeID, userID in
 		SELECT ft.tree_id, ft.name, ft.description, ft.owner_user_id,
 		       owner.full_name, owner.email, ftm.role,
 		       COUNT(m.member_id) FILTER (WHERE m.deleted_at IS NULL) AS member_count,
-		       ft.created_at, ft.updated_at
+		       ft.name_settings, ft.created_at, ft.updated_at
 		FROM family_trees ft
 		JOIN family_tree_memberships ftm ON ftm.tree_id = ft.tree_id
 		JOIN users owner ON owner.user_id = ft.owner_user_id
@@ -114,6 +115,7 @@ func (r *FamilyTreeRepository) GetForUser(ctx context.Context, treeID, userID in
 		&tree.OwnerEmail,
 		&tree.UserRole,
 		&tree.MemberCount,
+		&tree.NameSettings,
 		&tree.CreatedAt,
 		&tree.UpdatedAt,
 	)
@@ -126,6 +128,69 @@ func (r *FamilyTreeRepository) GetForUser(ctx context.Context, treeID, userID in
 	return tree, nil
 }
 
+func (r *FamilyTreeRepository) GetNameSettings(ctx context.Context, treeID int) (*domain.NameSettings, error) {
+	settings := &domain.NameSettings{}
+	err := r.db.QueryRow(ctx, `SELECT name_settings FROM family_trees WHERE tree_id = $1`, treeID).Scan(settings)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.NewNotFoundError("family_tree")
+	}
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return settings, nil
+}
+
+func (r *FamilyTreeRepository) UpdateNameSettings(ctx context.Context, treeID int, settings *domain.NameSettings) error {
+	query := `
+		UPDATE family_trees
+		SET name_settings = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE tree_id = $2
+	`
+	result, err := r.db.Exec(ctx, query, settings, treeID)
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.NewNotFoundError("family_tree")
+	}
+	return nil
+}
+
+// GetPrivacyPolicy returns the tree's policy, the default one until the owner
+// sets their own
+func (r *FamilyTreeRepository) GetPrivacyPolicy(ctx context.Context, treeID int) (*domain.PrivacyPolicy, error) {
+	var policy *domain.PrivacyPolicy
+	err := r.db.QueryRow(ctx, `SELECT privacy_policy FROM family_trees WHERE tree_id = $1`, treeID).Scan(&policy)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.NewNotFoundError("family_tree")
+	}
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	if policy == nil {
+		defaultPolicy := domain.DefaultPrivacyPolicy()
+		policy = &defaultPolicy
+	}
+	return policy, nil
+}
+
+// UpdatePrivacyPolicy stores the tree's policy, nil restores the default one
+func (r *FamilyTreeRepository) UpdatePrivacyPolicy(ctx context.Context, treeID int, policy *domain.PrivacyPolicy) error {
+	query := `
+		UPDATE family_trees
+		SET privacy_policy = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE tree_id = $2
+	`
+	result, err := r.db.Exec(ctx, query, policy, treeID)
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.NewNotFoundError("family_tree")
+	}
+	return nil
+}
+
 func (r *FamilyTreeRepository) HasAccess(ctx context.Context, treeID, userID int) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM family_tree_memberships WHERE tree_id = $1 AND user_id = $2)`
 	var exists bool
@@ -135,12 +200,198 @@ func (r *FamilyTreeRepository) HasAccess(ctx context.Context, treeID, userID int
 	return exists, nil
 }
 
+// GetRole returns the user's role in the tree
+func (r *FamilyTreeRepository) GetRole(ctx context.Context, treeID, userID int) (string, error) {
+	query := `SELECT role FROM family_tree_memberships WHERE tree_id = $1 AND user_id = $2`
+	var role string
+	err := r.db.QueryRow(ctx, query, treeID, userID).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", domain.NewNotFoundError("family_tree")
+	}
+	if err != nil {
+		return "", domain.NewDatabaseError(err)
+	}
+	return role, nil
+}
+
+// ListMemberships returns the users with access to the tree, the owner first
+// and the others in the order they joined
+func (r *FamilyTreeRepository) ListMemberships(ctx context.Context, treeID int) ([]*domain.FamilyTreeMembership, error) {
+	query := `
+		SELECT ftm.tree_id, ftm.user_id, u.full_name, u.email, u.avatar, ftm.role, ftm.joined_at
+		FROM family_tree_memberships ftm
+		JOIN users u ON u.user_id = ftm.user_id
+		WHERE ftm.tree_id = $1
+		ORDER BY ftm.role = 'owner' DESC, ftm.joined_at, ftm.user_id
+	`
+	rows, err := r.db.Query(ctx, query, treeID)
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	defer rows.Close()
+
+	var memberships []*domain.FamilyTreeMembership
+	for rows.Next() {
+		membership := &domain.FamilyTreeMembership{}
+		if err := rows.Scan(
+			&membership.TreeID,
+			&membership.UserID,
+			&membership.FullName,
+			&membership.Email,
+			&membership.Avatar,
+			&membership.Role,
+			&membership.JoinedAt,
+		); err != nil {
+			return nil, domain.NewDatabaseError(err)
+		}
+		memberships = append(memberships, membership)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return memberships, nil
+}
+
+// UpdateMembershipRole changes the role of a member of the tree other than
+// its owner
+// UpdateMembershipRole changes the role of a member other than the owner, a
+// role that can't share the tree revokes the share links the member made and
+// it returns how many
+func (r *FamilyTreeRepository) UpdateMembershipRole(ctx context.Context, treeID, userID int, role string) (*domain.RevokedAccess, error) {
+	revoked := &domain.RevokedAccess{}
+	err := doWithQuerier(ctx, r.db, func(txCtx context.Context) error {
+		querier := getQuerier(txCtx, r.db)
+
+		query := `
+			UPDATE family_tree_memberships
+			SET role = $3
+			WHERE tree_id = $1 AND user_id = $2 AND role <> 'owner'
+		`
+		result, err := querier.Exec(txCtx, query, treeID, userID, role)
+		if err != nil {
+			return domain.NewDatabaseError(err)
+		}
+		if result.RowsAffected() == 0 {
+			return domain.NewNotFoundError("membership")
+		}
+
+		if !domain.TreeRoleAllows(role, domain.TreePermissionShare) {
+			revoked.ShareLinks, err = revokeShareLinks(txCtx, querier, treeID, userID)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return revoked, nil
+}
+
+// DeleteMembership takes the tree away from a member other than its owner
+// together with the share links and calendar feeds they made for it, and
+// returns how many of them it revoked
+func (r *FamilyTreeRepository) DeleteMembership(ctx context.Context, treeID, userID int) (*domain.RevokedAccess, error) {
+	revoked := &domain.RevokedAccess{}
+	err := doWithQuerier(ctx, r.db, func(txCtx context.Context) error {
+		querier := getQuerier(txCtx, r.db)
+
+		query := `DELETE FROM family_tree_memberships WHERE tree_id = $1 AND user_id = $2 AND role <> 'owner'`
+		result, err := querier.Exec(txCtx, query, treeID, userID)
+		if err != nil {
+			return domain.NewDatabaseError(err)
+		}
+		if result.RowsAffected() == 0 {
+			return domain.NewNotFoundError("membership")
+		}
+
+		if revoked.ShareLinks, err = revokeShareLinks(txCtx, querier, treeID, userID); err != nil {
+			return err
+		}
+
+		feedsQuery := `
+			UPDATE family_tree_calendar_feeds
+			SET revoked_at = NOW()
+			WHERE tree_id = $1 AND user_id = $2 AND revoked_at IS NULL
+		`
+		result, err = querier.Exec(txCtx, feedsQuery, treeID, userID)
+		if err != nil {
+			return domain.NewDatabaseError(err)
+		}
+		revoked.CalendarFeeds = int(result.RowsAffected())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return revoked, nil
+}
+
+// TransferOwnership hands the tree from its owner to another member, the
+// previous owner stays on as an editor and the share links they made are
+// revoked, it returns how many
+func (r *FamilyTreeRepository) TransferOwnership(ctx context.Context, treeID, ownerUserID, newOwnerUserID int) (*domain.RevokedAccess, error) {
+	revoked := &domain.RevokedAccess{}
+	err := doWithQuerier(ctx, r.db, func(txCtx context.Context) error {
+		querier := getQuerier(txCtx, r.db)
+
+		membershipQuery := `
+			UPDATE family_tree_memberships
+			SET role = $3
+			WHERE tree_id = $1 AND user_id = $2
+		`
+		result, err := querier.Exec(txCtx, membershipQuery, treeID, newOwnerUserID, domain.TreeRoleOwner)
+		if err != nil {
+			return domain.NewDatabaseError(err)
+		}
+		if result.RowsAffected() == 0 {
+			return domain.NewNotFoundError("membership")
+		}
+
+		treeQuery := `
+			UPDATE family_trees
+			SET owner_user_id = $3, updated_at = NOW()
+			WHERE tree_id = $1 AND owner_user_id = $2
+		`
+		result, err = querier.Exec(txCtx, treeQuery, treeID, ownerUserID, newOwnerUserID)
+		if err != nil {
+			return domain.NewDatabaseError(err)
+		}
+		if result.RowsAffected() == 0 {
+			return domain.NewNotFoundError("family_tree")
+		}
+
+		if _, err := querier.Exec(txCtx, membershipQuery, treeID, ownerUserID, domain.TreeRoleEditor); err != nil {
+			return domain.NewDatabaseError(err)
+		}
+		revoked.ShareLinks, err = revokeShareLinks(txCtx, querier, treeID, ownerUserID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return revoked, nil
+}
+
+// revokeShareLinks revokes the share links a user made for the tree and
+// returns how many
+func revokeShareLinks(ctx context.Context, querier Querier, treeID, userID int) (int, error) {
+	query := `
+		UPDATE family_tree_share_links
+		SET revoked_at = NOW()
+		WHERE tree_id = $1 AND created_by = $2 AND revoked_at IS NULL
+	`
+	result, err := querier.Exec(ctx, query, treeID, userID)
+	if err != nil {
+		return 0, domain.NewDatabaseError(err)
+	}
+	return int(result.RowsAffected()), nil
+}
+
 func (r *FamilyTreeRepository) CreateInvitation(ctx context.Context, invitation *domain.FamilyTreeInvitation) error {
 	query := `
 		INSERT INTO family_tree_invitations (
-			tree_id, inviter_user_id, invitee_user_id, invitee_email, message, expires_at
+			tree_id, inviter_user_id, invitee_user_id, invitee_email, role, message, expires_at
 		)
-		VALUES ($1, $2, $3, LOWER($4), $5, $6)
+		VALUES ($1, $2, $3, LOWER($4), $5, $6, $7)
 		RETURNING invitation_id, status, created_at
 	`
 	if err := r.db.QueryRow(ctx, query,
@@ -148,6 +399,7 @@ func (r *FamilyTreeRepository) CreateInvitation(ctx context.Context, invitation
 		invitation.InviterUserID,
 		invitation.InviteeUserID,
 		strings.TrimSpace(invitation.InviteeEmail),
+		invitation.Role,
 		invitation.Message,
 		invitation.ExpiresAt,
 	).Scan(&invitation.InvitationID, &invitation.Status, &invitation.CreatedAt); err != nil {
@@ -165,7 +417,7 @@ func (r *FamilyTreeRepository) ListTreeInvitations(ctx context.Context, treeID,
 
 	query := `
 		SELECT i.invitation_id, i.tree_id, ft.name, i.inviter_user_id, inviter.full_name,
-		       i.invitee_user_id, i.invitee_email, i.status, i.message,
+		       i.invitee_user_id, i.invitee_email, i.role, i.status, i.message,
 		       i.created_at, i.expires_at, i.responded_at
 		FROM family_tree_invitations i
 		JOIN family_trees ft ON ft.tree_id = i.tree_id
@@ -179,7 +431,7 @@ func (r *FamilyTreeRepository) ListTreeInvitations(ctx context.Context, treeID,
 func (r *FamilyTreeRepository) ListPendingInvitationsForUser(ctx context.Context, userID int) ([]*domain.FamilyTreeInvitation, error) {
 	query := `
 		SELECT i.invitation_id, i.tree_id, ft.name, i.inviter_user_id, inviter.full_name,
-		       i.invitee_user_id, i.invitee_email, i.status, i.message,
+		       i.invitee_user_id, i.invitee_email, i.role, i.status, i.message,
 		       i.created_at, i.expires_at, i.responded_at
 		FROM family_tree_invitations i
 		JOIN family_trees ft ON ft.tree_id = i.tree_id
@@ -210,6 +462,7 @@ func (r *FamilyTreeRepository) scanInvitations(ctx context.Context, query string
 			&invitation.InviterName,
 			&invitation.InviteeUserID,
 			&invitation.InviteeEmail,
+			&invitation.Role,
 			&invitation.Status,
 			&invitation.Message,
 			&invitation.CreatedAt,
@@ -226,15 +479,18 @@ func (r *FamilyTreeRepository) scanInvitations(ctx context.Context, query string
 	return invitations, nil
 }
 
-func (r *FamilyTreeRepository) RespondToInvitation(ctx context.Context, invitationID, userID int, accept bool) error {
+// RespondToInvitation accepts or declines a pending invitation and returns the
+// tree it was for. Accepting it makes the user a member with the invited role.
+func (r *FamilyTreeRepository) RespondToInvitation(ctx context.Context, invitationID, userID int, accept bool) (int, error) {
 	status := domain.InvitationStatusDeclined
 	if accept {
 		status = domain.InvitationStatusAccepted
 	}
 
-	return doWithQuerier(ctx, r.db, func(txCtx context.Context) error {
+	var treeID int
+	var role string
+	err := doWithQuerier(ctx, r.db, func(txCtx context.Context) error {
 		querier := getQuerier(txCtx, r.db)
-		var treeID int
 		query := `
 			UPDATE family_tree_invitations
 			SET status = $1, responded_at = NOW()
@@ -242,9 +498,9 @@ func (r *FamilyTreeRepository) RespondToInvitation(ctx context.Context, invitati
 			  AND invitee_user_id = $3
 			  AND status = 'pending'
 			  AND (expires_at IS NULL OR expires_at > NOW())
-			RETURNING tree_id
+			RETURNING tree_id, role
 		`
-		if err := querier.QueryRow(txCtx, query, status, invitationID, userID).Scan(&treeID); err != nil {
+		if err := querier.QueryRow(txCtx, query, status, invitationID, userID).Scan(&treeID, &role); err != nil {
 			if errors.Is(err, pgx.ErrNoRows) {
 				return domain.NewNotFoundError("invitation")
 			}
@@ -260,11 +516,15 @@ func (r *FamilyTreeRepository) RespondToInvitation(ctx context.Context, invitati
 			VALUES ($1, $2, $3)
 			ON CONFLICT (tree_id, user_id) DO NOTHING
 		`
-		if _, err := querier.Exec(txCtx, membershipQuery, treeID, userID, domain.TreeRoleEditor); err != nil {
+		if _, err := querier.Exec(txCtx, membershipQuery, treeID, userID, role); err != nil {
 			return domain.NewDatabaseError(err)
 		}
 		return nil
 	})
+	if err != nil {
+		return 0, err
+	}
+	return treeID, nil
 }
 
 func (r *FamilyTreeRepository) CreateShareLink(ctx context.Context, link *domain.FamilyTreeShareLink) error {
@@ -275,11 +535,11 @@ func (r *FamilyTreeRepository) CreateShareLink(ctx context.Context, link *domain
 	link.Token = token
 
 	query := `
-		INSERT INTO family_tree_share_links (tree_id, token, created_by, expires_at, max_visits)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO family_tree_share_links (tree_id, token, created_by, expires_at, max_visits, living_policy)
+		VALUES ($1, $2, $3, $4, $5, $6)
 		RETURNING share_id, created_at, visit_count
 	`
-	if err := r.db.QueryRow(ctx, query, link.TreeID, link.Token, link.CreatedBy, link.ExpiresAt, link.MaxVisits).
+	if err := r.db.QueryRow(ctx, query, link.TreeID, link.Token, link.CreatedBy, link.ExpiresAt, link.MaxVisits, link.LivingPolicy).
 		Scan(&link.ShareID, &link.CreatedAt, &link.VisitCount); err != nil {
 		return domain.NewDatabaseError(err)
 	}
@@ -294,7 +554,7 @@ func (r *FamilyTreeRepository) ListShareLinks(ctx context.Context, treeID, userI
 	}
 
 	query := `
-		SELECT share_id, tree_id, token, created_by, created_at, expires_at, max_visits, visit_count, revoked_at
+		SELECT share_id, tree_id, token, created_by, created_at, expires_at, max_visits, visit_count, revoked_at, living_policy
 		FROM family_tree_share_links
 		WHERE tree_id = $1
 		ORDER BY created_at DESC
@@ -308,7 +568,7 @@ func (r *FamilyTreeRepository) ListShareLinks(ctx context.Context, treeID, userI
 	var links []*domain.FamilyTreeShareLink
 	for rows.Next() {
 		link := &domain.FamilyTreeShareLink{}
-		if err := rows.Scan(&link.ShareID, &link.TreeID, &link.Token, &link.CreatedBy, &link.CreatedAt, &link.ExpiresAt, &link.MaxVisits, &link.VisitCount, &link.RevokedAt); err != nil {
+		if err := rows.Scan(&link.ShareID, &link.TreeID, &link.Token, &link.CreatedBy, &link.CreatedAt, &link.ExpiresAt, &link.MaxVisits, &link.VisitCount, &link.RevokedAt, &link.LivingPolicy); err != nil {
 			return nil, domain.NewDatabaseError(err)
 		}
 		links = append(links, link)
@@ -319,6 +579,28 @@ func (r *FamilyTreeRepository) ListShareLinks(ctx context.Context, treeID, userI
 	return links, nil
 }
 
+func (r *FamilyTreeRepository) UpdateShareLinkPolicy(ctx context.Context, treeID, shareID, userID int, livingPolicy string) error {
+	if ok, err := r.HasAccess(ctx, treeID, userID); err != nil {
+		return err
+	} else if !ok {
+		return domain.NewNotFoundError("family_tree")
+	}
+
+	query := `
+		UPDATE family_tree_share_links
+		SET living_policy = $3
+		WHERE tree_id = $1 AND share_id = $2 AND revoked_at IS NULL
+	`
+	result, err := r.db.Exec(ctx, query, treeID, shareID, livingPolicy)
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.NewNotFoundError("share_link")
+	}
+	return nil
+}
+
 func (r *FamilyTreeRepository) RevokeShareLink(ctx context.Context, treeID, shareID, userID int) error {
 	if ok, err := r.HasAccess(ctx, treeID, userID); err != nil {
 		return err
@@ -349,10 +631,10 @@ func (r *FamilyTreeRepository) ConsumeShareLink(ctx context.Context, token strin
 		  AND revoked_at IS NULL
 		  AND (expires_at IS NULL OR expires_at > NOW())
 		  AND (max_visits IS NULL OR visit_count < max_visits)
-		RETURNING share_id, tree_id, token, created_by, created_at, expires_at, max_visits, visit_count, revoked_at
+		RETURNING share_id, tree_id, token, created_by, created_at, expires_at, max_visits, visit_count, revoked_at, living_policy
 	`
 	link := &domain.FamilyTreeShareLink{}
-	err := r.db.QueryRow(ctx, query, token).Scan(&link.ShareID, &link.TreeID, &link.Token, &link.CreatedBy, &link.CreatedAt, &link.ExpiresAt, &link.MaxVisits, &link.VisitCount, &link.RevokedAt)
+	err := r.db.QueryRow(ctx, query, token).Scan(&link.ShareID, &link.TreeID, &link.Token, &link.CreatedBy, &link.CreatedAt, &link.ExpiresAt, &link.MaxVisits, &link.VisitCount, &link.RevokedAt, &link.LivingPolicy)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, domain.NewNotFoundError("share_link")
 	}
@@ -362,6 +644,103 @@ func (r *FamilyTreeRepository) ConsumeShareLink(ctx context.Context, token strin
 	return link, nil
 }
 
+func (r *FamilyTreeRepository) CreateCalendarFeed(ctx context.Context, feed *domain.FamilyTreeCalendarFeed) error {
+	token, err := randomShareToken()
+	if err != nil {
+		return domain.NewInternalError(err)
+	}
+	feed.Token = token
+
+	query := `
+		INSERT INTO family_tree_calendar_feeds (tree_id, user_id, token)
+		VALUES ($1, $2, $3)
+		RETURNING feed_id, created_at
+	`
+	if err := r.db.QueryRow(ctx, query, feed.TreeID, feed.UserID, feed.Token).
+		Scan(&feed.FeedID, &feed.CreatedAt); err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	return nil
+}
+
+func (r *FamilyTreeRepository) ListCalendarFeeds(ctx context.Context, treeID, userID int) ([]*domain.FamilyTreeCalendarFeed, error) {
+	query := `
+		SELECT feed_id, tree_id, user_id, token, created_at, last_accessed_at, revoked_at
+		FROM family_tree_calendar_feeds
+		WHERE tree_id = $1 AND user_id = $2
+		ORDER BY created_at DESC
+	`
+	rows, err := r.db.Query(ctx, query, treeID, userID)
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	defer rows.Close()
+
+	var feeds []*domain.FamilyTreeCalendarFeed
+	for rows.Next() {
+		feed := &domain.FamilyTreeCalendarFeed{}
+		if err := rows.Scan(&feed.FeedID, &feed.TreeID, &feed.UserID, &feed.Token, &feed.CreatedAt, &feed.LastAccessedAt, &feed.RevokedAt); err != nil {
+			return nil, domain.NewDatabaseError(err)
+		}
+		feeds = append(feeds, feed)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return feeds, nil
+}
+
+func (r *FamilyTreeRepository) RevokeCalendarFeed(ctx context.Context, treeID, feedID, userID int) error {
+	query := `
+		UPDATE family_tree_calendar_feeds
+		SET revoked_at = NOW()
+		WHERE tree_id = $1 AND feed_id = $2 AND user_id = $3 AND revoked_at IS NULL
+	`
+	result, err := r.db.Exec(ctx, query, treeID, feedID, userID)
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.NewNotFoundError("calendar_feed")
+	}
+	return nil
+}
+
+// GetCalendarFeedByToken returns the feed of an unrevoked token
+func (r *FamilyTreeRepository) GetCalendarFeedByToken(ctx context.Context, token string) (*domain.FamilyTreeCalendarFeed, error) {
+	query := `
+		SELECT feed_id, tree_id, user_id, token, created_at, last_accessed_at, revoked_at
+		FROM family_tree_calendar_feeds
+		WHERE token = $1 AND revoked_at IS NULL
+	`
+	feed := &domain.FamilyTreeCalendarFeed{}
+	err := r.db.QueryRow(ctx, query, token).Scan(&feed.FeedID, &feed.TreeID, &feed.UserID, &feed.Token, &feed.CreatedAt, &feed.LastAccessedAt, &feed.RevokedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.NewNotFoundError("calendar_feed")
+	}
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return feed, nil
+}
+
+// TouchCalendarFeed records a successful fetch of the feed
+func (r *FamilyTreeRepository) TouchCalendarFeed(ctx context.Context, feed *domain.FamilyTreeCalendarFeed) error {
+	query := `
+		UPDATE family_tree_calendar_feeds
+		SET last_accessed_at = NOW()
+		WHERE feed_id = $1
+		RETURNING last_accessed_at
+	`
+	if err := r.db.QueryRow(ctx, query, feed.FeedID).Scan(&feed.LastAccessedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.NewNotFoundError("calendar_feed")
+		}
+		return domain.NewDatabaseError(err)
+	}
+	return nil
+}
+
 func randomShareToken() (string, error) {
 	buf := make([]byte, 32)
 	if _, err := rand.Read(buf); err != nil {
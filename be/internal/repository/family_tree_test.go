@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// membershipFixture is a tree with an owner and an editor who each made a
+// share link for it
+type membershipFixture struct {
+	treeID, ownerID, editorID int
+}
+
+// newMembershipFixture needs a migrated database, DATABASE_DSN points to it
+func newMembershipFixture(t *testing.T) (*pgxpool.Pool, membershipFixture) {
+	t.Helper()
+	dsn := os.Getenv("DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("DATABASE_DSN isn't set")
+	}
+	ctx := context.Background()
+	db, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("pgxpool.New() error = %v", err)
+	}
+	t.Cleanup(db.Close)
+
+	suffix := time.Now().UnixNano()
+	var fixture membershipFixture
+	for _, user := range []struct {
+		id   *int
+		name string
+	}{{&fixture.ownerID, "owner"}, {&fixture.editorID, "editor"}} {
+		query := `INSERT INTO users (full_name, email, is_active) VALUES ($1, $2, TRUE) RETURNING user_id`
+		email := fmt.Sprintf("%s-%d@example.com", user.name, suffix)
+		if err := db.QueryRow(ctx, query, user.name, email).Scan(user.id); err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+		userID := *user.id
+		t.Cleanup(func() { db.Exec(context.Background(), `DELETE FROM users WHERE user_id = $1`, userID) })
+	}
+
+	query := `INSERT INTO family_trees (name, owner_user_id) VALUES ('Fixture', $1) RETURNING tree_id`
+	if err := db.QueryRow(ctx, query, fixture.ownerID).Scan(&fixture.treeID); err != nil {
+		t.Fatalf("create tree: %v", err)
+	}
+	t.Cleanup(func() { db.Exec(context.Background(), `DELETE FROM family_trees WHERE tree_id = $1`, fixture.treeID) })
+
+	for _, member := range []struct {
+		userID int
+		role   string
+	}{{fixture.ownerID, domain.TreeRoleOwner}, {fixture.editorID, domain.TreeRoleEditor}} {
+		query := `INSERT INTO family_tree_memberships (tree_id, user_id, role) VALUES ($1, $2, $3)`
+		if _, err := db.Exec(ctx, query, fixture.treeID, member.userID, member.role); err != nil {
+			t.Fatalf("create membership: %v", err)
+		}
+		query = `INSERT INTO family_tree_share_links (tree_id, token, created_by) VALUES ($1, $2, $3)`
+		token := fmt.Sprintf("token-%d-%d", suffix, member.userID)
+		if _, err := db.Exec(ctx, query, fixture.treeID, token, member.userID); err != nil {
+			t.Fatalf("create share link: %v", err)
+		}
+	}
+	return db, fixture
+}
+
+func activeShareLinks(t *testing.T, db *pgxpool.Pool, treeID, userID int) int {
+	t.Helper()
+	var count int
+	query := `SELECT COUNT(*) FROM family_tree_share_links WHERE tree_id = $1 AND created_by = $2 AND revoked_at IS NULL`
+	if err := db.QueryRow(context.Background(), query, treeID, userID).Scan(&count); err != nil {
+		t.Fatalf("count share links: %v", err)
+	}
+	return count
+}
+
+func TestUpdateMembershipRoleRevokesShareLinks(t *testing.T) {
+	db, fixture := newMembershipFixture(t)
+	repo := NewFamilyTreeRepository(db)
+
+	revoked, err := repo.UpdateMembershipRole(context.Background(), fixture.treeID, fixture.editorID, domain.TreeRoleViewer)
+	if err != nil {
+		t.Fatalf("UpdateMembershipRole() error = %v", err)
+	}
+	if revoked.ShareLinks != 1 {
+		t.Errorf("revoked share links = %d, want 1", revoked.ShareLinks)
+	}
+	if got := activeShareLinks(t, db, fixture.treeID, fixture.editorID); got != 0 {
+		t.Errorf("editor's active share links = %d, want 0", got)
+	}
+	if got := activeShareLinks(t, db, fixture.treeID, fixture.ownerID); got != 1 {
+		t.Errorf("owner's active share links = %d, want 1", got)
+	}
+}
+
+func TestUpdateMembershipRoleKeepsOwner(t *testing.T) {
+	db, fixture := newMembershipFixture(t)
+	repo := NewFamilyTreeRepository(db)
+
+	_, err := repo.UpdateMembershipRole(context.Background(), fixture.treeID, fixture.ownerID, domain.TreeRoleViewer)
+	if !domain.IsDomainError(err, domain.ErrCodeNotFound) {
+		t.Fatalf("UpdateMembershipRole() error = %v, want not found", err)
+	}
+	if got := activeShareLinks(t, db, fixture.treeID, fixture.ownerID); got != 1 {
+		t.Errorf("owner's active share links = %d, want 1", got)
+	}
+}
+
+func TestTransferOwnershipRevokesShareLinks(t *testing.T) {
+	db, fixture := newMembershipFixture(t)
+	repo := NewFamilyTreeRepository(db)
+
+	revoked, err := repo.TransferOwnership(context.Background(), fixture.treeID, fixture.ownerID, fixture.editorID)
+	if err != nil {
+		t.Fatalf("TransferOwnership() error = %v", err)
+	}
+	if revoked.ShareLinks != 1 {
+		t.Errorf("revoked share links = %d, want 1", revoked.ShareLinks)
+	}
+	if got := activeShareLinks(t, db, fixture.treeID, fixture.ownerID); got != 0 {
+		t.Errorf("previous owner's active share links = %d, want 0", got)
+	}
+	if got := activeShareLinks(t, db, fixture.treeID, fixture.editorID); got != 1 {
+		t.Errorf("new owner's active share links = %d, want 1", got)
+	}
+}
@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"errors"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/escalopa/family-tree/internal/domain"
@@ -18,16 +20,18 @@ func NewHistoryRepository(db *pgxpool.Pool) *HistoryRepository {
 	return &HistoryRepository{db: db}
 }
 
+// Create records the history entry in the changeset of ctx
 func (r *HistoryRepository) Create(ctx context.Context, history *domain.History) error {
+	history.ChangesetID = domain.ChangesetFromContext(ctx)
 	querier := getQuerier(ctx, r.db)
 	query := `
-		INSERT INTO members_history (member_id, user_id, change_type, old_values, new_values, member_version)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO members_history (member_id, user_id, change_type, old_values, new_values, member_version, changeset_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING history_id, changed_at
 	`
 	err := querier.QueryRow(ctx, query,
 		history.MemberID, history.UserID, history.ChangeType,
-		history.OldValues, history.NewValues, history.MemberVersion,
+		history.OldValues, history.NewValues, history.MemberVersion, history.ChangesetID,
 	).Scan(&history.HistoryID, &history.ChangedAt)
 	if err != nil {
 		return domain.NewDatabaseError(err)
@@ -35,6 +39,7 @@ func (r *HistoryRepository) Create(ctx context.Context, history *domain.History)
 	return nil
 }
 
+// CreateBatch records the history entries in the changeset of ctx
 func (r *HistoryRepository) CreateBatch(ctx context.Context, histories ...*domain.History) error {
 	if len(histories) == 0 {
 		return nil
@@ -43,15 +48,17 @@ func (r *HistoryRepository) CreateBatch(ctx context.Context, histories ...*domai
 	querier := getQuerier(ctx, r.db)
 	batch := &pgx.Batch{}
 	query := `
-		INSERT INTO members_history (member_id, user_id, change_type, old_values, new_values, member_version)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO members_history (member_id, user_id, change_type, old_values, new_values, member_version, changeset_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING history_id, changed_at
 	`
 
+	changesetID := domain.ChangesetFromContext(ctx)
 	for _, history := range histories {
+		history.ChangesetID = changesetID
 		batch.Queue(query,
 			history.MemberID, history.UserID, history.ChangeType,
-			history.OldValues, history.NewValues, history.MemberVersion,
+			history.OldValues, history.NewValues, history.MemberVersion, history.ChangesetID,
 		)
 	}
 
@@ -71,7 +78,7 @@ func (r *HistoryRepository) CreateBatch(ctx context.Context, histories ...*domai
 func (r *HistoryRepository) Get(ctx context.Context, historyID int) (*domain.HistoryWithUser, error) {
 	query := `
 		SELECT h.history_id, h.member_id, h.user_id, h.changed_at, h.change_type,
-		       h.old_values, h.new_values, h.member_version, u.full_name, u.email,
+		       h.old_values, h.new_values, h.member_version, h.changeset_id, u.full_name, u.email,
 		       COALESCE(
 			       (SELECT jsonb_object_agg(mn.language_code, mn.name)
 			        FROM member_names mn
@@ -86,7 +93,7 @@ func (r *HistoryRepository) Get(ctx context.Context, historyID int) (*domain.His
 	history := &domain.HistoryWithUser{}
 	err := r.db.QueryRow(ctx, query, historyID).Scan(
 		&history.HistoryID, &history.MemberID, &history.UserID, &history.ChangedAt, &history.ChangeType,
-		&history.OldValues, &history.NewValues, &history.MemberVersion, &history.UserFullName, &history.UserEmail,
+		&history.OldValues, &history.NewValues, &history.MemberVersion, &history.ChangesetID, &history.UserFullName, &history.UserEmail,
 		&history.MemberNames,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -99,10 +106,132 @@ func (r *HistoryRepository) Get(ctx context.Context, historyID int) (*domain.His
 	return history, nil
 }
 
+// GetLatest returns the newest history entry of a member with the change type
+func (r *HistoryRepository) GetLatest(ctx context.Context, memberID int, changeType string) (*domain.History, error) {
+	query := `
+		SELECT history_id, member_id, user_id, changed_at, change_type, old_values, new_values, member_version, changeset_id
+		FROM members_history
+		WHERE member_id = $1 AND change_type = $2
+		ORDER BY history_id DESC
+		LIMIT 1
+	`
+	history := &domain.History{}
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, memberID, changeType).Scan(
+		&history.HistoryID, &history.MemberID, &history.UserID, &history.ChangedAt, &history.ChangeType,
+		&history.OldValues, &history.NewValues, &history.MemberVersion, &history.ChangesetID,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.NewNotFoundError("history")
+	}
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return history, nil
+}
+
+// GetSnapshot returns the last entry that recorded the whole member at or
+// before the version
+func (r *HistoryRepository) GetSnapshot(ctx context.Context, memberID, version int) (*domain.History, error) {
+	query := `
+		SELECT history_id, member_id, user_id, changed_at, change_type, old_values, new_values, member_version, changeset_id
+		FROM members_history
+		WHERE member_id = $1 AND member_version <= $2 AND change_type = ANY($3)
+		ORDER BY history_id DESC
+		LIMIT 1
+	`
+	changeTypes := []string{domain.ChangeTypeInsert, domain.ChangeTypeUpdate, domain.ChangeTypeRestore}
+	history := &domain.History{}
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, memberID, version, changeTypes).Scan(
+		&history.HistoryID, &history.MemberID, &history.UserID, &history.ChangedAt, &history.ChangeType,
+		&history.OldValues, &history.NewValues, &history.MemberVersion, &history.ChangesetID,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.NewNotFoundError("history")
+	}
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return history, nil
+}
+
+// ListByMemberIDSinceVersion returns the history of a member written after
+// the version, oldest first
+func (r *HistoryRepository) ListByMemberIDSinceVersion(ctx context.Context, memberID, version int) ([]*domain.HistoryWithUser, error) {
+	query := `
+		SELECT h.history_id, h.member_id, h.user_id, h.changed_at, h.change_type,
+		       h.old_values, h.new_values, h.member_version, h.changeset_id, u.full_name, u.email,
+		       COALESCE(
+			       (SELECT jsonb_object_agg(mn.language_code, mn.name)
+			        FROM member_names mn
+			        WHERE mn.member_id = h.member_id),
+			       '{}'::jsonb
+		       ) as member_names
+		FROM members_history h
+		JOIN users u ON h.user_id = u.user_id
+		WHERE h.member_id = $1 AND h.member_version > $2
+		ORDER BY h.history_id ASC
+	`
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, memberID, version)
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	defer rows.Close()
+
+	histories := []*domain.HistoryWithUser{}
+	for rows.Next() {
+		h := &domain.HistoryWithUser{}
+		if err := rows.Scan(
+			&h.HistoryID, &h.MemberID, &h.UserID, &h.ChangedAt, &h.ChangeType,
+			&h.OldValues, &h.NewValues, &h.MemberVersion, &h.ChangesetID, &h.UserFullName, &h.UserEmail,
+			&h.MemberNames,
+		); err != nil {
+			return nil, domain.NewDatabaseError(err)
+		}
+		histories = append(histories, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return histories, nil
+}
+
+// ListByTreeIDSince returns the history of a tree's members, deleted ones
+// included, written after since with one of the change types, oldest first
+func (r *HistoryRepository) ListByTreeIDSince(ctx context.Context, treeID int, since time.Time, changeTypes []string) ([]*domain.History, error) {
+	query := `
+		SELECT h.history_id, h.member_id, h.user_id, h.changed_at, h.change_type, h.old_values, h.new_values, h.member_version, h.changeset_id
+		FROM members_history h
+		JOIN members m ON m.member_id = h.member_id
+		WHERE m.tree_id = $1 AND h.changed_at > $2 AND h.change_type = ANY($3)
+		ORDER BY h.history_id ASC
+	`
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, treeID, since, changeTypes)
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	defer rows.Close()
+
+	histories := []*domain.History{}
+	for rows.Next() {
+		history := &domain.History{}
+		if err := rows.Scan(
+			&history.HistoryID, &history.MemberID, &history.UserID, &history.ChangedAt, &history.ChangeType,
+			&history.OldValues, &history.NewValues, &history.MemberVersion, &history.ChangesetID,
+		); err != nil {
+			return nil, domain.NewDatabaseError(err)
+		}
+		histories = append(histories, history)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return histories, nil
+}
+
 func (r *HistoryRepository) GetByMemberID(ctx context.Context, memberID int, cursor *string, limit int) ([]*domain.HistoryWithUser, *string, error) {
 	query := `
 		SELECT h.history_id, h.member_id, h.user_id, h.changed_at, h.change_type,
-		       h.old_values, h.new_values, h.member_version, u.full_name, u.email,
+		       h.old_values, h.new_values, h.member_version, h.changeset_id, u.full_name, u.email,
 		       COALESCE(
 			       (SELECT jsonb_object_agg(mn.language_code, mn.name)
 			        FROM member_names mn
@@ -128,7 +257,7 @@ func (r *HistoryRepository) GetByMemberID(ctx context.Context, memberID int, cur
 		h := &domain.HistoryWithUser{}
 		err := rows.Scan(
 			&h.HistoryID, &h.MemberID, &h.UserID, &h.ChangedAt, &h.ChangeType,
-			&h.OldValues, &h.NewValues, &h.MemberVersion, &h.UserFullName, &h.UserEmail,
+			&h.OldValues, &h.NewValues, &h.MemberVersion, &h.ChangesetID, &h.UserFullName, &h.UserEmail,
 			&h.MemberNames,
 		)
 		if err != nil {
@@ -150,10 +279,201 @@ func (r *HistoryRepository) GetByMemberID(ctx context.Context, memberID int, cur
 	return histories, nextCursor, nil
 }
 
+// ListByChangeset returns the history a changeset wrote for a tree's
+// members, deleted ones included, oldest first
+func (r *HistoryRepository) ListByChangeset(ctx context.Context, treeID int, changesetID string) ([]*domain.History, error) {
+	query := `
+		SELECT h.history_id, h.member_id, h.user_id, h.changed_at, h.change_type, h.old_values, h.new_values, h.member_version, h.changeset_id
+		FROM members_history h
+		JOIN members m ON m.member_id = h.member_id
+		WHERE m.tree_id = $1 AND h.changeset_id = $2
+		ORDER BY h.history_id ASC
+	`
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, treeID, changesetID)
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	defer rows.Close()
+
+	histories := []*domain.History{}
+	for rows.Next() {
+		history := &domain.History{}
+		if err := rows.Scan(
+			&history.HistoryID, &history.MemberID, &history.UserID, &history.ChangedAt, &history.ChangeType,
+			&history.OldValues, &history.NewValues, &history.MemberVersion, &history.ChangesetID,
+		); err != nil {
+			return nil, domain.NewDatabaseError(err)
+		}
+		histories = append(histories, history)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return histories, nil
+}
+
+// ListByMemberIDUntil returns the history of a member up to and including
+// the history entry, oldest first
+func (r *HistoryRepository) ListByMemberIDUntil(ctx context.Context, memberID, historyID int) ([]*domain.History, error) {
+	query := `
+		SELECT history_id, member_id, user_id, changed_at, change_type, old_values, new_values, member_version, changeset_id
+		FROM members_history
+		WHERE member_id = $1 AND history_id <= $2
+		ORDER BY history_id ASC
+	`
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, memberID, historyID)
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	defer rows.Close()
+
+	histories := []*domain.History{}
+	for rows.Next() {
+		history := &domain.History{}
+		if err := rows.Scan(
+			&history.HistoryID, &history.MemberID, &history.UserID, &history.ChangedAt, &history.ChangeType,
+			&history.OldValues, &history.NewValues, &history.MemberVersion, &history.ChangesetID,
+		); err != nil {
+			return nil, domain.NewDatabaseError(err)
+		}
+		histories = append(histories, history)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return histories, nil
+}
+
+// ListMemberIDsChangedAfter returns which of the members have history
+// written after the history entry outside the changeset
+func (r *HistoryRepository) ListMemberIDsChangedAfter(ctx context.Context, memberIDs []int, historyID int, changesetID string) ([]int, error) {
+	query := `
+		SELECT DISTINCT member_id
+		FROM members_history
+		WHERE member_id = ANY($1) AND history_id > $2
+		  AND changeset_id IS DISTINCT FROM $3::uuid
+		ORDER BY member_id
+	`
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, memberIDs, historyID, changesetID)
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	defer rows.Close()
+
+	changed := []int{}
+	for rows.Next() {
+		var memberID int
+		if err := rows.Scan(&memberID); err != nil {
+			return nil, domain.NewDatabaseError(err)
+		}
+		changed = append(changed, memberID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return changed, nil
+}
+
+// ListByTreeID returns the changes made to a tree's members, deleted ones
+// included, newest first. The cursor carries the time and id of the last
+// change since changes written together share a timestamp
+func (r *HistoryRepository) ListByTreeID(ctx context.Context, filter domain.ActivityFilter, cursor *string, limit int) ([]*domain.HistoryWithUser, *string, error) {
+	var cursorAt *time.Time
+	var cursorID *int
+	if cursor != nil && *cursor != "" {
+		at, id, ok := parseActivityCursor(*cursor)
+		if !ok {
+			return nil, nil, domain.NewValidationError("error.validation.invalid_cursor")
+		}
+		cursorAt, cursorID = &at, &id
+	}
+
+	query := `
+		SELECT h.history_id, h.member_id, h.user_id, h.changed_at, h.change_type,
+		       h.old_values, h.new_values, h.member_version, h.changeset_id, u.full_name, u.email,
+		       COALESCE(
+			       (SELECT jsonb_object_agg(mn.language_code, mn.name)
+			        FROM member_names mn
+			        WHERE mn.member_id = h.member_id),
+			       '{}'::jsonb
+		       ) as member_names
+		FROM members_history h
+		JOIN users u ON h.user_id = u.user_id
+		JOIN members m ON m.member_id = h.member_id
+		WHERE m.tree_id = $1
+		  AND (($2::int IS NULL) OR h.user_id = $2)
+		  AND (($3::text[] IS NULL) OR h.change_type = ANY($3))
+		  AND (($4::int[] IS NULL) OR h.member_id = ANY($4))
+		  AND (($5::timestamp IS NULL) OR h.changed_at >= $5)
+		  AND (($6::timestamp IS NULL) OR h.changed_at < $6)
+		  AND (($7::uuid IS NULL) OR h.changeset_id = $7)
+		  AND (($8::timestamp IS NULL) OR (h.changed_at, h.history_id) < ($8, $9::int))
+		ORDER BY h.changed_at DESC, h.history_id DESC
+		LIMIT $10
+	`
+
+	var changeTypes []string
+	if len(filter.ChangeTypes) > 0 {
+		changeTypes = filter.ChangeTypes
+	}
+
+	rows, err := r.db.Query(ctx, query,
+		filter.TreeID, filter.UserID, changeTypes, filter.MemberIDs,
+		filter.From, filter.To, filter.ChangesetID, cursorAt, cursorID, limit,
+	)
+	if err != nil {
+		return nil, nil, domain.NewDatabaseError(err)
+	}
+	defer rows.Close()
+
+	histories := []*domain.HistoryWithUser{}
+	for rows.Next() {
+		h := &domain.HistoryWithUser{}
+		err := rows.Scan(
+			&h.HistoryID, &h.MemberID, &h.UserID, &h.ChangedAt, &h.ChangeType,
+			&h.OldValues, &h.NewValues, &h.MemberVersion, &h.ChangesetID, &h.UserFullName, &h.UserEmail,
+			&h.MemberNames,
+		)
+		if err != nil {
+			return nil, nil, domain.NewDatabaseError(err)
+		}
+		histories = append(histories, h)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, domain.NewDatabaseError(err)
+	}
+
+	var nextCursor *string
+	if len(histories) == limit && limit > 0 {
+		last := histories[len(histories)-1]
+		next := last.ChangedAt.Format(time.RFC3339Nano) + "_" + strconv.Itoa(last.HistoryID)
+		nextCursor = &next
+	}
+
+	return histories, nextCursor, nil
+}
+
+func parseActivityCursor(cursor string) (time.Time, int, bool) {
+	at, id, found := strings.Cut(cursor, "_")
+	if !found {
+		return time.Time{}, 0, false
+	}
+	changedAt, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	historyID, err := strconv.Atoi(id)
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	return changedAt, historyID, true
+}
+
 func (r *HistoryRepository) GetByUserID(ctx context.Context, userID int, cursor *string, limit int) ([]*domain.HistoryWithUser, *string, error) {
 	query := `
 		SELECT h.history_id, h.member_id, h.user_id, h.changed_at, h.change_type,
-		       h.old_values, h.new_values, h.member_version, u.full_name, u.email,
+		       h.old_values, h.new_values, h.member_version, h.changeset_id, u.full_name, u.email,
 		       COALESCE(
 			       (SELECT jsonb_object_agg(mn.language_code, mn.name)
 			        FROM member_names mn
@@ -179,7 +499,7 @@ func (r *HistoryRepository) GetByUserID(ctx context.Context, userID int, cursor
 		h := &domain.HistoryWithUser{}
 		err := rows.Scan(
 			&h.HistoryID, &h.MemberID, &h.UserID, &h.ChangedAt, &h.ChangeType,
-			&h.OldValues, &h.NewValues, &h.MemberVersion, &h.UserFullName, &h.UserEmail,
+			&h.OldValues, &h.NewValues, &h.MemberVersion, &h.ChangesetID, &h.UserFullName, &h.UserEmail,
 			&h.MemberNames,
 		)
 		if err != nil {
@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type MediaRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewMediaRepository(db *pgxpool.Pool) *MediaRepository {
+	return &MediaRepository{db: db}
+}
+
+const selectMediaColumns = `
+		SELECT mi.media_id, mi.tree_id, mi.storage_key, mi.caption,
+		       mi.media_date, mi.media_date_qualifier, mi.media_date_end, mi.media_date_calendar,
+		       mi.place_id, mi.uploaded_by, mi.created_at, mi.updated_at,
+		       COALESCE(
+			       (SELECT jsonb_agg(jsonb_build_object(
+				           'member_id', mt.member_id,
+				           'face', CASE WHEN mt.face_x IS NULL THEN NULL ELSE jsonb_build_object(
+					           'x', mt.face_x, 'y', mt.face_y, 'width', mt.face_width, 'height', mt.face_height
+				           ) END
+			           ) ORDER BY mt.created_at, mt.member_id)
+			        FROM media_tags mt
+			        WHERE mt.media_id = mi.media_id),
+			       '[]'::jsonb
+		       ) AS tags
+		FROM media_items mi
+`
+
+func scanMedia(row pgx.Row, media *domain.Media) error {
+	return row.Scan(
+		&media.MediaID, &media.TreeID, &media.StorageKey, &media.Caption,
+		&media.Date, &media.DateQualifier, &media.DateEnd, &media.DateCalendar,
+		&media.PlaceID, &media.UploadedBy, &media.CreatedAt, &media.UpdatedAt,
+		&media.Tags,
+	)
+}
+
+func (r *MediaRepository) Create(ctx context.Context, media *domain.Media) error {
+	querier := getQuerier(ctx, r.db)
+	query := `
+		INSERT INTO media_items (tree_id, storage_key, caption,
+		                         media_date, media_date_qualifier, media_date_end, media_date_calendar,
+		                         place_id, uploaded_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING media_id, created_at, updated_at
+	`
+	err := querier.QueryRow(ctx, query,
+		media.TreeID, media.StorageKey, media.Caption,
+		media.Date, media.DateQualifier, media.DateEnd, media.DateCalendar,
+		media.PlaceID, media.UploadedBy,
+	).Scan(&media.MediaID, &media.CreatedAt, &media.UpdatedAt)
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	return nil
+}
+
+func (r *MediaRepository) Get(ctx context.Context, mediaID int) (*domain.Media, error) {
+	query := selectMediaColumns + `
+		WHERE mi.media_id = $1 AND mi.deleted_at IS NULL
+	`
+	media := &domain.Media{}
+	err := scanMedia(getQuerier(ctx, r.db).QueryRow(ctx, query, mediaID), media)
+	if errors.Is(err, pgx.ErrNoRows) {
+		slog.Warn("MediaRepository.Get: media not found", "media_id", mediaID)
+		return nil, domain.NewNotFoundError("media")
+	}
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return media, nil
+}
+
+func (r *MediaRepository) ListByTreeID(ctx context.Context, treeID int) ([]*domain.Media, error) {
+	query := selectMediaColumns + `
+		WHERE mi.tree_id = $1 AND mi.deleted_at IS NULL
+		ORDER BY mi.created_at DESC, mi.media_id DESC
+	`
+	return r.list(ctx, query, treeID)
+}
+
+func (r *MediaRepository) ListByMemberID(ctx context.Context, memberID int) ([]*domain.Media, error) {
+	query := selectMediaColumns + `
+		WHERE mi.deleted_at IS NULL
+		  AND EXISTS (SELECT 1 FROM media_tags t WHERE t.media_id = mi.media_id AND t.member_id = $1)
+		ORDER BY mi.media_date ASC NULLS LAST, mi.media_id ASC
+	`
+	return r.list(ctx, query, memberID)
+}
+
+func (r *MediaRepository) list(ctx context.Context, query string, args ...any) ([]*domain.Media, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	defer rows.Close()
+
+	var items []*domain.Media
+	for rows.Next() {
+		media := &domain.Media{}
+		if err := scanMedia(rows, media); err != nil {
+			return nil, domain.NewDatabaseError(err)
+		}
+		items = append(items, media)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return items, nil
+}
+
+func (r *MediaRepository) Update(ctx context.Context, media *domain.Media) error {
+	querier := getQuerier(ctx, r.db)
+	query := `
+		UPDATE media_items
+		SET caption = $1,
+		    media_date = $2, media_date_qualifier = $3, media_date_end = $4, media_date_calendar = $5,
+		    place_id = $6, updated_at = CURRENT_TIMESTAMP
+		WHERE media_id = $7 AND deleted_at IS NULL
+		RETURNING updated_at
+	`
+	err := querier.QueryRow(ctx, query,
+		media.Caption,
+		media.Date, media.DateQualifier, media.DateEnd, media.DateCalendar,
+		media.PlaceID, media.MediaID,
+	).Scan(&media.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.NewNotFoundError("media")
+	}
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	return nil
+}
+
+func (r *MediaRepository) Delete(ctx context.Context, mediaID int) error {
+	querier := getQuerier(ctx, r.db)
+	query := `
+		UPDATE media_items
+		SET deleted_at = CURRENT_TIMESTAMP
+		WHERE media_id = $1 AND deleted_at IS NULL
+	`
+	result, err := querier.Exec(ctx, query, mediaID)
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.NewNotFoundError("media")
+	}
+	return nil
+}
+
+func (r *MediaRepository) UpsertTag(ctx context.Context, mediaID int, tag domain.MediaTag) error {
+	querier := getQuerier(ctx, r.db)
+	var x, y, width, height *float64
+	if tag.Face != nil {
+		x, y, width, height = &tag.Face.X, &tag.Face.Y, &tag.Face.Width, &tag.Face.Height
+	}
+	query := `
+		INSERT INTO media_tags (media_id, member_id, face_x, face_y, face_width, face_height)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (media_id, member_id) DO UPDATE
+		SET face_x = EXCLUDED.face_x, face_y = EXCLUDED.face_y,
+		    face_width = EXCLUDED.face_width, face_height = EXCLUDED.face_height
+	`
+	if _, err := querier.Exec(ctx, query, mediaID, tag.MemberID, x, y, width, height); err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	return nil
+}
+
+func (r *MediaRepository) DeleteTag(ctx context.Context, mediaID, memberID int) error {
+	querier := getQuerier(ctx, r.db)
+	result, err := querier.Exec(ctx, `DELETE FROM media_tags WHERE media_id = $1 AND member_id = $2`, mediaID, memberID)
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.NewNotFoundError("media_tag")
+	}
+	return nil
+}
+
+// IsKeyInUse reports whether a stored image is still referenced by a live
+// media item or a member picture, a profile picture chosen from the gallery
+// shares its key with the media item. Superseded pictures count as in use
+// until they are purged
+func (r *MediaRepository) IsKeyInUse(ctx context.Context, key string) (bool, error) {
+	query := `
+		SELECT EXISTS (SELECT 1 FROM media_items WHERE storage_key = $1 AND deleted_at IS NULL)
+		    OR EXISTS (SELECT 1 FROM members WHERE picture = $1 AND deleted_at IS NULL)
+		    OR EXISTS (SELECT 1 FROM superseded_pictures WHERE storage_key = $1)
+	`
+	var inUse bool
+	if err := getQuerier(ctx, r.db).QueryRow(ctx, query, key).Scan(&inUse); err != nil {
+		return false, domain.NewDatabaseError(err)
+	}
+	return inUse, nil
+}
+
+// SupersedePicture keeps a picture replaced or removed from a member so a
+// rollback can bring it back
+func (r *MediaRepository) SupersedePicture(ctx context.Context, treeID int, key string) error {
+	query := `
+		INSERT INTO superseded_pictures (storage_key, tree_id)
+		VALUES ($1, $2)
+		ON CONFLICT (storage_key) DO UPDATE SET superseded_at = CURRENT_TIMESTAMP
+	`
+	if _, err := getQuerier(ctx, r.db).Exec(ctx, query, key, treeID); err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	return nil
+}
+
+// PurgePictures forgets the superseded pictures of a tree and returns the keys
+// no longer referenced by a live media item or member, to delete from storage
+func (r *MediaRepository) PurgePictures(ctx context.Context, treeID int) ([]string, error) {
+	query := `
+		DELETE FROM superseded_pictures sp
+		WHERE sp.tree_id = $1
+		RETURNING sp.storage_key,
+		          EXISTS (SELECT 1 FROM media_items WHERE storage_key = sp.storage_key AND deleted_at IS NULL)
+		       OR EXISTS (SELECT 1 FROM members WHERE picture = sp.storage_key AND deleted_at IS NULL)
+	`
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, treeID)
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	defer rows.Close()
+
+	keys := []string{}
+	for rows.Next() {
+		var (
+			key   string
+			inUse bool
+		)
+		if err := rows.Scan(&key, &inUse); err != nil {
+			return nil, domain.NewDatabaseError(err)
+		}
+		if !inUse {
+			keys = append(keys, key)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return keys, nil
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/escalopa/family-tree/internal/domain"
 	"github.com/jackc/pgx/v5"
@@ -21,13 +23,70 @@ func NewMemberRepository(db *pgxpool.Pool) *MemberRepository {
 	}
 }
 
+// memberColumns are the members columns read by scanMember, in scan order
+var memberColumns = []string{
+	"member_id", "tree_id", "gender", "picture",
+	"date_of_birth", "date_of_birth_qualifier", "date_of_birth_end", "date_of_birth_calendar",
+	"date_of_death", "date_of_death_qualifier", "date_of_death_end", "date_of_death_calendar",
+	"birth_place_id", "death_place_id", "burial_place_id",
+	"father_id", "mother_id", "nicknames", "profession", "living_status", "version", "deleted_at",
+}
+
+// selectMemberColumns renders memberColumns for a SELECT list, prefixed with the table alias if any,
+// followed by the name parts keyed by language, the languages of suggested names and the custom field values keyed by field key
+func selectMemberColumns(alias string) string {
+	table := alias
+	if table == "" {
+		table = "members"
+	}
+	extra := `,
+		COALESCE(
+			(SELECT jsonb_object_agg(np.language_code, jsonb_build_object(
+				'kunya', COALESCE(np.kunya, ''), 'laqab', COALESCE(np.laqab, ''), 'family', COALESCE(np.family_name, '')))
+			 FROM member_names np
+			 WHERE np.member_id = ` + table + `.member_id
+			   AND (np.kunya IS NOT NULL OR np.laqab IS NOT NULL OR np.family_name IS NOT NULL)),
+			'{}'::jsonb
+		) AS name_parts,
+		ARRAY(
+			SELECT sn.language_code
+			FROM member_names sn
+			WHERE sn.member_id = ` + table + `.member_id AND sn.is_suggested
+			ORDER BY sn.language_code
+		) AS suggested_names,
+		COALESCE(
+			(SELECT jsonb_object_agg(cf.field_key, cv.value)
+			 FROM member_custom_values cv
+			 JOIN tree_custom_fields cf ON cf.field_id = cv.field_id
+			 WHERE cv.member_id = ` + table + `.member_id),
+			'{}'::jsonb
+		) AS custom_fields`
+
+	if alias == "" {
+		return strings.Join(memberColumns, ", ") + extra
+	}
+	return alias + "." + strings.Join(memberColumns, ", "+alias+".") + extra
+}
+
+func scanMember(row pgx.Row, member *domain.Member, extra ...any) error {
+	dest := []any{
+		&member.MemberID, &member.TreeID, &member.Gender, &member.Picture,
+		&member.DateOfBirth, &member.DateOfBirthQualifier, &member.DateOfBirthEnd, &member.DateOfBirthCalendar,
+		&member.DateOfDeath, &member.DateOfDeathQualifier, &member.DateOfDeathEnd, &member.DateOfDeathCalendar,
+		&member.BirthPlaceID, &member.DeathPlaceID, &member.BurialPlaceID,
+		&member.FatherID, &member.MotherID, &member.Nicknames, &member.Profession, &member.LivingStatus, &member.Version, &member.DeletedAt,
+		&member.NameParts, &member.SuggestedNames, &member.CustomFields,
+	}
+	return row.Scan(append(dest, extra...)...)
+}
+
 func (r *MemberRepository) GetMemberNames(ctx context.Context, memberID int) (map[string]string, error) {
 	query := `
 		SELECT language_code, name
 		FROM member_names
 		WHERE member_id = $1
 	`
-	rows, err := r.db.Query(ctx, query, memberID)
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, memberID)
 	if err != nil {
 		return nil, domain.NewDatabaseError(err)
 	}
@@ -55,7 +114,7 @@ func (r *MemberRepository) GetMemberNamesByIDs(ctx context.Context, memberIDs []
 		FROM member_names
 		WHERE member_id = ANY($1)
 	`
-	rows, err := r.db.Query(ctx, query, memberIDs)
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, memberIDs)
 	if err != nil {
 		return nil, domain.NewDatabaseError(err)
 	}
@@ -87,15 +146,20 @@ func (r *MemberRepository) createTx(ctx context.Context, member *domain.Member)
 	querier := getQuerier(ctx, r.db)
 
 	query := `
-		INSERT INTO members (tree_id, gender, picture, date_of_birth, date_of_death,
-		                     father_id, mother_id, nicknames, profession, version)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, 1)
+		INSERT INTO members (tree_id, gender, picture,
+		                     date_of_birth, date_of_birth_qualifier, date_of_birth_end, date_of_birth_calendar,
+		                     date_of_death, date_of_death_qualifier, date_of_death_end, date_of_death_calendar,
+		                     birth_place_id, death_place_id, burial_place_id,
+		                     father_id, mother_id, nicknames, profession, living_status, version)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, 1)
 		RETURNING member_id, version
 	`
 	err := querier.QueryRow(ctx, query,
 		member.TreeID, member.Gender, member.Picture,
-		member.DateOfBirth, member.DateOfDeath, member.FatherID, member.MotherID,
-		member.Nicknames, member.Profession,
+		member.DateOfBirth, member.DateOfBirthQualifier, member.DateOfBirthEnd, member.DateOfBirthCalendar,
+		member.DateOfDeath, member.DateOfDeathQualifier, member.DateOfDeathEnd, member.DateOfDeathCalendar,
+		member.BirthPlaceID, member.DeathPlaceID, member.BurialPlaceID,
+		member.FatherID, member.MotherID, member.Nicknames, member.Profession, member.LivingStatus,
 	).Scan(&member.MemberID, &member.Version)
 	if err != nil {
 		return domain.NewDatabaseError(err)
@@ -103,12 +167,15 @@ func (r *MemberRepository) createTx(ctx context.Context, member *domain.Member)
 
 	batch := &pgx.Batch{}
 	nameQuery := `
-			INSERT INTO member_names (member_id, language_code, name, created_at, updated_at)
-			VALUES ($1, $2, $3, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+			INSERT INTO member_names (member_id, language_code, name, kunya, laqab, family_name, is_suggested, created_at, updated_at)
+			VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), $7, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 		`
 	for langCode, name := range member.Names {
-		batch.Queue(nameQuery, member.MemberID, langCode, name)
+		parts := member.NameParts[langCode]
+		batch.Queue(nameQuery, member.MemberID, langCode, name, parts.Kunya, parts.Laqab, parts.Family,
+			slices.Contains(member.SuggestedNames, langCode))
 	}
+	queueCustomValues(batch, member)
 
 	br := querier.SendBatch(ctx, batch)
 	if err := br.Close(); err != nil {
@@ -120,17 +187,12 @@ func (r *MemberRepository) createTx(ctx context.Context, member *domain.Member)
 
 func (r *MemberRepository) Get(ctx context.Context, memberID int) (*domain.Member, error) {
 	query := `
-		SELECT member_id, tree_id, gender, picture, date_of_birth, date_of_death,
-		       father_id, mother_id, nicknames, profession, version, deleted_at
+		SELECT ` + selectMemberColumns("") + `
 		FROM members
 		WHERE member_id = $1 AND deleted_at IS NULL
 	`
 	member := &domain.Member{}
-	err := r.db.QueryRow(ctx, query, memberID).Scan(
-		&member.MemberID, &member.TreeID, &member.Gender,
-		&member.Picture, &member.DateOfBirth, &member.DateOfDeath, &member.FatherID,
-		&member.MotherID, &member.Nicknames, &member.Profession, &member.Version, &member.DeletedAt,
-	)
+	err := scanMember(getQuerier(ctx, r.db).QueryRow(ctx, query, memberID), member)
 	if errors.Is(err, pgx.ErrNoRows) {
 		slog.Warn("MemberRepository.Get: member not found", "member_id", memberID)
 		return nil, domain.NewNotFoundError("member")
@@ -159,16 +221,22 @@ func (r *MemberRepository) updateTx(ctx context.Context, member *domain.Member,
 
 	query := `
 		UPDATE members
-		SET gender = $1, picture = $2, date_of_birth = $3,
-		    date_of_death = $4, father_id = $5, mother_id = $6, nicknames = $7, profession = $8,
+		SET gender = $1, picture = $2,
+		    date_of_birth = $3, date_of_birth_qualifier = $4, date_of_birth_end = $5, date_of_birth_calendar = $6,
+		    date_of_death = $7, date_of_death_qualifier = $8, date_of_death_end = $9, date_of_death_calendar = $10,
+		    birth_place_id = $11, death_place_id = $12, burial_place_id = $13,
+		    father_id = $14, mother_id = $15, nicknames = $16, profession = $17, living_status = $18,
 		    version = version + 1
-		WHERE member_id = $9 AND version = $10 AND deleted_at IS NULL
+		WHERE member_id = $19 AND version = $20 AND deleted_at IS NULL
 		RETURNING version
 	`
 	err := querier.QueryRow(ctx, query,
 		member.Gender, member.Picture,
-		member.DateOfBirth, member.DateOfDeath, member.FatherID, member.MotherID,
-		member.Nicknames, member.Profession, member.MemberID, expectedVersion,
+		member.DateOfBirth, member.DateOfBirthQualifier, member.DateOfBirthEnd, member.DateOfBirthCalendar,
+		member.DateOfDeath, member.DateOfDeathQualifier, member.DateOfDeathEnd, member.DateOfDeathCalendar,
+		member.BirthPlaceID, member.DeathPlaceID, member.BurialPlaceID,
+		member.FatherID, member.MotherID, member.Nicknames, member.Profession, member.LivingStatus,
+		member.MemberID, expectedVersion,
 	).Scan(&member.Version)
 	if errors.Is(err, pgx.ErrNoRows) {
 		slog.Warn("MemberRepository.Update: version conflict", "member_id", member.MemberID, "expected_version", expectedVersion)
@@ -180,16 +248,23 @@ func (r *MemberRepository) updateTx(ctx context.Context, member *domain.Member,
 
 	batch := &pgx.Batch{}
 	nameQuery := `
-			INSERT INTO member_names (member_id, language_code, name, created_at, updated_at)
-			VALUES ($1, $2, $3, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+			INSERT INTO member_names (member_id, language_code, name, kunya, laqab, family_name, is_suggested, created_at, updated_at)
+			VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), $7, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 			ON CONFLICT (member_id, language_code)
 			DO UPDATE SET
 				name = EXCLUDED.name,
+				kunya = EXCLUDED.kunya,
+				laqab = EXCLUDED.laqab,
+				family_name = EXCLUDED.family_name,
+				is_suggested = EXCLUDED.is_suggested,
 				updated_at = CURRENT_TIMESTAMP
 		`
 	for langCode, name := range member.Names {
-		batch.Queue(nameQuery, member.MemberID, langCode, name)
+		parts := member.NameParts[langCode]
+		batch.Queue(nameQuery, member.MemberID, langCode, name, parts.Kunya, parts.Laqab, parts.Family,
+			slices.Contains(member.SuggestedNames, langCode))
 	}
+	queueCustomValues(batch, member)
 
 	br := querier.SendBatch(ctx, batch)
 	if err := br.Close(); err != nil {
@@ -199,6 +274,22 @@ func (r *MemberRepository) updateTx(ctx context.Context, member *domain.Member,
 	return nil
 }
 
+// queueCustomValues replaces the member's custom values, keys without a
+// field in the member's tree are skipped
+func queueCustomValues(batch *pgx.Batch, member *domain.Member) {
+	batch.Queue(`DELETE FROM member_custom_values WHERE member_id = $1`, member.MemberID)
+	valueQuery := `
+		INSERT INTO member_custom_values (member_id, field_id, value)
+		SELECT m.member_id, cf.field_id, $3
+		FROM members m
+		JOIN tree_custom_fields cf ON cf.tree_id = m.tree_id AND cf.field_key = $2
+		WHERE m.member_id = $1
+	`
+	for key, value := range member.CustomFields {
+		batch.Queue(valueQuery, member.MemberID, key, value)
+	}
+}
+
 // Delete performs a soft delete and cleans up all member data
 // Returns the picture URL if one exists, for cleanup from storage
 func (r *MemberRepository) Delete(ctx context.Context, memberID int) (*string, error) {
@@ -247,6 +338,86 @@ func (r *MemberRepository) deleteTx(ctx context.Context, memberID int, pictureUR
 	return nil
 }
 
+// ListDeleted returns the soft-deleted members of a tree, newest first, with the
+// names saved in their delete history since member_names are dropped on delete
+func (r *MemberRepository) ListDeleted(ctx context.Context, treeID int) ([]*domain.TrashedMember, error) {
+	query := `
+		SELECT m.member_id, m.tree_id, m.gender, m.father_id, m.mother_id, m.deleted_at,
+		       COALESCE(
+			       (SELECT h.old_values -> 'names'
+			        FROM members_history h
+			        WHERE h.member_id = m.member_id AND h.change_type = $2
+			        ORDER BY h.history_id DESC
+			        LIMIT 1),
+			       '{}'::jsonb
+		       ) AS names
+		FROM members m
+		WHERE m.tree_id = $1 AND m.deleted_at IS NOT NULL
+		ORDER BY m.deleted_at DESC, m.member_id DESC
+	`
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, treeID, domain.ChangeTypeDelete)
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	defer rows.Close()
+
+	members := []*domain.TrashedMember{}
+	for rows.Next() {
+		member := &domain.TrashedMember{}
+		if err := rows.Scan(
+			&member.MemberID, &member.TreeID, &member.Gender, &member.FatherID, &member.MotherID, &member.DeletedAt,
+			&member.Names,
+		); err != nil {
+			return nil, domain.NewDatabaseError(err)
+		}
+		members = append(members, member)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return members, nil
+}
+
+// GetDeleted returns a soft-deleted member, without names
+func (r *MemberRepository) GetDeleted(ctx context.Context, memberID int) (*domain.Member, error) {
+	query := `
+		SELECT ` + selectMemberColumns("") + `
+		FROM members
+		WHERE member_id = $1 AND deleted_at IS NOT NULL
+	`
+	member := &domain.Member{}
+	err := scanMember(getQuerier(ctx, r.db).QueryRow(ctx, query, memberID), member)
+	if errors.Is(err, pgx.ErrNoRows) {
+		slog.Warn("MemberRepository.GetDeleted: deleted member not found", "member_id", memberID)
+		return nil, domain.NewNotFoundError("deleted_member")
+	}
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return member, nil
+}
+
+// Restore clears the soft delete of a member and returns its version,
+// the caller writes back its names and fields
+func (r *MemberRepository) Restore(ctx context.Context, memberID int) (int, error) {
+	query := `
+		UPDATE members
+		SET deleted_at = NULL
+		WHERE member_id = $1 AND deleted_at IS NOT NULL
+		RETURNING version
+	`
+	var version int
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, memberID).Scan(&version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		slog.Warn("MemberRepository.Restore: deleted member not found", "member_id", memberID)
+		return 0, domain.NewNotFoundError("deleted_member")
+	}
+	if err != nil {
+		return 0, domain.NewDatabaseError(err)
+	}
+	return version, nil
+}
+
 func (r *MemberRepository) UpdatePicture(ctx context.Context, memberID int, pictureURL string) error {
 	querier := getQuerier(ctx, r.db)
 	query := `UPDATE members SET picture = $1, version = version + 1 WHERE member_id = $2 AND deleted_at IS NULL RETURNING version`
@@ -264,7 +435,118 @@ func (r *MemberRepository) UpdatePicture(ctx context.Context, memberID int, pict
 
 func (r *MemberRepository) DeletePicture(ctx context.Context, memberID int) error {
 	query := `UPDATE members SET picture = NULL, version = version + 1 WHERE member_id = $1 AND deleted_at IS NULL`
-	_, err := r.db.Exec(ctx, query, memberID)
+	_, err := getQuerier(ctx, r.db).Exec(ctx, query, memberID)
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	return nil
+}
+
+func (r *MemberRepository) GetBiographies(ctx context.Context, memberID int) (map[string]string, error) {
+	query := `SELECT language_code, biography FROM member_biographies WHERE member_id = $1`
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, memberID)
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	defer rows.Close()
+
+	biographies := make(map[string]string)
+	for rows.Next() {
+		var langCode, biography string
+		if err := rows.Scan(&langCode, &biography); err != nil {
+			return nil, domain.NewDatabaseError(err)
+		}
+		biographies[langCode] = biography
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return biographies, nil
+}
+
+// UpdateBiography stores the biography of one language, an empty text removes
+// it. The member version is bumped so the change is versioned like any other
+func (r *MemberRepository) UpdateBiography(ctx context.Context, memberID int, languageCode, biography string, expectedVersion int) (int, error) {
+	var version int
+	err := doWithQuerier(ctx, r.db, func(txCtx context.Context) error {
+		querier := getQuerier(txCtx, r.db)
+		if err := r.bumpVersion(txCtx, querier, memberID, expectedVersion, &version); err != nil {
+			return err
+		}
+
+		var err error
+		if biography == "" {
+			_, err = querier.Exec(txCtx, `DELETE FROM member_biographies WHERE member_id = $1 AND language_code = $2`, memberID, languageCode)
+		} else {
+			query := `
+				INSERT INTO member_biographies (member_id, language_code, biography)
+				VALUES ($1, $2, $3)
+				ON CONFLICT (member_id, language_code)
+				DO UPDATE SET biography = EXCLUDED.biography, updated_at = CURRENT_TIMESTAMP
+			`
+			_, err = querier.Exec(txCtx, query, memberID, languageCode, biography)
+		}
+		if err != nil {
+			return domain.NewDatabaseError(err)
+		}
+		return nil
+	})
+	return version, err
+}
+
+func (r *MemberRepository) GetNotes(ctx context.Context, memberID int) (*string, error) {
+	var notes string
+	err := getQuerier(ctx, r.db).QueryRow(ctx, `SELECT notes FROM member_notes WHERE member_id = $1`, memberID).Scan(&notes)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return &notes, nil
+}
+
+// UpdateNotes stores the research notes, an empty text removes them
+func (r *MemberRepository) UpdateNotes(ctx context.Context, memberID int, notes string, expectedVersion int) (int, error) {
+	var version int
+	err := doWithQuerier(ctx, r.db, func(txCtx context.Context) error {
+		querier := getQuerier(txCtx, r.db)
+		if err := r.bumpVersion(txCtx, querier, memberID, expectedVersion, &version); err != nil {
+			return err
+		}
+
+		var err error
+		if notes == "" {
+			_, err = querier.Exec(txCtx, `DELETE FROM member_notes WHERE member_id = $1`, memberID)
+		} else {
+			query := `
+				INSERT INTO member_notes (member_id, notes)
+				VALUES ($1, $2)
+				ON CONFLICT (member_id)
+				DO UPDATE SET notes = EXCLUDED.notes, updated_at = CURRENT_TIMESTAMP
+			`
+			_, err = querier.Exec(txCtx, query, memberID, notes)
+		}
+		if err != nil {
+			return domain.NewDatabaseError(err)
+		}
+		return nil
+	})
+	return version, err
+}
+
+func (r *MemberRepository) bumpVersion(ctx context.Context, querier Querier, memberID, expectedVersion int, version *int) error {
+	query := `
+		UPDATE members
+		SET version = version + 1
+		WHERE member_id = $1 AND version = $2 AND deleted_at IS NULL
+		RETURNING version
+	`
+	err := querier.QueryRow(ctx, query, memberID, expectedVersion).Scan(version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		slog.Warn("MemberRepository.bumpVersion: version conflict", "member_id", memberID, "expected_version", expectedVersion)
+		return domain.NewVersionConflictError()
+	}
 	if err != nil {
 		return domain.NewDatabaseError(err)
 	}
@@ -273,8 +555,7 @@ func (r *MemberRepository) DeletePicture(ctx context.Context, memberID int) erro
 
 func (r *MemberRepository) List(ctx context.Context, filter domain.MemberFilter, cursor *string, limit int) ([]*domain.Member, *string, error) {
 	query := `
-		SELECT DISTINCT m.member_id, m.tree_id, m.gender, m.picture, m.date_of_birth,
-		       m.date_of_death, m.father_id, m.mother_id, m.nicknames, m.profession, m.version, m.deleted_at,
+		SELECT DISTINCT ` + selectMemberColumns("m") + `,
 		       CASE WHEN COUNT(ms.spouse_id) > 0 THEN true ELSE false END as is_married
 		FROM members m
 		LEFT JOIN members_spouse ms ON (m.member_id = ms.father_id OR m.member_id = ms.mother_id) AND ms.deleted_at IS NULL
@@ -302,10 +583,16 @@ func (r *MemberRepository) List(ctx context.Context, filter domain.MemberFilter,
 		      ELSE (ms.father_id IS NULL AND ms.mother_id IS NULL)
 		    END
 		  ))
-		GROUP BY m.member_id, m.tree_id, m.gender, m.picture, m.date_of_birth,
-		         m.date_of_death, m.father_id, m.mother_id, m.nicknames, m.profession, m.version, m.deleted_at
+		  AND (($8::text IS NULL) OR EXISTS (
+		    SELECT 1 FROM member_custom_values cv
+		    JOIN tree_custom_fields cf ON cf.field_id = cv.field_id
+		    WHERE cv.member_id = m.member_id
+		      AND (($9::text IS NULL) OR cf.field_key = $9)
+		      AND cv.value ILIKE '%' || $8 || '%'
+		  ))
+		GROUP BY m.member_id
 		ORDER BY m.member_id
-		LIMIT $8
+		LIMIT $10
 	`
 
 	var cursorValue *string
@@ -313,7 +600,7 @@ func (r *MemberRepository) List(ctx context.Context, filter domain.MemberFilter,
 		cursorValue = cursor
 	}
 
-	rows, err := r.db.Query(ctx, query,
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query,
 		filter.TreeID,
 		cursorValue,
 		filter.Name,
@@ -321,6 +608,8 @@ func (r *MemberRepository) List(ctx context.Context, filter domain.MemberFilter,
 		filter.EnglishName,
 		filter.Gender,
 		filter.IsMarried,
+		filter.CustomValue,
+		filter.CustomField,
 		limit,
 	)
 	if err != nil {
@@ -332,12 +621,7 @@ func (r *MemberRepository) List(ctx context.Context, filter domain.MemberFilter,
 	var memberIDs []int
 	for rows.Next() {
 		member := &domain.Member{}
-		err := rows.Scan(
-			&member.MemberID, &member.TreeID, &member.Gender,
-			&member.Picture, &member.DateOfBirth, &member.DateOfDeath, &member.FatherID,
-			&member.MotherID, &member.Nicknames, &member.Profession, &member.Version, &member.DeletedAt,
-			&member.IsMarried,
-		)
+		err := scanMember(rows, member, &member.IsMarried)
 		if err != nil {
 			return nil, nil, domain.NewDatabaseError(err)
 		}
@@ -368,13 +652,12 @@ func (r *MemberRepository) List(ctx context.Context, filter domain.MemberFilter,
 
 func (r *MemberRepository) GetAll(ctx context.Context) ([]*domain.Member, error) {
 	query := `
-		SELECT member_id, gender, picture, date_of_birth, date_of_death,
-		       father_id, mother_id, nicknames, profession, version, deleted_at
+		SELECT ` + selectMemberColumns("") + `
 		FROM members
 		WHERE deleted_at IS NULL
 		ORDER BY member_id
 	`
-	rows, err := r.db.Query(ctx, query)
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query)
 	if err != nil {
 		return nil, domain.NewDatabaseError(err)
 	}
@@ -384,11 +667,7 @@ func (r *MemberRepository) GetAll(ctx context.Context) ([]*domain.Member, error)
 	var memberIDs []int
 	for rows.Next() {
 		member := &domain.Member{}
-		err := rows.Scan(
-			&member.MemberID, &member.Gender,
-			&member.Picture, &member.DateOfBirth, &member.DateOfDeath, &member.FatherID,
-			&member.MotherID, &member.Nicknames, &member.Profession, &member.Version, &member.DeletedAt,
-		)
+		err := scanMember(rows, member)
 		if err != nil {
 			return nil, domain.NewDatabaseError(err)
 		}
@@ -412,13 +691,12 @@ func (r *MemberRepository) GetAll(ctx context.Context) ([]*domain.Member, error)
 
 func (r *MemberRepository) GetAllByTreeID(ctx context.Context, treeID int) ([]*domain.Member, error) {
 	query := `
-		SELECT member_id, tree_id, gender, picture, date_of_birth, date_of_death,
-		       father_id, mother_id, nicknames, profession, version, deleted_at
+		SELECT ` + selectMemberColumns("") + `
 		FROM members
 		WHERE tree_id = $1 AND deleted_at IS NULL
 		ORDER BY member_id
 	`
-	rows, err := r.db.Query(ctx, query, treeID)
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, treeID)
 	if err != nil {
 		return nil, domain.NewDatabaseError(err)
 	}
@@ -428,11 +706,7 @@ func (r *MemberRepository) GetAllByTreeID(ctx context.Context, treeID int) ([]*d
 	var memberIDs []int
 	for rows.Next() {
 		member := &domain.Member{}
-		err := rows.Scan(
-			&member.MemberID, &member.TreeID, &member.Gender,
-			&member.Picture, &member.DateOfBirth, &member.DateOfDeath, &member.FatherID,
-			&member.MotherID, &member.Nicknames, &member.Profession, &member.Version, &member.DeletedAt,
-		)
+		err := scanMember(rows, member)
 		if err != nil {
 			return nil, domain.NewDatabaseError(err)
 		}
@@ -463,7 +737,7 @@ func (r *MemberRepository) HasChildrenWithParents(ctx context.Context, fatherID,
 		)
 	`
 	var hasChildren bool
-	err := r.db.QueryRow(ctx, query, fatherID, motherID).Scan(&hasChildren)
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, fatherID, motherID).Scan(&hasChildren)
 	if err != nil {
 		return false, domain.NewDatabaseError(err)
 	}
@@ -472,14 +746,13 @@ func (r *MemberRepository) HasChildrenWithParents(ctx context.Context, fatherID,
 
 func (r *MemberRepository) GetChildrenByParents(ctx context.Context, fatherID, motherID int) ([]*domain.Member, error) {
 	query := `
-		SELECT member_id, gender, picture, date_of_birth, date_of_death,
-		       father_id, mother_id, nicknames, profession, version, deleted_at
+		SELECT ` + selectMemberColumns("") + `
 		FROM members
 		WHERE deleted_at IS NULL
 		  AND father_id = $1 AND mother_id = $2
 		ORDER BY date_of_birth ASC NULLS LAST, member_id ASC
 	`
-	rows, err := r.db.Query(ctx, query, fatherID, motherID)
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, fatherID, motherID)
 	if err != nil {
 		return nil, domain.NewDatabaseError(err)
 	}
@@ -489,12 +762,7 @@ func (r *MemberRepository) GetChildrenByParents(ctx context.Context, fatherID, m
 	var memberIDs []int
 	for rows.Next() {
 		member := &domain.Member{}
-		err := rows.Scan(
-			&member.MemberID, &member.Gender,
-			&member.Picture, &member.DateOfBirth, &member.DateOfDeath,
-			&member.FatherID, &member.MotherID, &member.Nicknames, &member.Profession,
-			&member.Version, &member.DeletedAt,
-		)
+		err := scanMember(rows, member)
 		if err != nil {
 			return nil, domain.NewDatabaseError(err)
 		}
@@ -519,14 +787,13 @@ func (r *MemberRepository) GetChildrenByParents(ctx context.Context, fatherID, m
 
 func (r *MemberRepository) GetChildrenByParentID(ctx context.Context, parentID int) ([]*domain.Member, error) {
 	query := `
-		SELECT member_id, gender, picture, date_of_birth, date_of_death,
-		       father_id, mother_id, nicknames, profession, version, deleted_at
+		SELECT ` + selectMemberColumns("") + `
 		FROM members
 		WHERE deleted_at IS NULL
 		  AND (father_id = $1 OR mother_id = $1)
 		ORDER BY date_of_birth ASC NULLS LAST, member_id ASC
 	`
-	rows, err := r.db.Query(ctx, query, parentID)
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, parentID)
 	if err != nil {
 		return nil, domain.NewDatabaseError(err)
 	}
@@ -536,12 +803,7 @@ func (r *MemberRepository) GetChildrenByParentID(ctx context.Context, parentID i
 	var memberIDs []int
 	for rows.Next() {
 		member := &domain.Member{}
-		err := rows.Scan(
-			&member.MemberID, &member.Gender,
-			&member.Picture, &member.DateOfBirth, &member.DateOfDeath,
-			&member.FatherID, &member.MotherID, &member.Nicknames, &member.Profession,
-			&member.Version, &member.DeletedAt,
-		)
+		err := scanMember(rows, member)
 		if err != nil {
 			return nil, domain.NewDatabaseError(err)
 		}
@@ -566,9 +828,7 @@ func (r *MemberRepository) GetChildrenByParentID(ctx context.Context, parentID i
 
 func (r *MemberRepository) GetSiblingsByMemberID(ctx context.Context, memberID int) ([]*domain.Member, error) {
 	query := `
-		SELECT DISTINCT m.member_id, m.gender, m.picture,
-		       m.date_of_birth, m.date_of_death, m.father_id, m.mother_id, m.nicknames,
-		       m.profession, m.version, m.deleted_at
+		SELECT DISTINCT ` + selectMemberColumns("m") + `
 		FROM members m
 		WHERE m.deleted_at IS NULL
 		  AND m.member_id != $1
@@ -583,7 +843,7 @@ func (r *MemberRepository) GetSiblingsByMemberID(ctx context.Context, memberID i
 		  )
 		ORDER BY m.date_of_birth ASC NULLS LAST, m.member_id ASC
 	`
-	rows, err := r.db.Query(ctx, query, memberID)
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, memberID)
 	if err != nil {
 		return nil, domain.NewDatabaseError(err)
 	}
@@ -593,12 +853,7 @@ func (r *MemberRepository) GetSiblingsByMemberID(ctx context.Context, memberID i
 	var memberIDs []int
 	for rows.Next() {
 		member := &domain.Member{}
-		err := rows.Scan(
-			&member.MemberID, &member.Gender,
-			&member.Picture, &member.DateOfBirth, &member.DateOfDeath,
-			&member.FatherID, &member.MotherID, &member.Nicknames, &member.Profession,
-			&member.Version, &member.DeletedAt,
-		)
+		err := scanMember(rows, member)
 		if err != nil {
 			return nil, domain.NewDatabaseError(err)
 		}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type NameSpellingRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewNameSpellingRepository(db *pgxpool.Pool) *NameSpellingRepository {
+	return &NameSpellingRepository{db: db}
+}
+
+func (r *NameSpellingRepository) ListByTreeID(ctx context.Context, treeID int) ([]*domain.NameSpelling, error) {
+	query := `
+		SELECT tree_id, arabic, language_code, spelling, created_at, updated_at
+		FROM tree_name_spellings
+		WHERE tree_id = $1
+		ORDER BY arabic, language_code
+	`
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, treeID)
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	defer rows.Close()
+
+	var spellings []*domain.NameSpelling
+	for rows.Next() {
+		spelling := &domain.NameSpelling{}
+		err := rows.Scan(&spelling.TreeID, &spelling.Arabic, &spelling.LanguageCode, &spelling.Spelling,
+			&spelling.CreatedAt, &spelling.UpdatedAt)
+		if err != nil {
+			return nil, domain.NewDatabaseError(err)
+		}
+		spellings = append(spellings, spelling)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return spellings, nil
+}
+
+func (r *NameSpellingRepository) Upsert(ctx context.Context, spelling *domain.NameSpelling) error {
+	query := `
+		INSERT INTO tree_name_spellings (tree_id, arabic, language_code, spelling)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (tree_id, arabic, language_code)
+		DO UPDATE SET spelling = EXCLUDED.spelling, updated_at = CURRENT_TIMESTAMP
+		RETURNING created_at, updated_at
+	`
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, spelling.TreeID, spelling.Arabic, spelling.LanguageCode, spelling.Spelling).
+		Scan(&spelling.CreatedAt, &spelling.UpdatedAt)
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	return nil
+}
+
+func (r *NameSpellingRepository) Delete(ctx context.Context, treeID int, arabic, languageCode string) error {
+	query := `DELETE FROM tree_name_spellings WHERE tree_id = $1 AND arabic = $2 AND language_code = $3`
+	result, err := getQuerier(ctx, r.db).Exec(ctx, query, treeID, arabic, languageCode)
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.NewNotFoundError("name_spelling")
+	}
+	return nil
+}
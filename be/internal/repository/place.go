@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PlaceRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPlaceRepository(db *pgxpool.Pool) *PlaceRepository {
+	return &PlaceRepository{db: db}
+}
+
+const selectPlaceColumns = `
+		SELECT p.place_id, p.tree_id, p.latitude, p.longitude, p.created_at, p.updated_at,
+		       COALESCE(
+			       (SELECT jsonb_object_agg(pn.language_code, pn.name)
+			        FROM place_names pn
+			        WHERE pn.place_id = p.place_id),
+			       '{}'::jsonb
+		       ) AS names
+		FROM places p
+`
+
+func scanPlace(row pgx.Row, place *domain.Place) error {
+	return row.Scan(
+		&place.PlaceID, &place.TreeID, &place.Latitude, &place.Longitude, &place.CreatedAt, &place.UpdatedAt,
+		&place.Names,
+	)
+}
+
+func (r *PlaceRepository) Create(ctx context.Context, place *domain.Place) error {
+	return doWithQuerier(ctx, r.db, func(txCtx context.Context) error {
+		querier := getQuerier(txCtx, r.db)
+
+		query := `
+			INSERT INTO places (tree_id, latitude, longitude)
+			VALUES ($1, $2, $3)
+			RETURNING place_id, created_at, updated_at
+		`
+		err := querier.QueryRow(txCtx, query, place.TreeID, place.Latitude, place.Longitude).
+			Scan(&place.PlaceID, &place.CreatedAt, &place.UpdatedAt)
+		if err != nil {
+			return domain.NewDatabaseError(err)
+		}
+
+		return r.replaceNames(txCtx, querier, place)
+	})
+}
+
+func (r *PlaceRepository) Get(ctx context.Context, placeID int) (*domain.Place, error) {
+	query := selectPlaceColumns + `
+		WHERE p.place_id = $1
+	`
+	place := &domain.Place{}
+	err := scanPlace(r.db.QueryRow(ctx, query, placeID), place)
+	if errors.Is(err, pgx.ErrNoRows) {
+		slog.Warn("PlaceRepository.Get: place not found", "place_id", placeID)
+		return nil, domain.NewNotFoundError("place")
+	}
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return place, nil
+}
+
+func (r *PlaceRepository) ListByTreeID(ctx context.Context, treeID int) ([]*domain.Place, error) {
+	query := selectPlaceColumns + `
+		WHERE p.tree_id = $1
+		ORDER BY p.place_id
+	`
+	rows, err := r.db.Query(ctx, query, treeID)
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	defer rows.Close()
+
+	var places []*domain.Place
+	for rows.Next() {
+		place := &domain.Place{}
+		if err := scanPlace(rows, place); err != nil {
+			return nil, domain.NewDatabaseError(err)
+		}
+		places = append(places, place)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return places, nil
+}
+
+func (r *PlaceRepository) Update(ctx context.Context, place *domain.Place) error {
+	return doWithQuerier(ctx, r.db, func(txCtx context.Context) error {
+		querier := getQuerier(txCtx, r.db)
+
+		query := `
+			UPDATE places
+			SET latitude = $1, longitude = $2, updated_at = CURRENT_TIMESTAMP
+			WHERE place_id = $3
+			RETURNING updated_at
+		`
+		err := querier.QueryRow(txCtx, query, place.Latitude, place.Longitude, place.PlaceID).Scan(&place.UpdatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.NewNotFoundError("place")
+		}
+		if err != nil {
+			return domain.NewDatabaseError(err)
+		}
+
+		return r.replaceNames(txCtx, querier, place)
+	})
+}
+
+func (r *PlaceRepository) Delete(ctx context.Context, placeID int) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM places WHERE place_id = $1`, placeID)
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.NewNotFoundError("place")
+	}
+	return nil
+}
+
+func (r *PlaceRepository) replaceNames(ctx context.Context, querier Querier, place *domain.Place) error {
+	batch := &pgx.Batch{}
+	batch.Queue(`DELETE FROM place_names WHERE place_id = $1`, place.PlaceID)
+	nameQuery := `
+		INSERT INTO place_names (place_id, language_code, name, created_at, updated_at)
+		VALUES ($1, $2, $3, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+	`
+	for langCode, name := range place.Names {
+		batch.Queue(nameQuery, place.PlaceID, langCode, name)
+	}
+
+	br := querier.SendBatch(ctx, batch)
+	if err := br.Close(); err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	return nil
+}
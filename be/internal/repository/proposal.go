@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ProposalRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewProposalRepository(db *pgxpool.Pool) *ProposalRepository {
+	return &ProposalRepository{db: db}
+}
+
+const proposalColumns = `
+	p.proposal_id, p.tree_id, p.proposer_user_id, u.full_name, p.kind, p.member_id, p.spouse_id,
+	p.payload, p.base_version, p.base_values, p.comment, p.status, p.reviewer_user_id,
+	p.review_comment, p.created_at, p.updated_at, p.reviewed_at
+`
+
+func (r *ProposalRepository) Create(ctx context.Context, proposal *domain.Proposal) error {
+	payload, baseValues := proposalPayload(proposal)
+	query := `
+		INSERT INTO member_proposals (tree_id, proposer_user_id, kind, member_id, spouse_id, payload, base_version, base_values, comment, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING proposal_id, created_at, updated_at
+	`
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query,
+		proposal.TreeID, proposal.ProposerUserID, proposal.Kind, proposal.MemberID, proposal.SpouseID,
+		payload, proposal.BaseVersion, baseValues, proposal.Comment, proposal.Status,
+	).Scan(&proposal.ProposalID, &proposal.CreatedAt, &proposal.UpdatedAt)
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	return nil
+}
+
+func (r *ProposalRepository) Get(ctx context.Context, proposalID int) (*domain.Proposal, error) {
+	query := `
+		SELECT ` + proposalColumns + `
+		FROM member_proposals p
+		JOIN users u ON u.user_id = p.proposer_user_id
+		WHERE p.proposal_id = $1
+	`
+	proposal, err := scanProposal(getQuerier(ctx, r.db).QueryRow(ctx, query, proposalID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NewNotFoundError("proposal")
+		}
+		return nil, domain.NewDatabaseError(err)
+	}
+	return proposal, nil
+}
+
+// List returns the proposals of a tree oldest first, the way they are reviewed
+func (r *ProposalRepository) List(ctx context.Context, filter domain.ProposalFilter, cursor *string, limit int) ([]*domain.Proposal, *string, error) {
+	query := `
+		SELECT ` + proposalColumns + `
+		FROM member_proposals p
+		JOIN users u ON u.user_id = p.proposer_user_id
+		WHERE p.tree_id = $1
+		  AND (($2::text IS NULL) OR p.proposal_id > $2::int)
+		  AND (($3::text IS NULL) OR p.status = $3)
+		  AND (($4::int IS NULL) OR p.proposer_user_id = $4)
+		ORDER BY p.proposal_id
+		LIMIT $5
+	`
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, filter.TreeID, cursor, filter.Status, filter.ProposerUserID, limit)
+	if err != nil {
+		return nil, nil, domain.NewDatabaseError(err)
+	}
+	defer rows.Close()
+
+	var proposals []*domain.Proposal
+	for rows.Next() {
+		proposal, err := scanProposal(rows)
+		if err != nil {
+			return nil, nil, domain.NewDatabaseError(err)
+		}
+		proposals = append(proposals, proposal)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, domain.NewDatabaseError(err)
+	}
+
+	var nextCursor *string
+	if len(proposals) == limit {
+		lastID := strconv.Itoa(proposals[len(proposals)-1].ProposalID)
+		nextCursor = &lastID
+	}
+	return proposals, nextCursor, nil
+}
+
+// Revise replaces what an open proposal suggests and sends it back to review
+func (r *ProposalRepository) Revise(ctx context.Context, proposal *domain.Proposal) error {
+	payload, baseValues := proposalPayload(proposal)
+	query := `
+		UPDATE member_proposals
+		SET payload = $2, base_version = $3, base_values = $4, comment = $5, status = $6,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE proposal_id = $1 AND status IN ('pending', 'changes_requested')
+		RETURNING updated_at
+	`
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query,
+		proposal.ProposalID, payload, proposal.BaseVersion, baseValues, proposal.Comment, proposal.Status,
+	).Scan(&proposal.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.NewConflictError("error.proposal.closed", nil)
+		}
+		return domain.NewDatabaseError(err)
+	}
+	return nil
+}
+
+// Review records an editor's decision on a proposal that is still in the
+// status it was read in
+// Lock claims a proposal still in the status until the transaction ends, a
+// proposal reviewed in the meantime is closed
+func (r *ProposalRepository) Lock(ctx context.Context, proposalID int, status string) error {
+	query := `SELECT proposal_id FROM member_proposals WHERE proposal_id = $1 AND status = $2 FOR UPDATE`
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, proposalID, status).Scan(&proposalID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.NewConflictError("error.proposal.closed", nil)
+	}
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	return nil
+}
+
+func (r *ProposalRepository) Review(ctx context.Context, proposal *domain.Proposal, fromStatus string) error {
+	query := `
+		UPDATE member_proposals
+		SET status = $3, member_id = $4, spouse_id = $5, reviewer_user_id = $6, review_comment = $7,
+		    reviewed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE proposal_id = $1 AND status = $2
+		RETURNING reviewed_at, updated_at
+	`
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query,
+		proposal.ProposalID, fromStatus, proposal.Status, proposal.MemberID, proposal.SpouseID,
+		proposal.ReviewerUserID, proposal.ReviewComment,
+	).Scan(&proposal.ReviewedAt, &proposal.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.NewConflictError("error.proposal.closed", nil)
+		}
+		return domain.NewDatabaseError(err)
+	}
+	return nil
+}
+
+// proposalPayload returns the proposed values and the values the proposer
+// edited as stored
+func proposalPayload(proposal *domain.Proposal) ([]byte, []byte) {
+	var payload, baseValues []byte
+	switch {
+	case proposal.Member != nil:
+		payload, _ = json.Marshal(proposal.Member)
+	case proposal.Spouse != nil:
+		payload, _ = json.Marshal(proposal.Spouse)
+	}
+	if proposal.BaseSpouse != nil {
+		baseValues, _ = json.Marshal(proposal.BaseSpouse)
+	}
+	return payload, baseValues
+}
+
+func scanProposal(row pgx.Row) (*domain.Proposal, error) {
+	proposal := &domain.Proposal{}
+	var payload, baseValues []byte
+	err := row.Scan(
+		&proposal.ProposalID, &proposal.TreeID, &proposal.ProposerUserID, &proposal.ProposerName,
+		&proposal.Kind, &proposal.MemberID, &proposal.SpouseID, &payload, &proposal.BaseVersion,
+		&baseValues, &proposal.Comment, &proposal.Status, &proposal.ReviewerUserID,
+		&proposal.ReviewComment, &proposal.CreatedAt, &proposal.UpdatedAt, &proposal.ReviewedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(payload) > 0 {
+		switch proposal.Kind {
+		case domain.ProposalKindCreateMember, domain.ProposalKindUpdateMember:
+			proposal.Member = &domain.Member{}
+			if err := json.Unmarshal(payload, proposal.Member); err != nil {
+				return nil, err
+			}
+		case domain.ProposalKindAddSpouse, domain.ProposalKindUpdateSpouse:
+			proposal.Spouse = &domain.Spouse{}
+			if err := json.Unmarshal(payload, proposal.Spouse); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(baseValues) > 0 {
+		proposal.BaseSpouse = &domain.Spouse{}
+		if err := json.Unmarshal(baseValues, proposal.BaseSpouse); err != nil {
+			return nil, err
+		}
+	}
+	return proposal, nil
+}
@@ -17,6 +17,7 @@ func NewScoreRepository(db *pgxpool.Pool) *ScoreRepository {
 	return &ScoreRepository{db: db}
 }
 
+// Create records the scores in the changeset of ctx
 func (r *ScoreRepository) Create(ctx context.Context, scores ...domain.Score) error {
 	if len(scores) == 0 {
 		return nil
@@ -25,9 +26,10 @@ func (r *ScoreRepository) Create(ctx context.Context, scores ...domain.Score) er
 	querier := getQuerier(ctx, r.db)
 	batch := &pgx.Batch{}
 	query := `
-		INSERT INTO user_scores (user_id, member_id, field_name, points, member_version)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO user_scores (user_id, member_id, field_name, points, member_version, changeset_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
 	`
+	changesetID := domain.ChangesetFromContext(ctx)
 	for i := range scores {
 		batch.Queue(query,
 			scores[i].UserID,
@@ -35,6 +37,7 @@ func (r *ScoreRepository) Create(ctx context.Context, scores ...domain.Score) er
 			scores[i].FieldName,
 			scores[i].Points,
 			scores[i].MemberVersion,
+			changesetID,
 		)
 	}
 
@@ -173,7 +176,17 @@ func (r *ScoreRepository) GetTotalByUserID(ctx context.Context, userID int) (int
 // DeleteByMemberAndField removes scores for a specific member field (used when updating)
 func (r *ScoreRepository) DeleteByMemberAndField(ctx context.Context, memberID int, fieldName string, memberVersion int) error {
 	query := `DELETE FROM user_scores WHERE member_id = $1 AND field_name = $2 AND member_version = $3`
-	_, err := r.db.Exec(ctx, query, memberID, fieldName, memberVersion)
+	_, err := getQuerier(ctx, r.db).Exec(ctx, query, memberID, fieldName, memberVersion)
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	return nil
+}
+
+// DeleteByChangeset takes back the points awarded in a changeset
+func (r *ScoreRepository) DeleteByChangeset(ctx context.Context, changesetID string) error {
+	query := `DELETE FROM user_scores WHERE changeset_id = $1`
+	_, err := getQuerier(ctx, r.db).Exec(ctx, query, changesetID)
 	if err != nil {
 		return domain.NewDatabaseError(err)
 	}
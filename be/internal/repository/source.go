@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SourceRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSourceRepository(db *pgxpool.Pool) *SourceRepository {
+	return &SourceRepository{db: db}
+}
+
+const selectSourceColumns = `
+		SELECT source_id, tree_id, source_type, title, author, description, attachment_key,
+		       created_by, created_at, updated_at
+		FROM sources
+`
+
+func scanSource(row pgx.Row, source *domain.Source) error {
+	return row.Scan(
+		&source.SourceID, &source.TreeID, &source.SourceType, &source.Title, &source.Author, &source.Description, &source.AttachmentKey,
+		&source.CreatedBy, &source.CreatedAt, &source.UpdatedAt,
+	)
+}
+
+func (r *SourceRepository) Create(ctx context.Context, source *domain.Source) error {
+	query := `
+		INSERT INTO sources (tree_id, source_type, title, author, description, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING source_id, created_at, updated_at
+	`
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query,
+		source.TreeID, source.SourceType, source.Title, source.Author, source.Description, source.CreatedBy,
+	).Scan(&source.SourceID, &source.CreatedAt, &source.UpdatedAt)
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	return nil
+}
+
+func (r *SourceRepository) Get(ctx context.Context, sourceID int) (*domain.Source, error) {
+	query := selectSourceColumns + `
+		WHERE source_id = $1
+	`
+	source := &domain.Source{}
+	err := scanSource(getQuerier(ctx, r.db).QueryRow(ctx, query, sourceID), source)
+	if errors.Is(err, pgx.ErrNoRows) {
+		slog.Warn("SourceRepository.Get: source not found", "source_id", sourceID)
+		return nil, domain.NewNotFoundError("source")
+	}
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return source, nil
+}
+
+func (r *SourceRepository) ListByTreeID(ctx context.Context, treeID int) ([]*domain.Source, error) {
+	query := selectSourceColumns + `
+		WHERE tree_id = $1
+		ORDER BY title, source_id
+	`
+	rows, err := r.db.Query(ctx, query, treeID)
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	defer rows.Close()
+
+	var sources []*domain.Source
+	for rows.Next() {
+		source := &domain.Source{}
+		if err := scanSource(rows, source); err != nil {
+			return nil, domain.NewDatabaseError(err)
+		}
+		sources = append(sources, source)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return sources, nil
+}
+
+func (r *SourceRepository) Update(ctx context.Context, source *domain.Source) error {
+	query := `
+		UPDATE sources
+		SET source_type = $1, title = $2, author = $3, description = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE source_id = $5
+		RETURNING updated_at
+	`
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query,
+		source.SourceType, source.Title, source.Author, source.Description, source.SourceID,
+	).Scan(&source.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.NewNotFoundError("source")
+	}
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	return nil
+}
+
+func (r *SourceRepository) UpdateAttachment(ctx context.Context, sourceID int, key *string) error {
+	query := `UPDATE sources SET attachment_key = $1, updated_at = CURRENT_TIMESTAMP WHERE source_id = $2`
+	result, err := getQuerier(ctx, r.db).Exec(ctx, query, key, sourceID)
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.NewNotFoundError("source")
+	}
+	return nil
+}
+
+func (r *SourceRepository) Delete(ctx context.Context, sourceID int) error {
+	result, err := getQuerier(ctx, r.db).Exec(ctx, `DELETE FROM sources WHERE source_id = $1`, sourceID)
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.NewNotFoundError("source")
+	}
+	return nil
+}
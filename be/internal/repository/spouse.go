@@ -21,16 +21,28 @@ func NewSpouseRepository(db *pgxpool.Pool) *SpouseRepository {
 func (r *SpouseRepository) Create(ctx context.Context, spouse *domain.Spouse) error {
 	querier := getQuerier(ctx, r.db)
 	query := `
-		INSERT INTO members_spouse (father_id, mother_id, marriage_date, divorce_date)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO members_spouse (father_id, mother_id,
+		                            marriage_date, marriage_date_qualifier, marriage_date_end, marriage_date_calendar,
+		                            divorce_date, divorce_date_qualifier, divorce_date_end, divorce_date_calendar, marriage_place_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		ON CONFLICT (father_id, mother_id)
 		DO UPDATE SET
 			marriage_date = EXCLUDED.marriage_date,
+			marriage_date_qualifier = EXCLUDED.marriage_date_qualifier,
+			marriage_date_end = EXCLUDED.marriage_date_end,
+			marriage_date_calendar = EXCLUDED.marriage_date_calendar,
 			divorce_date = EXCLUDED.divorce_date,
+			divorce_date_qualifier = EXCLUDED.divorce_date_qualifier,
+			divorce_date_end = EXCLUDED.divorce_date_end,
+			divorce_date_calendar = EXCLUDED.divorce_date_calendar,
+			marriage_place_id = EXCLUDED.marriage_place_id,
 			deleted_at = NULL
 		RETURNING spouse_id
 	`
-	err := querier.QueryRow(ctx, query, spouse.FatherID, spouse.MotherID, spouse.MarriageDate, spouse.DivorceDate).Scan(&spouse.SpouseID)
+	err := querier.QueryRow(ctx, query, spouse.FatherID, spouse.MotherID,
+		spouse.MarriageDate, spouse.MarriageDateQualifier, spouse.MarriageDateEnd, spouse.MarriageDateCalendar,
+		spouse.DivorceDate, spouse.DivorceDateQualifier, spouse.DivorceDateEnd, spouse.DivorceDateCalendar, spouse.MarriagePlaceID,
+	).Scan(&spouse.SpouseID)
 	if err != nil {
 		return domain.NewDatabaseError(err)
 	}
@@ -39,13 +51,17 @@ func (r *SpouseRepository) Create(ctx context.Context, spouse *domain.Spouse) er
 
 func (r *SpouseRepository) Get(ctx context.Context, spouseID int) (*domain.Spouse, error) {
 	query := `
-		SELECT spouse_id, father_id, mother_id, marriage_date, divorce_date, deleted_at
+		SELECT spouse_id, father_id, mother_id,
+		       marriage_date, marriage_date_qualifier, marriage_date_end, marriage_date_calendar,
+		       divorce_date, divorce_date_qualifier, divorce_date_end, divorce_date_calendar, marriage_place_id, deleted_at
 		FROM members_spouse
 		WHERE spouse_id = $1 AND deleted_at IS NULL
 	`
 	spouse := &domain.Spouse{}
-	err := r.db.QueryRow(ctx, query, spouseID).Scan(
-		&spouse.SpouseID, &spouse.FatherID, &spouse.MotherID, &spouse.MarriageDate, &spouse.DivorceDate, &spouse.DeletedAt,
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, spouseID).Scan(
+		&spouse.SpouseID, &spouse.FatherID, &spouse.MotherID,
+		&spouse.MarriageDate, &spouse.MarriageDateQualifier, &spouse.MarriageDateEnd, &spouse.MarriageDateCalendar,
+		&spouse.DivorceDate, &spouse.DivorceDateQualifier, &spouse.DivorceDateEnd, &spouse.DivorceDateCalendar, &spouse.MarriagePlaceID, &spouse.DeletedAt,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		slog.Warn("SpouseRepository.Get: spouse relationship not found", "spouse_id", spouseID)
@@ -59,13 +75,17 @@ func (r *SpouseRepository) Get(ctx context.Context, spouseID int) (*domain.Spous
 
 func (r *SpouseRepository) GetByParents(ctx context.Context, fatherID, motherID int) (*domain.Spouse, error) {
 	query := `
-		SELECT spouse_id, father_id, mother_id, marriage_date, divorce_date, deleted_at
+		SELECT spouse_id, father_id, mother_id,
+		       marriage_date, marriage_date_qualifier, marriage_date_end, marriage_date_calendar,
+		       divorce_date, divorce_date_qualifier, divorce_date_end, divorce_date_calendar, marriage_place_id, deleted_at
 		FROM members_spouse
 		WHERE father_id = $1 AND mother_id = $2 AND deleted_at IS NULL
 	`
 	spouse := &domain.Spouse{}
-	err := r.db.QueryRow(ctx, query, fatherID, motherID).Scan(
-		&spouse.SpouseID, &spouse.FatherID, &spouse.MotherID, &spouse.MarriageDate, &spouse.DivorceDate, &spouse.DeletedAt,
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, fatherID, motherID).Scan(
+		&spouse.SpouseID, &spouse.FatherID, &spouse.MotherID,
+		&spouse.MarriageDate, &spouse.MarriageDateQualifier, &spouse.MarriageDateEnd, &spouse.MarriageDateCalendar,
+		&spouse.DivorceDate, &spouse.DivorceDateQualifier, &spouse.DivorceDateEnd, &spouse.DivorceDateCalendar, &spouse.MarriagePlaceID, &spouse.DeletedAt,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		slog.Warn("SpouseRepository.Get: spouse relationship not found", "father_id", fatherID, "mother_id", motherID)
@@ -80,10 +100,16 @@ func (r *SpouseRepository) GetByParents(ctx context.Context, fatherID, motherID
 func (r *SpouseRepository) Update(ctx context.Context, spouse *domain.Spouse) error {
 	query := `
 		UPDATE members_spouse
-		SET marriage_date = $1, divorce_date = $2
-		WHERE spouse_id = $3 AND deleted_at IS NULL
+		SET marriage_date = $1, marriage_date_qualifier = $2, marriage_date_end = $3, marriage_date_calendar = $4,
+		    divorce_date = $5, divorce_date_qualifier = $6, divorce_date_end = $7, divorce_date_calendar = $8,
+		    marriage_place_id = $9
+		WHERE spouse_id = $10 AND deleted_at IS NULL
 	`
-	result, err := r.db.Exec(ctx, query, spouse.MarriageDate, spouse.DivorceDate, spouse.SpouseID)
+	result, err := getQuerier(ctx, r.db).Exec(ctx, query,
+		spouse.MarriageDate, spouse.MarriageDateQualifier, spouse.MarriageDateEnd, spouse.MarriageDateCalendar,
+		spouse.DivorceDate, spouse.DivorceDateQualifier, spouse.DivorceDateEnd, spouse.DivorceDateCalendar, spouse.MarriagePlaceID,
+		spouse.SpouseID,
+	)
 	if err != nil {
 		return domain.NewDatabaseError(err)
 	}
@@ -100,7 +126,7 @@ func (r *SpouseRepository) Delete(ctx context.Context, spouseID int) error {
 		SET deleted_at = NOW()
 		WHERE spouse_id = $1 AND deleted_at IS NULL
 	`
-	result, err := r.db.Exec(ctx, query, spouseID)
+	result, err := getQuerier(ctx, r.db).Exec(ctx, query, spouseID)
 	if err != nil {
 		return domain.NewDatabaseError(err)
 	}
@@ -111,9 +137,102 @@ func (r *SpouseRepository) Delete(ctx context.Context, spouseID int) error {
 	return nil
 }
 
+// GetDeleted returns a soft-deleted spouse relationship
+func (r *SpouseRepository) GetDeleted(ctx context.Context, spouseID int) (*domain.Spouse, error) {
+	query := `
+		SELECT spouse_id, father_id, mother_id,
+		       marriage_date, marriage_date_qualifier, marriage_date_end, marriage_date_calendar,
+		       divorce_date, divorce_date_qualifier, divorce_date_end, divorce_date_calendar, marriage_place_id, deleted_at
+		FROM members_spouse
+		WHERE spouse_id = $1 AND deleted_at IS NOT NULL
+	`
+	spouse := &domain.Spouse{}
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, spouseID).Scan(
+		&spouse.SpouseID, &spouse.FatherID, &spouse.MotherID,
+		&spouse.MarriageDate, &spouse.MarriageDateQualifier, &spouse.MarriageDateEnd, &spouse.MarriageDateCalendar,
+		&spouse.DivorceDate, &spouse.DivorceDateQualifier, &spouse.DivorceDateEnd, &spouse.DivorceDateCalendar, &spouse.MarriagePlaceID, &spouse.DeletedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		slog.Warn("SpouseRepository.GetDeleted: deleted spouse relationship not found", "spouse_id", spouseID)
+		return nil, domain.NewNotFoundError("deleted_spouse")
+	}
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return spouse, nil
+}
+
+// ListDeletedByTreeID returns the soft-deleted spouse relationships of a tree,
+// including those removed along with a deleted partner, newest first
+func (r *SpouseRepository) ListDeletedByTreeID(ctx context.Context, treeID int) ([]*domain.Spouse, error) {
+	return r.listByTreeID(ctx, treeID, "ms.deleted_at IS NOT NULL", "ms.deleted_at DESC, ms.spouse_id DESC")
+}
+
+// ListAllByTreeID returns every spouse relationship of a tree, deleted ones included
+func (r *SpouseRepository) ListAllByTreeID(ctx context.Context, treeID int) ([]*domain.Spouse, error) {
+	return r.listByTreeID(ctx, treeID, "TRUE", "ms.spouse_id ASC")
+}
+
+func (r *SpouseRepository) listByTreeID(ctx context.Context, treeID int, condition, orderBy string) ([]*domain.Spouse, error) {
+	query := `
+		SELECT ms.spouse_id, ms.father_id, ms.mother_id,
+		       ms.marriage_date, ms.marriage_date_qualifier, ms.marriage_date_end, ms.marriage_date_calendar,
+		       ms.divorce_date, ms.divorce_date_qualifier, ms.divorce_date_end, ms.divorce_date_calendar, ms.marriage_place_id, ms.deleted_at
+		FROM members_spouse ms
+		JOIN members m1 ON m1.member_id = ms.father_id
+		JOIN members m2 ON m2.member_id = ms.mother_id
+		WHERE ` + condition + `
+		  AND m1.tree_id = $1
+		  AND m2.tree_id = $1
+		ORDER BY ` + orderBy + `
+	`
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, treeID)
+	if err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	defer rows.Close()
+
+	spouses := []*domain.Spouse{}
+	for rows.Next() {
+		spouse := &domain.Spouse{}
+		if err := rows.Scan(
+			&spouse.SpouseID, &spouse.FatherID, &spouse.MotherID,
+			&spouse.MarriageDate, &spouse.MarriageDateQualifier, &spouse.MarriageDateEnd, &spouse.MarriageDateCalendar,
+			&spouse.DivorceDate, &spouse.DivorceDateQualifier, &spouse.DivorceDateEnd, &spouse.DivorceDateCalendar, &spouse.MarriagePlaceID, &spouse.DeletedAt,
+		); err != nil {
+			return nil, domain.NewDatabaseError(err)
+		}
+		spouses = append(spouses, spouse)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.NewDatabaseError(err)
+	}
+	return spouses, nil
+}
+
+// Restore clears the soft delete of a spouse relationship
+func (r *SpouseRepository) Restore(ctx context.Context, spouseID int) error {
+	query := `
+		UPDATE members_spouse
+		SET deleted_at = NULL
+		WHERE spouse_id = $1 AND deleted_at IS NOT NULL
+	`
+	result, err := getQuerier(ctx, r.db).Exec(ctx, query, spouseID)
+	if err != nil {
+		return domain.NewDatabaseError(err)
+	}
+	if result.RowsAffected() == 0 {
+		slog.Warn("SpouseRepository.Restore: deleted spouse relationship not found", "spouse_id", spouseID)
+		return domain.NewNotFoundError("deleted_spouse")
+	}
+	return nil
+}
+
 func (r *SpouseRepository) GetAllSpouses(ctx context.Context) (map[int][]domain.SpouseWithMemberInfo, error) {
 	query := `
-		SELECT ms.spouse_id, ms.father_id, ms.mother_id, ms.marriage_date, ms.divorce_date
+		SELECT ms.spouse_id, ms.father_id, ms.mother_id,
+		       ms.marriage_date, ms.marriage_date_qualifier, ms.marriage_date_end, ms.marriage_date_calendar,
+		       ms.divorce_date, ms.divorce_date_qualifier, ms.divorce_date_end, ms.divorce_date_calendar, ms.marriage_place_id
 		FROM members_spouse ms
 		JOIN members m1 ON m1.member_id = ms.father_id
 		JOIN members m2 ON m2.member_id = ms.mother_id
@@ -122,7 +241,7 @@ func (r *SpouseRepository) GetAllSpouses(ctx context.Context) (map[int][]domain.
 		  AND m2.deleted_at IS NULL
 		ORDER BY ms.marriage_date ASC NULLS LAST, ms.spouse_id ASC
 	`
-	rows, err := r.db.Query(ctx, query)
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query)
 	if err != nil {
 		return nil, domain.NewDatabaseError(err)
 	}
@@ -131,20 +250,38 @@ func (r *SpouseRepository) GetAllSpouses(ctx context.Context) (map[int][]domain.
 	spouseMap := make(map[int][]domain.SpouseWithMemberInfo)
 	for rows.Next() {
 		var spouse domain.Spouse
-		if err := rows.Scan(&spouse.SpouseID, &spouse.FatherID, &spouse.MotherID, &spouse.MarriageDate, &spouse.DivorceDate); err != nil {
+		if err := rows.Scan(
+			&spouse.SpouseID, &spouse.FatherID, &spouse.MotherID,
+			&spouse.MarriageDate, &spouse.MarriageDateQualifier, &spouse.MarriageDateEnd, &spouse.MarriageDateCalendar,
+			&spouse.DivorceDate, &spouse.DivorceDateQualifier, &spouse.DivorceDateEnd, &spouse.DivorceDateCalendar, &spouse.MarriagePlaceID,
+		); err != nil {
 			return nil, domain.NewDatabaseError(err)
 		}
 		spouseMap[spouse.FatherID] = append(spouseMap[spouse.FatherID], domain.SpouseWithMemberInfo{
-			SpouseID:     spouse.SpouseID,
-			MemberID:     spouse.MotherID,
-			MarriageDate: spouse.MarriageDate,
-			DivorceDate:  spouse.DivorceDate,
+			SpouseID:              spouse.SpouseID,
+			MemberID:              spouse.MotherID,
+			MarriageDate:          spouse.MarriageDate,
+			MarriageDateQualifier: spouse.MarriageDateQualifier,
+			MarriageDateEnd:       spouse.MarriageDateEnd,
+			MarriageDateCalendar:  spouse.MarriageDateCalendar,
+			DivorceDate:           spouse.DivorceDate,
+			DivorceDateQualifier:  spouse.DivorceDateQualifier,
+			DivorceDateEnd:        spouse.DivorceDateEnd,
+			DivorceDateCalendar:   spouse.DivorceDateCalendar,
+			MarriagePlaceID:       spouse.MarriagePlaceID,
 		})
 		spouseMap[spouse.MotherID] = append(spouseMap[spouse.MotherID], domain.SpouseWithMemberInfo{
-			SpouseID:     spouse.SpouseID,
-			MemberID:     spouse.FatherID,
-			MarriageDate: spouse.MarriageDate,
-			DivorceDate:  spouse.DivorceDate,
+			SpouseID:              spouse.SpouseID,
+			MemberID:              spouse.FatherID,
+			MarriageDate:          spouse.MarriageDate,
+			MarriageDateQualifier: spouse.MarriageDateQualifier,
+			MarriageDateEnd:       spouse.MarriageDateEnd,
+			MarriageDateCalendar:  spouse.MarriageDateCalendar,
+			DivorceDate:           spouse.DivorceDate,
+			DivorceDateQualifier:  spouse.DivorceDateQualifier,
+			DivorceDateEnd:        spouse.DivorceDateEnd,
+			DivorceDateCalendar:   spouse.DivorceDateCalendar,
+			MarriagePlaceID:       spouse.MarriagePlaceID,
 		})
 	}
 	if err := rows.Err(); err != nil {
@@ -155,7 +292,9 @@ func (r *SpouseRepository) GetAllSpouses(ctx context.Context) (map[int][]domain.
 
 func (r *SpouseRepository) GetAllSpousesByTreeID(ctx context.Context, treeID int) (map[int][]domain.SpouseWithMemberInfo, error) {
 	query := `
-		SELECT ms.spouse_id, ms.father_id, ms.mother_id, ms.marriage_date, ms.divorce_date
+		SELECT ms.spouse_id, ms.father_id, ms.mother_id,
+		       ms.marriage_date, ms.marriage_date_qualifier, ms.marriage_date_end, ms.marriage_date_calendar,
+		       ms.divorce_date, ms.divorce_date_qualifier, ms.divorce_date_end, ms.divorce_date_calendar, ms.marriage_place_id
 		FROM members_spouse ms
 		JOIN members m1 ON m1.member_id = ms.father_id
 		JOIN members m2 ON m2.member_id = ms.mother_id
@@ -166,7 +305,7 @@ func (r *SpouseRepository) GetAllSpousesByTreeID(ctx context.Context, treeID int
 		  AND m2.tree_id = $1
 		ORDER BY ms.marriage_date ASC NULLS LAST, ms.spouse_id ASC
 	`
-	rows, err := r.db.Query(ctx, query, treeID)
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, treeID)
 	if err != nil {
 		return nil, domain.NewDatabaseError(err)
 	}
@@ -175,20 +314,38 @@ func (r *SpouseRepository) GetAllSpousesByTreeID(ctx context.Context, treeID int
 	spouseMap := make(map[int][]domain.SpouseWithMemberInfo)
 	for rows.Next() {
 		var spouse domain.Spouse
-		if err := rows.Scan(&spouse.SpouseID, &spouse.FatherID, &spouse.MotherID, &spouse.MarriageDate, &spouse.DivorceDate); err != nil {
+		if err := rows.Scan(
+			&spouse.SpouseID, &spouse.FatherID, &spouse.MotherID,
+			&spouse.MarriageDate, &spouse.MarriageDateQualifier, &spouse.MarriageDateEnd, &spouse.MarriageDateCalendar,
+			&spouse.DivorceDate, &spouse.DivorceDateQualifier, &spouse.DivorceDateEnd, &spouse.DivorceDateCalendar, &spouse.MarriagePlaceID,
+		); err != nil {
 			return nil, domain.NewDatabaseError(err)
 		}
 		spouseMap[spouse.FatherID] = append(spouseMap[spouse.FatherID], domain.SpouseWithMemberInfo{
-			SpouseID:     spouse.SpouseID,
-			MemberID:     spouse.MotherID,
-			MarriageDate: spouse.MarriageDate,
-			DivorceDate:  spouse.DivorceDate,
+			SpouseID:              spouse.SpouseID,
+			MemberID:              spouse.MotherID,
+			MarriageDate:          spouse.MarriageDate,
+			MarriageDateQualifier: spouse.MarriageDateQualifier,
+			MarriageDateEnd:       spouse.MarriageDateEnd,
+			MarriageDateCalendar:  spouse.MarriageDateCalendar,
+			DivorceDate:           spouse.DivorceDate,
+			DivorceDateQualifier:  spouse.DivorceDateQualifier,
+			DivorceDateEnd:        spouse.DivorceDateEnd,
+			DivorceDateCalendar:   spouse.DivorceDateCalendar,
+			MarriagePlaceID:       spouse.MarriagePlaceID,
 		})
 		spouseMap[spouse.MotherID] = append(spouseMap[spouse.MotherID], domain.SpouseWithMemberInfo{
-			SpouseID:     spouse.SpouseID,
-			MemberID:     spouse.FatherID,
-			MarriageDate: spouse.MarriageDate,
-			DivorceDate:  spouse.DivorceDate,
+			SpouseID:              spouse.SpouseID,
+			MemberID:              spouse.FatherID,
+			MarriageDate:          spouse.MarriageDate,
+			MarriageDateQualifier: spouse.MarriageDateQualifier,
+			MarriageDateEnd:       spouse.MarriageDateEnd,
+			MarriageDateCalendar:  spouse.MarriageDateCalendar,
+			DivorceDate:           spouse.DivorceDate,
+			DivorceDateQualifier:  spouse.DivorceDateQualifier,
+			DivorceDateEnd:        spouse.DivorceDateEnd,
+			DivorceDateCalendar:   spouse.DivorceDateCalendar,
+			MarriagePlaceID:       spouse.MarriagePlaceID,
 		})
 	}
 	if err := rows.Err(); err != nil {
@@ -205,7 +362,14 @@ func (r *SpouseRepository) GetByMemberID(ctx context.Context, memberID int) ([]d
 			m.gender,
 			m.picture,
 			ms.marriage_date,
-			ms.divorce_date
+			ms.marriage_date_qualifier,
+			ms.marriage_date_end,
+			ms.marriage_date_calendar,
+			ms.divorce_date,
+			ms.divorce_date_qualifier,
+			ms.divorce_date_end,
+			ms.divorce_date_calendar,
+			ms.marriage_place_id
 		FROM members_spouse ms
 		JOIN members m ON (
 			(ms.father_id = $1 AND m.member_id = ms.mother_id) OR
@@ -214,7 +378,7 @@ func (r *SpouseRepository) GetByMemberID(ctx context.Context, memberID int) ([]d
 		WHERE ms.deleted_at IS NULL AND m.deleted_at IS NULL
 		ORDER BY ms.marriage_date ASC NULLS LAST, m.date_of_birth ASC NULLS LAST, m.member_id ASC
 	`
-	rows, err := r.db.Query(ctx, query, memberID)
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, memberID)
 	if err != nil {
 		return nil, domain.NewDatabaseError(err)
 	}
@@ -230,7 +394,14 @@ func (r *SpouseRepository) GetByMemberID(ctx context.Context, memberID int) ([]d
 			&spouse.Gender,
 			&spouse.Picture,
 			&spouse.MarriageDate,
+			&spouse.MarriageDateQualifier,
+			&spouse.MarriageDateEnd,
+			&spouse.MarriageDateCalendar,
 			&spouse.DivorceDate,
+			&spouse.DivorceDateQualifier,
+			&spouse.DivorceDateEnd,
+			&spouse.DivorceDateCalendar,
+			&spouse.MarriagePlaceID,
 		); err != nil {
 			return nil, domain.NewDatabaseError(err)
 		}
@@ -248,7 +419,7 @@ func (r *SpouseRepository) GetByMemberID(ctx context.Context, memberID int) ([]d
 			FROM member_names
 			WHERE member_id = ANY($1)
 		`
-		nameRows, err := r.db.Query(ctx, namesQuery, memberIDs)
+		nameRows, err := getQuerier(ctx, r.db).Query(ctx, namesQuery, memberIDs)
 		if err != nil {
 			return nil, domain.NewDatabaseError(err)
 		}
@@ -32,9 +32,11 @@ func (tm *TransactionManager) Do(ctx context.Context, fn func(ctx context.Contex
 	return doWithQuerier(ctx, tm.db, fn)
 }
 
+// doWithQuerier runs fn in a transaction, joining the one already in the
+// context when there is one
 func doWithQuerier(ctx context.Context, db *pgxpool.Pool, fn func(ctx context.Context) error) error {
-	querier := getQuerier(ctx, nil) // nil means not in a transaction
-	if querier != nil {
+	// getQuerier can't tell, a nil pool in the Querier interface isn't nil
+	if _, ok := ctx.Value(txKey).(pgx.Tx); ok {
 		return fn(ctx)
 	}
 
@@ -191,3 +191,43 @@ func (r *UserRepository) CreateRoleHistory(ctx context.Context, userID, oldRoleI
 	}
 	return nil
 }
+
+// ListRoleHistory returns the role grants and revokes of a user, newest first
+func (r *UserRepository) ListRoleHistory(ctx context.Context, userID int, cursor *string, limit int) ([]*domain.RoleHistory, *string, error) {
+	query := `
+		SELECT h.history_id, h.user_id, h.old_role_id, h.new_role_id, h.changed_by, u.full_name,
+		       h.changed_at, h.action_type
+		FROM user_role_history h
+		JOIN users u ON u.user_id = h.changed_by
+		WHERE h.user_id = $1
+		  AND (($2::text IS NULL) OR h.history_id < $2::int)
+		ORDER BY h.history_id DESC
+		LIMIT $3
+	`
+	rows, err := r.db.Query(ctx, query, userID, cursor, limit)
+	if err != nil {
+		return nil, nil, domain.NewDatabaseError(err)
+	}
+	defer rows.Close()
+
+	var history []*domain.RoleHistory
+	for rows.Next() {
+		h := &domain.RoleHistory{}
+		err := rows.Scan(&h.HistoryID, &h.UserID, &h.OldRoleID, &h.NewRoleID, &h.ChangedBy, &h.ChangedByName,
+			&h.ChangedAt, &h.ActionType)
+		if err != nil {
+			return nil, nil, domain.NewDatabaseError(err)
+		}
+		history = append(history, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, domain.NewDatabaseError(err)
+	}
+
+	var nextCursor *string
+	if len(history) == limit {
+		lastID := strconv.Itoa(history[len(history)-1].HistoryID)
+		nextCursor = &lastID
+	}
+	return history, nextCursor, nil
+}
@@ -86,6 +86,16 @@ func NewApp(cfg *config.Config) (*App, error) {
 	historyRepo := repository.NewHistoryRepository(pool)
 	scoreRepo := repository.NewScoreRepository(pool)
 	roleRepo := repository.NewRoleRepository(pool)
+	placeRepo := repository.NewPlaceRepository(pool)
+	eventRepo := repository.NewEventRepository(pool)
+	mediaRepo := repository.NewMediaRepository(pool)
+	sourceRepo := repository.NewSourceRepository(pool)
+	citationRepo := repository.NewCitationRepository(pool)
+	customFieldRepo := repository.NewCustomFieldRepository(pool)
+	nameSpellingRepo := repository.NewNameSpellingRepository(pool)
+	proposalRepo := repository.NewProposalRepository(pool)
+	auditRepo := repository.NewAuditRepository(pool)
+	changesetRepo := repository.NewChangesetRepository(pool)
 	_ = roleRepo // May be used later
 
 	txManager := repository.NewTransactionManager(pool)
@@ -101,27 +111,54 @@ func NewApp(cfg *config.Config) (*App, error) {
 	cookieManager := cookie.NewManager(&cfg.Server.Cookie)
 
 	// Create validators
-	marriageValidator := validator.NewMarriageValidator(memberRepo, spouseRepo)
+	marriageValidator := validator.NewMarriageValidator(memberRepo, spouseRepo, familyGraphRepo)
 	birthDateValidator := validator.NewBirthDateValidator(memberRepo, spouseRepo)
 	relationshipValidator := validator.NewRelationshipValidator(memberRepo, spouseRepo)
-
-	authUseCase := usecase.NewAuthUseCase(userRepo, sessionRepo, oauthStateRepo, oauthManager, tokenMgr)
-	userUseCase := usecase.NewUserUseCase(userRepo, scoreRepo, historyRepo)
-	familyTreeUseCase := usecase.NewFamilyTreeUseCase(familyTreeRepo, userRepo)
-	memberUseCase := usecase.NewMemberUseCase(memberRepo, spouseRepo, historyRepo, scoreRepo, s3Client, txManager, marriageValidator, birthDateValidator, relationshipValidator)
-	spouseUseCase := usecase.NewSpouseUseCase(spouseRepo, memberRepo, historyRepo, scoreRepo, txManager, marriageValidator)
-	treeUseCase := usecase.NewTreeUseCase(memberRepo, spouseRepo, familyGraphRepo)
-	languageUseCase := usecase.NewLanguageUseCase(langRepo, langPrefRepo)
+	placeValidator := validator.NewPlaceValidator(placeRepo)
+
+	authUseCase := usecase.NewAuthUseCase(userRepo, sessionRepo, oauthStateRepo, oauthManager, tokenMgr, auditRepo)
+	userUseCase := usecase.NewUserUseCase(userRepo, scoreRepo, historyRepo, auditRepo)
+	familyTreeUseCase := usecase.NewFamilyTreeUseCase(familyTreeRepo, userRepo, auditRepo)
+	memberUseCase := usecase.NewMemberUseCase(memberRepo, spouseRepo, historyRepo, scoreRepo, mediaRepo, citationRepo, customFieldRepo, familyTreeRepo, nameSpellingRepo, changesetRepo, s3Client, txManager, marriageValidator, birthDateValidator, relationshipValidator, placeValidator)
+	spouseUseCase := usecase.NewSpouseUseCase(spouseRepo, memberRepo, historyRepo, scoreRepo, txManager, marriageValidator, placeValidator)
+	proposalUseCase := usecase.NewProposalUseCase(proposalRepo, familyTreeRepo, memberRepo, spouseRepo, memberUseCase, spouseUseCase, txManager)
+	familyUnitUseCase := usecase.NewFamilyUnitUseCase(familyGraphRepo, memberRepo, historyRepo, txManager, marriageValidator)
+	treeUseCase := usecase.NewTreeUseCase(memberRepo, spouseRepo, familyGraphRepo, familyTreeRepo, historyRepo)
+	timelineUseCase := usecase.NewTimelineUseCase(memberRepo, spouseRepo, eventRepo, familyGraphRepo, familyTreeRepo)
+	activityUseCase := usecase.NewActivityUseCase(historyRepo, memberRepo, familyTreeRepo)
+	calendarUseCase := usecase.NewCalendarUseCase(familyTreeRepo, userRepo, memberRepo, spouseRepo)
+	placeUseCase := usecase.NewPlaceUseCase(placeRepo, memberRepo, familyTreeRepo)
+	mediaUseCase := usecase.NewMediaUseCase(mediaRepo, memberRepo, historyRepo, scoreRepo, familyTreeRepo, s3Client, txManager, placeValidator)
+	sourceUseCase := usecase.NewSourceUseCase(sourceRepo, citationRepo, memberRepo, spouseRepo, eventRepo, s3Client)
+	eventUseCase := usecase.NewEventUseCase(eventRepo, memberRepo, historyRepo, scoreRepo, familyTreeRepo, txManager, placeValidator)
+	customFieldUseCase := usecase.NewCustomFieldUseCase(customFieldRepo)
+	languageUseCase := usecase.NewLanguageUseCase(langRepo, langPrefRepo, auditRepo)
+	auditUseCase := usecase.NewAuditUseCase(auditRepo, familyTreeRepo)
+	changesetUseCase := usecase.NewChangesetUseCase(changesetRepo)
 
 	authHandler := handler.NewAuthHandler(authUseCase, userUseCase, cookieManager)
 	userHandler := handler.NewUserHandler(userUseCase)
 	memberHandler := handler.NewMemberHandler(memberUseCase, languageUseCase, familyTreeUseCase)
 	spouseHandler := handler.NewSpouseHandler(spouseUseCase, memberUseCase, familyTreeUseCase)
+	familyUnitHandler := handler.NewFamilyUnitHandler(familyUnitUseCase, familyTreeUseCase)
+	proposalHandler := handler.NewProposalHandler(proposalUseCase, languageUseCase, familyTreeUseCase)
+	auditHandler := handler.NewAuditHandler(auditUseCase)
 	treeHandler := handler.NewTreeHandler(treeUseCase, familyTreeUseCase)
 	familyTreeHandler := handler.NewFamilyTreeHandler(familyTreeUseCase, treeUseCase)
+	timelineHandler := handler.NewTimelineHandler(timelineUseCase, familyTreeUseCase)
+	activityHandler := handler.NewActivityHandler(activityUseCase, familyTreeUseCase)
+	changesetHandler := handler.NewChangesetHandler(changesetUseCase, memberUseCase, familyTreeUseCase)
+	calendarHandler := handler.NewCalendarHandler(calendarUseCase)
+	placeHandler := handler.NewPlaceHandler(placeUseCase, familyTreeUseCase)
+	eventHandler := handler.NewEventHandler(eventUseCase, memberUseCase, familyTreeUseCase)
+	mediaHandler := handler.NewMediaHandler(mediaUseCase, memberUseCase, familyTreeUseCase)
+	sourceHandler := handler.NewSourceHandler(sourceUseCase, familyTreeUseCase)
+	customFieldHandler := handler.NewCustomFieldHandler(customFieldUseCase, familyTreeUseCase)
 	languageHandler := handler.NewLanguageHandler(languageUseCase)
 
 	authMiddleware := middleware.NewAuthMiddleware(tokenMgr, authUseCase, userRepo, cookieManager)
+	changesetMiddleware := middleware.NewChangesetMiddleware(changesetUseCase)
+	treeRoleMiddleware := middleware.NewTreeRoleMiddleware(familyTreeUseCase)
 
 	authLimiterMiddleware := newRateLimiter(redisClient, cfg.RateLimit.Auth)
 	apiLimiterMiddleware := newRateLimiter(redisClient, cfg.RateLimit.API)
@@ -132,10 +169,24 @@ func NewApp(cfg *config.Config) (*App, error) {
 		userHandler,
 		memberHandler,
 		spouseHandler,
+		familyUnitHandler,
+		proposalHandler,
+		auditHandler,
 		treeHandler,
 		familyTreeHandler,
+		timelineHandler,
+		activityHandler,
+		changesetHandler,
+		calendarHandler,
+		placeHandler,
+		eventHandler,
+		mediaHandler,
+		sourceHandler,
+		customFieldHandler,
 		languageHandler,
 		authMiddleware,
+		changesetMiddleware,
+		treeRoleMiddleware,
 		cfg.Server.AllowedOrigins,
 		cfg.Server.EnableHSTS,
 		authLimiterMiddleware,
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/escalopa/family-tree/internal/domain"
+)
+
+type (
+	activityUseCaseRepo struct {
+		history HistoryRepository
+		member  MemberRepository
+	}
+
+	activityUseCase struct {
+		repo    activityUseCaseRepo
+		privacy treePrivacy
+	}
+)
+
+func NewActivityUseCase(historyRepo HistoryRepository, memberRepo MemberRepository, treeRepo FamilyTreeRepository) *activityUseCase {
+	return &activityUseCase{
+		repo: activityUseCaseRepo{
+			history: historyRepo,
+			member:  memberRepo,
+		},
+		privacy: treePrivacy{tree: treeRepo, member: memberRepo},
+	}
+}
+
+// List returns the changes made in a tree newest first, limited to the
+// subtree of filter.MemberID when it is set and stripped by the tree's
+// privacy policy. Research notes are for members who may edit the tree, the
+// others see that notes changed but not their text.
+func (uc *activityUseCase) List(ctx context.Context, filter domain.ActivityFilter, viewer domain.Viewer, cursor *string, limit int) ([]*domain.HistoryWithUser, *string, error) {
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return nil, nil, domain.NewValidationError("error.activity.invalid_range")
+	}
+
+	members, err := uc.repo.member.GetAllByTreeID(ctx, filter.TreeID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	memberMap := make(map[int]*domain.Member, len(members))
+	for _, m := range members {
+		memberMap[m.MemberID] = m
+	}
+
+	filter.MemberIDs = nil
+	if filter.MemberID != nil {
+		if _, exists := memberMap[*filter.MemberID]; !exists {
+			return nil, nil, domain.NewNotFoundError("member")
+		}
+		subtree := domain.DescendantsOf(*filter.MemberID, members)
+		filter.MemberIDs = make([]int, 0, len(subtree))
+		for memberID := range subtree {
+			filter.MemberIDs = append(filter.MemberIDs, memberID)
+		}
+	}
+
+	privacy, err := uc.privacy.For(ctx, filter.TreeID, viewer, members)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	histories, nextCursor, err := uc.repo.history.ListByTreeID(ctx, filter, cursor, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	readsNotes := domain.TreeRoleAllows(viewer.TreeRole, domain.TreePermissionEdit)
+
+	for _, history := range histories {
+		member, exists := memberMap[history.MemberID]
+		if !exists {
+			member = recordedMember(history)
+		}
+		privacy.ApplyHistory(history, member)
+		if history.ChangeType == domain.ChangeTypeUpdateNotes && !readsNotes {
+			history.OldValues = withoutNotes(history.OldValues)
+			history.NewValues = withoutNotes(history.NewValues)
+		}
+	}
+
+	return histories, nextCursor, nil
+}
+
+// withoutNotes clears the text of recorded notes
+func withoutNotes(values json.RawMessage) json.RawMessage {
+	var fields map[string]json.RawMessage
+	if len(values) == 0 || json.Unmarshal(values, &fields) != nil {
+		return values
+	}
+	if _, ok := fields[domain.NotesField]; !ok {
+		return values
+	}
+	fields[domain.NotesField] = json.RawMessage("null")
+	data, _ := json.Marshal(fields)
+	return data
+}
+
+// recordedMember rebuilds a member that is no longer in the tree from the
+// values its change recorded, so the privacy policy still applies to it
+func recordedMember(history *domain.HistoryWithUser) *domain.Member {
+	member := &domain.Member{}
+	for _, values := range []json.RawMessage{history.OldValues, history.NewValues} {
+		if len(values) > 0 && json.Unmarshal(values, member) == nil {
+			break
+		}
+	}
+	member.MemberID = history.MemberID
+	return member
+}
@@ -0,0 +1,259 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/escalopa/family-tree/internal/domain"
+)
+
+// treePast rebuilds a tree as it was at a point in time by undoing the
+// history written since
+type treePast struct {
+	member  MemberRepository
+	spouse  SpouseRepository
+	history HistoryRepository
+}
+
+// At returns the members and spouse relationships of the tree at asOf
+func (p treePast) At(ctx context.Context, treeID int, asOf time.Time) (*domain.TreeState, error) {
+	// History times are stored in UTC without a zone
+	asOf = asOf.UTC()
+
+	members, err := p.member.GetAllByTreeID(ctx, treeID)
+	if err != nil {
+		return nil, err
+	}
+	spouses, err := p.spouse.ListAllByTreeID(ctx, treeID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := p.history.ListByTreeIDSince(ctx, treeID, asOf, domain.AsOfChangeTypes)
+	if err != nil {
+		return nil, err
+	}
+	return domain.RewindHistory(treeID, members, spouses, history, asOf), nil
+}
+
+// GetAsOf returns the member as it was at asOf with its computed fields and
+// its parents, children and siblings taken from the tree at that time
+func (uc *memberUseCase) GetAsOf(ctx context.Context, treeID, memberID int, asOf time.Time, viewer domain.Viewer) (*domain.MemberAsOf, error) {
+	state, err := uc.past.At(ctx, treeID, asOf)
+	if err != nil {
+		return nil, err
+	}
+	member, ok := state.Member(memberID)
+	if !ok {
+		return nil, domain.NewNotFoundError("member")
+	}
+
+	privacy, err := uc.privacy.For(ctx, treeID, viewer, state.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	lineage := []*domain.Member{member}
+	for fatherID := member.FatherID; fatherID != nil && len(lineage) <= domain.MaxLineageDepth; {
+		father, ok := state.Member(*fatherID)
+		if !ok {
+			break
+		}
+		lineage = append(lineage, father)
+		fatherID = father.FatherID
+	}
+
+	computed := &domain.MemberWithComputed{Member: *member}
+	computed.FullNames = uc.formatFullNames(ctx, member, lineage)
+	computed.Age, computed.AgeMin, computed.AgeMax = computeAge(member)
+	computed.Spouses = state.SpousesOf(memberID)
+	computed.IsMarried = len(computed.Spouses) > 0
+	for i := range computed.Spouses {
+		privacy.ApplySpouse(&computed.Spouses[i])
+	}
+	privacy.ApplyComputed(computed)
+
+	result := &domain.MemberAsOf{
+		Member:   computed,
+		Children: state.Children(memberID),
+		Siblings: state.Siblings(memberID),
+	}
+	for _, parentID := range []*int{member.FatherID, member.MotherID} {
+		if parentID == nil {
+			continue
+		}
+		if parent, ok := state.Member(*parentID); ok {
+			result.Parents = append(result.Parents, parent)
+		}
+	}
+	for _, family := range [][]*domain.Member{result.Parents, result.Children, result.Siblings} {
+		for _, relative := range family {
+			privacy.ApplyMember(relative)
+		}
+	}
+	return result, nil
+}
+
+// RestoreAsOf puts the tree, or the subtree under rootID, back the way it was
+// at asOf. Every change goes through the same checks and history as the
+// matching edit would, so a change the tree doesn't allow anymore is skipped
+// and reported instead of failing the whole restore.
+func (uc *memberUseCase) RestoreAsOf(ctx context.Context, treeID int, rootID *int, asOf time.Time, userID int) (*domain.AsOfRestore, error) {
+	if !asOf.Before(time.Now()) {
+		return nil, domain.NewValidationError("error.as_of.in_future")
+	}
+
+	state, err := uc.past.At(ctx, treeID, asOf)
+	if err != nil {
+		return nil, err
+	}
+	current, err := uc.repo.member.GetAllByTreeID(ctx, treeID)
+	if err != nil {
+		return nil, err
+	}
+	spouses, err := uc.repo.spouse.ListAllByTreeID(ctx, treeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if rootID != nil {
+		if _, ok := state.Member(*rootID); !ok {
+			if _, err := uc.getInTree(ctx, treeID, *rootID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	plan := state.PlanRestore(current, spouses, rootID)
+	result := &domain.AsOfRestore{}
+	skip := func(memberID, spouseID *int, err error) error {
+		var domainErr *domain.DomainError
+		if !errors.As(err, &domainErr) {
+			return err
+		}
+		switch domainErr.Code {
+		case domain.ErrCodeInternal, domain.ErrCodeDatabaseError, domain.ErrCodeExternalService:
+			return err
+		}
+		result.Skipped = append(result.Skipped, domain.AsOfRestoreSkip{MemberID: memberID, SpouseID: spouseID, Err: domainErr})
+		return nil
+	}
+
+	// Skipped changes failed before writing anything, any other error rolls
+	// the whole restore back
+	err = uc.tx.Do(ctx, func(txCtx context.Context) error {
+		return uc.applyRestore(txCtx, treeID, plan, result, skip, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// applyRestore carries out a restore plan, counting the changes in result.
+// skip decides whether a failed change is reported or stops the rest.
+func (uc *memberUseCase) applyRestore(ctx context.Context, treeID int, plan *domain.RestorePlan, result *domain.AsOfRestore, skip func(memberID, spouseID *int, err error) error, userID int) error {
+	for _, past := range plan.Restore {
+		member := *past
+		if err := uc.restoreDeletedMember(ctx, treeID, &member, userID); err != nil {
+			if err := skip(&past.MemberID, nil, err); err != nil {
+				return err
+			}
+			continue
+		}
+		result.MembersRestored++
+	}
+
+	for _, past := range plan.RestoreSpouses {
+		spouse, err := uc.repo.spouse.GetDeleted(ctx, past.SpouseID)
+		if err == nil {
+			err = uc.restoreSpouse(ctx, treeID, spouse, userID)
+		}
+		if err != nil {
+			if err := skip(nil, &past.SpouseID, err); err != nil {
+				return err
+			}
+			continue
+		}
+		result.SpousesRestored++
+	}
+
+	for _, past := range plan.UpdateSpouses {
+		currentSpouse, err := uc.repo.spouse.Get(ctx, past.SpouseID)
+		if err == nil {
+			spouse := *past
+			err = uc.revertSpouse(ctx, treeID, currentSpouse, &spouse, userID)
+		}
+		if err != nil {
+			if err := skip(nil, &past.SpouseID, err); err != nil {
+				return err
+			}
+			continue
+		}
+		result.SpousesUpdated++
+	}
+
+	for _, past := range plan.Update {
+		if err := uc.restoreMemberValues(ctx, treeID, past, userID); err != nil {
+			if err := skip(&past.MemberID, nil, err); err != nil {
+				return err
+			}
+			continue
+		}
+		result.MembersUpdated++
+	}
+
+	for _, member := range plan.Delete {
+		if err := uc.Delete(ctx, member.MemberID, userID); err != nil {
+			if err := skip(&member.MemberID, nil, err); err != nil {
+				return err
+			}
+			continue
+		}
+		result.MembersDeleted++
+	}
+
+	// Deleting a member ends its spouse relationships along with it
+	for _, spouse := range plan.RemoveSpouses {
+		currentSpouse, err := uc.repo.spouse.Get(ctx, spouse.SpouseID)
+		if domain.IsDomainError(err, domain.ErrCodeNotFound) {
+			continue
+		}
+		if err == nil {
+			err = uc.removeSpouse(ctx, currentSpouse, userID)
+		}
+		if err != nil {
+			if err := skip(nil, &spouse.SpouseID, err); err != nil {
+				return err
+			}
+			continue
+		}
+		result.SpousesRemoved++
+	}
+
+	return nil
+}
+
+// restoreMemberValues sets a live member back to past values, its picture
+// included
+func (uc *memberUseCase) restoreMemberValues(ctx context.Context, treeID int, past *domain.Member, userID int) error {
+	member, err := uc.getInTree(ctx, treeID, past.MemberID)
+	if err != nil {
+		return err
+	}
+
+	if member.Version != past.Version {
+		values := *past
+		if values.Nicknames == nil {
+			values.Nicknames = []string{}
+		}
+		if err := uc.Update(ctx, &values, member.Version, userID); err != nil {
+			return err
+		}
+		if member, err = uc.getInTree(ctx, treeID, past.MemberID); err != nil {
+			return err
+		}
+	}
+
+	return uc.restorePicture(ctx, member, past.Picture, userID)
+}
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strconv"
+
+	"github.com/escalopa/family-tree/internal/domain"
+)
+
+type (
+	auditUseCaseRepo struct {
+		audit AuditRepository
+		tree  FamilyTreeRepository
+	}
+
+	auditUseCase struct {
+		repo auditUseCaseRepo
+	}
+)
+
+func NewAuditUseCase(auditRepo AuditRepository, treeRepo FamilyTreeRepository) *auditUseCase {
+	return &auditUseCase{
+		repo: auditUseCaseRepo{
+			audit: auditRepo,
+			tree:  treeRepo,
+		},
+	}
+}
+
+// List returns audit events newest first. Super admins read every event, tree
+// owners read the events of their tree.
+func (uc *auditUseCase) List(ctx context.Context, filter domain.AuditFilter, viewer domain.Viewer, cursor *string, limit int) ([]*domain.AuditEvent, *string, error) {
+	if viewer.Role < domain.RoleSuperAdmin {
+		if filter.TreeID == nil {
+			return nil, nil, domain.NewForbiddenError("error.audit.tree_required")
+		}
+		tree, err := uc.repo.tree.GetForUser(ctx, *filter.TreeID, viewer.UserID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if tree.UserRole != domain.TreeRoleOwner {
+			return nil, nil, domain.NewForbiddenError("error.family_tree.owner_required")
+		}
+	}
+	return uc.repo.audit.List(ctx, filter, cursor, limit)
+}
+
+// auditLog appends audit events with the request they came from. A failed
+// write is logged and never fails the action it records.
+type auditLog struct {
+	repo AuditRepository
+}
+
+func (l auditLog) record(ctx context.Context, event *domain.AuditEvent) {
+	info := domain.RequestInfoFromContext(ctx)
+	event.IPAddress = info.IPAddress
+	event.UserAgent = info.UserAgent
+	event.RequestID = info.RequestID
+
+	if err := l.repo.Create(ctx, event); err != nil {
+		slog.Error("record audit event", "error", err, "action", event.Action, "request_id", event.RequestID)
+	}
+}
+
+func auditID(id int) *string {
+	value := strconv.Itoa(id)
+	return &value
+}
+
+func auditDetails(details map[string]any) json.RawMessage {
+	data, _ := json.Marshal(details)
+	return data
+}
@@ -24,8 +24,9 @@ type (
 	}
 
 	authUseCase struct {
-		repo authUseCaseRepo
-		mgr  authUseCaseManager
+		repo  authUseCaseRepo
+		mgr   authUseCaseManager
+		audit auditLog
 	}
 )
 
@@ -35,6 +36,7 @@ func NewAuthUseCase(
 	oauthStateRepo OAuthStateRepository,
 	oauthMgr OAuthManager,
 	tokenMgr TokenManager,
+	auditRepo AuditRepository,
 ) *authUseCase {
 	return &authUseCase{
 		repo: authUseCaseRepo{
@@ -46,6 +48,7 @@ func NewAuthUseCase(
 			oauth: oauthMgr,
 			token: tokenMgr,
 		},
+		audit: auditLog{repo: auditRepo},
 	}
 }
 
@@ -86,11 +89,13 @@ func (uc *authUseCase) generateState() (string, error) {
 
 func (uc *authUseCase) HandleCallback(ctx context.Context, provider, code, state string) (*domain.User, *domain.AuthTokens, error) {
 	if err := uc.validateState(ctx, state, provider); err != nil {
+		uc.recordLoginFailed(ctx, provider, "invalid_state")
 		return nil, nil, err
 	}
 
 	userInfo, err := uc.mgr.oauth.GetUserInfo(ctx, provider, code)
 	if err != nil {
+		uc.recordLoginFailed(ctx, provider, "provider_error")
 		return nil, nil, domain.NewExternalServiceError(err)
 	}
 
@@ -144,9 +149,25 @@ func (uc *authUseCase) HandleCallback(ctx context.Context, provider, code, state
 		SessionID:    sessionID,
 	}
 
+	uc.audit.record(ctx, &domain.AuditEvent{
+		ActorUserID: &user.UserID,
+		Action:      domain.AuditActionLogin,
+		TargetType:  domain.AuditTargetUser,
+		TargetID:    auditID(user.UserID),
+		Details:     auditDetails(map[string]any{"provider": provider}),
+	})
+
 	return user, tokens, nil
 }
 
+func (uc *authUseCase) recordLoginFailed(ctx context.Context, provider, reason string) {
+	uc.audit.record(ctx, &domain.AuditEvent{
+		Action:     domain.AuditActionLoginFailed,
+		TargetType: domain.AuditTargetUser,
+		Details:    auditDetails(map[string]any{"provider": provider, "reason": reason}),
+	})
+}
+
 func (uc *authUseCase) validateState(ctx context.Context, state, provider string) error {
 	oauthState, err := uc.repo.oauthState.Get(ctx, state)
 	if err != nil {
@@ -215,12 +236,32 @@ func (uc *authUseCase) RefreshTokens(ctx context.Context, refreshToken string) (
 	return tokens, nil
 }
 
-func (uc *authUseCase) Logout(ctx context.Context, sessionID string) error {
-	return uc.repo.session.Revoke(ctx, sessionID)
+func (uc *authUseCase) Logout(ctx context.Context, sessionID string, userID int) error {
+	if err := uc.repo.session.Revoke(ctx, sessionID); err != nil {
+		return err
+	}
+
+	uc.audit.record(ctx, &domain.AuditEvent{
+		ActorUserID: &userID,
+		Action:      domain.AuditActionLogout,
+		TargetType:  domain.AuditTargetUser,
+		TargetID:    auditID(userID),
+	})
+	return nil
 }
 
 func (uc *authUseCase) LogoutAll(ctx context.Context, userID int) error {
-	return uc.repo.session.RevokeAllByUser(ctx, userID)
+	if err := uc.repo.session.RevokeAllByUser(ctx, userID); err != nil {
+		return err
+	}
+
+	uc.audit.record(ctx, &domain.AuditEvent{
+		ActorUserID: &userID,
+		Action:      domain.AuditActionLogoutAll,
+		TargetType:  domain.AuditTargetUser,
+		TargetID:    auditID(userID),
+	})
+	return nil
 }
 
 func (uc *authUseCase) ValidateSession(ctx context.Context, sessionID string) (*domain.Session, error) {
@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/escalopa/family-tree/internal/pkg/markdown"
+)
+
+func (uc *memberUseCase) GetBiographies(ctx context.Context, memberID int) (map[string]string, error) {
+	return uc.repo.member.GetBiographies(ctx, memberID)
+}
+
+// UpdateBiography replaces the biography of one language with sanitized
+// Markdown, an empty text removes it
+func (uc *memberUseCase) UpdateBiography(ctx context.Context, memberID int, languageCode, biography string, expectedVersion, userID int) error {
+	biographies, err := uc.repo.member.GetBiographies(ctx, memberID)
+	if err != nil {
+		return err
+	}
+
+	biography = markdown.Sanitize(biography)
+	oldBiography, existed := biographies[languageCode]
+	if oldBiography == biography {
+		return nil
+	}
+
+	return uc.tx.Do(ctx, func(txCtx context.Context) error {
+		version, err := uc.repo.member.UpdateBiography(txCtx, memberID, languageCode, biography, expectedVersion)
+		if err != nil {
+			return err
+		}
+
+		field := domain.BiographyField(languageCode)
+		if err := uc.recordTextHistory(txCtx, memberID, domain.ChangeTypeUpdateBio, field, optionalText(oldBiography), optionalText(biography), version, userID); err != nil {
+			return err
+		}
+
+		if existed || biography == "" {
+			return nil
+		}
+		return uc.repo.score.Create(txCtx, domain.Score{
+			UserID:        userID,
+			MemberID:      memberID,
+			FieldName:     field,
+			Points:        domain.PointsBiography,
+			MemberVersion: version,
+		})
+	})
+}
+
+func (uc *memberUseCase) GetNotes(ctx context.Context, memberID int) (*string, error) {
+	return uc.repo.member.GetNotes(ctx, memberID)
+}
+
+// UpdateNotes replaces the research notes, they are plain text for editors
+// and earn no points
+func (uc *memberUseCase) UpdateNotes(ctx context.Context, memberID int, notes string, expectedVersion, userID int) error {
+	oldNotes, err := uc.repo.member.GetNotes(ctx, memberID)
+	if err != nil {
+		return err
+	}
+
+	notes = strings.TrimSpace(notes)
+	if oldNotes == nil && notes == "" || oldNotes != nil && *oldNotes == notes {
+		return nil
+	}
+
+	return uc.tx.Do(ctx, func(txCtx context.Context) error {
+		version, err := uc.repo.member.UpdateNotes(txCtx, memberID, notes, expectedVersion)
+		if err != nil {
+			return err
+		}
+		return uc.recordTextHistory(txCtx, memberID, domain.ChangeTypeUpdateNotes, domain.NotesField, oldNotes, optionalText(notes), version, userID)
+	})
+}
+
+// rollbackText restores the texts saved in the old values of a biography or
+// notes change
+func (uc *memberUseCase) rollbackText(ctx context.Context, history *domain.HistoryWithUser, currentVersion, userID int) error {
+	var oldValues map[string]*string
+	if err := json.Unmarshal(history.OldValues, &oldValues); err != nil || len(oldValues) != 1 {
+		return domain.NewValidationError("error.history.rollback_invalid_snapshot")
+	}
+
+	for field, value := range oldValues {
+		text := ""
+		if value != nil {
+			text = *value
+		}
+
+		if history.ChangeType == domain.ChangeTypeUpdateNotes && field == domain.NotesField {
+			return uc.UpdateNotes(ctx, history.MemberID, text, currentVersion, userID)
+		}
+		if languageCode, ok := domain.BiographyLanguage(field); ok && history.ChangeType == domain.ChangeTypeUpdateBio {
+			return uc.UpdateBiography(ctx, history.MemberID, languageCode, text, currentVersion, userID)
+		}
+	}
+	return domain.NewValidationError("error.history.rollback_invalid_snapshot")
+}
+
+func (uc *memberUseCase) recordTextHistory(ctx context.Context, memberID int, changeType, field string, oldText, newText *string, version, userID int) error {
+	oldValuesJSON, _ := json.Marshal(map[string]any{field: oldText})
+	newValuesJSON, _ := json.Marshal(map[string]any{field: newText})
+	return uc.repo.history.Create(ctx, &domain.History{
+		MemberID:      memberID,
+		UserID:        userID,
+		ChangeType:    changeType,
+		OldValues:     oldValuesJSON,
+		NewValues:     newValuesJSON,
+		MemberVersion: version,
+	})
+}
+
+func optionalText(text string) *string {
+	if text == "" {
+		return nil
+	}
+	return &text
+}
@@ -0,0 +1,202 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/escalopa/family-tree/internal/domain"
+)
+
+type (
+	calendarUseCaseRepo struct {
+		tree   FamilyTreeRepository
+		user   UserRepository
+		member MemberRepository
+		spouse SpouseRepository
+	}
+
+	calendarUseCase struct {
+		repo    calendarUseCaseRepo
+		privacy treePrivacy
+	}
+)
+
+func NewCalendarUseCase(treeRepo FamilyTreeRepository, userRepo UserRepository, memberRepo MemberRepository, spouseRepo SpouseRepository) *calendarUseCase {
+	return &calendarUseCase{
+		repo: calendarUseCaseRepo{
+			tree:   treeRepo,
+			user:   userRepo,
+			member: memberRepo,
+			spouse: spouseRepo,
+		},
+		privacy: treePrivacy{tree: treeRepo, member: memberRepo},
+	}
+}
+
+func (uc *calendarUseCase) CreateFeed(ctx context.Context, treeID, userID int) (*domain.FamilyTreeCalendarFeed, error) {
+	if err := uc.ensureAccess(ctx, treeID, userID); err != nil {
+		return nil, err
+	}
+	feed := &domain.FamilyTreeCalendarFeed{
+		TreeID: treeID,
+		UserID: userID,
+	}
+	if err := uc.repo.tree.CreateCalendarFeed(ctx, feed); err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+func (uc *calendarUseCase) ListFeeds(ctx context.Context, treeID, userID int) ([]*domain.FamilyTreeCalendarFeed, error) {
+	if err := uc.ensureAccess(ctx, treeID, userID); err != nil {
+		return nil, err
+	}
+	return uc.repo.tree.ListCalendarFeeds(ctx, treeID, userID)
+}
+
+func (uc *calendarUseCase) RevokeFeed(ctx context.Context, treeID, feedID, userID int) error {
+	if err := uc.ensureAccess(ctx, treeID, userID); err != nil {
+		return err
+	}
+	return uc.repo.tree.RevokeCalendarFeed(ctx, treeID, feedID, userID)
+}
+
+// GetFeedContent resolves a feed token and builds its events with the
+// subscriber's current role, so a demotion or removal from the tree takes
+// effect on the next refresh. Only a fetch that passes those checks counts as
+// an access of the feed.
+func (uc *calendarUseCase) GetFeedContent(ctx context.Context, token string) (*domain.CalendarFeedContent, error) {
+	feed, err := uc.repo.tree.GetCalendarFeedByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriber, err := uc.repo.user.Get(ctx, feed.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !subscriber.IsActive {
+		return nil, domain.NewNotFoundError("calendar_feed")
+	}
+
+	tree, err := uc.repo.tree.GetForUser(ctx, feed.TreeID, feed.UserID)
+	if err != nil {
+		if domain.IsDomainError(err, domain.ErrCodeNotFound) {
+			return nil, domain.NewNotFoundError("calendar_feed")
+		}
+		return nil, err
+	}
+
+	if err := uc.repo.tree.TouchCalendarFeed(ctx, feed); err != nil {
+		return nil, err
+	}
+
+	members, err := uc.repo.member.GetAllByTreeID(ctx, feed.TreeID)
+	if err != nil {
+		return nil, err
+	}
+
+	spouses, err := uc.repo.spouse.GetAllSpousesByTreeID(ctx, feed.TreeID)
+	if err != nil {
+		return nil, err
+	}
+
+	viewer := domain.Viewer{UserID: subscriber.UserID, Role: subscriber.RoleID, TreeRole: tree.UserRole}
+	privacy, err := uc.privacy.For(ctx, feed.TreeID, viewer, members)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.CalendarFeedContent{
+		Feed:     feed,
+		TreeName: tree.Name,
+		Language: subscriber.PreferredLanguage,
+		Events:   uc.buildEvents(members, spouses, privacy),
+	}, nil
+}
+
+func (uc *calendarUseCase) buildEvents(members []*domain.Member, spouses map[int][]domain.SpouseWithMemberInfo, privacy *domain.Privacy) []*domain.CalendarEvent {
+	memberMap := make(map[int]*domain.Member, len(members))
+	for _, m := range members {
+		memberMap[m.MemberID] = m
+	}
+	names := func(m *domain.Member) map[string]string {
+		if privacy.CanSee(domain.PrivacyFieldNames, m) {
+			return m.Names
+		}
+		return nil
+	}
+
+	var events []*domain.CalendarEvent
+	for _, m := range members {
+		// Events carry the full date, so only dates the viewer may see in
+		// full are put on the calendar
+		if !privacy.CanSee(domain.PrivacyFieldDates, m) {
+			continue
+		}
+		// Birthdays are only useful for the living, the deceased are
+		// remembered on the day they passed instead. Approximate dates have
+		// no day to put on a calendar
+		if m.BirthRange().IsExact() && m.DateOfDeath == nil {
+			events = append(events, &domain.CalendarEvent{
+				UID:         fmt.Sprintf("member-%d-birthday", m.MemberID),
+				EventType:   domain.CalendarEventBirthday,
+				Date:        *m.DateOfBirth,
+				MemberID:    m.MemberID,
+				MemberNames: names(m),
+			})
+		}
+		if m.DeathRange().IsExact() {
+			events = append(events, &domain.CalendarEvent{
+				UID:         fmt.Sprintf("member-%d-remembrance", m.MemberID),
+				EventType:   domain.CalendarEventRemembrance,
+				Date:        *m.DateOfDeath,
+				MemberID:    m.MemberID,
+				MemberNames: names(m),
+			})
+		}
+	}
+
+	for _, husband := range members {
+		if husband.Gender != "M" || husband.DateOfDeath != nil {
+			continue
+		}
+		for _, wife := range spouses[husband.MemberID] {
+			if !wife.MarriageRange().IsExact() || wife.DivorceDate != nil {
+				continue
+			}
+			partner := memberMap[wife.MemberID]
+			if partner == nil || partner.DateOfDeath != nil {
+				continue
+			}
+			partnerID := partner.MemberID
+			events = append(events, &domain.CalendarEvent{
+				UID:          fmt.Sprintf("spouse-%d-anniversary", wife.SpouseID),
+				EventType:    domain.CalendarEventMarriageAnniversary,
+				Date:         *wife.MarriageDate,
+				MemberID:     husband.MemberID,
+				MemberNames:  names(husband),
+				PartnerID:    &partnerID,
+				PartnerNames: names(partner),
+			})
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].UID < events[j].UID
+	})
+
+	return events
+}
+
+func (uc *calendarUseCase) ensureAccess(ctx context.Context, treeID, userID int) error {
+	ok, err := uc.repo.tree.HasAccess(ctx, treeID, userID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return domain.NewNotFoundError("family_tree")
+	}
+	return nil
+}
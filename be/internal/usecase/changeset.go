@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/escalopa/family-tree/internal/domain"
+)
+
+type (
+	changesetUseCaseRepo struct {
+		changeset ChangesetRepository
+	}
+
+	changesetUseCase struct {
+		repo changesetUseCaseRepo
+	}
+)
+
+func NewChangesetUseCase(changesetRepo ChangesetRepository) *changesetUseCase {
+	return &changesetUseCase{
+		repo: changesetUseCaseRepo{
+			changeset: changesetRepo,
+		},
+	}
+}
+
+// Create opens an explicit changeset the user's next requests can write in
+func (uc *changesetUseCase) Create(ctx context.Context, changeset *domain.Changeset) error {
+	return uc.repo.changeset.Create(ctx, changeset)
+}
+
+// Open checks that a request may write in the changeset: it has to be one of
+// the user's in the same tree and not reverted yet
+func (uc *changesetUseCase) Open(ctx context.Context, treeID int, changesetID string, userID int) error {
+	changeset, err := uc.repo.changeset.Get(ctx, treeID, changesetID)
+	if err != nil {
+		return err
+	}
+	if changeset.UserID != userID {
+		return domain.NewForbiddenError("error.changeset.not_owner")
+	}
+	if changeset.RevertedAt != nil {
+		return domain.NewConflictError("error.changeset.already_reverted", nil)
+	}
+	return nil
+}
+
+func (uc *changesetUseCase) List(ctx context.Context, treeID int, cursor *string, limit int) ([]*domain.Changeset, *string, error) {
+	return uc.repo.changeset.List(ctx, treeID, cursor, limit)
+}
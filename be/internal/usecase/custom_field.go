@@ -0,0 +1,227 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/escalopa/family-tree/internal/domain"
+)
+
+const (
+	customTextMaxLength  = 1000
+	customFieldMaxOption = 100
+)
+
+type (
+	customFieldUseCaseRepo struct {
+		customField CustomFieldRepository
+	}
+
+	customFieldUseCase struct {
+		repo customFieldUseCaseRepo
+	}
+)
+
+func NewCustomFieldUseCase(customFieldRepo CustomFieldRepository) *customFieldUseCase {
+	return &customFieldUseCase{
+		repo: customFieldUseCaseRepo{
+			customField: customFieldRepo,
+		},
+	}
+}
+
+func (uc *customFieldUseCase) Create(ctx context.Context, field *domain.CustomField) error {
+	field.Key = strings.TrimSpace(field.Key)
+	if !domain.IsValidCustomFieldKey(field.Key) {
+		return domain.NewValidationError("error.custom_field.invalid_key")
+	}
+	if !domain.IsValidCustomFieldType(field.Type) {
+		return domain.NewValidationError("error.custom_field.invalid_type")
+	}
+	if err := validateCustomField(field); err != nil {
+		return err
+	}
+
+	fields, err := uc.repo.customField.ListByTreeID(ctx, field.TreeID)
+	if err != nil {
+		return err
+	}
+	for _, existing := range fields {
+		if existing.Key == field.Key {
+			return domain.NewAlreadyExistsError("custom_field")
+		}
+	}
+
+	return uc.repo.customField.Create(ctx, field)
+}
+
+func (uc *customFieldUseCase) Get(ctx context.Context, treeID, fieldID int) (*domain.CustomField, error) {
+	field, err := uc.repo.customField.Get(ctx, fieldID)
+	if err != nil {
+		return nil, err
+	}
+	if field.TreeID != treeID {
+		return nil, domain.NewNotFoundError("custom_field")
+	}
+	return field, nil
+}
+
+func (uc *customFieldUseCase) List(ctx context.Context, treeID int) ([]*domain.CustomField, error) {
+	return uc.repo.customField.ListByTreeID(ctx, treeID)
+}
+
+// Update changes the names, options, required flag and order of a field.
+// Enum options can only be dropped while no member holds them
+func (uc *customFieldUseCase) Update(ctx context.Context, field *domain.CustomField) error {
+	existing, err := uc.Get(ctx, field.TreeID, field.FieldID)
+	if err != nil {
+		return err
+	}
+	field.Key = existing.Key
+	field.Type = existing.Type
+	if err := validateCustomField(field); err != nil {
+		return err
+	}
+
+	if field.Type == domain.CustomFieldTypeEnum {
+		inUse, err := uc.repo.customField.HasValuesOutside(ctx, field.FieldID, field.Options)
+		if err != nil {
+			return err
+		}
+		if inUse {
+			return domain.NewValidationError("error.custom_field.option_in_use")
+		}
+	}
+
+	return uc.repo.customField.Update(ctx, field)
+}
+
+func (uc *customFieldUseCase) Delete(ctx context.Context, treeID, fieldID int) error {
+	if _, err := uc.Get(ctx, treeID, fieldID); err != nil {
+		return err
+	}
+	return uc.repo.customField.Delete(ctx, fieldID)
+}
+
+func validateCustomField(field *domain.CustomField) error {
+	if len(field.Names) == 0 {
+		return domain.NewValidationError("error.custom_field.names_required")
+	}
+	for langCode, name := range field.Names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return domain.NewValidationError("error.custom_field.names_required")
+		}
+		field.Names[langCode] = name
+	}
+
+	if field.Type != domain.CustomFieldTypeEnum {
+		field.Options = []string{}
+		return nil
+	}
+
+	options := make([]string, 0, len(field.Options))
+	for _, option := range field.Options {
+		option = strings.TrimSpace(option)
+		if option == "" || slices.Contains(options, option) {
+			continue
+		}
+		options = append(options, option)
+	}
+	if len(options) == 0 {
+		return domain.NewValidationError("error.custom_field.options_required")
+	}
+	if len(options) > customFieldMaxOption {
+		return domain.NewValidationError("error.custom_field.too_many_options")
+	}
+	field.Options = options
+	return nil
+}
+
+// validateCustomValues checks the member's values against the tree schema and
+// rewrites them in canonical form, empty values are dropped
+func (uc *memberUseCase) validateCustomValues(ctx context.Context, treeID, memberID int, values map[string]string) error {
+	fields, err := uc.repo.customField.ListByTreeID(ctx, treeID)
+	if err != nil {
+		return err
+	}
+
+	schema := make(map[string]*domain.CustomField, len(fields))
+	for _, field := range fields {
+		schema[field.Key] = field
+	}
+
+	for key, value := range values {
+		field, ok := schema[key]
+		if !ok {
+			return domain.NewValidationError("error.custom_field.unknown").WithParams(map[string]string{"key": key})
+		}
+
+		value = strings.TrimSpace(value)
+		if value == "" {
+			delete(values, key)
+			continue
+		}
+
+		canonical, err := uc.canonicalCustomValue(ctx, field, treeID, memberID, value)
+		if err != nil {
+			return err
+		}
+		values[key] = canonical
+	}
+
+	for _, field := range fields {
+		if _, ok := values[field.Key]; field.IsRequired && !ok {
+			return domain.NewValidationError("error.custom_field.required").WithParams(map[string]string{"key": field.Key})
+		}
+	}
+	return nil
+}
+
+func (uc *memberUseCase) canonicalCustomValue(ctx context.Context, field *domain.CustomField, treeID, memberID int, value string) (string, error) {
+	invalid := domain.NewValidationError("error.custom_field.invalid_value").WithParams(map[string]string{"key": field.Key})
+
+	switch field.Type {
+	case domain.CustomFieldTypeNumber:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", invalid
+		}
+		return strconv.FormatFloat(number, 'f', -1, 64), nil
+	case domain.CustomFieldTypeDate:
+		date, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return "", invalid
+		}
+		return date.Format(time.DateOnly), nil
+	case domain.CustomFieldTypeEnum:
+		if !field.HasOption(value) {
+			return "", invalid
+		}
+		return value, nil
+	case domain.CustomFieldTypeMember:
+		referenceID, err := strconv.Atoi(value)
+		if err != nil || referenceID == memberID {
+			return "", invalid
+		}
+		reference, err := uc.repo.member.Get(ctx, referenceID)
+		if err != nil {
+			if domain.IsDomainError(err, domain.ErrCodeNotFound) {
+				return "", invalid
+			}
+			return "", err
+		}
+		if reference.TreeID != treeID {
+			return "", invalid
+		}
+		return strconv.Itoa(referenceID), nil
+	default:
+		if len([]rune(value)) > customTextMaxLength {
+			return "", invalid
+		}
+		return value, nil
+	}
+}
@@ -0,0 +1,315 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/escalopa/family-tree/internal/domain"
+)
+
+type (
+	eventUseCaseRepo struct {
+		event   EventRepository
+		member  MemberRepository
+		history HistoryRepository
+		score   ScoreRepository
+	}
+
+	eventUseCaseValidator struct {
+		place PlaceValidator
+	}
+
+	eventUseCase struct {
+		repo      eventUseCaseRepo
+		validator eventUseCaseValidator
+		privacy   treePrivacy
+		tx        TransactionManager
+	}
+)
+
+func NewEventUseCase(
+	eventRepo EventRepository,
+	memberRepo MemberRepository,
+	historyRepo HistoryRepository,
+	scoreRepo ScoreRepository,
+	treeRepo FamilyTreeRepository,
+	txManager TransactionManager,
+	placeValidator PlaceValidator,
+) *eventUseCase {
+	return &eventUseCase{
+		repo: eventUseCaseRepo{
+			event:   eventRepo,
+			member:  memberRepo,
+			history: historyRepo,
+			score:   scoreRepo,
+		},
+		validator: eventUseCaseValidator{
+			place: placeValidator,
+		},
+		privacy: treePrivacy{tree: treeRepo, member: memberRepo},
+		tx:      txManager,
+	}
+}
+
+func (uc *eventUseCase) List(ctx context.Context, memberID int, viewer domain.Viewer) ([]*domain.MemberEvent, error) {
+	member, err := uc.repo.member.Get(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	privacy, err := uc.privacy.For(ctx, member.TreeID, viewer, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := uc.repo.event.ListByMemberID(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		privacy.ApplyEvent(event, member)
+	}
+	return events, nil
+}
+
+func (uc *eventUseCase) Get(ctx context.Context, memberID, eventID int, viewer domain.Viewer) (*domain.MemberEvent, error) {
+	member, err := uc.repo.member.Get(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	privacy, err := uc.privacy.For(ctx, member.TreeID, viewer, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := uc.getForMember(ctx, memberID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	privacy.ApplyEvent(event, member)
+	return event, nil
+}
+
+func (uc *eventUseCase) Create(ctx context.Context, event *domain.MemberEvent, userID int) error {
+	member, err := uc.repo.member.Get(ctx, event.MemberID)
+	if err != nil {
+		return err
+	}
+	if err := uc.validateEvent(ctx, event, member); err != nil {
+		return err
+	}
+
+	return uc.tx.Do(ctx, func(txCtx context.Context) error {
+		return uc.createTx(txCtx, event, member, userID)
+	})
+}
+
+func (uc *eventUseCase) createTx(ctx context.Context, event *domain.MemberEvent, member *domain.Member, userID int) error {
+	if err := uc.repo.event.Create(ctx, event); err != nil {
+		return err
+	}
+
+	newValues, _ := json.Marshal(event)
+	uc.recordEventHistory(ctx, member, domain.ChangeTypeAddEvent, nil, newValues, userID)
+
+	return uc.calculateAndRecordScores(ctx, event, member, userID)
+}
+
+func (uc *eventUseCase) Update(ctx context.Context, event *domain.MemberEvent, userID int) error {
+	oldEvent, err := uc.getForMember(ctx, event.MemberID, event.EventID)
+	if err != nil {
+		return err
+	}
+
+	member, err := uc.repo.member.Get(ctx, event.MemberID)
+	if err != nil {
+		return err
+	}
+	if err := uc.validateEvent(ctx, event, member); err != nil {
+		return err
+	}
+
+	return uc.tx.Do(ctx, func(txCtx context.Context) error {
+		return uc.updateTx(txCtx, event, oldEvent, member, userID)
+	})
+}
+
+func (uc *eventUseCase) updateTx(ctx context.Context, event, oldEvent *domain.MemberEvent, member *domain.Member, userID int) error {
+	if err := uc.repo.event.Update(ctx, event); err != nil {
+		return err
+	}
+	event.CreatedAt = oldEvent.CreatedAt
+
+	oldValues, _ := json.Marshal(oldEvent)
+	newValues, _ := json.Marshal(event)
+	uc.recordEventHistory(ctx, member, domain.ChangeTypeUpdateEvent, oldValues, newValues, userID)
+
+	return uc.updateScores(ctx, oldEvent, event, member, userID)
+}
+
+func (uc *eventUseCase) Delete(ctx context.Context, memberID, eventID, userID int) error {
+	oldEvent, err := uc.getForMember(ctx, memberID, eventID)
+	if err != nil {
+		return err
+	}
+
+	member, err := uc.repo.member.Get(ctx, memberID)
+	if err != nil {
+		return err
+	}
+
+	return uc.tx.Do(ctx, func(txCtx context.Context) error {
+		return uc.deleteTx(txCtx, oldEvent, member, userID)
+	})
+}
+
+func (uc *eventUseCase) deleteTx(ctx context.Context, oldEvent *domain.MemberEvent, member *domain.Member, userID int) error {
+	if err := uc.repo.event.Delete(ctx, oldEvent.EventID); err != nil {
+		return err
+	}
+
+	oldValues, _ := json.Marshal(oldEvent)
+	uc.recordEventHistory(ctx, member, domain.ChangeTypeDeleteEvent, oldValues, nil, userID)
+
+	return nil
+}
+
+// getForMember hides events of other members behind a not found error
+func (uc *eventUseCase) getForMember(ctx context.Context, memberID, eventID int) (*domain.MemberEvent, error) {
+	event, err := uc.repo.event.Get(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event.MemberID != memberID {
+		return nil, domain.NewNotFoundError("event")
+	}
+	return event, nil
+}
+
+func (uc *eventUseCase) validateEvent(ctx context.Context, event *domain.MemberEvent, member *domain.Member) error {
+	if !domain.IsValidEventType(event.EventType) {
+		return domain.NewValidationError("error.event.invalid_type")
+	}
+	if event.EventType == domain.EventTypeCustom {
+		if event.CustomType == nil || strings.TrimSpace(*event.CustomType) == "" {
+			return domain.NewValidationError("error.event.custom_type_required")
+		}
+		customType := strings.TrimSpace(*event.CustomType)
+		event.CustomType = &customType
+	} else {
+		event.CustomType = nil
+	}
+
+	var err error
+	if event.DateCalendar, err = domain.NormalizeCalendar(event.DateCalendar, event.Date); err != nil {
+		return err
+	}
+	event.Date, event.DateQualifier, event.DateEnd, err = domain.NormalizeDate(event.Date, event.DateQualifier, event.DateEnd, event.DateCalendar)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.validator.place.InTree(ctx, member.TreeID, event.PlaceID); err != nil {
+		return err
+	}
+
+	seen := make(map[int]bool, len(event.ParticipantIDs))
+	participantIDs := make([]int, 0, len(event.ParticipantIDs))
+	for _, participantID := range event.ParticipantIDs {
+		if seen[participantID] {
+			continue
+		}
+		seen[participantID] = true
+
+		if participantID == member.MemberID {
+			return domain.NewValidationError("error.event.invalid_participant")
+		}
+		participant, err := uc.repo.member.Get(ctx, participantID)
+		if err != nil {
+			if domain.IsDomainError(err, domain.ErrCodeNotFound) {
+				return domain.NewValidationError("error.event.invalid_participant")
+			}
+			return err
+		}
+		if participant.TreeID != member.TreeID {
+			return domain.NewValidationError("error.event.invalid_participant")
+		}
+		participantIDs = append(participantIDs, participantID)
+	}
+	event.ParticipantIDs = participantIDs
+
+	return nil
+}
+
+// recordEventHistory versions the event against the member it belongs to
+func (uc *eventUseCase) recordEventHistory(
+	ctx context.Context,
+	member *domain.Member,
+	changeType string,
+	oldValues, newValues json.RawMessage,
+	userID int,
+) {
+	history := &domain.History{
+		MemberID:      member.MemberID,
+		UserID:        userID,
+		ChangeType:    changeType,
+		OldValues:     oldValues,
+		NewValues:     newValues,
+		MemberVersion: member.Version,
+	}
+
+	if err := uc.repo.history.Create(ctx, history); err != nil {
+		slog.Error("create history for event", "error", err, "member_id", member.MemberID, "change_type", changeType)
+	}
+}
+
+func (uc *eventUseCase) calculateAndRecordScores(ctx context.Context, event *domain.MemberEvent, member *domain.Member, userID int) error {
+	scores := []domain.Score{
+		{UserID: userID, MemberID: member.MemberID, FieldName: eventFieldName(event, ""), Points: domain.PointsEvent, MemberVersion: member.Version},
+	}
+
+	if event.Date != nil {
+		scores = append(scores, domain.Score{UserID: userID, MemberID: member.MemberID, FieldName: eventFieldName(event, "date"), Points: domain.PointsEventDate, MemberVersion: member.Version})
+	}
+
+	if event.PlaceID != nil {
+		scores = append(scores, domain.Score{UserID: userID, MemberID: member.MemberID, FieldName: eventFieldName(event, "place"), Points: domain.PointsEventPlace, MemberVersion: member.Version})
+	}
+
+	if event.Description != nil && *event.Description != "" {
+		scores = append(scores, domain.Score{UserID: userID, MemberID: member.MemberID, FieldName: eventFieldName(event, "description"), Points: domain.PointsEventDescription, MemberVersion: member.Version})
+	}
+
+	return uc.repo.score.Create(ctx, scores...)
+}
+
+func (uc *eventUseCase) updateScores(ctx context.Context, oldEvent, newEvent *domain.MemberEvent, member *domain.Member, userID int) error {
+	var scores []domain.Score
+
+	if oldEvent.Date == nil && newEvent.Date != nil {
+		scores = append(scores, domain.Score{UserID: userID, MemberID: member.MemberID, FieldName: eventFieldName(newEvent, "date"), Points: domain.PointsEventDate, MemberVersion: member.Version})
+	}
+
+	if oldEvent.PlaceID == nil && newEvent.PlaceID != nil {
+		scores = append(scores, domain.Score{UserID: userID, MemberID: member.MemberID, FieldName: eventFieldName(newEvent, "place"), Points: domain.PointsEventPlace, MemberVersion: member.Version})
+	}
+
+	if (oldEvent.Description == nil || *oldEvent.Description == "") && newEvent.Description != nil && *newEvent.Description != "" {
+		scores = append(scores, domain.Score{UserID: userID, MemberID: member.MemberID, FieldName: eventFieldName(newEvent, "description"), Points: domain.PointsEventDescription, MemberVersion: member.Version})
+	}
+
+	return uc.repo.score.Create(ctx, scores...)
+}
+
+// eventFieldName keys scores per event so filling the same field on two
+// events is rewarded twice
+func eventFieldName(event *domain.MemberEvent, field string) string {
+	if field == "" {
+		return fmt.Sprintf("event_%d", event.EventID)
+	}
+	return fmt.Sprintf("event_%d_%s", event.EventID, field)
+}
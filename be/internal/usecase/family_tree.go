@@ -14,15 +14,17 @@ type familyTreeUseCaseRepo struct {
 }
 
 type familyTreeUseCase struct {
-	repo familyTreeUseCaseRepo
+	repo  familyTreeUseCaseRepo
+	audit auditLog
 }
 
-func NewFamilyTreeUseCase(treeRepo FamilyTreeRepository, userRepo UserRepository) *familyTreeUseCase {
+func NewFamilyTreeUseCase(treeRepo FamilyTreeRepository, userRepo UserRepository, auditRepo AuditRepository) *familyTreeUseCase {
 	return &familyTreeUseCase{
 		repo: familyTreeUseCaseRepo{
 			tree: treeRepo,
 			user: userRepo,
 		},
+		audit: auditLog{repo: auditRepo},
 	}
 }
 
@@ -54,8 +56,70 @@ func (uc *familyTreeUseCase) EnsureAccess(ctx context.Context, treeID, userID in
 	return nil
 }
 
-func (uc *familyTreeUseCase) Invite(ctx context.Context, treeID, inviterUserID int, inviteeEmail string, message *string, expiresAt *time.Time) (*domain.FamilyTreeInvitation, error) {
-	if err := uc.EnsureAccess(ctx, treeID, inviterUserID); err != nil {
+// Role returns the user's role in the tree, a tree the user isn't a member
+// of is not found
+func (uc *familyTreeUseCase) Role(ctx context.Context, treeID, userID int) (string, error) {
+	return uc.repo.tree.GetRole(ctx, treeID, userID)
+}
+
+// EnsurePermission allows members whose role in the tree grants the permission
+func (uc *familyTreeUseCase) EnsurePermission(ctx context.Context, treeID, userID int, permission string) error {
+	role, err := uc.repo.tree.GetRole(ctx, treeID, userID)
+	if err != nil {
+		return err
+	}
+	if !domain.TreeRoleAllows(role, permission) {
+		return domain.NewForbiddenError("error.family_tree.permission_denied")
+	}
+	return nil
+}
+
+// UpdateNameSettings sets how full names are built in each language of the tree
+func (uc *familyTreeUseCase) UpdateNameSettings(ctx context.Context, treeID, userID int, settings *domain.NameSettings) error {
+	if err := uc.EnsurePermission(ctx, treeID, userID, domain.TreePermissionManage); err != nil {
+		return err
+	}
+	for _, format := range settings.Formats {
+		if !domain.IsValidNameFormat(format) {
+			return domain.NewValidationError("error.family_tree.invalid_name_format")
+		}
+	}
+	if settings.NasabDepth < 0 || settings.NasabDepth > domain.MaxLineageDepth {
+		return domain.NewValidationError("error.family_tree.invalid_nasab_depth")
+	}
+	return uc.repo.tree.UpdateNameSettings(ctx, treeID, settings)
+}
+
+func (uc *familyTreeUseCase) GetPrivacyPolicy(ctx context.Context, treeID, userID int) (*domain.PrivacyPolicy, error) {
+	if err := uc.EnsureAccess(ctx, treeID, userID); err != nil {
+		return nil, err
+	}
+	return uc.repo.tree.GetPrivacyPolicy(ctx, treeID)
+}
+
+// UpdatePrivacyPolicy replaces the visibility rules of the tree, nil restores
+// the default ones
+func (uc *familyTreeUseCase) UpdatePrivacyPolicy(ctx context.Context, treeID, userID int, policy *domain.PrivacyPolicy) error {
+	if err := uc.EnsurePermission(ctx, treeID, userID, domain.TreePermissionManage); err != nil {
+		return err
+	}
+	if policy != nil {
+		if err := policy.Validate(); err != nil {
+			return err
+		}
+	}
+	return uc.repo.tree.UpdatePrivacyPolicy(ctx, treeID, policy)
+}
+
+// Invite asks a user to join the tree with a role, editor when none is given
+func (uc *familyTreeUseCase) Invite(ctx context.Context, treeID, inviterUserID int, inviteeEmail, role string, message *string, expiresAt *time.Time) (*domain.FamilyTreeInvitation, error) {
+	if err := uc.EnsurePermission(ctx, treeID, inviterUserID, domain.TreePermissionShare); err != nil {
+		return nil, err
+	}
+	if role == "" {
+		role = domain.TreeRoleEditor
+	}
+	if err := validateMemberRole(role); err != nil {
 		return nil, err
 	}
 
@@ -82,16 +146,164 @@ func (uc *familyTreeUseCase) Invite(ctx context.Context, treeID, inviterUserID i
 		InviterUserID: inviterUserID,
 		InviteeUserID: &invitee.UserID,
 		InviteeEmail:  invitee.Email,
+		Role:          role,
 		Message:       message,
 		ExpiresAt:     expiresAt,
 	}
 	if err := uc.repo.tree.CreateInvitation(ctx, invitation); err != nil {
 		return nil, err
 	}
+
+	uc.audit.record(ctx, &domain.AuditEvent{
+		ActorUserID: &inviterUserID,
+		Action:      domain.AuditActionInvitationSent,
+		TargetType:  domain.AuditTargetInvitation,
+		TargetID:    auditID(invitation.InvitationID),
+		TreeID:      &treeID,
+		Details:     auditDetails(map[string]any{"invitee_user_id": invitee.UserID, "role": role}),
+	})
 	return invitation, nil
 }
 
+// ListMemberships returns who has access to the tree, any member may see it
+func (uc *familyTreeUseCase) ListMemberships(ctx context.Context, treeID, userID int) ([]*domain.FamilyTreeMembership, error) {
+	if err := uc.EnsureAccess(ctx, treeID, userID); err != nil {
+		return nil, err
+	}
+	return uc.repo.tree.ListMemberships(ctx, treeID)
+}
+
+// UpdateMembershipRole makes another member of the tree an editor or a viewer,
+// the owner's role only changes by transferring ownership. Share links the
+// member made stop working once their role can't share the tree.
+func (uc *familyTreeUseCase) UpdateMembershipRole(ctx context.Context, treeID, userID, memberUserID int, role string) error {
+	if err := uc.EnsurePermission(ctx, treeID, userID, domain.TreePermissionManage); err != nil {
+		return err
+	}
+	if err := validateMemberRole(role); err != nil {
+		return err
+	}
+	if memberUserID == userID {
+		return domain.NewValidationError("error.membership.owner_role")
+	}
+	revoked, err := uc.repo.tree.UpdateMembershipRole(ctx, treeID, memberUserID, role)
+	if err != nil {
+		return err
+	}
+
+	uc.audit.record(ctx, &domain.AuditEvent{
+		ActorUserID: &userID,
+		Action:      domain.AuditActionMembershipRoleChanged,
+		TargetType:  domain.AuditTargetMembership,
+		TargetID:    auditID(memberUserID),
+		TreeID:      &treeID,
+		Details: auditDetails(map[string]any{
+			"role":                role,
+			"revoked_share_links": revoked.ShareLinks,
+		}),
+	})
+	return nil
+}
+
+// RemoveMembership takes the tree away from another member, the share links
+// and calendar feeds they made for it stop working with it
+func (uc *familyTreeUseCase) RemoveMembership(ctx context.Context, treeID, userID, memberUserID int) error {
+	if err := uc.EnsurePermission(ctx, treeID, userID, domain.TreePermissionManage); err != nil {
+		return err
+	}
+	if memberUserID == userID {
+		return domain.NewValidationError("error.membership.owner_cannot_leave")
+	}
+	revoked, err := uc.repo.tree.DeleteMembership(ctx, treeID, memberUserID)
+	if err != nil {
+		return err
+	}
+
+	uc.audit.record(ctx, &domain.AuditEvent{
+		ActorUserID: &userID,
+		Action:      domain.AuditActionMembershipRemoved,
+		TargetType:  domain.AuditTargetMembership,
+		TargetID:    auditID(memberUserID),
+		TreeID:      &treeID,
+		Details: auditDetails(map[string]any{
+			"revoked_share_links":    revoked.ShareLinks,
+			"revoked_calendar_feeds": revoked.CalendarFeeds,
+		}),
+	})
+	return nil
+}
+
+// Leave gives up the user's own access to the tree with the share links and
+// calendar feeds they made for it. The owner has to transfer ownership first
+// so a tree always has one.
+func (uc *familyTreeUseCase) Leave(ctx context.Context, treeID, userID int) error {
+	role, err := uc.repo.tree.GetRole(ctx, treeID, userID)
+	if err != nil {
+		return err
+	}
+	if role == domain.TreeRoleOwner {
+		return domain.NewValidationError("error.membership.owner_cannot_leave")
+	}
+	revoked, err := uc.repo.tree.DeleteMembership(ctx, treeID, userID)
+	if err != nil {
+		return err
+	}
+
+	uc.audit.record(ctx, &domain.AuditEvent{
+		ActorUserID: &userID,
+		Action:      domain.AuditActionMembershipLeft,
+		TargetType:  domain.AuditTargetMembership,
+		TargetID:    auditID(userID),
+		TreeID:      &treeID,
+		Details: auditDetails(map[string]any{
+			"revoked_share_links":    revoked.ShareLinks,
+			"revoked_calendar_feeds": revoked.CalendarFeeds,
+		}),
+	})
+	return nil
+}
+
+// TransferOwnership makes another member the owner of the tree, the owner
+// stays on as an editor and the share links they made stop working
+func (uc *familyTreeUseCase) TransferOwnership(ctx context.Context, treeID, userID, newOwnerUserID int) error {
+	if err := uc.EnsurePermission(ctx, treeID, userID, domain.TreePermissionManage); err != nil {
+		return err
+	}
+	if newOwnerUserID == userID {
+		return domain.NewValidationError("error.membership.already_owner")
+	}
+	revoked, err := uc.repo.tree.TransferOwnership(ctx, treeID, userID, newOwnerUserID)
+	if err != nil {
+		return err
+	}
+
+	uc.audit.record(ctx, &domain.AuditEvent{
+		ActorUserID: &userID,
+		Action:      domain.AuditActionOwnershipTransferred,
+		TargetType:  domain.AuditTargetMembership,
+		TargetID:    auditID(newOwnerUserID),
+		TreeID:      &treeID,
+		Details: auditDetails(map[string]any{
+			"previous_owner_user_id": userID,
+			"revoked_share_links":    revoked.ShareLinks,
+		}),
+	})
+	return nil
+}
+
+// validateMemberRole allows the roles a membership can be given, ownership is
+// only transferred
+func validateMemberRole(role string) error {
+	if role != domain.TreeRoleEditor && role != domain.TreeRoleViewer {
+		return domain.NewValidationError("error.membership.invalid_role")
+	}
+	return nil
+}
+
 func (uc *familyTreeUseCase) ListTreeInvitations(ctx context.Context, treeID, userID int) ([]*domain.FamilyTreeInvitation, error) {
+	if err := uc.EnsurePermission(ctx, treeID, userID, domain.TreePermissionShare); err != nil {
+		return nil, err
+	}
 	return uc.repo.tree.ListTreeInvitations(ctx, treeID, userID)
 }
 
@@ -100,40 +312,132 @@ func (uc *familyTreeUseCase) ListMyInvitations(ctx context.Context, userID int)
 }
 
 func (uc *familyTreeUseCase) AcceptInvitation(ctx context.Context, invitationID, userID int) error {
-	return uc.repo.tree.RespondToInvitation(ctx, invitationID, userID, true)
+	return uc.respondToInvitation(ctx, invitationID, userID, true)
 }
 
 func (uc *familyTreeUseCase) DeclineInvitation(ctx context.Context, invitationID, userID int) error {
-	return uc.repo.tree.RespondToInvitation(ctx, invitationID, userID, false)
+	return uc.respondToInvitation(ctx, invitationID, userID, false)
 }
 
-func (uc *familyTreeUseCase) CreateShareLink(ctx context.Context, treeID, userID int, expiresAt *time.Time, maxVisits *int) (*domain.FamilyTreeShareLink, error) {
-	if err := uc.EnsureAccess(ctx, treeID, userID); err != nil {
+func (uc *familyTreeUseCase) respondToInvitation(ctx context.Context, invitationID, userID int, accept bool) error {
+	treeID, err := uc.repo.tree.RespondToInvitation(ctx, invitationID, userID, accept)
+	if err != nil {
+		return err
+	}
+
+	action := domain.AuditActionInvitationDeclined
+	if accept {
+		action = domain.AuditActionInvitationAccepted
+	}
+	uc.audit.record(ctx, &domain.AuditEvent{
+		ActorUserID: &userID,
+		Action:      action,
+		TargetType:  domain.AuditTargetInvitation,
+		TargetID:    auditID(invitationID),
+		TreeID:      &treeID,
+	})
+	return nil
+}
+
+func (uc *familyTreeUseCase) CreateShareLink(ctx context.Context, treeID, userID int, expiresAt *time.Time, maxVisits *int, livingPolicy string) (*domain.FamilyTreeShareLink, error) {
+	if err := uc.EnsurePermission(ctx, treeID, userID, domain.TreePermissionShare); err != nil {
 		return nil, err
 	}
 	if maxVisits != nil && *maxVisits <= 0 {
 		return nil, domain.NewValidationError("error.validation.max_visits_positive")
 	}
+	if livingPolicy == "" {
+		livingPolicy = domain.LivingPolicyRedact
+	}
+	if !domain.IsValidLivingPolicy(livingPolicy) {
+		return nil, domain.NewValidationError("error.share_link.invalid_living_policy")
+	}
 	link := &domain.FamilyTreeShareLink{
-		TreeID:    treeID,
-		CreatedBy: userID,
-		ExpiresAt: expiresAt,
-		MaxVisits: maxVisits,
+		TreeID:       treeID,
+		CreatedBy:    userID,
+		ExpiresAt:    expiresAt,
+		MaxVisits:    maxVisits,
+		LivingPolicy: livingPolicy,
 	}
 	if err := uc.repo.tree.CreateShareLink(ctx, link); err != nil {
 		return nil, err
 	}
+
+	uc.audit.record(ctx, &domain.AuditEvent{
+		ActorUserID: &userID,
+		Action:      domain.AuditActionShareLinkCreated,
+		TargetType:  domain.AuditTargetShareLink,
+		TargetID:    auditID(link.ShareID),
+		TreeID:      &treeID,
+		Details: auditDetails(map[string]any{
+			"expires_at":    expiresAt,
+			"max_visits":    maxVisits,
+			"living_policy": livingPolicy,
+		}),
+	})
 	return link, nil
 }
 
 func (uc *familyTreeUseCase) ListShareLinks(ctx context.Context, treeID, userID int) ([]*domain.FamilyTreeShareLink, error) {
+	if err := uc.EnsurePermission(ctx, treeID, userID, domain.TreePermissionShare); err != nil {
+		return nil, err
+	}
 	return uc.repo.tree.ListShareLinks(ctx, treeID, userID)
 }
 
+func (uc *familyTreeUseCase) UpdateShareLinkPolicy(ctx context.Context, treeID, shareID, userID int, livingPolicy string) error {
+	if !domain.IsValidLivingPolicy(livingPolicy) {
+		return domain.NewValidationError("error.share_link.invalid_living_policy")
+	}
+	if err := uc.EnsurePermission(ctx, treeID, userID, domain.TreePermissionShare); err != nil {
+		return err
+	}
+	if err := uc.repo.tree.UpdateShareLinkPolicy(ctx, treeID, shareID, userID, livingPolicy); err != nil {
+		return err
+	}
+
+	uc.audit.record(ctx, &domain.AuditEvent{
+		ActorUserID: &userID,
+		Action:      domain.AuditActionShareLinkUpdated,
+		TargetType:  domain.AuditTargetShareLink,
+		TargetID:    auditID(shareID),
+		TreeID:      &treeID,
+		Details:     auditDetails(map[string]any{"living_policy": livingPolicy}),
+	})
+	return nil
+}
+
 func (uc *familyTreeUseCase) RevokeShareLink(ctx context.Context, treeID, shareID, userID int) error {
-	return uc.repo.tree.RevokeShareLink(ctx, treeID, shareID, userID)
+	if err := uc.EnsurePermission(ctx, treeID, userID, domain.TreePermissionShare); err != nil {
+		return err
+	}
+	if err := uc.repo.tree.RevokeShareLink(ctx, treeID, shareID, userID); err != nil {
+		return err
+	}
+
+	uc.audit.record(ctx, &domain.AuditEvent{
+		ActorUserID: &userID,
+		Action:      domain.AuditActionShareLinkRevoked,
+		TargetType:  domain.AuditTargetShareLink,
+		TargetID:    auditID(shareID),
+		TreeID:      &treeID,
+	})
+	return nil
 }
 
+// ConsumeShareLink counts a visit to a share link, visitors are anonymous
 func (uc *familyTreeUseCase) ConsumeShareLink(ctx context.Context, token string) (*domain.FamilyTreeShareLink, error) {
-	return uc.repo.tree.ConsumeShareLink(ctx, token)
+	link, err := uc.repo.tree.ConsumeShareLink(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.audit.record(ctx, &domain.AuditEvent{
+		Action:     domain.AuditActionShareLinkVisited,
+		TargetType: domain.AuditTargetShareLink,
+		TargetID:   auditID(link.ShareID),
+		TreeID:     &link.TreeID,
+		Details:    auditDetails(map[string]any{"visit_count": link.VisitCount}),
+	})
+	return link, nil
 }
@@ -0,0 +1,379 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/escalopa/family-tree/internal/domain"
+)
+
+type (
+	familyUnitUseCaseRepo struct {
+		graph   FamilyGraphRepository
+		member  MemberRepository
+		history HistoryRepository
+	}
+
+	familyUnitUseCaseValidator struct {
+		marriage MarriageValidator
+	}
+
+	familyUnitUseCase struct {
+		repo      familyUnitUseCaseRepo
+		validator familyUnitUseCaseValidator
+		tx        TransactionManager
+	}
+)
+
+func NewFamilyUnitUseCase(
+	graphRepo FamilyGraphRepository,
+	memberRepo MemberRepository,
+	historyRepo HistoryRepository,
+	txManager TransactionManager,
+	marriageValidator MarriageValidator,
+) *familyUnitUseCase {
+	return &familyUnitUseCase{
+		repo: familyUnitUseCaseRepo{
+			graph:   graphRepo,
+			member:  memberRepo,
+			history: historyRepo,
+		},
+		validator: familyUnitUseCaseValidator{
+			marriage: marriageValidator,
+		},
+		tx: txManager,
+	}
+}
+
+func (uc *familyUnitUseCase) Get(ctx context.Context, treeID, familyUnitID int) (*domain.FamilyUnit, error) {
+	unit, err := uc.repo.graph.GetFamilyUnit(ctx, familyUnitID)
+	if err != nil {
+		return nil, err
+	}
+	if unit.TreeID != treeID {
+		return nil, domain.NewNotFoundError("family_unit")
+	}
+	return unit, nil
+}
+
+func (uc *familyUnitUseCase) Create(ctx context.Context, unit *domain.FamilyUnit, userID int) error {
+	partners, err := uc.getPartners(ctx, unit)
+	if err != nil {
+		return err
+	}
+
+	existing, err := uc.repo.graph.ListFamilyUnitsByPartnerID(ctx, unit.PartnerIDs[0])
+	if err != nil {
+		return err
+	}
+	for _, other := range existing {
+		if slices.Equal(sortedIDs(other.PartnerIDs), sortedIDs(unit.PartnerIDs)) {
+			return domain.NewConflictError("error.family_unit.already_exists", nil)
+		}
+	}
+
+	if err := uc.validateUnit(ctx, unit, partners); err != nil {
+		return err
+	}
+
+	// the marriage state only matters for a couple still together, an ended
+	// marriage is recorded beside the remarriage that followed it
+	if len(partners) == 2 {
+		validate := uc.validator.marriage.Permitted
+		if unit.Status == domain.FamilyStatusActive || unit.Status == domain.FamilyStatusUnknown {
+			validate = uc.validator.marriage.Create
+		}
+		if err := validate(ctx, partners[0].MemberID, partners[1].MemberID); err != nil {
+			return err
+		}
+	}
+
+	return uc.tx.Do(ctx, func(txCtx context.Context) error {
+		if err := uc.repo.graph.CreateFamilyUnit(txCtx, unit); err != nil {
+			return err
+		}
+
+		newValues, _ := json.Marshal(unit)
+		uc.recordFamilyUnitHistory(txCtx, unit.PartnerIDs, domain.ChangeTypeAddFamilyUnit, nil, newValues, userID)
+		return nil
+	})
+}
+
+// Update edits the relationship, status and dates of a unit, partners are
+// fixed once the unit exists. A unit mirroring a spouse record takes its type,
+// dates and divorce from that record
+func (uc *familyUnitUseCase) Update(ctx context.Context, unit *domain.FamilyUnit, userID int) error {
+	oldUnit, err := uc.Get(ctx, unit.TreeID, unit.FamilyUnitID)
+	if err != nil {
+		return err
+	}
+
+	unit.Source = oldUnit.Source
+	unit.SourceSpouseID = oldUnit.SourceSpouseID
+	unit.PartnerIDs = oldUnit.PartnerIDs
+	unit.ChildIDs = oldUnit.ChildIDs
+	unit.ChildRelations = oldUnit.ChildRelations
+
+	if oldUnit.Source == domain.FamilyUnitSourceSpouse {
+		if unit.RelationshipType != oldUnit.RelationshipType ||
+			!sameDate(unit.StartDate, oldUnit.StartDate) || !sameDate(unit.EndDate, oldUnit.EndDate) ||
+			(unit.Status != oldUnit.Status && (unit.Status == domain.FamilyStatusDivorced || oldUnit.Status == domain.FamilyStatusDivorced)) {
+			return domain.NewValidationError("error.family_unit.managed_by_spouse")
+		}
+	}
+
+	partners, err := uc.getMembers(ctx, unit.PartnerIDs)
+	if err != nil {
+		return err
+	}
+	if err := uc.validateUnit(ctx, unit, partners); err != nil {
+		return err
+	}
+
+	return uc.tx.Do(ctx, func(txCtx context.Context) error {
+		if err := uc.repo.graph.UpdateFamilyUnit(txCtx, unit); err != nil {
+			return err
+		}
+
+		oldValues, _ := json.Marshal(oldUnit)
+		newValues, _ := json.Marshal(unit)
+		uc.recordFamilyUnitHistory(txCtx, unit.PartnerIDs, domain.ChangeTypeUpdateFamilyUnit, oldValues, newValues, userID)
+		return nil
+	})
+}
+
+// Delete removes a unit created through the API, units built from spouse
+// records and parents go away with them
+func (uc *familyUnitUseCase) Delete(ctx context.Context, treeID, familyUnitID, userID int) error {
+	oldUnit, err := uc.Get(ctx, treeID, familyUnitID)
+	if err != nil {
+		return err
+	}
+	if oldUnit.Source != domain.FamilyUnitSourceManual {
+		return domain.NewValidationError("error.family_unit.not_manual")
+	}
+	if len(oldUnit.ChildIDs) > 0 {
+		return domain.NewConflictError("error.family_unit.has_children", nil)
+	}
+
+	return uc.tx.Do(ctx, func(txCtx context.Context) error {
+		if err := uc.repo.graph.DeleteFamilyUnit(txCtx, familyUnitID); err != nil {
+			return err
+		}
+
+		oldValues, _ := json.Marshal(oldUnit)
+		uc.recordFamilyUnitHistory(txCtx, oldUnit.PartnerIDs, domain.ChangeTypeDeleteFamilyUnit, oldValues, nil, userID)
+		return nil
+	})
+}
+
+// AddChild links a child to a unit, biological children follow their father
+// and mother and are not linked by hand
+func (uc *familyUnitUseCase) AddChild(ctx context.Context, treeID, familyUnitID, childID int, relationType string, userID int) error {
+	if !domain.IsValidChildRelation(relationType) {
+		return domain.NewValidationError("error.family_unit.invalid_relation_type")
+	}
+	if relationType == domain.ChildRelationBiological {
+		return domain.NewValidationError("error.family_unit.biological_via_parents")
+	}
+
+	unit, err := uc.Get(ctx, treeID, familyUnitID)
+	if err != nil {
+		return err
+	}
+	if _, ok := unit.ChildRelations[childID]; ok {
+		return domain.NewConflictError("error.family_unit.child_exists", nil)
+	}
+
+	child, err := uc.repo.member.Get(ctx, childID)
+	if err != nil {
+		if domain.IsDomainError(err, domain.ErrCodeNotFound) {
+			return domain.NewValidationError("error.family_unit.invalid_child")
+		}
+		return err
+	}
+	if child.TreeID != unit.TreeID || unit.HasPartner(childID) {
+		return domain.NewValidationError("error.family_unit.invalid_child")
+	}
+
+	partners, err := uc.getMembers(ctx, unit.PartnerIDs)
+	if err != nil {
+		return err
+	}
+	if childBirth := child.BirthRange(); childBirth != nil {
+		for _, partner := range partners {
+			if partnerBirth := partner.BirthRange(); partnerBirth != nil && childBirth.IsDefinitelyBefore(partnerBirth) {
+				return domain.NewValidationError("error.family_unit.child_before_partner_birth")
+			}
+		}
+	}
+
+	link := domain.FamilyUnitChild{FamilyUnitID: familyUnitID, ChildID: childID, RelationType: relationType}
+	return uc.tx.Do(ctx, func(txCtx context.Context) error {
+		if err := uc.repo.graph.AddFamilyUnitChild(txCtx, familyUnitID, childID, relationType); err != nil {
+			return err
+		}
+
+		newValues, _ := json.Marshal(link)
+		uc.recordFamilyUnitHistory(txCtx, append([]int{childID}, unit.PartnerIDs...), domain.ChangeTypeAddChild, nil, newValues, userID)
+		return nil
+	})
+}
+
+func (uc *familyUnitUseCase) RemoveChild(ctx context.Context, treeID, familyUnitID, childID, userID int) error {
+	unit, err := uc.Get(ctx, treeID, familyUnitID)
+	if err != nil {
+		return err
+	}
+	relationType, ok := unit.ChildRelations[childID]
+	if !ok {
+		return domain.NewNotFoundError("family_unit_child")
+	}
+	if relationType == domain.ChildRelationBiological {
+		return domain.NewValidationError("error.family_unit.biological_via_parents")
+	}
+
+	link := domain.FamilyUnitChild{FamilyUnitID: familyUnitID, ChildID: childID, RelationType: relationType}
+	return uc.tx.Do(ctx, func(txCtx context.Context) error {
+		if err := uc.repo.graph.RemoveFamilyUnitChild(txCtx, familyUnitID, childID); err != nil {
+			return err
+		}
+
+		oldValues, _ := json.Marshal(link)
+		uc.recordFamilyUnitHistory(txCtx, append([]int{childID}, unit.PartnerIDs...), domain.ChangeTypeRemoveChild, oldValues, nil, userID)
+		return nil
+	})
+}
+
+// getPartners loads the partners of a new unit and orders a couple husband
+// first, the way spouse records are
+func (uc *familyUnitUseCase) getPartners(ctx context.Context, unit *domain.FamilyUnit) ([]*domain.Member, error) {
+	partnerIDs := make([]int, 0, len(unit.PartnerIDs))
+	for _, partnerID := range unit.PartnerIDs {
+		if !slices.Contains(partnerIDs, partnerID) {
+			partnerIDs = append(partnerIDs, partnerID)
+		}
+	}
+	if len(partnerIDs) == 0 || len(partnerIDs) > 2 {
+		return nil, domain.NewValidationError("error.family_unit.invalid_partners")
+	}
+
+	partners, err := uc.getMembers(ctx, partnerIDs)
+	if err != nil {
+		if domain.IsDomainError(err, domain.ErrCodeNotFound) {
+			return nil, domain.NewValidationError("error.family_unit.invalid_partners")
+		}
+		return nil, err
+	}
+	for _, partner := range partners {
+		if partner.TreeID != unit.TreeID {
+			return nil, domain.NewValidationError("error.family_unit.invalid_partners")
+		}
+	}
+
+	if len(partners) == 2 {
+		if partners[0].Gender == "F" {
+			partners[0], partners[1] = partners[1], partners[0]
+		}
+		if partners[0].Gender != "M" {
+			return nil, domain.NewValidationError("error.member.invalid_father")
+		}
+		if partners[1].Gender != "F" {
+			return nil, domain.NewValidationError("error.member.invalid_mother")
+		}
+	}
+
+	unit.PartnerIDs = make([]int, 0, len(partners))
+	for _, partner := range partners {
+		unit.PartnerIDs = append(unit.PartnerIDs, partner.MemberID)
+	}
+	return partners, nil
+}
+
+func (uc *familyUnitUseCase) getMembers(ctx context.Context, memberIDs []int) ([]*domain.Member, error) {
+	members := make([]*domain.Member, 0, len(memberIDs))
+	for _, memberID := range memberIDs {
+		member, err := uc.repo.member.Get(ctx, memberID)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+func (uc *familyUnitUseCase) validateUnit(ctx context.Context, unit *domain.FamilyUnit, partners []*domain.Member) error {
+	if !domain.IsValidRelationshipType(unit.RelationshipType) {
+		return domain.NewValidationError("error.family_unit.invalid_relationship_type")
+	}
+	if !domain.IsValidFamilyStatus(unit.Status) {
+		return domain.NewValidationError("error.family_unit.invalid_status")
+	}
+	if unit.EndDate != nil && unit.Status == domain.FamilyStatusActive {
+		return domain.NewValidationError("error.family_unit.end_date_while_active")
+	}
+
+	start := domain.NewDateRange(unit.StartDate, domain.DateQualifierExact, nil)
+	end := domain.NewDateRange(unit.EndDate, domain.DateQualifierExact, nil)
+	if start != nil && end != nil && end.IsDefinitelyBefore(start) {
+		return domain.NewValidationError("error.family_unit.end_before_start")
+	}
+
+	if len(partners) == 2 {
+		return uc.validator.marriage.MarriageDate(ctx, partners[0].MemberID, partners[1].MemberID, start)
+	}
+	if len(partners) == 1 {
+		if birth := partners[0].BirthRange(); birth != nil && start != nil && start.IsDefinitelyBefore(birth) {
+			return domain.NewValidationError("error.family_unit.start_before_partner_birth")
+		}
+	}
+	return nil
+}
+
+// recordFamilyUnitHistory versions the change against every member it touches
+func (uc *familyUnitUseCase) recordFamilyUnitHistory(
+	ctx context.Context,
+	memberIDs []int,
+	changeType string,
+	oldValues, newValues json.RawMessage,
+	userID int,
+) {
+	histories := make([]*domain.History, 0, len(memberIDs))
+	for _, memberID := range memberIDs {
+		memberVersion := 0
+		if member, err := uc.repo.member.Get(ctx, memberID); err != nil {
+			slog.Error("get member for history", "error", err, "member_id", memberID)
+		} else {
+			memberVersion = member.Version
+		}
+
+		histories = append(histories, &domain.History{
+			MemberID:      memberID,
+			UserID:        userID,
+			ChangeType:    changeType,
+			OldValues:     oldValues,
+			NewValues:     newValues,
+			MemberVersion: memberVersion,
+		})
+	}
+
+	if err := uc.repo.history.CreateBatch(ctx, histories...); err != nil {
+		slog.Error("create batch history for family unit", "error", err, "member_ids", memberIDs, "change_type", changeType)
+	}
+}
+
+func sortedIDs(ids []int) []int {
+	sorted := slices.Clone(ids)
+	slices.Sort(sorted)
+	return sorted
+}
+
+func sameDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+
+	"github.com/escalopa/family-tree/internal/domain"
+)
+
+// HistoryDiff compares the member right after two of its history entries,
+// either one may come first. Both versions are stripped for the viewer before
+// they're compared, so a field the viewer may not see never shows up as
+// changed.
+func (uc *memberUseCase) HistoryDiff(ctx context.Context, treeID, memberID, fromID, toID int, viewer domain.Viewer) (*domain.MemberDiff, error) {
+	member, err := uc.getRecordedInTree(ctx, treeID, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := uc.repo.history.ListByMemberIDUntil(ctx, memberID, max(fromID, toID))
+	if err != nil {
+		return nil, err
+	}
+	until := func(historyID int) ([]*domain.History, error) {
+		i := slices.IndexFunc(history, func(entry *domain.History) bool { return entry.HistoryID == historyID })
+		if i < 0 {
+			return nil, domain.NewValidationError("error.history.member_mismatch")
+		}
+		return history[:i+1], nil
+	}
+	fromHistory, err := until(fromID)
+	if err != nil {
+		return nil, err
+	}
+	toHistory, err := until(toID)
+	if err != nil {
+		return nil, err
+	}
+
+	privacy, err := uc.privacy.For(ctx, treeID, viewer, nil)
+	if err != nil {
+		return nil, err
+	}
+	from, to := domain.ReplayMemberHistory(fromHistory), domain.ReplayMemberHistory(toHistory)
+	privacy.ApplyVersion(from, member)
+	privacy.ApplyVersion(to, member)
+
+	diff := domain.DiffMemberVersions(memberID, from, to)
+
+	partners := make(map[int]*domain.Member)
+	for i := range diff.Spouses {
+		change := &diff.Spouses[i]
+		partner, ok := partners[change.PartnerID]
+		if !ok {
+			partner, err = uc.getRecordedInTree(ctx, treeID, change.PartnerID)
+			if err != nil && !domain.IsDomainError(err, domain.ErrCodeNotFound) {
+				return nil, err
+			}
+			partners[change.PartnerID] = partner
+		}
+		if partner == nil {
+			continue
+		}
+		change.PartnerGender = partner.Gender
+		if privacy.CanSee(domain.PrivacyFieldNames, partner) {
+			change.PartnerNames = partner.Names
+		}
+	}
+
+	return diff, nil
+}
+
+// getRecordedInTree returns a member of the tree whether or not it's in the trash
+func (uc *memberUseCase) getRecordedInTree(ctx context.Context, treeID, memberID int) (*domain.Member, error) {
+	member, err := uc.repo.member.Get(ctx, memberID)
+	if domain.IsDomainError(err, domain.ErrCodeNotFound) {
+		member, err = uc.repo.member.GetDeleted(ctx, memberID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if member.TreeID != treeID {
+		return nil, domain.NewNotFoundError("member")
+	}
+	return member, nil
+}
@@ -14,19 +14,22 @@ type (
 	}
 
 	languageUseCase struct {
-		repo languageUseCaseRepo
+		repo  languageUseCaseRepo
+		audit auditLog
 	}
 )
 
 func NewLanguageUseCase(
 	langRepo LanguageRepository,
 	langPrefRepo UserLanguagePreferenceRepository,
+	auditRepo AuditRepository,
 ) *languageUseCase {
 	return &languageUseCase{
 		repo: languageUseCaseRepo{
 			lang:     langRepo,
 			langPref: langPrefRepo,
 		},
+		audit: auditLog{repo: auditRepo},
 	}
 }
 
@@ -43,12 +46,23 @@ func (uc *languageUseCase) List(ctx context.Context, activeOnly bool) ([]*domain
 	return uc.repo.lang.GetAll(ctx, filter)
 }
 
-func (uc *languageUseCase) ToggleActive(ctx context.Context, code string, isActive bool) error {
+func (uc *languageUseCase) ToggleActive(ctx context.Context, code string, isActive bool, userID int) error {
 	if !i18n.IsSupported(code) {
 		return domain.NewNotFoundError("language")
 	}
 
-	return uc.repo.lang.ToggleActive(ctx, code, isActive)
+	if err := uc.repo.lang.ToggleActive(ctx, code, isActive); err != nil {
+		return err
+	}
+
+	uc.audit.record(ctx, &domain.AuditEvent{
+		ActorUserID: &userID,
+		Action:      domain.AuditActionLanguageToggled,
+		TargetType:  domain.AuditTargetLanguage,
+		TargetID:    &code,
+		Details:     auditDetails(map[string]any{"is_active": isActive}),
+	})
+	return nil
 }
 
 func (uc *languageUseCase) UpdatePreference(ctx context.Context, pref *domain.UserLanguagePreference) error {
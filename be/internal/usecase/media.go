@@ -0,0 +1,415 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"mime"
+	"path/filepath"
+
+	"github.com/escalopa/family-tree/internal/domain"
+)
+
+type (
+	mediaUseCaseRepo struct {
+		media   MediaRepository
+		member  MemberRepository
+		history HistoryRepository
+		score   ScoreRepository
+	}
+
+	mediaUseCaseValidator struct {
+		place PlaceValidator
+	}
+
+	mediaUseCase struct {
+		repo      mediaUseCaseRepo
+		validator mediaUseCaseValidator
+		privacy   treePrivacy
+		s3Client  S3Client
+		tx        TransactionManager
+	}
+)
+
+func NewMediaUseCase(
+	mediaRepo MediaRepository,
+	memberRepo MemberRepository,
+	historyRepo HistoryRepository,
+	scoreRepo ScoreRepository,
+	treeRepo FamilyTreeRepository,
+	s3Client S3Client,
+	txManager TransactionManager,
+	placeValidator PlaceValidator,
+) *mediaUseCase {
+	return &mediaUseCase{
+		repo: mediaUseCaseRepo{
+			media:   mediaRepo,
+			member:  memberRepo,
+			history: historyRepo,
+			score:   scoreRepo,
+		},
+		validator: mediaUseCaseValidator{
+			place: placeValidator,
+		},
+		privacy:  treePrivacy{tree: treeRepo, member: memberRepo},
+		s3Client: s3Client,
+		tx:       txManager,
+	}
+}
+
+// Upload stores the image and creates an untagged media item, history is
+// written once members are tagged since it is kept per member
+func (uc *mediaUseCase) Upload(ctx context.Context, media *domain.Media, data []byte, filename string) error {
+	if err := uc.validateMedia(ctx, media); err != nil {
+		return err
+	}
+
+	key, err := uc.s3Client.UploadImage(ctx, data, filename)
+	if err != nil {
+		return err
+	}
+	media.StorageKey = key
+
+	if err := uc.repo.media.Create(ctx, media); err != nil {
+		if deleteErr := uc.s3Client.DeleteImage(ctx, key); deleteErr != nil {
+			slog.Error("rollback S3 upload after media create error", "error", deleteErr, "key", key, "original_error", err)
+		}
+		return err
+	}
+	media.Tags = []domain.MediaTag{}
+	return nil
+}
+
+func (uc *mediaUseCase) Get(ctx context.Context, treeID, mediaID int, viewer domain.Viewer) (*domain.Media, error) {
+	media, err := uc.getInTree(ctx, treeID, mediaID)
+	if err != nil {
+		return nil, err
+	}
+
+	privacy, err := uc.privacy.For(ctx, treeID, viewer, nil)
+	if err != nil {
+		return nil, err
+	}
+	tagged := make(map[int]*domain.Member, len(media.Tags))
+	for _, tag := range media.Tags {
+		member, err := uc.repo.member.Get(ctx, tag.MemberID)
+		if err != nil {
+			return nil, err
+		}
+		tagged[member.MemberID] = member
+	}
+	if !canSeeMedia(media, tagged, privacy) {
+		return nil, domain.NewNotFoundError("media")
+	}
+	return media, nil
+}
+
+func (uc *mediaUseCase) GetFile(ctx context.Context, treeID, mediaID int, viewer domain.Viewer) ([]byte, string, error) {
+	media, err := uc.Get(ctx, treeID, mediaID, viewer)
+	if err != nil {
+		return nil, "", err
+	}
+
+	data, err := uc.s3Client.GetImage(ctx, media.StorageKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, mime.TypeByExtension(filepath.Ext(media.StorageKey)), nil
+}
+
+func (uc *mediaUseCase) List(ctx context.Context, treeID int, viewer domain.Viewer) ([]*domain.Media, error) {
+	items, err := uc.repo.media.ListByTreeID(ctx, treeID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.filterVisible(ctx, treeID, items, viewer)
+}
+
+func (uc *mediaUseCase) ListByMember(ctx context.Context, memberID int, viewer domain.Viewer) ([]*domain.Media, error) {
+	member, err := uc.repo.member.Get(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := uc.repo.media.ListByMemberID(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.filterVisible(ctx, member.TreeID, items, viewer)
+}
+
+func (uc *mediaUseCase) Update(ctx context.Context, media *domain.Media, userID int) error {
+	oldMedia, err := uc.getInTree(ctx, media.TreeID, media.MediaID)
+	if err != nil {
+		return err
+	}
+	if err := uc.validateMedia(ctx, media); err != nil {
+		return err
+	}
+
+	media.StorageKey = oldMedia.StorageKey
+	media.UploadedBy = oldMedia.UploadedBy
+	media.Tags = oldMedia.Tags
+	media.CreatedAt = oldMedia.CreatedAt
+
+	return uc.tx.Do(ctx, func(txCtx context.Context) error {
+		if err := uc.repo.media.Update(txCtx, media); err != nil {
+			return err
+		}
+
+		oldValues, _ := json.Marshal(oldMedia)
+		newValues, _ := json.Marshal(media)
+		return uc.recordTaggedHistory(txCtx, media.Tags, domain.ChangeTypeUpdateMedia, oldValues, newValues, userID)
+	})
+}
+
+func (uc *mediaUseCase) Delete(ctx context.Context, treeID, mediaID, userID int) error {
+	oldMedia, err := uc.getInTree(ctx, treeID, mediaID)
+	if err != nil {
+		return err
+	}
+
+	err = uc.tx.Do(ctx, func(txCtx context.Context) error {
+		if err := uc.repo.media.Delete(txCtx, mediaID); err != nil {
+			return err
+		}
+
+		oldValues, _ := json.Marshal(oldMedia)
+		return uc.recordTaggedHistory(txCtx, oldMedia.Tags, domain.ChangeTypeDeleteMedia, oldValues, nil, userID)
+	})
+	if err != nil {
+		return err
+	}
+
+	// A member may still use the photo as profile picture
+	inUse, err := uc.repo.media.IsKeyInUse(ctx, oldMedia.StorageKey)
+	if err != nil {
+		slog.Warn("check media usage before delete", "error", err, "media_id", mediaID, "key", oldMedia.StorageKey)
+		return nil
+	}
+	if !inUse {
+		if err := uc.s3Client.DeleteImage(ctx, oldMedia.StorageKey); err != nil {
+			slog.Warn("delete media from S3", "error", err, "media_id", mediaID, "key", oldMedia.StorageKey)
+		}
+	}
+	return nil
+}
+
+func (uc *mediaUseCase) Tag(ctx context.Context, treeID, mediaID int, tag domain.MediaTag, userID int) error {
+	media, err := uc.getInTree(ctx, treeID, mediaID)
+	if err != nil {
+		return err
+	}
+	if tag.Face != nil && !tag.Face.Valid() {
+		return domain.NewValidationError("error.media.invalid_face")
+	}
+
+	member, err := uc.repo.member.Get(ctx, tag.MemberID)
+	if err != nil {
+		return err
+	}
+	if member.TreeID != media.TreeID {
+		return domain.NewValidationError("error.media.member_not_in_tree")
+	}
+
+	var oldTag *domain.MediaTag
+	for i := range media.Tags {
+		if media.Tags[i].MemberID == tag.MemberID {
+			oldTag = &media.Tags[i]
+		}
+	}
+
+	return uc.tx.Do(ctx, func(txCtx context.Context) error {
+		if err := uc.repo.media.UpsertTag(txCtx, mediaID, tag); err != nil {
+			return err
+		}
+
+		var oldValues json.RawMessage
+		if oldTag != nil {
+			oldValues, _ = json.Marshal(map[string]any{"media_id": mediaID, "face": oldTag.Face})
+		}
+		newValues, _ := json.Marshal(map[string]any{"media_id": mediaID, "face": tag.Face})
+		return uc.repo.history.Create(txCtx, &domain.History{
+			MemberID:      member.MemberID,
+			UserID:        userID,
+			ChangeType:    domain.ChangeTypeTagMedia,
+			OldValues:     oldValues,
+			NewValues:     newValues,
+			MemberVersion: member.Version,
+		})
+	})
+}
+
+func (uc *mediaUseCase) Untag(ctx context.Context, treeID, mediaID, memberID, userID int) error {
+	media, err := uc.getInTree(ctx, treeID, mediaID)
+	if err != nil {
+		return err
+	}
+	if !media.HasTag(memberID) {
+		return domain.NewNotFoundError("media_tag")
+	}
+
+	member, err := uc.repo.member.Get(ctx, memberID)
+	if err != nil {
+		return err
+	}
+
+	return uc.tx.Do(ctx, func(txCtx context.Context) error {
+		if err := uc.repo.media.DeleteTag(txCtx, mediaID, memberID); err != nil {
+			return err
+		}
+
+		oldValues, _ := json.Marshal(map[string]any{"media_id": mediaID})
+		return uc.repo.history.Create(txCtx, &domain.History{
+			MemberID:      member.MemberID,
+			UserID:        userID,
+			ChangeType:    domain.ChangeTypeUntagMedia,
+			OldValues:     oldValues,
+			NewValues:     nil,
+			MemberVersion: member.Version,
+		})
+	})
+}
+
+// SetProfilePicture points the member picture at a photo they are tagged in,
+// both share the stored image so neither deletes it while the other uses it
+func (uc *mediaUseCase) SetProfilePicture(ctx context.Context, treeID, memberID, mediaID, userID int) error {
+	media, err := uc.getInTree(ctx, treeID, mediaID)
+	if err != nil {
+		return err
+	}
+	if !media.HasTag(memberID) {
+		return domain.NewValidationError("error.media.member_not_tagged")
+	}
+
+	oldMember, err := uc.repo.member.Get(ctx, memberID)
+	if err != nil {
+		return err
+	}
+	if oldMember.Picture != nil && *oldMember.Picture == media.StorageKey {
+		return nil
+	}
+
+	return uc.tx.Do(ctx, func(txCtx context.Context) error {
+		return uc.setProfilePictureTx(txCtx, media, oldMember, userID)
+	})
+}
+
+func (uc *mediaUseCase) setProfilePictureTx(ctx context.Context, media *domain.Media, oldMember *domain.Member, userID int) error {
+	if err := uc.repo.member.UpdatePicture(ctx, oldMember.MemberID, media.StorageKey); err != nil {
+		return err
+	}
+
+	oldValuesJSON, _ := json.Marshal(map[string]any{"picture": oldMember.Picture})
+	newValuesJSON, _ := json.Marshal(map[string]any{"picture": media.StorageKey, "media_id": media.MediaID})
+	history := &domain.History{
+		MemberID:      oldMember.MemberID,
+		UserID:        userID,
+		ChangeType:    domain.ChangeTypeAddPicture,
+		OldValues:     oldValuesJSON,
+		NewValues:     newValuesJSON,
+		MemberVersion: oldMember.Version + 1,
+	}
+	if err := uc.repo.history.Create(ctx, history); err != nil {
+		return err
+	}
+
+	// The replaced picture is kept until purged, history can roll back to it
+	if oldMember.Picture != nil && *oldMember.Picture != "" {
+		return uc.repo.media.SupersedePicture(ctx, media.TreeID, *oldMember.Picture)
+	}
+
+	return uc.repo.score.Create(ctx, domain.Score{
+		UserID:        userID,
+		MemberID:      oldMember.MemberID,
+		FieldName:     "picture",
+		Points:        domain.PointsPicture,
+		MemberVersion: oldMember.Version + 1,
+	})
+}
+
+func (uc *mediaUseCase) getInTree(ctx context.Context, treeID, mediaID int) (*domain.Media, error) {
+	media, err := uc.repo.media.Get(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	if media.TreeID != treeID {
+		return nil, domain.NewNotFoundError("media")
+	}
+	return media, nil
+}
+
+func (uc *mediaUseCase) validateMedia(ctx context.Context, media *domain.Media) error {
+	var err error
+	if media.DateCalendar, err = domain.NormalizeCalendar(media.DateCalendar, media.Date); err != nil {
+		return err
+	}
+	media.Date, media.DateQualifier, media.DateEnd, err = domain.NormalizeDate(media.Date, media.DateQualifier, media.DateEnd, media.DateCalendar)
+	if err != nil {
+		return err
+	}
+	return uc.validator.place.InTree(ctx, media.TreeID, media.PlaceID)
+}
+
+func (uc *mediaUseCase) filterVisible(ctx context.Context, treeID int, items []*domain.Media, viewer domain.Viewer) ([]*domain.Media, error) {
+	if len(items) == 0 {
+		return items, nil
+	}
+
+	members, err := uc.repo.member.GetAllByTreeID(ctx, treeID)
+	if err != nil {
+		return nil, err
+	}
+	privacy, err := uc.privacy.For(ctx, treeID, viewer, members)
+	if err != nil {
+		return nil, err
+	}
+	tagged := make(map[int]*domain.Member, len(members))
+	for _, m := range members {
+		tagged[m.MemberID] = m
+	}
+
+	visible := make([]*domain.Media, 0, len(items))
+	for _, media := range items {
+		if canSeeMedia(media, tagged, privacy) {
+			visible = append(visible, media)
+		}
+	}
+	return visible, nil
+}
+
+// recordTaggedHistory versions a media change on every member tagged in it
+func (uc *mediaUseCase) recordTaggedHistory(ctx context.Context, tags []domain.MediaTag, changeType string, oldValues, newValues json.RawMessage, userID int) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	histories := make([]*domain.History, 0, len(tags))
+	for _, tag := range tags {
+		member, err := uc.repo.member.Get(ctx, tag.MemberID)
+		if err != nil {
+			return err
+		}
+		histories = append(histories, &domain.History{
+			MemberID:      member.MemberID,
+			UserID:        userID,
+			ChangeType:    changeType,
+			OldValues:     oldValues,
+			NewValues:     newValues,
+			MemberVersion: member.Version,
+		})
+	}
+	return uc.repo.history.CreateBatch(ctx, histories...)
+}
+
+// canSeeMedia applies the picture rule of the policy to the gallery: a photo
+// is only visible when the viewer may see the picture of everyone tagged in it
+func canSeeMedia(media *domain.Media, tagged map[int]*domain.Member, privacy *domain.Privacy) bool {
+	for _, tag := range media.Tags {
+		member := tagged[tag.MemberID]
+		if member != nil && !privacy.CanSee(domain.PrivacyFieldPicture, member) {
+			return false
+		}
+	}
+	return true
+}
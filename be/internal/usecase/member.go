@@ -5,12 +5,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"maps"
 	"mime"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/google/uuid"
 )
 
 type (
@@ -18,18 +22,27 @@ type (
 		marriage     MarriageValidator
 		birthDate    BirthDateValidator
 		relationship RelationshipValidator
+		place        PlaceValidator
 	}
 
 	memberUseCaseRepo struct {
-		member  MemberRepository
-		spouse  SpouseRepository
-		history HistoryRepository
-		score   ScoreRepository
+		member      MemberRepository
+		spouse      SpouseRepository
+		history     HistoryRepository
+		score       ScoreRepository
+		media       MediaRepository
+		citation    CitationRepository
+		customField CustomFieldRepository
+		tree        FamilyTreeRepository
+		spelling    NameSpellingRepository
+		changeset   ChangesetRepository
 	}
 
 	memberUseCase struct {
 		repo      memberUseCaseRepo
 		validator memberUseCaseValidator
+		privacy   treePrivacy
+		past      treePast
 		s3Client  S3Client
 		tx        TransactionManager
 	}
@@ -40,15 +53,24 @@ func NewMemberUseCase(
 	spouseRepo SpouseRepository,
 	historyRepo HistoryRepository,
 	scoreRepo ScoreRepository,
+	mediaRepo MediaRepository,
+	citationRepo CitationRepository,
+	customFieldRepo CustomFieldRepository,
+	treeRepo FamilyTreeRepository,
+	spellingRepo NameSpellingRepository,
+	changesetRepo ChangesetRepository,
 	s3Client S3Client,
 	txManager TransactionManager,
 	marriageValidator MarriageValidator,
 	birthDateValidator BirthDateValidator,
 	relationshipValidator RelationshipValidator,
+	placeValidator PlaceValidator,
 ) *memberUseCase {
 	return &memberUseCase{
-		repo:      memberUseCaseRepo{memberRepo, spouseRepo, historyRepo, scoreRepo},
-		validator: memberUseCaseValidator{marriageValidator, birthDateValidator, relationshipValidator},
+		repo:      memberUseCaseRepo{memberRepo, spouseRepo, historyRepo, scoreRepo, mediaRepo, citationRepo, customFieldRepo, treeRepo, spellingRepo, changesetRepo},
+		validator: memberUseCaseValidator{marriageValidator, birthDateValidator, relationshipValidator, placeValidator},
+		privacy:   treePrivacy{tree: treeRepo, member: memberRepo},
+		past:      treePast{member: memberRepo, spouse: spouseRepo, history: historyRepo},
 		s3Client:  s3Client,
 		tx:        txManager,
 	}
@@ -61,11 +83,38 @@ func (uc *memberUseCase) Create(ctx context.Context, member *domain.Member, user
 			WithParams(map[string]string{"language": "all", "code": "all"})
 	}
 
+	if err := uc.suggestNames(ctx, member, nil); err != nil {
+		return err
+	}
+
+	if err := normalizeNameParts(member); err != nil {
+		return err
+	}
+
+	if err := normalizeMemberDates(member); err != nil {
+		return err
+	}
+
+	if !domain.IsValidLivingStatus(member.LivingStatus) {
+		return domain.NewValidationError("error.validation.invalid_living_status")
+	}
+	if member.LivingStatus == domain.LivingStatusLiving && member.DateOfDeath != nil {
+		return domain.NewValidationError("error.validation.living_with_death_date")
+	}
+
+	if err := uc.validator.place.InTree(ctx, member.TreeID, member.BirthPlaceID, member.DeathPlaceID, member.BurialPlaceID); err != nil {
+		return err
+	}
+
+	if err := uc.validateCustomValues(ctx, member.TreeID, member.MemberID, member.CustomFields); err != nil {
+		return err
+	}
+
 	if err := uc.validator.relationship.CheckParents(ctx, member.MemberID, member.FatherID, member.MotherID); err != nil {
 		return err
 	}
 
-	if err := uc.validator.birthDate.Create(ctx, member.DateOfBirth, member.FatherID, member.MotherID); err != nil {
+	if err := uc.validator.birthDate.Create(ctx, member.BirthRange(), member.FatherID, member.MotherID); err != nil {
 		return err
 	}
 
@@ -115,6 +164,41 @@ func (uc *memberUseCase) Update(ctx context.Context, member *domain.Member, expe
 			WithParams(map[string]string{"language": "all", "code": "all"})
 	}
 
+	if err := uc.suggestNames(ctx, member, oldMember); err != nil {
+		return err
+	}
+
+	// Name parts left out of the update are kept as they are
+	if member.NameParts == nil {
+		member.NameParts = maps.Clone(oldMember.NameParts)
+	}
+	if err := normalizeNameParts(member); err != nil {
+		return err
+	}
+
+	if err := normalizeMemberDates(member); err != nil {
+		return err
+	}
+
+	if !domain.IsValidLivingStatus(member.LivingStatus) {
+		return domain.NewValidationError("error.validation.invalid_living_status")
+	}
+	if member.LivingStatus == domain.LivingStatusLiving && member.DateOfDeath != nil {
+		return domain.NewValidationError("error.validation.living_with_death_date")
+	}
+
+	if err := uc.validator.place.InTree(ctx, oldMember.TreeID, member.BirthPlaceID, member.DeathPlaceID, member.BurialPlaceID); err != nil {
+		return err
+	}
+
+	// Custom values left out of the update are kept as they are
+	if member.CustomFields == nil {
+		member.CustomFields = maps.Clone(oldMember.CustomFields)
+	}
+	if err := uc.validateCustomValues(ctx, oldMember.TreeID, member.MemberID, member.CustomFields); err != nil {
+		return err
+	}
+
 	if oldMember.Gender != member.Gender {
 		spouses, err := uc.repo.spouse.GetByMemberID(ctx, member.MemberID)
 		if err != nil {
@@ -129,11 +213,11 @@ func (uc *memberUseCase) Update(ctx context.Context, member *domain.Member, expe
 		return err
 	}
 
-	if err := uc.validator.birthDate.Update(ctx, member.MemberID, member.DateOfBirth); err != nil {
+	if err := uc.validator.birthDate.Update(ctx, member.MemberID, member.BirthRange()); err != nil {
 		return err
 	}
 
-	if err := uc.validator.birthDate.Create(ctx, member.DateOfBirth, member.FatherID, member.MotherID); err != nil {
+	if err := uc.validator.birthDate.Create(ctx, member.BirthRange(), member.FatherID, member.MotherID); err != nil {
 		return err
 	}
 
@@ -186,28 +270,12 @@ func (uc *memberUseCase) Delete(ctx context.Context, memberID, userID int) error
 		return domain.NewConflictError("error.member.has_children", map[string]string{"count": fmt.Sprintf("%d", len(children))})
 	}
 
-	var pictureURL *string
-	err = uc.tx.Do(ctx, func(txCtx context.Context) error {
-		var err error
-		pictureURL, err = uc.deleteMemberTx(txCtx, memberID, oldMember, userID)
-		return err
+	return uc.tx.Do(ctx, func(txCtx context.Context) error {
+		return uc.deleteMemberTx(txCtx, memberID, oldMember, userID)
 	})
-
-	if err != nil {
-		return err
-	}
-
-	// Delete from S3 after successful transaction
-	if pictureURL != nil && *pictureURL != "" {
-		if err := uc.s3Client.DeleteImage(ctx, *pictureURL); err != nil {
-			slog.Error("delete member picture from storage", "error", err, "member_id", memberID, "picture", *pictureURL)
-		}
-	}
-
-	return nil
 }
 
-func (uc *memberUseCase) deleteMemberTx(ctx context.Context, memberID int, oldMember *domain.Member, userID int) (*string, error) {
+func (uc *memberUseCase) deleteMemberTx(ctx context.Context, memberID int, oldMember *domain.Member, userID int) error {
 	oldValuesJSON, _ := json.Marshal(oldMember)
 	history := &domain.History{
 		MemberID:      memberID,
@@ -218,38 +286,105 @@ func (uc *memberUseCase) deleteMemberTx(ctx context.Context, memberID int, oldMe
 		MemberVersion: oldMember.Version + 1,
 	}
 	if err := uc.repo.history.Create(ctx, history); err != nil {
-		return nil, err
+		return err
 	}
 
 	pictureURL, err := uc.repo.member.Delete(ctx, memberID)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return pictureURL, nil
+	// The picture is kept so restoring the member brings it back
+	return uc.supersedePicture(ctx, oldMember.TreeID, pictureURL)
 }
 
 func (uc *memberUseCase) Get(ctx context.Context, memberID int) (*domain.Member, error) {
 	return uc.repo.member.Get(ctx, memberID)
 }
 
-func (uc *memberUseCase) ListChildren(ctx context.Context, parentID int) ([]*domain.Member, error) {
-	return uc.repo.member.GetChildrenByParentID(ctx, parentID)
+// ListParents returns the father and mother of the member that are set
+func (uc *memberUseCase) ListParents(ctx context.Context, member *domain.Member, viewer domain.Viewer) ([]*domain.Member, error) {
+	var parents []*domain.Member
+	for _, parentID := range []*int{member.FatherID, member.MotherID} {
+		if parentID == nil {
+			continue
+		}
+		parent, err := uc.repo.member.Get(ctx, *parentID)
+		if err != nil {
+			return nil, err
+		}
+		parents = append(parents, parent)
+	}
+	return parents, uc.applyPrivacy(ctx, viewer, parents)
+}
+
+func (uc *memberUseCase) ListChildren(ctx context.Context, parentID int, viewer domain.Viewer) ([]*domain.Member, error) {
+	children, err := uc.repo.member.GetChildrenByParentID(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	return children, uc.applyPrivacy(ctx, viewer, children)
+}
+
+func (uc *memberUseCase) ListSiblings(ctx context.Context, memberID int, viewer domain.Viewer) ([]*domain.Member, error) {
+	siblings, err := uc.repo.member.GetSiblingsByMemberID(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+	return siblings, uc.applyPrivacy(ctx, viewer, siblings)
+}
+
+// applyPrivacy strips members of a single tree for the viewer
+func (uc *memberUseCase) applyPrivacy(ctx context.Context, viewer domain.Viewer, members []*domain.Member) error {
+	if len(members) == 0 {
+		return nil
+	}
+	privacy, err := uc.privacy.For(ctx, members[0].TreeID, viewer, nil)
+	if err != nil {
+		return err
+	}
+	for _, member := range members {
+		privacy.ApplyMember(member)
+	}
+	return nil
 }
 
-func (uc *memberUseCase) ListSiblings(ctx context.Context, memberID int) ([]*domain.Member, error) {
-	return uc.repo.member.GetSiblingsByMemberID(ctx, memberID)
+func (uc *memberUseCase) CitationCounts(ctx context.Context, memberID int) (map[string]int, error) {
+	return uc.repo.citation.CountByMemberFields(ctx, memberID)
 }
 
-func (uc *memberUseCase) List(ctx context.Context, filter domain.MemberFilter, cursor *string, limit int) ([]*domain.Member, *string, error) {
-	return uc.repo.member.List(ctx, filter, cursor, limit)
+func (uc *memberUseCase) List(ctx context.Context, filter domain.MemberFilter, viewer domain.Viewer, cursor *string, limit int) ([]*domain.Member, *string, error) {
+	members, nextCursor, err := uc.repo.member.List(ctx, filter, cursor, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := uc.applyPrivacy(ctx, viewer, members); err != nil {
+		return nil, nil, err
+	}
+	return members, nextCursor, nil
 }
 
-func (uc *memberUseCase) ListHistory(ctx context.Context, memberID int, cursor *string, limit int) ([]*domain.HistoryWithUser, *string, error) {
-	return uc.repo.history.GetByMemberID(ctx, memberID, cursor, limit)
+// ListHistory lists the recorded changes of the member, stripped for the viewer
+// the way the member itself is
+func (uc *memberUseCase) ListHistory(ctx context.Context, member *domain.Member, viewer domain.Viewer, cursor *string, limit int) ([]*domain.HistoryWithUser, *string, error) {
+	privacy, err := uc.privacy.For(ctx, member.TreeID, viewer, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	histories, nextCursor, err := uc.repo.history.GetByMemberID(ctx, member.MemberID, cursor, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, history := range histories {
+		privacy.ApplyHistory(history, member)
+	}
+
+	return histories, nextCursor, nil
 }
 
-func (uc *memberUseCase) Rollback(ctx context.Context, memberID, historyID, userID int) error {
+func (uc *memberUseCase) Rollback(ctx context.Context, treeID, memberID, historyID, userID int) error {
 	history, err := uc.repo.history.Get(ctx, historyID)
 	if err != nil {
 		return err
@@ -257,18 +392,30 @@ func (uc *memberUseCase) Rollback(ctx context.Context, memberID, historyID, user
 	if history.MemberID != memberID {
 		return domain.NewValidationError("error.history.member_mismatch")
 	}
-	if history.ChangeType != domain.ChangeTypeUpdate {
+	switch history.ChangeType {
+	case domain.ChangeTypeDelete:
+		return uc.restoreMember(ctx, treeID, memberID, history.OldValues, userID)
+	case domain.ChangeTypeAddSpouse, domain.ChangeTypeRemoveSpouse, domain.ChangeTypeUpdateSpouse:
+		return uc.rollbackSpouse(ctx, treeID, history, userID)
+	case domain.ChangeTypeAddPicture, domain.ChangeTypeDeletePicture:
+		return uc.rollbackPicture(ctx, treeID, history, userID)
+	case domain.ChangeTypeUpdate, domain.ChangeTypeUpdateBio, domain.ChangeTypeUpdateNotes:
+	default:
 		return domain.NewValidationError("error.history.rollback_unsupported")
 	}
 	if len(history.OldValues) == 0 {
 		return domain.NewValidationError("error.history.rollback_missing_snapshot")
 	}
 
-	currentMember, err := uc.repo.member.Get(ctx, memberID)
+	currentMember, err := uc.getInTree(ctx, treeID, memberID)
 	if err != nil {
 		return err
 	}
 
+	if history.ChangeType != domain.ChangeTypeUpdate {
+		return uc.rollbackText(ctx, history, currentMember.Version, userID)
+	}
+
 	var rollbackMember domain.Member
 	if err := json.Unmarshal(history.OldValues, &rollbackMember); err != nil {
 		return domain.NewValidationError("error.history.rollback_invalid_snapshot")
@@ -281,6 +428,123 @@ func (uc *memberUseCase) Rollback(ctx context.Context, memberID, historyID, user
 	return uc.Update(ctx, &rollbackMember, currentMember.Version, userID)
 }
 
+// RevertChangeset undoes every member and spouse change of a changeset in one
+// transaction, taking back the points it awarded. The changeset is claimed
+// first, so a concurrent revert waits and then fails. It fails when a member
+// it touched was changed afterwards, or when it holds changes that can't be
+// undone, instead of reverting part of it. The revert is a changeset of its own.
+func (uc *memberUseCase) RevertChangeset(ctx context.Context, treeID int, changesetID string, userID int) (*domain.Changeset, *domain.AsOfRestore, error) {
+	changeset, err := uc.repo.changeset.Get(ctx, treeID, changesetID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if changeset.RevertedAt != nil {
+		return nil, nil, domain.NewConflictError("error.changeset.already_reverted", nil)
+	}
+
+	revertID := domain.ChangesetFromContext(ctx)
+	if revertID == nil || *revertID == changesetID {
+		id := uuid.New().String()
+		revertID = &id
+		ctx = domain.ContextWithChangeset(ctx, id)
+	}
+	changeset.RevertedBy = &userID
+	changeset.RevertedIn = revertID
+
+	result := &domain.AsOfRestore{}
+	err = uc.tx.Do(ctx, func(txCtx context.Context) error {
+		// Marking it reverted locks the changeset until the revert commits
+		if err := uc.repo.changeset.MarkReverted(txCtx, changeset); err != nil {
+			return err
+		}
+		plan, err := uc.planRevert(txCtx, treeID, changesetID)
+		if err != nil {
+			return err
+		}
+		failFast := func(_, _ *int, err error) error {
+			return err
+		}
+		if err := uc.applyRestore(txCtx, treeID, plan, result, failFast, userID); err != nil {
+			return err
+		}
+		return uc.repo.score.DeleteByChangeset(txCtx, changesetID)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return changeset, result, nil
+}
+
+// planRevert works out how to bring back the members and spouses a changeset
+// changed as they were before it
+func (uc *memberUseCase) planRevert(ctx context.Context, treeID int, changesetID string) (*domain.RestorePlan, error) {
+	history, err := uc.repo.history.ListByChangeset(ctx, treeID, changesetID)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return nil, domain.NewValidationError("error.changeset.empty")
+	}
+
+	memberIDs := make(map[int]bool)
+	spouseIDs := make(map[int]bool)
+	for _, entry := range history {
+		if !slices.Contains(domain.AsOfChangeTypes, entry.ChangeType) {
+			return nil, domain.
+				NewValidationError("error.changeset.not_revertible").
+				WithParams(map[string]string{"change_type": entry.ChangeType})
+		}
+		memberIDs[entry.MemberID] = true
+		for _, values := range []json.RawMessage{entry.OldValues, entry.NewValues} {
+			var spouse struct {
+				SpouseID int `json:"spouse_id"`
+			}
+			if json.Unmarshal(values, &spouse) == nil && spouse.SpouseID != 0 {
+				spouseIDs[spouse.SpouseID] = true
+			}
+		}
+	}
+
+	// A member changed by someone else since the changeset started, in between
+	// its requests or after them, would lose that change
+	changed, err := uc.repo.history.ListMemberIDsChangedAfter(ctx, slices.Collect(maps.Keys(memberIDs)), history[0].HistoryID, changesetID)
+	if err != nil {
+		return nil, err
+	}
+	if len(changed) > 0 {
+		ids := make([]string, len(changed))
+		for i, memberID := range changed {
+			ids[i] = strconv.Itoa(memberID)
+		}
+		return nil, domain.NewConflictError("error.changeset.conflict", map[string]string{"members": strings.Join(ids, ", ")})
+	}
+
+	current, err := uc.repo.member.GetAllByTreeID(ctx, treeID)
+	if err != nil {
+		return nil, err
+	}
+	spouses, err := uc.repo.spouse.ListAllByTreeID(ctx, treeID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Deleting a member ends its spouse relationships without history of
+	// their own, the ones that ended while the changeset was written go back
+	first, last := history[0].ChangedAt, history[len(history)-1].ChangedAt
+	for _, spouse := range spouses {
+		if spouse.DeletedAt != nil && !spouse.DeletedAt.Before(first) && !spouse.DeletedAt.After(last) &&
+			(memberIDs[spouse.FatherID] || memberIDs[spouse.MotherID]) {
+			spouseIDs[spouse.SpouseID] = true
+		}
+	}
+
+	// History times are stored to the microsecond, the state just before the
+	// first entry is the tree before the changeset
+	state := domain.RewindHistory(treeID, current, spouses, history, first.Add(-time.Microsecond))
+	return state.PlanRevert(current, spouses, memberIDs, spouseIDs), nil
+}
+
 func (uc *memberUseCase) UploadPicture(ctx context.Context, memberID int, data []byte, filename string, userID int) (string, error) {
 	oldMember, err := uc.repo.member.Get(ctx, memberID)
 	if err != nil {
@@ -308,16 +572,6 @@ func (uc *memberUseCase) UploadPicture(ctx context.Context, memberID int, data [
 		return "", err
 	}
 
-	// Delete old picture from S3 after successful transaction
-	if oldMember.Picture != nil && *oldMember.Picture != "" {
-		if err := uc.s3Client.DeleteImage(ctx, *oldMember.Picture); err != nil {
-			slog.Warn("delete old picture from S3",
-				"error", err,
-				"member_id", memberID,
-				"old_picture", *oldMember.Picture)
-		}
-	}
-
 	return newPictureURL, nil
 }
 
@@ -345,6 +599,10 @@ func (uc *memberUseCase) uploadPictureTx(ctx context.Context, memberID int, newP
 		return err
 	}
 
+	if err := uc.supersedePicture(ctx, oldMember.TreeID, oldMember.Picture); err != nil {
+		return err
+	}
+
 	if oldMember.Picture == nil || *oldMember.Picture == "" {
 		scores := []domain.Score{
 			{
@@ -369,30 +627,13 @@ func (uc *memberUseCase) DeletePicture(ctx context.Context, memberID int, userID
 		return err
 	}
 
-	oldPictureURL := member.Picture
-
-	err = uc.tx.Do(ctx, func(txCtx context.Context) error {
-		return uc.deletePictureTx(txCtx, memberID, oldPictureURL, userID)
+	return uc.tx.Do(ctx, func(txCtx context.Context) error {
+		return uc.deletePictureTx(txCtx, member, userID)
 	})
-
-	if err != nil {
-		return err
-	}
-
-	// Delete from S3 after successful transaction
-	if oldPictureURL != nil && *oldPictureURL != "" {
-		if err := uc.s3Client.DeleteImage(ctx, *oldPictureURL); err != nil {
-			slog.Warn("delete picture from S3 after DB update",
-				"error", err,
-				"member_id", memberID,
-				"picture_url", *oldPictureURL)
-		}
-	}
-
-	return nil
 }
 
-func (uc *memberUseCase) deletePictureTx(ctx context.Context, memberID int, oldPictureURL *string, userID int) error {
+func (uc *memberUseCase) deletePictureTx(ctx context.Context, member *domain.Member, userID int) error {
+	memberID, oldPictureURL := member.MemberID, member.Picture
 	if err := uc.repo.member.DeletePicture(ctx, memberID); err != nil {
 		return err
 	}
@@ -416,15 +657,33 @@ func (uc *memberUseCase) deletePictureTx(ctx context.Context, memberID int, oldP
 		return err
 	}
 
-	return nil
+	return uc.supersedePicture(ctx, member.TreeID, oldPictureURL)
+}
+
+// supersedePicture keeps a picture taken off a member in storage until the
+// tree's superseded pictures are purged, so history can roll back to it
+func (uc *memberUseCase) supersedePicture(ctx context.Context, treeID int, key *string) error {
+	if key == nil || *key == "" {
+		return nil
+	}
+	return uc.repo.media.SupersedePicture(ctx, treeID, *key)
 }
 
-func (uc *memberUseCase) GetPicture(ctx context.Context, memberID int) ([]byte, string, error) {
+func (uc *memberUseCase) GetPicture(ctx context.Context, memberID int, viewer domain.Viewer) ([]byte, string, error) {
 	member, err := uc.repo.member.Get(ctx, memberID)
 	if err != nil {
 		return nil, "", err
 	}
 
+	privacy, err := uc.privacy.For(ctx, member.TreeID, viewer, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	// a hidden picture is reported like a missing one
+	if !privacy.CanSee(domain.PrivacyFieldPicture, member) {
+		return nil, "", domain.NewNotFoundError("picture")
+	}
+
 	if member.Picture == nil || *member.Picture == "" {
 		slog.Warn("memberUseCase.GetPicture: picture not found", "member_id", memberID)
 		return nil, "", domain.NewNotFoundError("picture")
@@ -454,10 +713,14 @@ func (uc *memberUseCase) ensureParentSpouseRelationship(ctx context.Context, fat
 	}
 
 	spouse := &domain.Spouse{
-		FatherID:     *fatherID,
-		MotherID:     *motherID,
-		MarriageDate: nil,
-		DivorceDate:  nil,
+		FatherID:              *fatherID,
+		MotherID:              *motherID,
+		MarriageDate:          nil,
+		MarriageDateQualifier: domain.DateQualifierExact,
+		MarriageDateCalendar:  domain.CalendarGregorian,
+		DivorceDate:           nil,
+		DivorceDateQualifier:  domain.DateQualifierExact,
+		DivorceDateCalendar:   domain.CalendarGregorian,
 	}
 
 	if err := uc.repo.spouse.Create(ctx, spouse); err != nil {
@@ -519,6 +782,9 @@ func (uc *memberUseCase) calculateAndRecordScores(ctx context.Context, member *d
 	scores := []domain.Score{}
 
 	for langCode := range member.Names {
+		if slices.Contains(member.SuggestedNames, langCode) {
+			continue
+		}
 		scores = append(scores, domain.Score{
 			UserID:        userID,
 			MemberID:      member.MemberID,
@@ -551,6 +817,10 @@ func (uc *memberUseCase) calculateAndRecordScores(ctx context.Context, member *d
 		scores = append(scores, domain.Score{UserID: userID, MemberID: member.MemberID, FieldName: "profession", Points: domain.PointsProfession, MemberVersion: member.Version})
 	}
 
+	for key := range member.CustomFields {
+		scores = append(scores, domain.Score{UserID: userID, MemberID: member.MemberID, FieldName: domain.CustomFieldName(key), Points: domain.PointsCustomField, MemberVersion: member.Version})
+	}
+
 	return uc.repo.score.Create(ctx, scores...)
 
 }
@@ -558,9 +828,13 @@ func (uc *memberUseCase) calculateAndRecordScores(ctx context.Context, member *d
 func (uc *memberUseCase) updateScores(ctx context.Context, oldMember, newMember *domain.Member, userID int) error {
 	scores := []domain.Score{}
 
+	// A suggested name scores once an editor writes or confirms it
 	for langCode, newName := range newMember.Names {
 		oldName, exists := oldMember.Names[langCode]
-		if newName != "" && (!exists || oldName == "") {
+		if slices.Contains(newMember.SuggestedNames, langCode) {
+			continue
+		}
+		if newName != "" && (!exists || oldName == "" || slices.Contains(oldMember.SuggestedNames, langCode)) {
 			scores = append(scores, domain.Score{
 				UserID:        userID,
 				MemberID:      newMember.MemberID,
@@ -599,81 +873,141 @@ func (uc *memberUseCase) updateScores(ctx context.Context, oldMember, newMember
 		scores = append(scores, domain.Score{UserID: userID, MemberID: newMember.MemberID, FieldName: "profession", Points: domain.PointsProfession, MemberVersion: newMember.Version})
 	}
 
+	for key := range newMember.CustomFields {
+		if _, exists := oldMember.CustomFields[key]; !exists {
+			scores = append(scores, domain.Score{UserID: userID, MemberID: newMember.MemberID, FieldName: domain.CustomFieldName(key), Points: domain.PointsCustomField, MemberVersion: newMember.Version})
+		}
+	}
+
 	return uc.repo.score.Create(ctx, scores...)
 }
 
-func (uc *memberUseCase) Compute(ctx context.Context, member *domain.Member, userRole int) *domain.MemberWithComputed {
+func (uc *memberUseCase) Compute(ctx context.Context, member *domain.Member, viewer domain.Viewer) (*domain.MemberWithComputed, error) {
+	privacy, err := uc.privacy.For(ctx, member.TreeID, viewer, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	computed := &domain.MemberWithComputed{
 		Member: *member,
 	}
 
 	computed.FullNames = uc.buildFullNamesForAllLanguages(ctx, member)
 
-	if member.DateOfBirth != nil {
-		endDate := time.Now()
-		if member.DateOfDeath != nil {
-			endDate = *member.DateOfDeath
-		}
-		age := int(endDate.Sub(*member.DateOfBirth).Hours() / 24 / 365)
-		computed.Age = &age
-	}
+	computed.Age, computed.AgeMin, computed.AgeMax = computeAge(member)
 
 	spouses, _ := uc.repo.spouse.GetByMemberID(ctx, member.MemberID)
 	computed.IsMarried = len(spouses) > 0
 	computed.Spouses = spouses
+	for i := range computed.Spouses {
+		privacy.ApplySpouse(&computed.Spouses[i])
+	}
+	privacy.ApplyComputed(computed)
 
-	if member.Gender == "F" {
-		if userRole < domain.RoleAdmin {
-			computed.Picture = nil
+	return computed, nil
+}
+
+// normalizeNameParts trims the name parts and drops empty ones, parts need a
+// given name in their language
+func normalizeNameParts(member *domain.Member) error {
+	for langCode, parts := range member.NameParts {
+		parts = domain.NameParts{
+			Kunya:  strings.TrimSpace(parts.Kunya),
+			Laqab:  strings.TrimSpace(parts.Laqab),
+			Family: strings.TrimSpace(parts.Family),
 		}
-		if userRole < domain.RoleSuperAdmin {
-			if computed.DateOfBirth != nil {
-				hiddenDate := time.Date(1, computed.DateOfBirth.Month(), computed.DateOfBirth.Day(), 0, 0, 0, 0, time.UTC)
-				computed.DateOfBirth = &hiddenDate
-			}
-			if computed.DateOfDeath != nil {
-				hiddenDate := time.Date(1, computed.DateOfDeath.Month(), computed.DateOfDeath.Day(), 0, 0, 0, 0, time.UTC)
-				computed.DateOfDeath = &hiddenDate
-			}
-			computed.Age = nil
+		if parts.IsEmpty() {
+			delete(member.NameParts, langCode)
+			continue
 		}
+		if _, ok := member.Names[langCode]; !ok {
+			return domain.NewValidationError("error.validation.name_parts_without_name").
+				WithParams(map[string]string{"code": langCode})
+		}
+		member.NameParts[langCode] = parts
 	}
+	return nil
+}
 
-	return computed
+// computeAge returns the age in whole years when the dates pin it down, and
+// otherwise the youngest and oldest the member can be; an open-ended date
+// leaves the matching bound unknown
+func computeAge(member *domain.Member) (*int, *int, *int) {
+	birth := member.BirthRange()
+	if birth == nil {
+		return nil, nil, nil
+	}
+
+	today := time.Now().UTC()
+	end := &domain.DateRange{Earliest: &today, Latest: &today}
+	if death := member.DeathRange(); death != nil {
+		end = death
+	}
+
+	var ageMin, ageMax *int
+	if birth.Latest != nil && end.Earliest != nil {
+		years := max(domain.YearsBetween(*birth.Latest, *end.Earliest), 0)
+		ageMin = &years
+	}
+	if birth.Earliest != nil && end.Latest != nil {
+		years := max(domain.YearsBetween(*birth.Earliest, *end.Latest), 0)
+		ageMax = &years
+	}
+
+	if ageMin != nil && ageMax != nil && *ageMin == *ageMax {
+		return ageMin, nil, nil
+	}
+	return nil, ageMin, ageMax
 }
 
-// buildFullNamesForAllLanguages builds full names in all available languages by tracing through the father's lineage
+// normalizeMemberDates applies the birth and death date qualifiers and calendars
+func normalizeMemberDates(member *domain.Member) error {
+	var err error
+	if member.DateOfBirthCalendar, err = domain.NormalizeCalendar(member.DateOfBirthCalendar, member.DateOfBirth); err != nil {
+		return err
+	}
+	if member.DateOfDeathCalendar, err = domain.NormalizeCalendar(member.DateOfDeathCalendar, member.DateOfDeath); err != nil {
+		return err
+	}
+	member.DateOfBirth, member.DateOfBirthQualifier, member.DateOfBirthEnd, err = domain.NormalizeDate(member.DateOfBirth, member.DateOfBirthQualifier, member.DateOfBirthEnd, member.DateOfBirthCalendar)
+	if err != nil {
+		return err
+	}
+	member.DateOfDeath, member.DateOfDeathQualifier, member.DateOfDeathEnd, err = domain.NormalizeDate(member.DateOfDeath, member.DateOfDeathQualifier, member.DateOfDeathEnd, member.DateOfDeathCalendar)
+	return err
+}
+
+// buildFullNamesForAllLanguages builds the member's full name in each of its languages by tracing through the
+// father's lineage, formatted by the tree's name rules of the language
 // Returns: map[languageCode]fullName
 // Example: {"ar": "محمد أحمد علي", "en": "Muhammad Ahmad Ali", "ru": "Мухаммад Ахмад Али"}
 func (uc *memberUseCase) buildFullNamesForAllLanguages(ctx context.Context, member *domain.Member) map[string]string {
-	namesPerLanguage := make(map[string][]string)
-	for langCode, name := range member.Names {
-		namesPerLanguage[langCode] = []string{name}
+	lineage := []*domain.Member{member}
+	currentFatherID := member.FatherID
+	for currentFatherID != nil && len(lineage) <= domain.MaxLineageDepth {
+		father, err := uc.repo.member.Get(ctx, *currentFatherID)
+		if err != nil {
+			break
+		}
+		lineage = append(lineage, father)
+		currentFatherID = father.FatherID
 	}
 
-	if member.FatherID != nil {
-		currentFatherID := member.FatherID
-		maxDepth := 100 // Prevent infinite loops
-		depth := 0
-
-		for currentFatherID != nil && depth < maxDepth {
-			father, err := uc.repo.member.Get(ctx, *currentFatherID)
-			if err != nil {
-				break
-			}
-
-			for langCode, name := range father.Names {
-				namesPerLanguage[langCode] = append(namesPerLanguage[langCode], name)
-			}
+	return uc.formatFullNames(ctx, member, lineage)
+}
 
-			currentFatherID = father.FatherID
-			depth++
-		}
+// formatFullNames formats the member's full name in each of its languages
+// from its paternal lineage, the member first
+func (uc *memberUseCase) formatFullNames(ctx context.Context, member *domain.Member, lineage []*domain.Member) map[string]string {
+	settings, err := uc.repo.tree.GetNameSettings(ctx, member.TreeID)
+	if err != nil {
+		slog.Error("get name settings for full names", "error", err, "tree_id", member.TreeID)
+		settings = &domain.NameSettings{}
 	}
 
 	fullNames := make(map[string]string)
-	for langCode, names := range namesPerLanguage {
-		fullNames[langCode] = strings.Join(names, " ")
+	for langCode := range member.Names {
+		fullNames[langCode] = domain.FormatFullName(*settings, langCode, lineage)
 	}
 
 	return fullNames
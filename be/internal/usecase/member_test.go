@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/escalopa/family-tree/internal/domain"
+)
+
+func day(year int, month time.Month, d int) *time.Time {
+	t := time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	return &t
+}
+
+func years(n int) *int {
+	return &n
+}
+
+func TestComputeAge(t *testing.T) {
+	// a day more than ten years ago, so a leap day can't shift the age
+	tenYearsAgo := time.Now().UTC().AddDate(-10, 0, -1)
+
+	tests := []struct {
+		name    string
+		member  domain.Member
+		wantAge *int
+		wantMin *int
+		wantMax *int
+	}{
+		{
+			name:   "unknown birth",
+			member: domain.Member{DateOfDeath: day(2000, time.January, 1)},
+		},
+		{
+			name: "exact dates",
+			member: domain.Member{
+				DateOfBirth: day(1950, time.June, 15), DateOfBirthQualifier: domain.DateQualifierExact,
+				DateOfDeath: day(2000, time.June, 14), DateOfDeathQualifier: domain.DateQualifierExact,
+			},
+			wantAge: years(49),
+		},
+		{
+			name:    "living",
+			member:  domain.Member{DateOfBirth: &tenYearsAgo, DateOfBirthQualifier: domain.DateQualifierExact},
+			wantAge: years(10),
+		},
+		{
+			name: "birth year only",
+			member: domain.Member{
+				DateOfBirth: day(1950, time.January, 1), DateOfBirthQualifier: domain.DateQualifierYear, DateOfBirthEnd: day(1950, time.December, 31),
+				DateOfDeath: day(2000, time.June, 1), DateOfDeathQualifier: domain.DateQualifierExact,
+			},
+			wantMin: years(49),
+			wantMax: years(50),
+		},
+		{
+			name: "born after an unknown bound",
+			member: domain.Member{
+				DateOfBirth: day(1950, time.January, 1), DateOfBirthQualifier: domain.DateQualifierAfter,
+				DateOfDeath: day(2000, time.June, 1), DateOfDeathQualifier: domain.DateQualifierExact,
+			},
+			wantMax: years(50),
+		},
+		{
+			name: "death before birth",
+			member: domain.Member{
+				DateOfBirth: day(2000, time.January, 1), DateOfBirthQualifier: domain.DateQualifierExact,
+				DateOfDeath: day(1999, time.January, 1), DateOfDeathQualifier: domain.DateQualifierExact,
+			},
+			wantAge: years(0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			age, ageMin, ageMax := computeAge(&tt.member)
+			for _, check := range []struct {
+				field     string
+				got, want *int
+			}{{"age", age, tt.wantAge}, {"age min", ageMin, tt.wantMin}, {"age max", ageMax, tt.wantMax}} {
+				if (check.got == nil) != (check.want == nil) || check.got != nil && *check.got != *check.want {
+					t.Errorf("%s = %v, want %v", check.field, deref(check.got), deref(check.want))
+				}
+			}
+		})
+	}
+}
+
+func deref(value *int) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+
+	"github.com/escalopa/family-tree/internal/domain"
+)
+
+// Edit updates a member from an editor's form. An edit of an older version
+// fails with a MemberConflict holding the member as it is now and what
+// changed since. With merge the edit is merged with those changes and saved
+// instead, only fields both sides changed differently fail it.
+func (uc *memberUseCase) Edit(ctx context.Context, member *domain.Member, expectedVersion int, merge bool, viewer domain.Viewer) error {
+	// Update normalizes what it saves, the edit is kept as sent for a merge
+	edit := *member
+	edit.Names = maps.Clone(member.Names)
+	edit.NameParts = maps.Clone(member.NameParts)
+	edit.CustomFields = maps.Clone(member.CustomFields)
+	err := uc.Update(ctx, &edit, expectedVersion, viewer.UserID)
+	if !domain.IsDomainError(err, domain.ErrCodeVersionConflict) {
+		if err == nil {
+			*member = edit
+		}
+		return err
+	}
+
+	current, err := uc.repo.member.Get(ctx, member.MemberID)
+	if err != nil {
+		return err
+	}
+	if expectedVersion > current.Version {
+		return domain.NewVersionConflictError()
+	}
+	base, err := uc.snapshotAt(ctx, member.MemberID, expectedVersion)
+	if err != nil {
+		return err
+	}
+
+	if !merge {
+		return uc.versionConflict(ctx, current, base, expectedVersion, nil, viewer)
+	}
+	merged, conflicts := domain.MergeMember(base, current, member)
+	if len(conflicts) > 0 {
+		return uc.versionConflict(ctx, current, base, expectedVersion, conflicts, viewer)
+	}
+	if err := uc.Update(ctx, merged, current.Version, viewer.UserID); err != nil {
+		return err
+	}
+	*member = *merged
+	return nil
+}
+
+// snapshotAt returns the member as it was recorded at the version, nil when
+// the version has no recorded snapshot
+func (uc *memberUseCase) snapshotAt(ctx context.Context, memberID, version int) (*domain.Member, error) {
+	history, err := uc.repo.history.GetSnapshot(ctx, memberID, version)
+	if domain.IsDomainError(err, domain.ErrCodeNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var member domain.Member
+	if err := json.Unmarshal(history.NewValues, &member); err != nil {
+		return nil, domain.NewInternalError(err)
+	}
+	return &member, nil
+}
+
+// versionConflict describes an edit of an older version to the viewer
+func (uc *memberUseCase) versionConflict(ctx context.Context, current, base *domain.Member, expectedVersion int, conflicts []domain.MergeConflict, viewer domain.Viewer) error {
+	history, err := uc.repo.history.ListByMemberIDSinceVersion(ctx, current.MemberID, expectedVersion)
+	if err != nil {
+		return err
+	}
+
+	conflict := domain.NewMemberConflict(conflicts)
+	conflict.Base = base
+	conflict.History = history
+
+	privacy, err := uc.privacy.For(ctx, current.TreeID, viewer, nil)
+	if err != nil {
+		return err
+	}
+	privacy.ApplyConflict(conflict, current)
+
+	if conflict.Current, err = uc.Compute(ctx, current, viewer); err != nil {
+		return err
+	}
+	if conflict.Base != nil {
+		conflict.Changes = domain.DiffFields(conflict.Base, &conflict.Current.Member, domain.MemberDiffSkip...)
+	}
+	return conflict
+}
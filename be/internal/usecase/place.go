@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/escalopa/family-tree/internal/domain"
+)
+
+type (
+	placeUseCaseRepo struct {
+		place  PlaceRepository
+		member MemberRepository
+	}
+
+	placeUseCase struct {
+		repo    placeUseCaseRepo
+		privacy treePrivacy
+	}
+)
+
+func NewPlaceUseCase(placeRepo PlaceRepository, memberRepo MemberRepository, treeRepo FamilyTreeRepository) *placeUseCase {
+	return &placeUseCase{
+		repo: placeUseCaseRepo{
+			place:  placeRepo,
+			member: memberRepo,
+		},
+		privacy: treePrivacy{tree: treeRepo, member: memberRepo},
+	}
+}
+
+func (uc *placeUseCase) Create(ctx context.Context, place *domain.Place) error {
+	if err := validatePlace(place); err != nil {
+		return err
+	}
+	return uc.repo.place.Create(ctx, place)
+}
+
+func (uc *placeUseCase) Get(ctx context.Context, treeID, placeID int) (*domain.Place, error) {
+	place, err := uc.repo.place.Get(ctx, placeID)
+	if err != nil {
+		return nil, err
+	}
+	if place.TreeID != treeID {
+		return nil, domain.NewNotFoundError("place")
+	}
+	return place, nil
+}
+
+func (uc *placeUseCase) List(ctx context.Context, treeID int) ([]*domain.Place, error) {
+	return uc.repo.place.ListByTreeID(ctx, treeID)
+}
+
+func (uc *placeUseCase) Update(ctx context.Context, place *domain.Place) error {
+	if _, err := uc.Get(ctx, place.TreeID, place.PlaceID); err != nil {
+		return err
+	}
+	if err := validatePlace(place); err != nil {
+		return err
+	}
+	return uc.repo.place.Update(ctx, place)
+}
+
+func (uc *placeUseCase) Delete(ctx context.Context, treeID, placeID int) error {
+	if _, err := uc.Get(ctx, treeID, placeID); err != nil {
+		return err
+	}
+	return uc.repo.place.Delete(ctx, placeID)
+}
+
+// GetMap returns the births and deaths that happened at places with
+// coordinates, oldest first. Generations count from 1 for members without
+// parents in the tree, a child is one below its deepest parent
+func (uc *placeUseCase) GetMap(ctx context.Context, filter domain.MapFilter, viewer domain.Viewer) ([]*domain.MapFeature, error) {
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return nil, domain.NewValidationError("error.map.invalid_range")
+	}
+	if filter.MinGeneration != nil && filter.MaxGeneration != nil && *filter.MaxGeneration < *filter.MinGeneration {
+		return nil, domain.NewValidationError("error.map.invalid_generation_range")
+	}
+
+	members, err := uc.repo.member.GetAllByTreeID(ctx, filter.TreeID)
+	if err != nil {
+		return nil, err
+	}
+	places, err := uc.repo.place.ListByTreeID(ctx, filter.TreeID)
+	if err != nil {
+		return nil, err
+	}
+	privacy, err := uc.privacy.For(ctx, filter.TreeID, viewer, members)
+	if err != nil {
+		return nil, err
+	}
+
+	placeMap := make(map[int]*domain.Place, len(places))
+	for _, place := range places {
+		if place.HasCoordinates() {
+			placeMap[place.PlaceID] = place
+		}
+	}
+	memberMap := make(map[int]*domain.Member, len(members))
+	for _, m := range members {
+		memberMap[m.MemberID] = m
+	}
+	generations := make(map[int]int, len(members))
+
+	var features []*domain.MapFeature
+	for _, m := range members {
+		generation := memberGeneration(m.MemberID, memberMap, generations, map[int]bool{})
+		if filter.MinGeneration != nil && generation < *filter.MinGeneration {
+			continue
+		}
+		if filter.MaxGeneration != nil && generation > *filter.MaxGeneration {
+			continue
+		}
+
+		// A date without its year cannot be placed in time, the place is
+		// still shown
+		shown := *m
+		privacy.ApplyMember(&shown)
+		datesVisible := privacy.CanSee(domain.PrivacyFieldDates, m)
+
+		facts := []struct {
+			eventType string
+			placeID   *int
+			date      *time.Time
+		}{
+			{domain.MapEventBirth, shown.BirthPlaceID, m.DateOfBirth},
+			{domain.MapEventDeath, shown.DeathPlaceID, m.DateOfDeath},
+		}
+		for _, fact := range facts {
+			if fact.placeID == nil || placeMap[*fact.placeID] == nil {
+				continue
+			}
+			date := fact.date
+			if !datesVisible {
+				date = nil
+			}
+			if !withinRange(date, filter.From, filter.To) {
+				continue
+			}
+			features = append(features, &domain.MapFeature{
+				EventType:   fact.eventType,
+				MemberID:    m.MemberID,
+				MemberNames: shown.Names,
+				Generation:  generation,
+				Date:        date,
+				Place:       placeMap[*fact.placeID],
+			})
+		}
+	}
+
+	sort.SliceStable(features, func(i, j int) bool {
+		left, right := features[i], features[j]
+		if left.Date == nil || right.Date == nil {
+			return left.Date != nil
+		}
+		return left.Date.Before(*right.Date)
+	})
+
+	return features, nil
+}
+
+func validatePlace(place *domain.Place) error {
+	if len(place.Names) == 0 {
+		return domain.NewValidationError("error.place.name_required")
+	}
+	if (place.Latitude == nil) != (place.Longitude == nil) {
+		return domain.NewValidationError("error.place.coordinates_incomplete")
+	}
+	if place.HasCoordinates() {
+		if *place.Latitude < -90 || *place.Latitude > 90 || *place.Longitude < -180 || *place.Longitude > 180 {
+			return domain.NewValidationError("error.place.invalid_coordinates")
+		}
+	}
+	return nil
+}
+
+// memberGeneration memoizes generations, visiting guards against a corrupt
+// parent cycle
+func memberGeneration(memberID int, memberMap map[int]*domain.Member, generations map[int]int, visiting map[int]bool) int {
+	if generation, ok := generations[memberID]; ok {
+		return generation
+	}
+	member := memberMap[memberID]
+	if member == nil || visiting[memberID] {
+		return 0
+	}
+	visiting[memberID] = true
+
+	generation := 1
+	for _, parentID := range []*int{member.FatherID, member.MotherID} {
+		if parentID == nil {
+			continue
+		}
+		if parent := memberGeneration(*parentID, memberMap, generations, visiting); parent+1 > generation {
+			generation = parent + 1
+		}
+	}
+
+	delete(visiting, memberID)
+	generations[memberID] = generation
+	return generation
+}
+
+// withinRange reports whether a date falls in an optional range, an unknown
+// date only passes when no range was asked for
+func withinRange(date, from, to *time.Time) bool {
+	if from == nil && to == nil {
+		return true
+	}
+	if date == nil {
+		return false
+	}
+	if from != nil && date.Before(*from) {
+		return false
+	}
+	if to != nil && date.After(*to) {
+		return false
+	}
+	return true
+}
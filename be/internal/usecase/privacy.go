@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/escalopa/family-tree/internal/domain"
+)
+
+// treePrivacy binds a tree's privacy policy to a viewer, every path that
+// shows members goes through it so they are stripped the same way
+type treePrivacy struct {
+	tree   FamilyTreeRepository
+	member MemberRepository
+}
+
+// For returns the privacy of the tree for the viewer. A viewer without a
+// tree role gets their role in the tree, or public when they have none.
+// members may be nil, they are only loaded when a rule depends on members
+// being alive
+func (p treePrivacy) For(ctx context.Context, treeID int, viewer domain.Viewer, members []*domain.Member) (*domain.Privacy, error) {
+	policy, err := p.tree.GetPrivacyPolicy(ctx, treeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if viewer.TreeRole == "" {
+		viewer.TreeRole = domain.TreeRolePublic
+		if viewer.UserID != 0 {
+			tree, err := p.tree.GetForUser(ctx, treeID, viewer.UserID)
+			switch {
+			case err == nil:
+				viewer.TreeRole = tree.UserRole
+			case !domain.IsDomainError(err, domain.ErrCodeNotFound):
+				return nil, err
+			}
+		}
+	}
+
+	var living map[int]bool
+	if policy.UsesLiving() {
+		if members == nil {
+			if members, err = p.member.GetAllByTreeID(ctx, treeID); err != nil {
+				return nil, err
+			}
+		}
+		living = domain.LivingMembers(members, time.Now())
+	}
+
+	return domain.NewPrivacy(*policy, viewer, living), nil
+}
@@ -0,0 +1,389 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/escalopa/family-tree/internal/domain"
+)
+
+type (
+	proposalUseCaseRepo struct {
+		proposal ProposalRepository
+		member   MemberRepository
+		spouse   SpouseRepository
+	}
+
+	proposalUseCaseEditor struct {
+		member MemberEditor
+		spouse SpouseEditor
+	}
+
+	proposalUseCase struct {
+		repo    proposalUseCaseRepo
+		editor  proposalUseCaseEditor
+		privacy treePrivacy
+		tx      TransactionManager
+	}
+)
+
+func NewProposalUseCase(
+	proposalRepo ProposalRepository,
+	treeRepo FamilyTreeRepository,
+	memberRepo MemberRepository,
+	spouseRepo SpouseRepository,
+	memberEditor MemberEditor,
+	spouseEditor SpouseEditor,
+	txManager TransactionManager,
+) *proposalUseCase {
+	return &proposalUseCase{
+		repo: proposalUseCaseRepo{
+			proposal: proposalRepo,
+			member:   memberRepo,
+			spouse:   spouseRepo,
+		},
+		editor: proposalUseCaseEditor{
+			member: memberEditor,
+			spouse: spouseEditor,
+		},
+		privacy: treePrivacy{tree: treeRepo, member: memberRepo},
+		tx:      txManager,
+	}
+}
+
+func (uc *proposalUseCase) Submit(ctx context.Context, proposal *domain.Proposal) error {
+	if err := uc.prepare(ctx, proposal); err != nil {
+		return err
+	}
+	proposal.Status = domain.ProposalStatusPending
+	return uc.repo.proposal.Create(ctx, proposal)
+}
+
+// Revise replaces what an open proposal suggests, only its proposer can revise
+// it and the kind and target stay the same
+func (uc *proposalUseCase) Revise(ctx context.Context, revised *domain.Proposal, userID int) error {
+	proposal, err := uc.get(ctx, revised.TreeID, revised.ProposalID)
+	if err != nil {
+		return err
+	}
+	if proposal.ProposerUserID != userID {
+		return domain.NewForbiddenError("error.proposal.not_proposer")
+	}
+	if !proposal.IsOpen() {
+		return domain.NewConflictError("error.proposal.closed", nil)
+	}
+
+	proposal.Member = revised.Member
+	proposal.Spouse = revised.Spouse
+	proposal.BaseVersion = revised.BaseVersion
+	proposal.Comment = revised.Comment
+	if err := uc.prepare(ctx, proposal); err != nil {
+		return err
+	}
+	proposal.Status = domain.ProposalStatusPending
+	if err := uc.repo.proposal.Revise(ctx, proposal); err != nil {
+		return err
+	}
+
+	*revised = *proposal
+	return nil
+}
+
+func (uc *proposalUseCase) List(ctx context.Context, filter domain.ProposalFilter, viewer domain.Viewer, cursor *string, limit int) ([]*domain.ProposalReview, *string, error) {
+	privacy, err := uc.privacy.For(ctx, filter.TreeID, viewer, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	proposals, nextCursor, err := uc.repo.proposal.List(ctx, filter, cursor, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reviews := make([]*domain.ProposalReview, 0, len(proposals))
+	for _, proposal := range proposals {
+		review, err := uc.review(ctx, proposal, privacy)
+		if err != nil {
+			return nil, nil, err
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, nextCursor, nil
+}
+
+// Get returns a proposal to a member who may edit the tree or to its
+// proposer, it is hidden from anyone else
+func (uc *proposalUseCase) Get(ctx context.Context, treeID, proposalID int, viewer domain.Viewer) (*domain.ProposalReview, error) {
+	proposal, err := uc.get(ctx, treeID, proposalID)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.TreeRoleAllows(viewer.TreeRole, domain.TreePermissionEdit) && proposal.ProposerUserID != viewer.UserID {
+		return nil, domain.NewNotFoundError("proposal")
+	}
+
+	privacy, err := uc.privacy.For(ctx, treeID, viewer, nil)
+	if err != nil {
+		return nil, err
+	}
+	return uc.review(ctx, proposal, privacy)
+}
+
+// Approve applies a pending proposal as its proposer, so history and scores
+// credit them, and records the reviewer on the proposal. The proposal is
+// claimed before it's applied so concurrent approvals apply it once.
+func (uc *proposalUseCase) Approve(ctx context.Context, treeID, proposalID, reviewerID int, comment *string) (*domain.Proposal, error) {
+	proposal, err := uc.get(ctx, treeID, proposalID)
+	if err != nil {
+		return nil, err
+	}
+	if proposal.Status != domain.ProposalStatusPending {
+		return nil, domain.NewConflictError("error.proposal.not_pending", nil)
+	}
+
+	// only its staleness is read, the changes go nowhere
+	review, err := uc.review(ctx, proposal, nil)
+	if err != nil {
+		return nil, err
+	}
+	if review.Stale {
+		return nil, domain.NewConflictError("error.proposal.stale", nil)
+	}
+
+	err = uc.tx.Do(ctx, func(txCtx context.Context) error {
+		if err := uc.repo.proposal.Lock(txCtx, proposal.ProposalID, domain.ProposalStatusPending); err != nil {
+			return err
+		}
+		if err := uc.apply(txCtx, proposal); err != nil {
+			return err
+		}
+		proposal.Status = domain.ProposalStatusApproved
+		proposal.ReviewerUserID = &reviewerID
+		proposal.ReviewComment = comment
+		return uc.repo.proposal.Review(txCtx, proposal, domain.ProposalStatusPending)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return proposal, nil
+}
+
+func (uc *proposalUseCase) Reject(ctx context.Context, treeID, proposalID, reviewerID int, reason string) (*domain.Proposal, error) {
+	proposal, err := uc.get(ctx, treeID, proposalID)
+	if err != nil {
+		return nil, err
+	}
+	if !proposal.IsOpen() {
+		return nil, domain.NewConflictError("error.proposal.closed", nil)
+	}
+
+	fromStatus := proposal.Status
+	proposal.Status = domain.ProposalStatusRejected
+	proposal.ReviewerUserID = &reviewerID
+	proposal.ReviewComment = &reason
+	if err := uc.repo.proposal.Review(ctx, proposal, fromStatus); err != nil {
+		return nil, err
+	}
+	return proposal, nil
+}
+
+// RequestChanges sends a pending proposal back to its proposer to revise
+func (uc *proposalUseCase) RequestChanges(ctx context.Context, treeID, proposalID, reviewerID int, comment string) (*domain.Proposal, error) {
+	proposal, err := uc.get(ctx, treeID, proposalID)
+	if err != nil {
+		return nil, err
+	}
+	if proposal.Status != domain.ProposalStatusPending {
+		return nil, domain.NewConflictError("error.proposal.not_pending", nil)
+	}
+
+	proposal.Status = domain.ProposalStatusChangesRequested
+	proposal.ReviewerUserID = &reviewerID
+	proposal.ReviewComment = &comment
+	if err := uc.repo.proposal.Review(ctx, proposal, domain.ProposalStatusPending); err != nil {
+		return nil, err
+	}
+	return proposal, nil
+}
+
+func (uc *proposalUseCase) get(ctx context.Context, treeID, proposalID int) (*domain.Proposal, error) {
+	proposal, err := uc.repo.proposal.Get(ctx, proposalID)
+	if err != nil {
+		return nil, err
+	}
+	if proposal.TreeID != treeID {
+		return nil, domain.NewNotFoundError("proposal")
+	}
+	return proposal, nil
+}
+
+// prepare checks that the proposal targets the tree and records the values
+// the proposer edited, the full checks run when the proposal is applied
+func (uc *proposalUseCase) prepare(ctx context.Context, proposal *domain.Proposal) error {
+	invalid := domain.NewValidationError("error.proposal.invalid_payload")
+
+	switch proposal.Kind {
+	case domain.ProposalKindCreateMember, domain.ProposalKindUpdateMember:
+		if proposal.Member == nil {
+			return invalid
+		}
+		proposal.Spouse, proposal.SpouseID, proposal.BaseSpouse = nil, nil, nil
+		proposal.Member.TreeID = proposal.TreeID
+		for _, parentID := range []*int{proposal.Member.FatherID, proposal.Member.MotherID} {
+			if parentID == nil {
+				continue
+			}
+			if _, err := uc.memberInTree(ctx, proposal.TreeID, *parentID); err != nil {
+				return err
+			}
+		}
+
+		if proposal.Kind == domain.ProposalKindCreateMember {
+			proposal.MemberID, proposal.BaseVersion = nil, nil
+			return nil
+		}
+
+		if proposal.MemberID == nil || proposal.BaseVersion == nil {
+			return invalid
+		}
+		current, err := uc.memberInTree(ctx, proposal.TreeID, *proposal.MemberID)
+		if err != nil {
+			return err
+		}
+		if current.Version != *proposal.BaseVersion {
+			return domain.NewVersionConflictError()
+		}
+		proposal.Member.MemberID = current.MemberID
+		proposal.Member.Picture = current.Picture
+		return nil
+
+	case domain.ProposalKindAddSpouse:
+		if proposal.Spouse == nil || proposal.Spouse.FatherID == 0 || proposal.Spouse.MotherID == 0 {
+			return invalid
+		}
+		proposal.Member, proposal.MemberID, proposal.BaseVersion = nil, nil, nil
+		proposal.SpouseID, proposal.BaseSpouse = nil, nil
+		for _, partnerID := range []int{proposal.Spouse.FatherID, proposal.Spouse.MotherID} {
+			if _, err := uc.memberInTree(ctx, proposal.TreeID, partnerID); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case domain.ProposalKindUpdateSpouse, domain.ProposalKindRemoveSpouse:
+		if proposal.SpouseID == nil || proposal.Kind == domain.ProposalKindUpdateSpouse && proposal.Spouse == nil {
+			return invalid
+		}
+		proposal.Member, proposal.MemberID, proposal.BaseVersion = nil, nil, nil
+		current, err := uc.spouseInTree(ctx, proposal.TreeID, *proposal.SpouseID)
+		if err != nil {
+			return err
+		}
+		proposal.BaseSpouse = current
+		if proposal.Kind == domain.ProposalKindRemoveSpouse {
+			proposal.Spouse = nil
+			return nil
+		}
+		proposal.Spouse.SpouseID = current.SpouseID
+		proposal.Spouse.FatherID = current.FatherID
+		proposal.Spouse.MotherID = current.MotherID
+		return nil
+	}
+
+	return invalid
+}
+
+// review compares a proposal with the tree as it is now, an open proposal is
+// stale once the member or relationship it edits has changed or is gone. The
+// member's values are stripped for the viewer privacy is for, nil strips none.
+func (uc *proposalUseCase) review(ctx context.Context, proposal *domain.Proposal, privacy *domain.Privacy) (*domain.ProposalReview, error) {
+	review := &domain.ProposalReview{Proposal: proposal}
+
+	switch proposal.Kind {
+	case domain.ProposalKindCreateMember:
+		review.Changes = domain.DiffMembers(nil, proposal.Member, privacy)
+
+	case domain.ProposalKindUpdateMember:
+		current, err := uc.repo.member.Get(ctx, *proposal.MemberID)
+		if err != nil && !domain.IsDomainError(err, domain.ErrCodeNotFound) {
+			return nil, err
+		}
+
+		proposed := *proposal.Member
+		if current != nil {
+			// Omitted name parts and custom fields keep the current values
+			if proposed.NameParts == nil {
+				proposed.NameParts = current.NameParts
+			}
+			if proposed.CustomFields == nil {
+				proposed.CustomFields = current.CustomFields
+			}
+		}
+		review.Changes = domain.DiffMembers(current, &proposed, privacy)
+		review.Stale = current == nil || current.Version != *proposal.BaseVersion
+
+	case domain.ProposalKindAddSpouse:
+		review.Changes = domain.DiffFields(nil, proposal.Spouse, domain.SpouseDiffSkip...)
+
+	case domain.ProposalKindUpdateSpouse, domain.ProposalKindRemoveSpouse:
+		current, err := uc.repo.spouse.Get(ctx, *proposal.SpouseID)
+		if err != nil && !domain.IsDomainError(err, domain.ErrCodeNotFound) {
+			return nil, err
+		}
+
+		before := proposal.BaseSpouse
+		if current != nil {
+			before = current
+		}
+		review.Changes = domain.DiffFields(before, proposal.Spouse, domain.SpouseDiffSkip...)
+		review.Stale = current == nil || !current.SameValues(proposal.BaseSpouse)
+	}
+
+	review.Stale = review.Stale && proposal.IsOpen()
+	return review, nil
+}
+
+func (uc *proposalUseCase) apply(ctx context.Context, proposal *domain.Proposal) error {
+	switch proposal.Kind {
+	case domain.ProposalKindCreateMember:
+		if err := uc.editor.member.Create(ctx, proposal.Member, proposal.ProposerUserID); err != nil {
+			return err
+		}
+		proposal.MemberID = &proposal.Member.MemberID
+		return nil
+	case domain.ProposalKindUpdateMember:
+		return uc.editor.member.Update(ctx, proposal.Member, *proposal.BaseVersion, proposal.ProposerUserID)
+	case domain.ProposalKindAddSpouse:
+		if err := uc.editor.spouse.Create(ctx, proposal.Spouse, proposal.ProposerUserID); err != nil {
+			return err
+		}
+		proposal.SpouseID = &proposal.Spouse.SpouseID
+		return nil
+	case domain.ProposalKindUpdateSpouse:
+		return uc.editor.spouse.Update(ctx, proposal.Spouse, proposal.ProposerUserID)
+	case domain.ProposalKindRemoveSpouse:
+		return uc.editor.spouse.Delete(ctx, *proposal.SpouseID, proposal.ProposerUserID)
+	}
+	return domain.NewValidationError("error.proposal.invalid_payload")
+}
+
+func (uc *proposalUseCase) memberInTree(ctx context.Context, treeID, memberID int) (*domain.Member, error) {
+	member, err := uc.repo.member.Get(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if member.TreeID != treeID {
+		return nil, domain.NewNotFoundError("member")
+	}
+	return member, nil
+}
+
+func (uc *proposalUseCase) spouseInTree(ctx context.Context, treeID, spouseID int) (*domain.Spouse, error) {
+	spouse, err := uc.repo.spouse.Get(ctx, spouseID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.memberInTree(ctx, treeID, spouse.FatherID); err != nil {
+		return nil, domain.NewNotFoundError("spouse")
+	}
+	return spouse, nil
+}
@@ -0,0 +1,385 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"mime"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/escalopa/family-tree/internal/domain"
+)
+
+type (
+	sourceUseCaseRepo struct {
+		source   SourceRepository
+		citation CitationRepository
+		member   MemberRepository
+		spouse   SpouseRepository
+		event    EventRepository
+	}
+
+	sourceUseCase struct {
+		repo     sourceUseCaseRepo
+		s3Client S3Client
+	}
+)
+
+func NewSourceUseCase(
+	sourceRepo SourceRepository,
+	citationRepo CitationRepository,
+	memberRepo MemberRepository,
+	spouseRepo SpouseRepository,
+	eventRepo EventRepository,
+	s3Client S3Client,
+) *sourceUseCase {
+	return &sourceUseCase{
+		repo: sourceUseCaseRepo{
+			source:   sourceRepo,
+			citation: citationRepo,
+			member:   memberRepo,
+			spouse:   spouseRepo,
+			event:    eventRepo,
+		},
+		s3Client: s3Client,
+	}
+}
+
+func (uc *sourceUseCase) CreateSource(ctx context.Context, source *domain.Source) error {
+	if err := validateSource(source); err != nil {
+		return err
+	}
+	return uc.repo.source.Create(ctx, source)
+}
+
+func (uc *sourceUseCase) GetSource(ctx context.Context, treeID, sourceID int) (*domain.Source, error) {
+	source, err := uc.repo.source.Get(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if source.TreeID != treeID {
+		return nil, domain.NewNotFoundError("source")
+	}
+	return source, nil
+}
+
+func (uc *sourceUseCase) ListSources(ctx context.Context, treeID int) ([]*domain.Source, error) {
+	return uc.repo.source.ListByTreeID(ctx, treeID)
+}
+
+func (uc *sourceUseCase) UpdateSource(ctx context.Context, source *domain.Source) error {
+	oldSource, err := uc.GetSource(ctx, source.TreeID, source.SourceID)
+	if err != nil {
+		return err
+	}
+	if err := validateSource(source); err != nil {
+		return err
+	}
+	source.AttachmentKey = oldSource.AttachmentKey
+	source.CreatedBy = oldSource.CreatedBy
+	source.CreatedAt = oldSource.CreatedAt
+	return uc.repo.source.Update(ctx, source)
+}
+
+func (uc *sourceUseCase) DeleteSource(ctx context.Context, treeID, sourceID int) error {
+	source, err := uc.GetSource(ctx, treeID, sourceID)
+	if err != nil {
+		return err
+	}
+	if err := uc.repo.source.Delete(ctx, sourceID); err != nil {
+		return err
+	}
+	uc.deleteAttachmentFile(ctx, source)
+	return nil
+}
+
+func (uc *sourceUseCase) UploadAttachment(ctx context.Context, treeID, sourceID int, data []byte, filename string) (string, error) {
+	source, err := uc.GetSource(ctx, treeID, sourceID)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := uc.s3Client.UploadImage(ctx, data, filename)
+	if err != nil {
+		return "", err
+	}
+
+	if err := uc.repo.source.UpdateAttachment(ctx, sourceID, &key); err != nil {
+		if deleteErr := uc.s3Client.DeleteImage(ctx, key); deleteErr != nil {
+			slog.Error("rollback S3 upload after source attachment error", "error", deleteErr, "source_id", sourceID, "key", key, "original_error", err)
+		}
+		return "", err
+	}
+
+	uc.deleteAttachmentFile(ctx, source)
+	return key, nil
+}
+
+func (uc *sourceUseCase) GetAttachment(ctx context.Context, treeID, sourceID int) ([]byte, string, error) {
+	source, err := uc.GetSource(ctx, treeID, sourceID)
+	if err != nil {
+		return nil, "", err
+	}
+	if source.AttachmentKey == nil || *source.AttachmentKey == "" {
+		return nil, "", domain.NewNotFoundError("attachment")
+	}
+
+	data, err := uc.s3Client.GetImage(ctx, *source.AttachmentKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, mime.TypeByExtension(filepath.Ext(*source.AttachmentKey)), nil
+}
+
+func (uc *sourceUseCase) DeleteAttachment(ctx context.Context, treeID, sourceID int) error {
+	source, err := uc.GetSource(ctx, treeID, sourceID)
+	if err != nil {
+		return err
+	}
+	if source.AttachmentKey == nil || *source.AttachmentKey == "" {
+		return domain.NewNotFoundError("attachment")
+	}
+
+	if err := uc.repo.source.UpdateAttachment(ctx, sourceID, nil); err != nil {
+		return err
+	}
+	uc.deleteAttachmentFile(ctx, source)
+	return nil
+}
+
+func (uc *sourceUseCase) deleteAttachmentFile(ctx context.Context, source *domain.Source) {
+	if source.AttachmentKey == nil || *source.AttachmentKey == "" {
+		return
+	}
+	if err := uc.s3Client.DeleteImage(ctx, *source.AttachmentKey); err != nil {
+		slog.Warn("delete source attachment from S3", "error", err, "source_id", source.SourceID, "key", *source.AttachmentKey)
+	}
+}
+
+func (uc *sourceUseCase) CreateCitation(ctx context.Context, treeID int, citation *domain.Citation) error {
+	if _, err := uc.GetSource(ctx, treeID, citation.SourceID); err != nil {
+		return err
+	}
+	if !domain.IsCitableField(citation.SubjectType, citation.FieldName) {
+		return domain.NewValidationError("error.citation.invalid_field")
+	}
+	if err := uc.ensureSubjectInTree(ctx, treeID, citation.SubjectType, citation.SubjectID); err != nil {
+		return err
+	}
+	return uc.repo.citation.Create(ctx, citation)
+}
+
+func (uc *sourceUseCase) UpdateCitation(ctx context.Context, treeID int, citation *domain.Citation) error {
+	oldCitation, err := uc.getCitation(ctx, treeID, citation.CitationID)
+	if err != nil {
+		return err
+	}
+	citation.SourceID = oldCitation.SourceID
+	citation.SubjectType = oldCitation.SubjectType
+	citation.SubjectID = oldCitation.SubjectID
+	citation.FieldName = oldCitation.FieldName
+	citation.CreatedBy = oldCitation.CreatedBy
+	citation.CreatedAt = oldCitation.CreatedAt
+	return uc.repo.citation.Update(ctx, citation)
+}
+
+func (uc *sourceUseCase) DeleteCitation(ctx context.Context, treeID, citationID int) error {
+	if _, err := uc.getCitation(ctx, treeID, citationID); err != nil {
+		return err
+	}
+	return uc.repo.citation.Delete(ctx, citationID)
+}
+
+func (uc *sourceUseCase) ListCitationsBySource(ctx context.Context, treeID, sourceID int) ([]*domain.Citation, error) {
+	if _, err := uc.GetSource(ctx, treeID, sourceID); err != nil {
+		return nil, err
+	}
+	return uc.repo.citation.ListBySourceID(ctx, sourceID)
+}
+
+func (uc *sourceUseCase) ListCitationsBySubject(ctx context.Context, treeID int, subjectType string, subjectID int) ([]*domain.Citation, error) {
+	if err := uc.ensureSubjectInTree(ctx, treeID, subjectType, subjectID); err != nil {
+		return nil, err
+	}
+	return uc.repo.citation.ListBySubject(ctx, subjectType, subjectID)
+}
+
+// ListUnsourcedFacts returns the filled facts of the tree without a citation.
+// Names and gender are left out since every member has them
+func (uc *sourceUseCase) ListUnsourcedFacts(ctx context.Context, treeID int) ([]*domain.UnsourcedFact, error) {
+	cited, err := uc.repo.citation.ListCitedFacts(ctx, treeID)
+	if err != nil {
+		return nil, err
+	}
+	citedSet := make(map[domain.FactRef]bool, len(cited))
+	for _, fact := range cited {
+		citedSet[fact] = true
+	}
+
+	var facts []*domain.UnsourcedFact
+	add := func(subjectType string, subjectID int, memberIDs []int, fields ...string) {
+		for _, field := range fields {
+			ref := domain.FactRef{SubjectType: subjectType, SubjectID: subjectID, FieldName: field}
+			if !citedSet[ref] {
+				facts = append(facts, &domain.UnsourcedFact{FactRef: ref, MemberIDs: memberIDs})
+			}
+		}
+	}
+
+	members, err := uc.repo.member.GetAllByTreeID(ctx, treeID)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range members {
+		add(domain.CitationSubjectMember, m.MemberID, []int{m.MemberID}, filledMemberFacts(m)...)
+	}
+
+	spousesByMember, err := uc.repo.spouse.GetAllSpousesByTreeID(ctx, treeID)
+	if err != nil {
+		return nil, err
+	}
+	memberIDs := make([]int, 0, len(spousesByMember))
+	for memberID := range spousesByMember {
+		memberIDs = append(memberIDs, memberID)
+	}
+	sort.Ints(memberIDs)
+	seenSpouses := make(map[int]bool)
+	for _, memberID := range memberIDs {
+		for _, spouse := range spousesByMember[memberID] {
+			if seenSpouses[spouse.SpouseID] {
+				continue
+			}
+			seenSpouses[spouse.SpouseID] = true
+
+			var fields []string
+			if spouse.MarriageDate != nil {
+				fields = append(fields, "marriage_date")
+			}
+			if spouse.DivorceDate != nil {
+				fields = append(fields, "divorce_date")
+			}
+			if spouse.MarriagePlaceID != nil {
+				fields = append(fields, "marriage_place_id")
+			}
+			add(domain.CitationSubjectSpouse, spouse.SpouseID, []int{memberID, spouse.MemberID}, fields...)
+		}
+	}
+
+	events, err := uc.repo.event.ListByTreeID(ctx, treeID)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		fields := []string{"event"}
+		if event.Date != nil {
+			fields = append(fields, "date")
+		}
+		if event.PlaceID != nil {
+			fields = append(fields, "place_id")
+		}
+		add(domain.CitationSubjectEvent, event.EventID, []int{event.MemberID}, fields...)
+	}
+
+	return facts, nil
+}
+
+func (uc *sourceUseCase) getCitation(ctx context.Context, treeID, citationID int) (*domain.Citation, error) {
+	citation, err := uc.repo.citation.Get(ctx, citationID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.GetSource(ctx, treeID, citation.SourceID); err != nil {
+		if domain.IsDomainError(err, domain.ErrCodeNotFound) {
+			return nil, domain.NewNotFoundError("citation")
+		}
+		return nil, err
+	}
+	return citation, nil
+}
+
+// ensureSubjectInTree resolves the member a subject belongs to, a subject of
+// another tree is reported as missing
+func (uc *sourceUseCase) ensureSubjectInTree(ctx context.Context, treeID int, subjectType string, subjectID int) error {
+	var memberID int
+	switch subjectType {
+	case domain.CitationSubjectMember:
+		memberID = subjectID
+	case domain.CitationSubjectSpouse:
+		spouse, err := uc.repo.spouse.Get(ctx, subjectID)
+		if err != nil {
+			return subjectError(err)
+		}
+		memberID = spouse.FatherID
+	case domain.CitationSubjectEvent:
+		event, err := uc.repo.event.Get(ctx, subjectID)
+		if err != nil {
+			return subjectError(err)
+		}
+		memberID = event.MemberID
+	default:
+		return domain.NewValidationError("error.citation.invalid_subject")
+	}
+
+	member, err := uc.repo.member.Get(ctx, memberID)
+	if err != nil {
+		return subjectError(err)
+	}
+	if member.TreeID != treeID {
+		return domain.NewValidationError("error.citation.invalid_subject")
+	}
+	return nil
+}
+
+func subjectError(err error) error {
+	if domain.IsDomainError(err, domain.ErrCodeNotFound) {
+		return domain.NewValidationError("error.citation.invalid_subject")
+	}
+	return err
+}
+
+func validateSource(source *domain.Source) error {
+	source.Title = strings.TrimSpace(source.Title)
+	if source.Title == "" {
+		return domain.NewValidationError("error.source.title_required")
+	}
+	switch source.SourceType {
+	case domain.SourceTypeDocument, domain.SourceTypeInterview, domain.SourceTypeGravestone,
+		domain.SourceTypeRegistry, domain.SourceTypeOther:
+		return nil
+	}
+	return domain.NewValidationError("error.source.invalid_type")
+}
+
+// filledMemberFacts lists the optional member fields that hold a value
+func filledMemberFacts(m *domain.Member) []string {
+	var fields []string
+	if len(m.Nicknames) > 0 {
+		fields = append(fields, "nicknames")
+	}
+	if m.Profession != nil && *m.Profession != "" {
+		fields = append(fields, "profession")
+	}
+	if m.FatherID != nil {
+		fields = append(fields, "father_id")
+	}
+	if m.MotherID != nil {
+		fields = append(fields, "mother_id")
+	}
+	if m.DateOfBirth != nil {
+		fields = append(fields, "date_of_birth")
+	}
+	if m.DateOfDeath != nil {
+		fields = append(fields, "date_of_death")
+	}
+	if m.BirthPlaceID != nil {
+		fields = append(fields, "birth_place_id")
+	}
+	if m.DeathPlaceID != nil {
+		fields = append(fields, "death_place_id")
+	}
+	if m.BurialPlaceID != nil {
+		fields = append(fields, "burial_place_id")
+	}
+	return fields
+}
@@ -6,7 +6,6 @@ import (
 	"log/slog"
 
 	"github.com/escalopa/family-tree/internal/domain"
-	"github.com/escalopa/family-tree/internal/pkg/validator"
 )
 
 type (
@@ -19,6 +18,7 @@ type (
 
 	spouseUseCaseValidator struct {
 		marriage MarriageValidator
+		place    PlaceValidator
 	}
 
 	spouseUseCase struct {
@@ -35,6 +35,7 @@ func NewSpouseUseCase(
 	scoreRepo ScoreRepository,
 	txManager TransactionManager,
 	marriageValidator MarriageValidator,
+	placeValidator PlaceValidator,
 ) *spouseUseCase {
 	return &spouseUseCase{
 		repo: spouseUseCaseRepo{
@@ -45,6 +46,7 @@ func NewSpouseUseCase(
 		},
 		validator: spouseUseCaseValidator{
 			marriage: marriageValidator,
+			place:    placeValidator,
 		},
 		tx: txManager,
 	}
@@ -100,8 +102,8 @@ func (uc *spouseUseCase) recordSpouseHistory(
 }
 
 func (uc *spouseUseCase) Create(ctx context.Context, spouse *domain.Spouse, userID int) error {
-	if !validator.ValidateDateOrder(spouse.MarriageDate, spouse.DivorceDate) {
-		return domain.NewValidationError("error.spouse.invalid_marriage_date")
+	if err := normalizeSpouseDates(spouse); err != nil {
+		return err
 	}
 
 	existingSpouse, err := uc.repo.spouse.GetByParents(ctx, spouse.FatherID, spouse.MotherID)
@@ -128,11 +130,15 @@ func (uc *spouseUseCase) Create(ctx context.Context, spouse *domain.Spouse, user
 		return domain.NewValidationError("error.member.invalid_mother")
 	}
 
+	if err := uc.validator.place.InTree(ctx, father.TreeID, spouse.MarriagePlaceID); err != nil {
+		return err
+	}
+
 	if err := uc.validator.marriage.Create(ctx, spouse.FatherID, spouse.MotherID); err != nil {
 		return err
 	}
 
-	if err := uc.validator.marriage.MarriageDate(ctx, spouse.FatherID, spouse.MotherID, spouse.MarriageDate); err != nil {
+	if err := uc.validator.marriage.MarriageDate(ctx, spouse.FatherID, spouse.MotherID, spouse.MarriageRange()); err != nil {
 		return err
 	}
 
@@ -170,8 +176,8 @@ func (uc *spouseUseCase) createTx(ctx context.Context, spouse *domain.Spouse, fa
 }
 
 func (uc *spouseUseCase) Update(ctx context.Context, spouse *domain.Spouse, userID int) error {
-	if !validator.ValidateDateOrder(spouse.MarriageDate, spouse.DivorceDate) {
-		return domain.NewValidationError("error.spouse.invalid_marriage_date")
+	if err := normalizeSpouseDates(spouse); err != nil {
+		return err
 	}
 
 	oldSpouse, err := uc.repo.spouse.Get(ctx, spouse.SpouseID)
@@ -182,7 +188,15 @@ func (uc *spouseUseCase) Update(ctx context.Context, spouse *domain.Spouse, user
 	spouse.FatherID = oldSpouse.FatherID
 	spouse.MotherID = oldSpouse.MotherID
 
-	if err := uc.validator.marriage.MarriageDate(ctx, spouse.FatherID, spouse.MotherID, spouse.MarriageDate); err != nil {
+	father, err := uc.repo.member.Get(ctx, spouse.FatherID)
+	if err != nil {
+		return err
+	}
+	if err := uc.validator.place.InTree(ctx, father.TreeID, spouse.MarriagePlaceID); err != nil {
+		return err
+	}
+
+	if err := uc.validator.marriage.MarriageDate(ctx, spouse.FatherID, spouse.MotherID, spouse.MarriageRange()); err != nil {
 		return err
 	}
 
@@ -232,3 +246,29 @@ func (uc *spouseUseCase) deleteTx(ctx context.Context, spouseID int, oldSpouse *
 
 	return nil
 }
+
+// normalizeSpouseDates applies the date qualifiers and calendars and rejects a divorce that
+// must have happened before the marriage
+func normalizeSpouseDates(spouse *domain.Spouse) error {
+	var err error
+	if spouse.MarriageDateCalendar, err = domain.NormalizeCalendar(spouse.MarriageDateCalendar, spouse.MarriageDate); err != nil {
+		return err
+	}
+	if spouse.DivorceDateCalendar, err = domain.NormalizeCalendar(spouse.DivorceDateCalendar, spouse.DivorceDate); err != nil {
+		return err
+	}
+	spouse.MarriageDate, spouse.MarriageDateQualifier, spouse.MarriageDateEnd, err = domain.NormalizeDate(spouse.MarriageDate, spouse.MarriageDateQualifier, spouse.MarriageDateEnd, spouse.MarriageDateCalendar)
+	if err != nil {
+		return err
+	}
+	spouse.DivorceDate, spouse.DivorceDateQualifier, spouse.DivorceDateEnd, err = domain.NormalizeDate(spouse.DivorceDate, spouse.DivorceDateQualifier, spouse.DivorceDateEnd, spouse.DivorceDateCalendar)
+	if err != nil {
+		return err
+	}
+
+	marriage, divorce := spouse.MarriageRange(), spouse.DivorceRange()
+	if marriage != nil && divorce != nil && divorce.IsDefinitelyBefore(marriage) {
+		return domain.NewValidationError("error.spouse.invalid_marriage_date")
+	}
+	return nil
+}
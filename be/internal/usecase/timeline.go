@@ -0,0 +1,370 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/escalopa/family-tree/internal/domain"
+)
+
+const (
+	defaultTimelineDegree = 1
+	maxTimelineDegree     = 10
+)
+
+// timelineEventOrder keeps same-day entries in a natural reading order, the
+// member's own events come between their birth and any marriage
+var timelineEventOrder = map[string]int{
+	domain.TimelineEventBirth:      0,
+	domain.TimelineEventMarriage:   2,
+	domain.TimelineEventDivorce:    3,
+	domain.TimelineEventSeparation: 3,
+	domain.TimelineEventWidowed:    3,
+	domain.TimelineEventDeath:      4,
+}
+
+func timelineOrder(eventType string) int {
+	if order, ok := timelineEventOrder[eventType]; ok {
+		return order
+	}
+	return 1
+}
+
+type (
+	timelineUseCaseRepo struct {
+		member MemberRepository
+		spouse SpouseRepository
+		event  EventRepository
+		graph  FamilyGraphRepository
+	}
+
+	timelineUseCase struct {
+		repo    timelineUseCaseRepo
+		privacy treePrivacy
+	}
+)
+
+func NewTimelineUseCase(memberRepo MemberRepository, spouseRepo SpouseRepository, eventRepo EventRepository, graphRepo FamilyGraphRepository, treeRepo FamilyTreeRepository) *timelineUseCase {
+	return &timelineUseCase{
+		repo: timelineUseCaseRepo{
+			member: memberRepo,
+			spouse: spouseRepo,
+			event:  eventRepo,
+			graph:  graphRepo,
+		},
+		privacy: treePrivacy{tree: treeRepo, member: memberRepo},
+	}
+}
+
+func (uc *timelineUseCase) List(ctx context.Context, filter domain.TimelineFilter, viewer domain.Viewer, cursor *string, limit int) ([]*domain.TimelineEntry, *string, error) {
+	offset := 0
+	if cursor != nil && *cursor != "" {
+		parsed, err := strconv.Atoi(*cursor)
+		if err != nil || parsed < 0 {
+			return nil, nil, domain.NewValidationError("error.validation.invalid_cursor")
+		}
+		offset = parsed
+	}
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return nil, nil, domain.NewValidationError("error.timeline.invalid_range")
+	}
+
+	members, err := uc.repo.member.GetAllByTreeID(ctx, filter.TreeID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	units, err := uc.repo.graph.ListFamilyUnitsByTreeID(ctx, filter.TreeID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	spouses, err := uc.repo.spouse.ListAllByTreeID(ctx, filter.TreeID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	events, err := uc.repo.event.ListByTreeID(ctx, filter.TreeID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	privacy, err := uc.privacy.For(ctx, filter.TreeID, viewer, members)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	memberMap := make(map[int]*domain.Member, len(members))
+	for _, m := range members {
+		memberMap[m.MemberID] = m
+	}
+
+	var scope map[int]bool
+	if filter.MemberID != nil {
+		if _, exists := memberMap[*filter.MemberID]; !exists {
+			return nil, nil, domain.NewNotFoundError("member")
+		}
+		degree := filter.Degree
+		if degree <= 0 {
+			degree = defaultTimelineDegree
+		}
+		scope = uc.collectRelatives(memberMap, units, *filter.MemberID, min(degree, maxTimelineDegree))
+	}
+
+	entries := uc.buildEntries(members, units, spouses, events, memberMap, privacy, scope)
+	entries = uc.filterByDate(entries, filter.From, filter.To)
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		left, right := entries[i], entries[j]
+		if !left.Date.Equal(right.Date) {
+			return left.Date.Before(right.Date)
+		}
+		if timelineOrder(left.EventType) != timelineOrder(right.EventType) {
+			return timelineOrder(left.EventType) < timelineOrder(right.EventType)
+		}
+		return left.MemberID < right.MemberID
+	})
+
+	if offset >= len(entries) {
+		return []*domain.TimelineEntry{}, nil, nil
+	}
+	end := min(offset+limit, len(entries))
+
+	var nextCursor *string
+	if end < len(entries) {
+		next := strconv.Itoa(end)
+		nextCursor = &next
+	}
+
+	return entries[offset:end], nextCursor, nil
+}
+
+func (uc *timelineUseCase) buildEntries(
+	members []*domain.Member,
+	units []*domain.FamilyUnit,
+	spouses []*domain.Spouse,
+	events []*domain.MemberEvent,
+	memberMap map[int]*domain.Member,
+	privacy *domain.Privacy,
+	scope map[int]bool,
+) []*domain.TimelineEntry {
+	inScope := func(memberID int) bool {
+		return scope == nil || scope[memberID]
+	}
+	names := func(m *domain.Member) map[string]string {
+		if privacy.CanSee(domain.PrivacyFieldNames, m) {
+			return m.Names
+		}
+		return nil
+	}
+
+	var entries []*domain.TimelineEntry
+	for _, m := range members {
+		if !inScope(m.MemberID) {
+			continue
+		}
+		// A date the viewer cannot see in full leaves nothing to place on a
+		// timeline, so those entries are omitted
+		if !privacy.CanSee(domain.PrivacyFieldDates, m) {
+			continue
+		}
+		if m.DateOfBirth != nil {
+			entries = append(entries, &domain.TimelineEntry{
+				EventType:     domain.TimelineEventBirth,
+				Date:          *m.DateOfBirth,
+				DateQualifier: m.DateOfBirthQualifier,
+				DateEnd:       m.DateOfBirthEnd,
+				DateCalendar:  m.DateOfBirthCalendar,
+				MemberID:      m.MemberID,
+				MemberNames:   names(m),
+			})
+		}
+		if m.DateOfDeath != nil {
+			entries = append(entries, &domain.TimelineEntry{
+				EventType:     domain.TimelineEventDeath,
+				Date:          *m.DateOfDeath,
+				DateQualifier: m.DateOfDeathQualifier,
+				DateEnd:       m.DateOfDeathEnd,
+				DateCalendar:  m.DateOfDeathCalendar,
+				MemberID:      m.MemberID,
+				MemberNames:   names(m),
+			})
+		}
+	}
+
+	for _, event := range events {
+		m := memberMap[event.MemberID]
+		if m == nil || event.Date == nil || !inScope(m.MemberID) {
+			continue
+		}
+		// Events follow the member's own dates
+		if !privacy.CanSee(domain.PrivacyFieldDates, m) {
+			continue
+		}
+		privacy.ApplyEvent(event, m)
+		eventID := event.EventID
+		entries = append(entries, &domain.TimelineEntry{
+			EventType:     event.EventType,
+			Date:          *event.Date,
+			DateQualifier: event.DateQualifier,
+			DateEnd:       event.DateEnd,
+			DateCalendar:  event.DateCalendar,
+			MemberID:      m.MemberID,
+			MemberNames:   names(m),
+			EventID:       &eventID,
+			CustomType:    event.CustomType,
+			PlaceID:       event.PlaceID,
+		})
+	}
+
+	spouseMap := make(map[int]*domain.Spouse, len(spouses))
+	for _, spouse := range spouses {
+		spouseMap[spouse.SpouseID] = spouse
+	}
+
+	for _, unit := range units {
+		if len(unit.PartnerIDs) == 0 {
+			continue
+		}
+		partners := make([]*domain.Member, 0, len(unit.PartnerIDs))
+		for _, partnerID := range unit.PartnerIDs {
+			if partner := memberMap[partnerID]; partner != nil {
+				partners = append(partners, partner)
+			}
+		}
+		if len(partners) == 0 {
+			continue
+		}
+		// Husband first, matching the father/mother order of members_spouse
+		sort.SliceStable(partners, func(i, j int) bool {
+			return partners[i].Gender == "M" && partners[j].Gender != "M"
+		})
+
+		relevant := false
+		for _, partner := range partners {
+			if inScope(partner.MemberID) {
+				relevant = true
+				break
+			}
+		}
+		if !relevant {
+			continue
+		}
+
+		// A unit made from a spouse relationship keeps the qualifier, range
+		// end and calendar its dates were entered with
+		var spouse *domain.Spouse
+		if unit.SourceSpouseID != nil {
+			spouse = spouseMap[*unit.SourceSpouseID]
+		}
+
+		unitID := unit.FamilyUnitID
+		newEntry := func(eventType string, date time.Time) *domain.TimelineEntry {
+			entry := &domain.TimelineEntry{
+				EventType:     eventType,
+				Date:          date,
+				DateQualifier: domain.DateQualifierExact,
+				DateCalendar:  domain.CalendarGregorian,
+				MemberID:      partners[0].MemberID,
+				MemberNames:   names(partners[0]),
+				FamilyUnitID:  &unitID,
+			}
+			if len(partners) > 1 {
+				partnerID := partners[1].MemberID
+				entry.PartnerID = &partnerID
+				entry.PartnerNames = names(partners[1])
+			}
+			return entry
+		}
+
+		if unit.StartDate != nil {
+			entry := newEntry(domain.TimelineEventMarriage, *unit.StartDate)
+			if spouse != nil && spouse.MarriageDate != nil {
+				entry.DateQualifier, entry.DateEnd, entry.DateCalendar = spouse.MarriageDateQualifier, spouse.MarriageDateEnd, spouse.MarriageDateCalendar
+			}
+			entries = append(entries, entry)
+		}
+		if unit.EndDate != nil {
+			entry := newEntry(timelineEndEventType(unit.Status), *unit.EndDate)
+			if spouse != nil && spouse.DivorceDate != nil {
+				entry.DateQualifier, entry.DateEnd, entry.DateCalendar = spouse.DivorceDateQualifier, spouse.DivorceDateEnd, spouse.DivorceDateCalendar
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+func (uc *timelineUseCase) filterByDate(entries []*domain.TimelineEntry, from, to *time.Time) []*domain.TimelineEntry {
+	if from == nil && to == nil {
+		return entries
+	}
+	filtered := make([]*domain.TimelineEntry, 0, len(entries))
+	for _, entry := range entries {
+		if from != nil && entry.Date.Before(*from) {
+			continue
+		}
+		if to != nil && entry.Date.After(*to) {
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+	return filtered
+}
+
+// collectRelatives returns the member and everyone reachable through parent,
+// child or partner links within the given number of steps
+func (uc *timelineUseCase) collectRelatives(memberMap map[int]*domain.Member, units []*domain.FamilyUnit, memberID, degree int) map[int]bool {
+	neighbors := make(map[int][]int)
+	link := func(a, b int) {
+		neighbors[a] = append(neighbors[a], b)
+		neighbors[b] = append(neighbors[b], a)
+	}
+	for _, m := range memberMap {
+		if m.FatherID != nil {
+			link(m.MemberID, *m.FatherID)
+		}
+		if m.MotherID != nil {
+			link(m.MemberID, *m.MotherID)
+		}
+	}
+	for _, unit := range units {
+		for i := 0; i < len(unit.PartnerIDs); i++ {
+			for j := i + 1; j < len(unit.PartnerIDs); j++ {
+				link(unit.PartnerIDs[i], unit.PartnerIDs[j])
+			}
+		}
+	}
+
+	scope := map[int]bool{memberID: true}
+	frontier := []int{memberID}
+	for step := 0; step < degree && len(frontier) > 0; step++ {
+		var next []int
+		for _, current := range frontier {
+			for _, neighbor := range neighbors[current] {
+				if scope[neighbor] {
+					continue
+				}
+				scope[neighbor] = true
+				next = append(next, neighbor)
+			}
+		}
+		frontier = next
+	}
+
+	return scope
+}
+
+func timelineEndEventType(status string) string {
+	switch status {
+	case "separated":
+		return domain.TimelineEventSeparation
+	case "widowed":
+		return domain.TimelineEventWidowed
+	default:
+		return domain.TimelineEventDivorce
+	}
+}
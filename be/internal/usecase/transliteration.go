@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/escalopa/family-tree/internal/pkg/translit"
+)
+
+// suggestNames fills the member's missing names from its Arabic name. A
+// suggested name sent back unchanged is suggested again, so it follows edits
+// of the Arabic name and of the tree's spellings, any other value is taken as
+// the editor's own
+func (uc *memberUseCase) suggestNames(ctx context.Context, member, oldMember *domain.Member) error {
+	source := strings.TrimSpace(member.Names[domain.TransliterationSource])
+
+	var spellings map[string]map[string]string
+	suggested := []string{}
+	for _, langCode := range slices.Sorted(maps.Keys(domain.NameScripts)) {
+		name := strings.TrimSpace(member.Names[langCode])
+		wasSuggested := oldMember != nil &&
+			slices.Contains(oldMember.SuggestedNames, langCode) && name == oldMember.Names[langCode]
+		if name != "" && !wasSuggested {
+			continue
+		}
+		if source == "" {
+			if wasSuggested {
+				suggested = append(suggested, langCode)
+			}
+			continue
+		}
+
+		if spellings == nil {
+			var err error
+			if spellings, err = uc.nameSpellings(ctx, member.TreeID); err != nil {
+				return err
+			}
+		}
+
+		name = translit.Transliterate(source, translit.Script(domain.NameScripts[langCode]), spellings[langCode])
+		if name == "" {
+			return domain.NewValidationError("error.validation.names_required").
+				WithParams(map[string]string{"code": langCode})
+		}
+		if member.Names == nil {
+			member.Names = make(map[string]string)
+		}
+		member.Names[langCode] = name
+		suggested = append(suggested, langCode)
+	}
+
+	member.SuggestedNames = suggested
+	return nil
+}
+
+// nameSpellings returns the tree's spellings keyed by language and then by the
+// Arabic name
+func (uc *memberUseCase) nameSpellings(ctx context.Context, treeID int) (map[string]map[string]string, error) {
+	list, err := uc.repo.spelling.ListByTreeID(ctx, treeID)
+	if err != nil {
+		return nil, err
+	}
+
+	spellings := make(map[string]map[string]string)
+	for _, spelling := range list {
+		if spellings[spelling.LanguageCode] == nil {
+			spellings[spelling.LanguageCode] = make(map[string]string)
+		}
+		spellings[spelling.LanguageCode][spelling.Arabic] = spelling.Spelling
+	}
+	return spellings, nil
+}
+
+// ConfirmName marks a suggested name as checked by an editor, who is scored
+// for it as if they had written it
+func (uc *memberUseCase) ConfirmName(ctx context.Context, memberID int, languageCode string, userID int) error {
+	oldMember, err := uc.repo.member.Get(ctx, memberID)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(oldMember.SuggestedNames, languageCode) {
+		return domain.NewValidationError("error.member.name_not_suggested").
+			WithParams(map[string]string{"code": languageCode})
+	}
+
+	member := *oldMember
+	member.SuggestedNames = slices.DeleteFunc(slices.Clone(oldMember.SuggestedNames), func(code string) bool {
+		return code == languageCode
+	})
+
+	return uc.tx.Do(ctx, func(txCtx context.Context) error {
+		if err := uc.saveNamesTx(txCtx, &member, oldMember, userID); err != nil {
+			return err
+		}
+		return uc.repo.score.Create(txCtx, domain.Score{
+			UserID:        userID,
+			MemberID:      member.MemberID,
+			FieldName:     "name_" + languageCode,
+			Points:        domain.PointsName,
+			MemberVersion: member.Version,
+		})
+	})
+}
+
+// SuggestTreeNames fills the missing names of every member of the tree and
+// refreshes the names still waiting for review, it returns how many members
+// changed
+func (uc *memberUseCase) SuggestTreeNames(ctx context.Context, treeID, userID int) (int, error) {
+	members, err := uc.repo.member.GetAllByTreeID(ctx, treeID)
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, oldMember := range members {
+		member := *oldMember
+		member.Names = maps.Clone(oldMember.Names)
+		if err := uc.suggestNames(ctx, &member, oldMember); err != nil {
+			if domain.IsDomainError(err, domain.ErrCodeInvalidInput) {
+				continue
+			}
+			return updated, err
+		}
+		if maps.Equal(member.Names, oldMember.Names) && slices.Equal(member.SuggestedNames, oldMember.SuggestedNames) {
+			continue
+		}
+
+		err := uc.tx.Do(ctx, func(txCtx context.Context) error {
+			return uc.saveNamesTx(txCtx, &member, oldMember, userID)
+		})
+		if err != nil {
+			return updated, err
+		}
+		updated++
+	}
+
+	return updated, nil
+}
+
+func (uc *memberUseCase) saveNamesTx(ctx context.Context, member, oldMember *domain.Member, userID int) error {
+	if err := uc.repo.member.Update(ctx, member, oldMember.Version); err != nil {
+		return err
+	}
+
+	oldValuesJSON, _ := json.Marshal(oldMember)
+	newValuesJSON, _ := json.Marshal(member)
+	return uc.repo.history.Create(ctx, &domain.History{
+		MemberID:      member.MemberID,
+		UserID:        userID,
+		ChangeType:    domain.ChangeTypeUpdate,
+		OldValues:     oldValuesJSON,
+		NewValues:     newValuesJSON,
+		MemberVersion: member.Version,
+	})
+}
+
+func (uc *memberUseCase) ListNameSpellings(ctx context.Context, treeID int) ([]*domain.NameSpelling, error) {
+	return uc.repo.spelling.ListByTreeID(ctx, treeID)
+}
+
+func (uc *memberUseCase) SaveNameSpelling(ctx context.Context, spelling *domain.NameSpelling) error {
+	spelling.Arabic = strings.Join(strings.Fields(spelling.Arabic), " ")
+	spelling.Spelling = strings.TrimSpace(spelling.Spelling)
+	if !translit.IsArabic(spelling.Arabic) {
+		return domain.NewValidationError("error.name_spelling.not_arabic")
+	}
+	if !domain.CanSuggestName(spelling.LanguageCode) {
+		return domain.NewValidationError("error.name_spelling.invalid_language").
+			WithParams(map[string]string{"code": spelling.LanguageCode})
+	}
+	if spelling.Spelling == "" {
+		return domain.NewValidationError("error.name_spelling.spelling_required")
+	}
+	return uc.repo.spelling.Upsert(ctx, spelling)
+}
+
+func (uc *memberUseCase) DeleteNameSpelling(ctx context.Context, treeID int, arabic, languageCode string) error {
+	return uc.repo.spelling.Delete(ctx, treeID, strings.Join(strings.Fields(arabic), " "), languageCode)
+}
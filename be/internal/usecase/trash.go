@@ -0,0 +1,387 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/escalopa/family-tree/internal/domain"
+)
+
+func (uc *memberUseCase) ListTrash(ctx context.Context, treeID int) (*domain.Trash, error) {
+	members, err := uc.repo.member.ListDeleted(ctx, treeID)
+	if err != nil {
+		return nil, err
+	}
+	spouses, err := uc.repo.spouse.ListDeletedByTreeID(ctx, treeID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Trash{Members: members, Spouses: spouses}, nil
+}
+
+// RestoreMember brings a deleted member back as it was when it was deleted
+func (uc *memberUseCase) RestoreMember(ctx context.Context, treeID, memberID, userID int) error {
+	history, err := uc.repo.history.GetLatest(ctx, memberID, domain.ChangeTypeDelete)
+	if err != nil {
+		return err
+	}
+	return uc.restoreMember(ctx, treeID, memberID, history.OldValues, userID)
+}
+
+// RestoreSpouse brings a deleted spouse relationship back, both partners must
+// be in the tree
+func (uc *memberUseCase) RestoreSpouse(ctx context.Context, treeID, spouseID, userID int) error {
+	spouse, err := uc.repo.spouse.GetDeleted(ctx, spouseID)
+	if err != nil {
+		return err
+	}
+	return uc.restoreSpouse(ctx, treeID, spouse, userID)
+}
+
+// PurgePictures deletes from storage the superseded pictures of a tree that
+// are no longer used, after which history can't roll back to them
+func (uc *memberUseCase) PurgePictures(ctx context.Context, treeID int) (int, error) {
+	keys, err := uc.repo.media.PurgePictures(ctx, treeID)
+	if err != nil {
+		return 0, err
+	}
+	for _, key := range keys {
+		if err := uc.s3Client.DeleteImage(ctx, key); err != nil {
+			slog.Warn("delete superseded picture from S3", "error", err, "tree_id", treeID, "picture", key)
+		}
+	}
+	return len(keys), nil
+}
+
+// restoreMember re-validates the member saved in a delete snapshot against the
+// tree as it is now and writes it back with its names and parents
+func (uc *memberUseCase) restoreMember(ctx context.Context, treeID, memberID int, snapshot json.RawMessage, userID int) error {
+	if len(snapshot) == 0 {
+		return domain.NewValidationError("error.history.rollback_missing_snapshot")
+	}
+	var member domain.Member
+	if err := json.Unmarshal(snapshot, &member); err != nil || len(member.Names) == 0 {
+		return domain.NewValidationError("error.history.rollback_invalid_snapshot")
+	}
+	member.MemberID = memberID
+	return uc.restoreDeletedMember(ctx, treeID, &member, userID)
+}
+
+// restoreDeletedMember brings a deleted member back with the given values
+func (uc *memberUseCase) restoreDeletedMember(ctx context.Context, treeID int, member *domain.Member, userID int) error {
+	deleted, err := uc.repo.member.GetDeleted(ctx, member.MemberID)
+	if err != nil {
+		return err
+	}
+	if deleted.TreeID != treeID {
+		return domain.NewNotFoundError("deleted_member")
+	}
+
+	member.MemberID = deleted.MemberID
+	member.TreeID = deleted.TreeID
+	if member.Nicknames == nil {
+		member.Nicknames = []string{}
+	}
+
+	for _, parentID := range []*int{member.FatherID, member.MotherID} {
+		if parentID == nil {
+			continue
+		}
+		if _, err := uc.repo.member.Get(ctx, *parentID); err != nil {
+			if domain.IsDomainError(err, domain.ErrCodeNotFound) {
+				return domain.NewConflictError("error.trash.parent_deleted", nil)
+			}
+			return err
+		}
+	}
+
+	if err := normalizeNameParts(member); err != nil {
+		return err
+	}
+
+	if err := normalizeMemberDates(member); err != nil {
+		return err
+	}
+
+	if !domain.IsValidLivingStatus(member.LivingStatus) {
+		return domain.NewValidationError("error.validation.invalid_living_status")
+	}
+	if member.LivingStatus == domain.LivingStatusLiving && member.DateOfDeath != nil {
+		return domain.NewValidationError("error.validation.living_with_death_date")
+	}
+
+	if err := uc.validator.place.InTree(ctx, treeID, member.BirthPlaceID, member.DeathPlaceID, member.BurialPlaceID); err != nil {
+		return err
+	}
+
+	if err := uc.validateCustomValues(ctx, treeID, member.MemberID, member.CustomFields); err != nil {
+		return err
+	}
+
+	if err := uc.validator.relationship.CheckParents(ctx, member.MemberID, member.FatherID, member.MotherID); err != nil {
+		return err
+	}
+
+	if err := uc.validator.birthDate.Create(ctx, member.BirthRange(), member.FatherID, member.MotherID); err != nil {
+		return err
+	}
+
+	// A picture purged since the delete can't come back
+	if member.Picture != nil && *member.Picture != "" {
+		kept, err := uc.repo.media.IsKeyInUse(ctx, *member.Picture)
+		if err != nil {
+			return err
+		}
+		if !kept {
+			member.Picture = nil
+		}
+	}
+
+	return uc.tx.Do(ctx, func(txCtx context.Context) error {
+		return uc.restoreMemberTx(txCtx, member, userID)
+	})
+}
+
+func (uc *memberUseCase) restoreMemberTx(ctx context.Context, member *domain.Member, userID int) error {
+	version, err := uc.repo.member.Restore(ctx, member.MemberID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.repo.member.Update(ctx, member, version); err != nil {
+		return err
+	}
+
+	if err := uc.ensureParentSpouseRelationship(ctx, member.FatherID, member.MotherID, userID); err != nil {
+		return err
+	}
+
+	newValuesJSON, _ := json.Marshal(member)
+	history := &domain.History{
+		MemberID:      member.MemberID,
+		UserID:        userID,
+		ChangeType:    domain.ChangeTypeRestore,
+		OldValues:     nil,
+		NewValues:     newValuesJSON,
+		MemberVersion: member.Version,
+	}
+	return uc.repo.history.Create(ctx, history)
+}
+
+// restoreSpouse re-runs the marriage rules on a deleted spouse relationship
+// before bringing it back, a marriage that hasn't ended must still be allowed
+// to exist alongside the partners' current marriages
+func (uc *memberUseCase) restoreSpouse(ctx context.Context, treeID int, spouse *domain.Spouse, userID int) error {
+	for _, partnerID := range []int{spouse.FatherID, spouse.MotherID} {
+		partner, err := uc.repo.member.Get(ctx, partnerID)
+		if err != nil {
+			if domain.IsDomainError(err, domain.ErrCodeNotFound) {
+				return domain.NewConflictError("error.trash.partner_deleted", nil)
+			}
+			return err
+		}
+		if partner.TreeID != treeID {
+			return domain.NewNotFoundError("deleted_spouse")
+		}
+	}
+
+	if err := normalizeSpouseDates(spouse); err != nil {
+		return err
+	}
+
+	if err := uc.validator.place.InTree(ctx, treeID, spouse.MarriagePlaceID); err != nil {
+		return err
+	}
+
+	if spouse.DivorceDate == nil {
+		if err := uc.validator.marriage.Create(ctx, spouse.FatherID, spouse.MotherID); err != nil {
+			return err
+		}
+	} else if err := uc.validator.marriage.Permitted(ctx, spouse.FatherID, spouse.MotherID); err != nil {
+		return err
+	}
+
+	if err := uc.validator.marriage.MarriageDate(ctx, spouse.FatherID, spouse.MotherID, spouse.MarriageRange()); err != nil {
+		return err
+	}
+
+	return uc.tx.Do(ctx, func(txCtx context.Context) error {
+		if err := uc.repo.spouse.Restore(txCtx, spouse.SpouseID); err != nil {
+			return err
+		}
+		spouse.DeletedAt = nil
+		newValues, _ := json.Marshal(spouse)
+		uc.recordSpouseHistory(txCtx, spouse.FatherID, spouse.MotherID, domain.ChangeTypeRestoreSpouse, nil, newValues, userID)
+		return nil
+	})
+}
+
+// rollbackSpouse undoes a spouse change recorded in the history of one of the partners
+func (uc *memberUseCase) rollbackSpouse(ctx context.Context, treeID int, history *domain.HistoryWithUser, userID int) error {
+	snapshot := history.OldValues
+	if history.ChangeType == domain.ChangeTypeAddSpouse {
+		snapshot = history.NewValues
+	}
+	if len(snapshot) == 0 {
+		return domain.NewValidationError("error.history.rollback_missing_snapshot")
+	}
+	var rollbackSpouse domain.Spouse
+	if err := json.Unmarshal(snapshot, &rollbackSpouse); err != nil || rollbackSpouse.SpouseID == 0 {
+		return domain.NewValidationError("error.history.rollback_invalid_snapshot")
+	}
+
+	if history.ChangeType == domain.ChangeTypeRemoveSpouse {
+		spouse, err := uc.repo.spouse.GetDeleted(ctx, rollbackSpouse.SpouseID)
+		if err != nil {
+			return err
+		}
+		return uc.restoreSpouse(ctx, treeID, spouse, userID)
+	}
+
+	currentSpouse, err := uc.repo.spouse.Get(ctx, rollbackSpouse.SpouseID)
+	if err != nil {
+		return err
+	}
+	if _, err := uc.getInTree(ctx, treeID, currentSpouse.FatherID); err != nil {
+		return err
+	}
+
+	if history.ChangeType == domain.ChangeTypeAddSpouse {
+		return uc.removeSpouse(ctx, currentSpouse, userID)
+	}
+	return uc.revertSpouse(ctx, treeID, currentSpouse, &rollbackSpouse, userID)
+}
+
+// removeSpouse ends a spouse relationship that has no children
+func (uc *memberUseCase) removeSpouse(ctx context.Context, currentSpouse *domain.Spouse, userID int) error {
+	hasChildren, err := uc.repo.member.HasChildrenWithParents(ctx, currentSpouse.FatherID, currentSpouse.MotherID)
+	if err != nil {
+		return err
+	}
+	if hasChildren {
+		return domain.NewConflictError("error.spouse.has_children", nil)
+	}
+
+	oldValues, _ := json.Marshal(currentSpouse)
+	return uc.tx.Do(ctx, func(txCtx context.Context) error {
+		if err := uc.repo.spouse.Delete(txCtx, currentSpouse.SpouseID); err != nil {
+			return err
+		}
+		uc.recordSpouseHistory(txCtx, currentSpouse.FatherID, currentSpouse.MotherID, domain.ChangeTypeRemoveSpouse, oldValues, nil, userID)
+		return nil
+	})
+}
+
+// revertSpouse sets a live spouse relationship back to earlier values
+func (uc *memberUseCase) revertSpouse(ctx context.Context, treeID int, currentSpouse, rollbackSpouse *domain.Spouse, userID int) error {
+	oldValues, _ := json.Marshal(currentSpouse)
+
+	rollbackSpouse.SpouseID = currentSpouse.SpouseID
+	rollbackSpouse.FatherID = currentSpouse.FatherID
+	rollbackSpouse.MotherID = currentSpouse.MotherID
+	rollbackSpouse.DeletedAt = nil
+	if err := normalizeSpouseDates(rollbackSpouse); err != nil {
+		return err
+	}
+
+	if err := uc.validator.place.InTree(ctx, treeID, rollbackSpouse.MarriagePlaceID); err != nil {
+		return err
+	}
+
+	if err := uc.validator.marriage.MarriageDate(ctx, rollbackSpouse.FatherID, rollbackSpouse.MotherID, rollbackSpouse.MarriageRange()); err != nil {
+		return err
+	}
+
+	return uc.tx.Do(ctx, func(txCtx context.Context) error {
+		if err := uc.repo.spouse.Update(txCtx, rollbackSpouse); err != nil {
+			return err
+		}
+		newValues, _ := json.Marshal(rollbackSpouse)
+		uc.recordSpouseHistory(txCtx, rollbackSpouse.FatherID, rollbackSpouse.MotherID, domain.ChangeTypeUpdateSpouse, oldValues, newValues, userID)
+		return nil
+	})
+}
+
+// rollbackPicture puts back the picture a member had before a picture change,
+// as long as it hasn't been purged
+func (uc *memberUseCase) rollbackPicture(ctx context.Context, treeID int, history *domain.HistoryWithUser, userID int) error {
+	if len(history.OldValues) == 0 {
+		return domain.NewValidationError("error.history.rollback_missing_snapshot")
+	}
+	var snapshot struct {
+		Picture *string `json:"picture"`
+	}
+	if err := json.Unmarshal(history.OldValues, &snapshot); err != nil {
+		return domain.NewValidationError("error.history.rollback_invalid_snapshot")
+	}
+
+	member, err := uc.getInTree(ctx, treeID, history.MemberID)
+	if err != nil {
+		return err
+	}
+	return uc.restorePicture(ctx, member, snapshot.Picture, userID)
+}
+
+// restorePicture puts back a picture the member had, or takes the current one
+// off when it had none
+func (uc *memberUseCase) restorePicture(ctx context.Context, member *domain.Member, picture *string, userID int) error {
+	currentPicture, rollbackPicture := "", ""
+	if member.Picture != nil {
+		currentPicture = *member.Picture
+	}
+	if picture != nil {
+		rollbackPicture = *picture
+	}
+	if currentPicture == rollbackPicture {
+		return nil
+	}
+
+	if rollbackPicture == "" {
+		return uc.tx.Do(ctx, func(txCtx context.Context) error {
+			return uc.deletePictureTx(txCtx, member, userID)
+		})
+	}
+
+	kept, err := uc.repo.media.IsKeyInUse(ctx, rollbackPicture)
+	if err != nil {
+		return err
+	}
+	if !kept {
+		return domain.NewValidationError("error.history.picture_purged")
+	}
+
+	return uc.tx.Do(ctx, func(txCtx context.Context) error {
+		if err := uc.repo.member.UpdatePicture(txCtx, member.MemberID, rollbackPicture); err != nil {
+			return err
+		}
+
+		oldValuesJSON, _ := json.Marshal(map[string]any{"picture": member.Picture})
+		newValuesJSON, _ := json.Marshal(map[string]any{"picture": rollbackPicture})
+		history := &domain.History{
+			MemberID:      member.MemberID,
+			UserID:        userID,
+			ChangeType:    domain.ChangeTypeAddPicture,
+			OldValues:     oldValuesJSON,
+			NewValues:     newValuesJSON,
+			MemberVersion: member.Version + 1,
+		}
+		if err := uc.repo.history.Create(txCtx, history); err != nil {
+			return err
+		}
+
+		return uc.supersedePicture(txCtx, member.TreeID, member.Picture)
+	})
+}
+
+// getInTree returns a live member of the tree
+func (uc *memberUseCase) getInTree(ctx context.Context, treeID, memberID int) (*domain.Member, error) {
+	member, err := uc.repo.member.Get(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if member.TreeID != treeID {
+		return nil, domain.NewNotFoundError("member")
+	}
+	return member, nil
+}
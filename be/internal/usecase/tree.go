@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/escalopa/family-tree/internal/domain"
 )
@@ -16,7 +17,9 @@ type (
 	}
 
 	treeUseCase struct {
-		repo treeUseCaseRepo
+		repo    treeUseCaseRepo
+		privacy treePrivacy
+		past    treePast
 	}
 )
 
@@ -24,6 +27,8 @@ func NewTreeUseCase(
 	memberRepo MemberRepository,
 	spouseRepo SpouseRepository,
 	graphRepo FamilyGraphRepository,
+	treeRepo FamilyTreeRepository,
+	historyRepo HistoryRepository,
 ) *treeUseCase {
 	return &treeUseCase{
 		repo: treeUseCaseRepo{
@@ -31,20 +36,87 @@ func NewTreeUseCase(
 			spouse: spouseRepo,
 			graph:  graphRepo,
 		},
+		privacy: treePrivacy{tree: treeRepo, member: memberRepo},
+		past:    treePast{member: memberRepo, spouse: spouseRepo, history: historyRepo},
 	}
 }
 
-func (uc *treeUseCase) Get(ctx context.Context, treeID int, rootID *int, userRole int) (*domain.MemberTreeNode, error) {
+// load returns the members of the tree, as they are or as they were at asOf,
+// along with the past state they were rebuilt from
+func (uc *treeUseCase) load(ctx context.Context, treeID int, asOf *time.Time) ([]*domain.Member, *domain.TreeState, error) {
+	if asOf == nil {
+		members, err := uc.repo.member.GetAllByTreeID(ctx, treeID)
+		return members, nil, err
+	}
+	state, err := uc.past.At(ctx, treeID, *asOf)
+	if err != nil {
+		return nil, nil, err
+	}
+	return state.Members, state, nil
+}
+
+func (uc *treeUseCase) spouseMap(ctx context.Context, treeID int, state *domain.TreeState) (map[int][]domain.SpouseWithMemberInfo, error) {
+	if state != nil {
+		return state.SpouseMap(), nil
+	}
+	return uc.repo.spouse.GetAllSpousesByTreeID(ctx, treeID)
+}
+
+func (uc *treeUseCase) familyUnits(ctx context.Context, treeID int, state *domain.TreeState) ([]*domain.FamilyUnit, error) {
+	units, err := uc.repo.graph.ListFamilyUnitsByTreeID(ctx, treeID)
+	if err != nil || state == nil {
+		return units, err
+	}
+	return state.FamilyUnits(units), nil
+}
+
+func (uc *treeUseCase) Get(ctx context.Context, treeID int, rootID *int, asOf *time.Time, viewer domain.Viewer) (*domain.MemberTreeNode, error) {
+	members, state, err := uc.load(ctx, treeID, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	privacy, err := uc.privacy.For(ctx, treeID, viewer, members)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := uc.get(ctx, treeID, members, state, rootID, privacy)
+	if err != nil {
+		return nil, err
+	}
+	domain.ApplyLivingPolicy(tree, domain.LivingMembers(members, time.Now()), domain.LivingPolicyShow)
+	return tree, nil
+}
+
+// GetPublic returns the tree as a guest sees it, with living members shown
+// as the share link's policy allows
+func (uc *treeUseCase) GetPublic(ctx context.Context, treeID int, livingPolicy string) (*domain.MemberTreeNode, error) {
 	members, err := uc.repo.member.GetAllByTreeID(ctx, treeID)
 	if err != nil {
 		return nil, err
 	}
 
+	viewer := domain.Viewer{Role: domain.RoleGuest, TreeRole: domain.TreeRolePublic}
+	privacy, err := uc.privacy.For(ctx, treeID, viewer, members)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := uc.get(ctx, treeID, members, nil, nil, privacy)
+	if err != nil {
+		return nil, err
+	}
+	domain.ApplyLivingPolicy(tree, domain.LivingMembers(members, time.Now()), livingPolicy)
+	return tree, nil
+}
+
+func (uc *treeUseCase) get(ctx context.Context, treeID int, members []*domain.Member, state *domain.TreeState, rootID *int, privacy *domain.Privacy) (*domain.MemberTreeNode, error) {
 	if len(members) == 0 {
 		return nil, nil
 	}
 
-	spouseMap, err := uc.repo.spouse.GetAllSpousesByTreeID(ctx, treeID)
+	spouseMap, err := uc.spouseMap(ctx, treeID, state)
 	if err != nil {
 		return nil, err
 	}
@@ -61,7 +133,7 @@ func (uc *treeUseCase) Get(ctx context.Context, treeID int, rootID *int, userRol
 
 		// Build tree - generation starts at 1
 		visited := make(map[int]bool)
-		tree := uc.buildTree(memberMap, spouseMap, *rootID, userRole, visited, nil, 1)
+		tree := uc.buildTree(memberMap, spouseMap, *rootID, privacy, visited, nil, 1)
 		return tree, nil
 	}
 
@@ -73,17 +145,22 @@ func (uc *treeUseCase) Get(ctx context.Context, treeID int, rootID *int, userRol
 
 	// Return the first root directly (single tree) - generation starts at 1
 	visited := make(map[int]bool)
-	tree := uc.buildTree(memberMap, spouseMap, roots[0].MemberID, userRole, visited, nil, 1)
+	tree := uc.buildTree(memberMap, spouseMap, roots[0].MemberID, privacy, visited, nil, 1)
 	return tree, nil
 }
 
-func (uc *treeUseCase) List(ctx context.Context, treeID int, rootID *int, userRole int) ([]*domain.MemberWithComputed, error) {
-	members, err := uc.repo.member.GetAllByTreeID(ctx, treeID)
+func (uc *treeUseCase) List(ctx context.Context, treeID int, rootID *int, asOf *time.Time, viewer domain.Viewer) ([]*domain.MemberWithComputed, error) {
+	members, state, err := uc.load(ctx, treeID, asOf)
 	if err != nil {
 		return nil, err
 	}
 
-	spouseMap, err := uc.repo.spouse.GetAllSpousesByTreeID(ctx, treeID)
+	privacy, err := uc.privacy.For(ctx, treeID, viewer, members)
+	if err != nil {
+		return nil, err
+	}
+
+	spouseMap, err := uc.spouseMap(ctx, treeID, state)
 	if err != nil {
 		return nil, err
 	}
@@ -96,22 +173,14 @@ func (uc *treeUseCase) List(ctx context.Context, treeID int, rootID *int, userRo
 	var result []*domain.MemberWithComputed
 	for _, m := range members {
 		spouseInfos := spouseMap[m.MemberID]
-		spouses := uc.hydrateSpouseInfo(spouseInfos, memberMap)
+		spouses := uc.hydrateSpouseInfo(spouseInfos, memberMap, privacy)
 
 		computed := &domain.MemberWithComputed{
 			Member:    *m,
 			IsMarried: len(spouseInfos) > 0,
 			Spouses:   spouses,
 		}
-
-		// Apply privacy rules
-		if m.Gender == "F" && userRole < domain.RoleSuperAdmin {
-			computed.DateOfBirth = nil
-			computed.DateOfDeath = nil
-		}
-		if m.Gender == "F" && userRole < domain.RoleAdmin {
-			computed.Picture = nil
-		}
+		privacy.ApplyComputed(computed)
 
 		result = append(result, computed)
 	}
@@ -119,8 +188,8 @@ func (uc *treeUseCase) List(ctx context.Context, treeID int, rootID *int, userRo
 	return result, nil
 }
 
-func (uc *treeUseCase) GetRelation(ctx context.Context, treeID, member1ID, member2ID int, userRole int) (*domain.MemberTreeNode, error) {
-	members, err := uc.repo.member.GetAllByTreeID(ctx, treeID)
+func (uc *treeUseCase) GetRelation(ctx context.Context, treeID, member1ID, member2ID int, asOf *time.Time, viewer domain.Viewer) (*domain.MemberTreeNode, error) {
+	members, state, err := uc.load(ctx, treeID, asOf)
 	if err != nil {
 		return nil, err
 	}
@@ -129,8 +198,13 @@ func (uc *treeUseCase) GetRelation(ctx context.Context, treeID, member1ID, membe
 		return nil, nil
 	}
 
+	privacy, err := uc.privacy.For(ctx, treeID, viewer, members)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get spouse relationships
-	spouseMap, err := uc.repo.spouse.GetAllSpousesByTreeID(ctx, treeID)
+	spouseMap, err := uc.spouseMap(ctx, treeID, state)
 	if err != nil {
 		return nil, err
 	}
@@ -169,12 +243,12 @@ func (uc *treeUseCase) GetRelation(ctx context.Context, treeID, member1ID, membe
 
 	// Build tree with path highlighting - generation starts at 1
 	visited := make(map[int]bool)
-	tree := uc.buildRelationTree(memberMap, spouseMap, root.MemberID, userRole, visited, pathMembers, 1)
+	tree := uc.buildRelationTree(memberMap, spouseMap, root.MemberID, privacy, visited, pathMembers, 1)
 	return tree, nil
 }
 
-func (uc *treeUseCase) GetGraph(ctx context.Context, treeID int, userRole int) (*domain.FamilyGraph, error) {
-	members, err := uc.repo.member.GetAllByTreeID(ctx, treeID)
+func (uc *treeUseCase) GetGraph(ctx context.Context, treeID int, asOf *time.Time, viewer domain.Viewer) (*domain.FamilyGraph, error) {
+	members, state, err := uc.load(ctx, treeID, asOf)
 	if err != nil {
 		return nil, err
 	}
@@ -182,16 +256,21 @@ func (uc *treeUseCase) GetGraph(ctx context.Context, treeID int, userRole int) (
 		return &domain.FamilyGraph{}, nil
 	}
 
-	units, err := uc.repo.graph.ListFamilyUnitsByTreeID(ctx, treeID)
+	privacy, err := uc.privacy.For(ctx, treeID, viewer, members)
+	if err != nil {
+		return nil, err
+	}
+
+	units, err := uc.familyUnits(ctx, treeID, state)
 	if err != nil {
 		return nil, err
 	}
 
-	return uc.buildGraph(members, units, userRole, nil), nil
+	return uc.buildGraph(members, units, privacy, nil), nil
 }
 
-func (uc *treeUseCase) GetRelationGraph(ctx context.Context, treeID, member1ID, member2ID int, userRole int) (*domain.FamilyGraph, error) {
-	members, err := uc.repo.member.GetAllByTreeID(ctx, treeID)
+func (uc *treeUseCase) GetRelationGraph(ctx context.Context, treeID, member1ID, member2ID int, asOf *time.Time, viewer domain.Viewer) (*domain.FamilyGraph, error) {
+	members, state, err := uc.load(ctx, treeID, asOf)
 	if err != nil {
 		return nil, err
 	}
@@ -199,6 +278,11 @@ func (uc *treeUseCase) GetRelationGraph(ctx context.Context, treeID, member1ID,
 		return &domain.FamilyGraph{}, nil
 	}
 
+	privacy, err := uc.privacy.For(ctx, treeID, viewer, members)
+	if err != nil {
+		return nil, err
+	}
+
 	memberMap := make(map[int]*domain.Member, len(members))
 	for _, member := range members {
 		memberMap[member.MemberID] = member
@@ -210,7 +294,7 @@ func (uc *treeUseCase) GetRelationGraph(ctx context.Context, treeID, member1ID,
 		return nil, domain.NewNotFoundError("member")
 	}
 
-	units, err := uc.repo.graph.ListFamilyUnitsByTreeID(ctx, treeID)
+	units, err := uc.familyUnits(ctx, treeID, state)
 	if err != nil {
 		return nil, err
 	}
@@ -231,7 +315,7 @@ func (uc *treeUseCase) GetRelationGraph(ctx context.Context, treeID, member1ID,
 		path.units[unitID] = true
 	}
 
-	graph := uc.buildGraph(members, units, userRole, path)
+	graph := uc.buildGraph(members, units, privacy, path)
 	graph.PathPersonIDs = pathPeople
 	graph.PathFamilyUnitIDs = pathUnits
 	return graph, nil
@@ -329,7 +413,7 @@ func (uc *treeUseCase) findOldestRoot(members []*domain.Member) *domain.Member {
 	return members[0]
 }
 
-func (uc *treeUseCase) buildTree(memberMap map[int]*domain.Member, spouseMap map[int][]domain.SpouseWithMemberInfo, rootID int, userRole int, visited map[int]bool, pathMembers map[int]bool, generationLevel int) *domain.MemberTreeNode {
+func (uc *treeUseCase) buildTree(memberMap map[int]*domain.Member, spouseMap map[int][]domain.SpouseWithMemberInfo, rootID int, privacy *domain.Privacy, visited map[int]bool, pathMembers map[int]bool, generationLevel int) *domain.MemberTreeNode {
 	// Avoid circular references
 	if visited[rootID] {
 		return nil
@@ -342,7 +426,7 @@ func (uc *treeUseCase) buildTree(memberMap map[int]*domain.Member, spouseMap map
 	}
 
 	spouseInfos := spouseMap[rootID]
-	spouses := uc.hydrateSpouseInfo(spouseInfos, memberMap)
+	spouses := uc.hydrateSpouseInfo(spouseInfos, memberMap, privacy)
 
 	node := &domain.MemberTreeNode{
 		MemberWithComputed: domain.MemberWithComputed{
@@ -355,14 +439,7 @@ func (uc *treeUseCase) buildTree(memberMap map[int]*domain.Member, spouseMap map
 		IsInPath: pathMembers != nil && pathMembers[rootID],
 	}
 
-	// Apply privacy rules
-	if root.Gender == "F" && userRole < domain.RoleSuperAdmin {
-		node.DateOfBirth = nil
-		node.DateOfDeath = nil
-	}
-	if root.Gender == "F" && userRole < domain.RoleAdmin {
-		node.Picture = nil
-	}
+	privacy.ApplyComputed(&node.MemberWithComputed)
 
 	// Find ALL children of this member (including children with spouses)
 	// Group children by their other parent (spouse) to handle multiple marriages
@@ -425,7 +502,7 @@ func (uc *treeUseCase) buildTree(memberMap map[int]*domain.Member, spouseMap map
 
 	// Recursively build child nodes
 	for _, childMember := range allChildren {
-		child := uc.buildTree(memberMap, spouseMap, childMember.MemberID, userRole, visited, pathMembers, generationLevel+1)
+		child := uc.buildTree(memberMap, spouseMap, childMember.MemberID, privacy, visited, pathMembers, generationLevel+1)
 		if child != nil {
 			node.Children = append(node.Children, child)
 		}
@@ -434,7 +511,7 @@ func (uc *treeUseCase) buildTree(memberMap map[int]*domain.Member, spouseMap map
 	return node
 }
 
-func (uc *treeUseCase) buildRelationTree(memberMap map[int]*domain.Member, spouseMap map[int][]domain.SpouseWithMemberInfo, rootID int, userRole int, visited map[int]bool, pathMembers map[int]bool, generationLevel int) *domain.MemberTreeNode {
+func (uc *treeUseCase) buildRelationTree(memberMap map[int]*domain.Member, spouseMap map[int][]domain.SpouseWithMemberInfo, rootID int, privacy *domain.Privacy, visited map[int]bool, pathMembers map[int]bool, generationLevel int) *domain.MemberTreeNode {
 	if !pathMembers[rootID] || visited[rootID] {
 		return nil
 	}
@@ -446,7 +523,7 @@ func (uc *treeUseCase) buildRelationTree(memberMap map[int]*domain.Member, spous
 	}
 
 	spouseInfos := spouseMap[rootID]
-	spouses := uc.hydrateSpouseInfo(spouseInfos, memberMap)
+	spouses := uc.hydrateSpouseInfo(spouseInfos, memberMap, privacy)
 
 	node := &domain.MemberTreeNode{
 		MemberWithComputed: domain.MemberWithComputed{
@@ -459,13 +536,7 @@ func (uc *treeUseCase) buildRelationTree(memberMap map[int]*domain.Member, spous
 		IsInPath: true,
 	}
 
-	if root.Gender == "F" && userRole < domain.RoleSuperAdmin {
-		node.DateOfBirth = nil
-		node.DateOfDeath = nil
-	}
-	if root.Gender == "F" && userRole < domain.RoleAdmin {
-		node.Picture = nil
-	}
+	privacy.ApplyComputed(&node.MemberWithComputed)
 
 	var pathChildren []*domain.Member
 	for _, m := range memberMap {
@@ -498,7 +569,7 @@ func (uc *treeUseCase) buildRelationTree(memberMap map[int]*domain.Member, spous
 	})
 
 	for _, childMember := range pathChildren {
-		child := uc.buildRelationTree(memberMap, spouseMap, childMember.MemberID, userRole, visited, pathMembers, generationLevel+1)
+		child := uc.buildRelationTree(memberMap, spouseMap, childMember.MemberID, privacy, visited, pathMembers, generationLevel+1)
 		if child != nil {
 			node.Children = append(node.Children, child)
 		}
@@ -567,7 +638,7 @@ func (uc *treeUseCase) findPath(memberMap map[int]*domain.Member, spouseMap map[
 	return nil
 }
 
-func (uc *treeUseCase) hydrateSpouseInfo(spouseInfos []domain.SpouseWithMemberInfo, memberMap map[int]*domain.Member) []domain.SpouseWithMemberInfo {
+func (uc *treeUseCase) hydrateSpouseInfo(spouseInfos []domain.SpouseWithMemberInfo, memberMap map[int]*domain.Member, privacy *domain.Privacy) []domain.SpouseWithMemberInfo {
 	if len(spouseInfos) == 0 {
 		return nil
 	}
@@ -578,6 +649,7 @@ func (uc *treeUseCase) hydrateSpouseInfo(spouseInfos []domain.SpouseWithMemberIn
 			spouseInfo.Names = spouse.Names
 			spouseInfo.Gender = spouse.Gender
 			spouseInfo.Picture = spouse.Picture
+			privacy.ApplySpouse(&spouseInfo)
 			spouses = append(spouses, spouseInfo)
 		}
 	}
@@ -589,7 +661,7 @@ type familyGraphPath struct {
 	units  map[int]bool
 }
 
-func (uc *treeUseCase) buildGraph(members []*domain.Member, units []*domain.FamilyUnit, userRole int, path *familyGraphPath) *domain.FamilyGraph {
+func (uc *treeUseCase) buildGraph(members []*domain.Member, units []*domain.FamilyUnit, privacy *domain.Privacy, path *familyGraphPath) *domain.FamilyGraph {
 	graph := &domain.FamilyGraph{
 		People:      make([]*domain.FamilyGraphPerson, 0, len(members)),
 		FamilyUnits: units,
@@ -607,13 +679,7 @@ func (uc *treeUseCase) buildGraph(members []*domain.Member, units []*domain.Fami
 			IsInPath: path != nil && path.people[member.MemberID],
 		}
 
-		if member.Gender == "F" && userRole < domain.RoleSuperAdmin {
-			person.DateOfBirth = nil
-			person.DateOfDeath = nil
-		}
-		if member.Gender == "F" && userRole < domain.RoleAdmin {
-			person.Picture = nil
-		}
+		privacy.ApplyComputed(&person.MemberWithComputed)
 
 		personByID[member.MemberID] = person
 		graph.People = append(graph.People, person)
@@ -678,9 +744,14 @@ func (uc *treeUseCase) buildGraph(members []*domain.Member, units []*domain.Fami
 		}
 	}
 
+	// order by the real dates, a stripped date may have lost its year
+	memberByID := make(map[int]*domain.Member, len(members))
+	for _, member := range members {
+		memberByID[member.MemberID] = member
+	}
 	sort.Slice(graph.People, func(i, j int) bool {
-		left := graph.People[i]
-		right := graph.People[j]
+		left := memberByID[graph.People[i].MemberID]
+		right := memberByID[graph.People[j].MemberID]
 		if left.DateOfBirth == nil && right.DateOfBirth == nil {
 			return left.MemberID < right.MemberID
 		}
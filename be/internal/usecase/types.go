@@ -15,6 +15,7 @@ type UserRepository interface {
 	List(ctx context.Context, filter domain.UserFilter, cursor *string, limit int) ([]*domain.User, *string, error)
 	GetWithScore(ctx context.Context, userID int) (*domain.UserWithScore, error)
 	CreateRoleHistory(ctx context.Context, userID, oldRoleID, newRoleID, changedBy int, actionType string) error
+	ListRoleHistory(ctx context.Context, userID int, cursor *string, limit int) ([]*domain.RoleHistory, *string, error)
 }
 
 type SessionRepository interface {
@@ -32,6 +33,10 @@ type MemberRepository interface {
 	Delete(ctx context.Context, memberID int) (*string, error)
 	UpdatePicture(ctx context.Context, memberID int, pictureURL string) error
 	DeletePicture(ctx context.Context, memberID int) error
+	GetBiographies(ctx context.Context, memberID int) (map[string]string, error)
+	UpdateBiography(ctx context.Context, memberID int, languageCode, biography string, expectedVersion int) (int, error)
+	GetNotes(ctx context.Context, memberID int) (*string, error)
+	UpdateNotes(ctx context.Context, memberID int, notes string, expectedVersion int) (int, error)
 	List(ctx context.Context, filter domain.MemberFilter, cursor *string, limit int) ([]*domain.Member, *string, error)
 	GetAll(ctx context.Context) ([]*domain.Member, error)
 	GetAllByTreeID(ctx context.Context, treeID int) ([]*domain.Member, error)
@@ -39,6 +44,9 @@ type MemberRepository interface {
 	GetChildrenByParents(ctx context.Context, fatherID, motherID int) ([]*domain.Member, error)
 	GetSiblingsByMemberID(ctx context.Context, memberID int) ([]*domain.Member, error)
 	HasChildrenWithParents(ctx context.Context, fatherID, motherID int) (bool, error)
+	ListDeleted(ctx context.Context, treeID int) ([]*domain.TrashedMember, error)
+	GetDeleted(ctx context.Context, memberID int) (*domain.Member, error)
+	Restore(ctx context.Context, memberID int) (int, error)
 }
 
 type FamilyTreeRepository interface {
@@ -46,14 +54,29 @@ type FamilyTreeRepository interface {
 	ListForUser(ctx context.Context, userID int) ([]*domain.FamilyTree, error)
 	GetForUser(ctx context.Context, treeID, userID int) (*domain.FamilyTree, error)
 	HasAccess(ctx context.Context, treeID, userID int) (bool, error)
+	GetRole(ctx context.Context, treeID, userID int) (string, error)
+	ListMemberships(ctx context.Context, treeID int) ([]*domain.FamilyTreeMembership, error)
+	UpdateMembershipRole(ctx context.Context, treeID, userID int, role string) (*domain.RevokedAccess, error)
+	DeleteMembership(ctx context.Context, treeID, userID int) (*domain.RevokedAccess, error)
+	TransferOwnership(ctx context.Context, treeID, ownerUserID, newOwnerUserID int) (*domain.RevokedAccess, error)
+	GetNameSettings(ctx context.Context, treeID int) (*domain.NameSettings, error)
+	UpdateNameSettings(ctx context.Context, treeID int, settings *domain.NameSettings) error
+	GetPrivacyPolicy(ctx context.Context, treeID int) (*domain.PrivacyPolicy, error)
+	UpdatePrivacyPolicy(ctx context.Context, treeID int, policy *domain.PrivacyPolicy) error
 	CreateInvitation(ctx context.Context, invitation *domain.FamilyTreeInvitation) error
 	ListTreeInvitations(ctx context.Context, treeID, userID int) ([]*domain.FamilyTreeInvitation, error)
 	ListPendingInvitationsForUser(ctx context.Context, userID int) ([]*domain.FamilyTreeInvitation, error)
-	RespondToInvitation(ctx context.Context, invitationID, userID int, accept bool) error
+	RespondToInvitation(ctx context.Context, invitationID, userID int, accept bool) (int, error)
 	CreateShareLink(ctx context.Context, link *domain.FamilyTreeShareLink) error
 	ListShareLinks(ctx context.Context, treeID, userID int) ([]*domain.FamilyTreeShareLink, error)
+	UpdateShareLinkPolicy(ctx context.Context, treeID, shareID, userID int, livingPolicy string) error
 	RevokeShareLink(ctx context.Context, treeID, shareID, userID int) error
 	ConsumeShareLink(ctx context.Context, token string) (*domain.FamilyTreeShareLink, error)
+	CreateCalendarFeed(ctx context.Context, feed *domain.FamilyTreeCalendarFeed) error
+	ListCalendarFeeds(ctx context.Context, treeID, userID int) ([]*domain.FamilyTreeCalendarFeed, error)
+	RevokeCalendarFeed(ctx context.Context, treeID, feedID, userID int) error
+	GetCalendarFeedByToken(ctx context.Context, token string) (*domain.FamilyTreeCalendarFeed, error)
+	TouchCalendarFeed(ctx context.Context, feed *domain.FamilyTreeCalendarFeed) error
 }
 
 type SpouseRepository interface {
@@ -65,17 +88,123 @@ type SpouseRepository interface {
 	GetAllSpouses(ctx context.Context) (map[int][]domain.SpouseWithMemberInfo, error)
 	GetAllSpousesByTreeID(ctx context.Context, treeID int) (map[int][]domain.SpouseWithMemberInfo, error)
 	GetByMemberID(ctx context.Context, memberID int) ([]domain.SpouseWithMemberInfo, error)
+	GetDeleted(ctx context.Context, spouseID int) (*domain.Spouse, error)
+	ListDeletedByTreeID(ctx context.Context, treeID int) ([]*domain.Spouse, error)
+	ListAllByTreeID(ctx context.Context, treeID int) ([]*domain.Spouse, error)
+	Restore(ctx context.Context, spouseID int) error
+}
+
+type PlaceRepository interface {
+	Create(ctx context.Context, place *domain.Place) error
+	Get(ctx context.Context, placeID int) (*domain.Place, error)
+	ListByTreeID(ctx context.Context, treeID int) ([]*domain.Place, error)
+	Update(ctx context.Context, place *domain.Place) error
+	Delete(ctx context.Context, placeID int) error
+}
+
+type CustomFieldRepository interface {
+	Create(ctx context.Context, field *domain.CustomField) error
+	Get(ctx context.Context, fieldID int) (*domain.CustomField, error)
+	ListByTreeID(ctx context.Context, treeID int) ([]*domain.CustomField, error)
+	Update(ctx context.Context, field *domain.CustomField) error
+	Delete(ctx context.Context, fieldID int) error
+	HasValuesOutside(ctx context.Context, fieldID int, options []string) (bool, error)
+}
+
+type NameSpellingRepository interface {
+	ListByTreeID(ctx context.Context, treeID int) ([]*domain.NameSpelling, error)
+	Upsert(ctx context.Context, spelling *domain.NameSpelling) error
+	Delete(ctx context.Context, treeID int, arabic, languageCode string) error
+}
+
+type EventRepository interface {
+	Create(ctx context.Context, event *domain.MemberEvent) error
+	Get(ctx context.Context, eventID int) (*domain.MemberEvent, error)
+	ListByMemberID(ctx context.Context, memberID int) ([]*domain.MemberEvent, error)
+	ListByTreeID(ctx context.Context, treeID int) ([]*domain.MemberEvent, error)
+	Update(ctx context.Context, event *domain.MemberEvent) error
+	Delete(ctx context.Context, eventID int) error
+}
+
+type MediaRepository interface {
+	Create(ctx context.Context, media *domain.Media) error
+	Get(ctx context.Context, mediaID int) (*domain.Media, error)
+	ListByTreeID(ctx context.Context, treeID int) ([]*domain.Media, error)
+	ListByMemberID(ctx context.Context, memberID int) ([]*domain.Media, error)
+	Update(ctx context.Context, media *domain.Media) error
+	Delete(ctx context.Context, mediaID int) error
+	UpsertTag(ctx context.Context, mediaID int, tag domain.MediaTag) error
+	DeleteTag(ctx context.Context, mediaID, memberID int) error
+	IsKeyInUse(ctx context.Context, key string) (bool, error)
+	SupersedePicture(ctx context.Context, treeID int, key string) error
+	PurgePictures(ctx context.Context, treeID int) ([]string, error)
+}
+
+type SourceRepository interface {
+	Create(ctx context.Context, source *domain.Source) error
+	Get(ctx context.Context, sourceID int) (*domain.Source, error)
+	ListByTreeID(ctx context.Context, treeID int) ([]*domain.Source, error)
+	Update(ctx context.Context, source *domain.Source) error
+	UpdateAttachment(ctx context.Context, sourceID int, key *string) error
+	Delete(ctx context.Context, sourceID int) error
+}
+
+type CitationRepository interface {
+	Create(ctx context.Context, citation *domain.Citation) error
+	Get(ctx context.Context, citationID int) (*domain.Citation, error)
+	ListBySourceID(ctx context.Context, sourceID int) ([]*domain.Citation, error)
+	ListBySubject(ctx context.Context, subjectType string, subjectID int) ([]*domain.Citation, error)
+	Update(ctx context.Context, citation *domain.Citation) error
+	Delete(ctx context.Context, citationID int) error
+	CountByMemberFields(ctx context.Context, memberID int) (map[string]int, error)
+	ListCitedFacts(ctx context.Context, treeID int) ([]domain.FactRef, error)
 }
 
 type FamilyGraphRepository interface {
+	GetFamilyUnit(ctx context.Context, familyUnitID int) (*domain.FamilyUnit, error)
 	ListFamilyUnitsByTreeID(ctx context.Context, treeID int) ([]*domain.FamilyUnit, error)
+	ListFamilyUnitsByPartnerID(ctx context.Context, memberID int) ([]*domain.FamilyUnit, error)
+	CreateFamilyUnit(ctx context.Context, unit *domain.FamilyUnit) error
+	UpdateFamilyUnit(ctx context.Context, unit *domain.FamilyUnit) error
+	DeleteFamilyUnit(ctx context.Context, familyUnitID int) error
+	AddFamilyUnitChild(ctx context.Context, familyUnitID, childID int, relationType string) error
+	RemoveFamilyUnitChild(ctx context.Context, familyUnitID, childID int) error
+}
+
+type ProposalRepository interface {
+	Create(ctx context.Context, proposal *domain.Proposal) error
+	Get(ctx context.Context, proposalID int) (*domain.Proposal, error)
+	List(ctx context.Context, filter domain.ProposalFilter, cursor *string, limit int) ([]*domain.Proposal, *string, error)
+	Revise(ctx context.Context, proposal *domain.Proposal) error
+	Lock(ctx context.Context, proposalID int, status string) error
+	Review(ctx context.Context, proposal *domain.Proposal, fromStatus string) error
+}
+
+type AuditRepository interface {
+	Create(ctx context.Context, event *domain.AuditEvent) error
+	List(ctx context.Context, filter domain.AuditFilter, cursor *string, limit int) ([]*domain.AuditEvent, *string, error)
+}
+
+type ChangesetRepository interface {
+	Create(ctx context.Context, changeset *domain.Changeset) error
+	Get(ctx context.Context, treeID int, changesetID string) (*domain.Changeset, error)
+	List(ctx context.Context, treeID int, cursor *string, limit int) ([]*domain.Changeset, *string, error)
+	MarkReverted(ctx context.Context, changeset *domain.Changeset) error
 }
 
 type HistoryRepository interface {
 	Create(ctx context.Context, history *domain.History) error
 	CreateBatch(ctx context.Context, histories ...*domain.History) error
 	Get(ctx context.Context, historyID int) (*domain.HistoryWithUser, error)
+	GetLatest(ctx context.Context, memberID int, changeType string) (*domain.History, error)
+	GetSnapshot(ctx context.Context, memberID, version int) (*domain.History, error)
+	ListByTreeIDSince(ctx context.Context, treeID int, since time.Time, changeTypes []string) ([]*domain.History, error)
 	GetByMemberID(ctx context.Context, memberID int, cursor *string, limit int) ([]*domain.HistoryWithUser, *string, error)
+	ListByMemberIDSinceVersion(ctx context.Context, memberID, version int) ([]*domain.HistoryWithUser, error)
+	ListByTreeID(ctx context.Context, filter domain.ActivityFilter, cursor *string, limit int) ([]*domain.HistoryWithUser, *string, error)
+	ListByChangeset(ctx context.Context, treeID int, changesetID string) ([]*domain.History, error)
+	ListByMemberIDUntil(ctx context.Context, memberID, historyID int) ([]*domain.History, error)
+	ListMemberIDsChangedAfter(ctx context.Context, memberIDs []int, historyID int, changesetID string) ([]int, error)
 	GetByUserID(ctx context.Context, userID int, cursor *string, limit int) ([]*domain.HistoryWithUser, *string, error)
 }
 
@@ -85,6 +214,7 @@ type ScoreRepository interface {
 	GetLeaderboard(ctx context.Context, limit int) ([]*domain.UserScore, error)
 	GetTotalByUserID(ctx context.Context, userID int) (int, error)
 	DeleteByMemberAndField(ctx context.Context, memberID int, fieldName string, memberVersion int) error
+	DeleteByChangeset(ctx context.Context, changesetID string) error
 }
 
 type RoleRepository interface {
@@ -128,20 +258,40 @@ type UserLanguagePreferenceRepository interface {
 	Upsert(ctx context.Context, pref *domain.UserLanguagePreference) error
 }
 
+// MemberEditor applies member changes with the same checks, history and
+// scores as a direct edit
+type MemberEditor interface {
+	Create(ctx context.Context, member *domain.Member, userID int) error
+	Update(ctx context.Context, member *domain.Member, expectedVersion, userID int) error
+}
+
+// SpouseEditor applies spouse relationship changes with the same checks and
+// history as a direct edit
+type SpouseEditor interface {
+	Create(ctx context.Context, spouse *domain.Spouse, userID int) error
+	Update(ctx context.Context, spouse *domain.Spouse, userID int) error
+	Delete(ctx context.Context, spouseID, userID int) error
+}
+
 type MarriageValidator interface {
 	Create(ctx context.Context, memberAID, memberBID int) error
-	MarriageDate(ctx context.Context, fatherID, motherID int, marriageDate *time.Time) error
+	Permitted(ctx context.Context, memberAID, memberBID int) error
+	MarriageDate(ctx context.Context, fatherID, motherID int, marriageDate *domain.DateRange) error
 }
 
 type BirthDateValidator interface {
-	Update(ctx context.Context, memberID int, newBirthDate *time.Time) error
-	Create(ctx context.Context, childBirth *time.Time, fatherID, motherID *int) error
+	Update(ctx context.Context, memberID int, newBirthDate *domain.DateRange) error
+	Create(ctx context.Context, childBirth *domain.DateRange, fatherID, motherID *int) error
 }
 
 type RelationshipValidator interface {
 	CheckParents(ctx context.Context, memberID int, fatherID, motherID *int) error
 }
 
+type PlaceValidator interface {
+	InTree(ctx context.Context, treeID int, placeIDs ...*int) error
+}
+
 type TransactionManager interface {
 	Do(ctx context.Context, fn func(ctx context.Context) error) error
 }
@@ -15,7 +15,8 @@ type (
 	}
 
 	userUseCase struct {
-		repo userUseCaseRepo
+		repo  userUseCaseRepo
+		audit auditLog
 	}
 )
 
@@ -23,6 +24,7 @@ func NewUserUseCase(
 	userRepo UserRepository,
 	scoreRepo ScoreRepository,
 	historyRepo HistoryRepository,
+	auditRepo AuditRepository,
 ) *userUseCase {
 	return &userUseCase{
 		repo: userUseCaseRepo{
@@ -30,6 +32,7 @@ func NewUserUseCase(
 			score:   scoreRepo,
 			history: historyRepo,
 		},
+		audit: auditLog{repo: auditRepo},
 	}
 }
 
@@ -53,23 +56,30 @@ func (uc *userUseCase) determineRoleActionType(oldRoleID, newRoleID int) string
 }
 
 func (uc *userUseCase) Update(ctx context.Context, userID int, roleID *int, isActive *bool, changedBy int) error {
-	var oldRoleID int
-	var recordRoleHistory bool
-
-	if roleID != nil {
-		user, err := uc.repo.user.Get(ctx, userID)
-		if err != nil {
-			return err
-		}
-		oldRoleID = user.RoleID
-		recordRoleHistory = true
+	oldUser, err := uc.repo.user.Get(ctx, userID)
+	if err != nil {
+		return err
 	}
+	oldRoleID := oldUser.RoleID
 
 	if err := uc.repo.user.Update(ctx, userID, roleID, isActive); err != nil {
 		return err
 	}
 
-	if recordRoleHistory {
+	if isActive != nil && *isActive != oldUser.IsActive {
+		action := domain.AuditActionUserDeactivated
+		if *isActive {
+			action = domain.AuditActionUserActivated
+		}
+		uc.audit.record(ctx, &domain.AuditEvent{
+			ActorUserID: &changedBy,
+			Action:      action,
+			TargetType:  domain.AuditTargetUser,
+			TargetID:    auditID(userID),
+		})
+	}
+
+	if roleID != nil {
 		actionType := uc.determineRoleActionType(oldRoleID, *roleID)
 		if err := uc.repo.user.CreateRoleHistory(ctx, userID, oldRoleID, *roleID, changedBy, actionType); err != nil {
 			slog.Error("record role change history",
@@ -81,6 +91,16 @@ func (uc *userUseCase) Update(ctx context.Context, userID int, roleID *int, isAc
 				"action_type", actionType,
 			)
 		}
+
+		if *roleID != oldRoleID {
+			uc.audit.record(ctx, &domain.AuditEvent{
+				ActorUserID: &changedBy,
+				Action:      domain.AuditActionUserRoleChanged,
+				TargetType:  domain.AuditTargetUser,
+				TargetID:    auditID(userID),
+				Details:     auditDetails(map[string]any{"old_role_id": oldRoleID, "new_role_id": *roleID}),
+			})
+		}
 	}
 
 	return nil
@@ -94,6 +114,10 @@ func (uc *userUseCase) ListScoreHistory(ctx context.Context, userID int, cursor
 	return uc.repo.score.GetByUserID(ctx, userID, cursor, limit)
 }
 
+func (uc *userUseCase) ListRoleHistory(ctx context.Context, userID int, cursor *string, limit int) ([]*domain.RoleHistory, *string, error) {
+	return uc.repo.user.ListRoleHistory(ctx, userID, cursor, limit)
+}
+
 func (uc *userUseCase) ListChanges(ctx context.Context, userID int, cursor *string, limit int) ([]*domain.HistoryWithUser, *string, error) {
 	return uc.repo.history.GetByUserID(ctx, userID, cursor, limit)
 }
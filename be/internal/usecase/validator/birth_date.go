@@ -2,7 +2,6 @@ package validator
 
 import (
 	"context"
-	"time"
 
 	"github.com/escalopa/family-tree/internal/domain"
 	"github.com/escalopa/family-tree/internal/usecase"
@@ -22,7 +21,7 @@ func NewBirthDateValidator(memberRepo usecase.MemberRepository, spouseRepo useca
 }
 
 // Update validates birth date changes for a member
-func (v *BirthDateValidator) Update(ctx context.Context, memberID int, newBirthDate *time.Time) error {
+func (v *BirthDateValidator) Update(ctx context.Context, memberID int, newBirthDate *domain.DateRange) error {
 	if newBirthDate == nil {
 		return nil
 	}
@@ -51,7 +50,8 @@ func (v *BirthDateValidator) Update(ctx context.Context, memberID int, newBirthD
 }
 
 // Create validates birth date when creating/updating a child
-func (v *BirthDateValidator) Create(ctx context.Context, childBirth *time.Time, fatherID, motherID *int) error {
+// Dates are ranges, so a rule only fails when no day in the ranges could satisfy it
+func (v *BirthDateValidator) Create(ctx context.Context, childBirth *domain.DateRange, fatherID, motherID *int) error {
 	if childBirth == nil {
 		return nil
 	}
@@ -62,7 +62,7 @@ func (v *BirthDateValidator) Create(ctx context.Context, childBirth *time.Time,
 		if err != nil {
 			return err
 		}
-		if father.DateOfBirth != nil && !father.DateOfBirth.Before(*childBirth) {
+		if fatherBirth := father.BirthRange(); fatherBirth != nil && !fatherBirth.CanBeBefore(childBirth) {
 			return domain.NewValidationError("error.member.parent_born_after_child")
 		}
 	}
@@ -72,7 +72,7 @@ func (v *BirthDateValidator) Create(ctx context.Context, childBirth *time.Time,
 		if err != nil {
 			return err
 		}
-		if mother.DateOfBirth != nil && !mother.DateOfBirth.Before(*childBirth) {
+		if motherBirth := mother.BirthRange(); motherBirth != nil && !motherBirth.CanBeBefore(childBirth) {
 			return domain.NewValidationError("error.member.parent_born_after_child")
 		}
 	}
@@ -88,14 +88,14 @@ func (v *BirthDateValidator) Create(ctx context.Context, childBirth *time.Time,
 }
 
 // validateBirthBeforeMarriages ensures member's birth date is before all their marriage dates
-func (v *BirthDateValidator) validateBirthBeforeMarriages(ctx context.Context, memberID int, birthDate *time.Time) error {
+func (v *BirthDateValidator) validateBirthBeforeMarriages(ctx context.Context, memberID int, birthDate *domain.DateRange) error {
 	spouses, err := v.spouseRepo.GetByMemberID(ctx, memberID)
 	if err != nil && !domain.IsDomainError(err, domain.ErrCodeNotFound) {
 		return err
 	}
 
 	for _, spouse := range spouses {
-		if spouse.MarriageDate != nil && !birthDate.Before(*spouse.MarriageDate) {
+		if marriage := spouse.MarriageRange(); marriage != nil && !birthDate.CanBeBefore(marriage) {
 			return domain.NewValidationError("error.member.birth_after_marriage")
 		}
 	}
@@ -104,14 +104,16 @@ func (v *BirthDateValidator) validateBirthBeforeMarriages(ctx context.Context, m
 }
 
 // validateBirthAfterParents ensures member's birth date is after parent birth dates
-func (v *BirthDateValidator) validateBirthAfterParents(ctx context.Context, member *domain.Member, birthDate *time.Time) error {
+func (v *BirthDateValidator) validateBirthAfterParents(ctx context.Context, member *domain.Member, birthDate *domain.DateRange) error {
 	if member.FatherID != nil {
 		father, err := v.memberRepo.Get(ctx, *member.FatherID)
 		if err != nil && !domain.IsDomainError(err, domain.ErrCodeNotFound) {
 			return err
 		}
-		if err == nil && father.DateOfBirth != nil && !father.DateOfBirth.Before(*birthDate) {
-			return domain.NewValidationError("error.member.parent_born_after_child")
+		if err == nil {
+			if parentBirth := father.BirthRange(); parentBirth != nil && !parentBirth.CanBeBefore(birthDate) {
+				return domain.NewValidationError("error.member.parent_born_after_child")
+			}
 		}
 	}
 
@@ -120,8 +122,10 @@ func (v *BirthDateValidator) validateBirthAfterParents(ctx context.Context, memb
 		if err != nil && !domain.IsDomainError(err, domain.ErrCodeNotFound) {
 			return err
 		}
-		if err == nil && mother.DateOfBirth != nil && !mother.DateOfBirth.Before(*birthDate) {
-			return domain.NewValidationError("error.member.parent_born_after_child")
+		if err == nil {
+			if parentBirth := mother.BirthRange(); parentBirth != nil && !parentBirth.CanBeBefore(birthDate) {
+				return domain.NewValidationError("error.member.parent_born_after_child")
+			}
 		}
 	}
 
@@ -129,14 +133,14 @@ func (v *BirthDateValidator) validateBirthAfterParents(ctx context.Context, memb
 }
 
 // validateBirthBeforeChildren ensures member's birth date is before children birth dates
-func (v *BirthDateValidator) validateBirthBeforeChildren(ctx context.Context, memberID int, birthDate *time.Time) error {
+func (v *BirthDateValidator) validateBirthBeforeChildren(ctx context.Context, memberID int, birthDate *domain.DateRange) error {
 	children, err := v.memberRepo.GetChildrenByParentID(ctx, memberID)
 	if err != nil && !domain.IsDomainError(err, domain.ErrCodeNotFound) {
 		return err
 	}
 
 	for _, child := range children {
-		if child.DateOfBirth != nil && !birthDate.Before(*child.DateOfBirth) {
+		if childBirth := child.BirthRange(); childBirth != nil && !birthDate.CanBeBefore(childBirth) {
 			return domain.NewValidationError("error.member.parent_born_after_child")
 		}
 	}
@@ -145,7 +149,7 @@ func (v *BirthDateValidator) validateBirthBeforeChildren(ctx context.Context, me
 }
 
 // validateBirthAfterParentsMarriage ensures child's birth is after parents' marriage date
-func (v *BirthDateValidator) validateBirthAfterParentsMarriage(ctx context.Context, childBirth *time.Time, fatherID, motherID int) error {
+func (v *BirthDateValidator) validateBirthAfterParentsMarriage(ctx context.Context, childBirth *domain.DateRange, fatherID, motherID int) error {
 	spouse, err := v.spouseRepo.GetByParents(ctx, fatherID, motherID)
 	if err != nil {
 		if domain.IsDomainError(err, domain.ErrCodeNotFound) {
@@ -154,7 +158,7 @@ func (v *BirthDateValidator) validateBirthAfterParentsMarriage(ctx context.Conte
 		return err
 	}
 
-	if spouse.MarriageDate != nil && childBirth.Before(*spouse.MarriageDate) {
+	if marriage := spouse.MarriageRange(); marriage != nil && childBirth.IsDefinitelyBefore(marriage) {
 		return domain.NewValidationError("error.member.birth_before_parents_marriage")
 	}
 
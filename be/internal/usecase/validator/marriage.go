@@ -2,7 +2,6 @@ package validator
 
 import (
 	"context"
-	"time"
 
 	"github.com/escalopa/family-tree/internal/domain"
 	"github.com/escalopa/family-tree/internal/usecase"
@@ -11,46 +10,67 @@ import (
 type MarriageValidator struct {
 	memberRepo usecase.MemberRepository
 	spouseRepo usecase.SpouseRepository
+	graphRepo  usecase.FamilyGraphRepository
 }
 
-func NewMarriageValidator(memberRepo usecase.MemberRepository, spouseRepo usecase.SpouseRepository) *MarriageValidator {
+func NewMarriageValidator(memberRepo usecase.MemberRepository, spouseRepo usecase.SpouseRepository, graphRepo usecase.FamilyGraphRepository) *MarriageValidator {
 	return &MarriageValidator{
 		memberRepo: memberRepo,
 		spouseRepo: spouseRepo,
+		graphRepo:  graphRepo,
 	}
 }
 
 // Create validates that two people can be married according to Islamic rules
 func (v *MarriageValidator) Create(ctx context.Context, memberAID, memberBID int) error {
-	// Fetch both members
-	personA, err := v.memberRepo.Get(ctx, memberAID)
+	personA, personB, err := v.getPair(ctx, memberAID, memberBID)
 	if err != nil {
 		return err
 	}
-	personB, err := v.memberRepo.Get(ctx, memberBID)
-	if err != nil {
+
+	if err := v.validatePermitted(ctx, personA, personB); err != nil {
 		return err
 	}
 
-	// 1. Blood Relationships (Nasab) - Permanent prohibitions
-	if err := v.validateBloodRelationships(ctx, personA, personB); err != nil {
+	// 3. Marriage state - Temporary prohibition
+	return v.validateMarriageState(ctx, personA, personB)
+}
+
+// Permitted validates only the permanent prohibitions, for marriages that
+// have already ended
+func (v *MarriageValidator) Permitted(ctx context.Context, memberAID, memberBID int) error {
+	personA, personB, err := v.getPair(ctx, memberAID, memberBID)
+	if err != nil {
 		return err
 	}
+	return v.validatePermitted(ctx, personA, personB)
+}
 
-	// 2. In-Law Relationships (Marriage-based) - Permanent prohibitions
-	if err := v.validateInLawRelationships(ctx, personA, personB); err != nil {
-		return err
+func (v *MarriageValidator) getPair(ctx context.Context, memberAID, memberBID int) (*domain.Member, *domain.Member, error) {
+	personA, err := v.memberRepo.Get(ctx, memberAID)
+	if err != nil {
+		return nil, nil, err
 	}
+	personB, err := v.memberRepo.Get(ctx, memberBID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return personA, personB, nil
+}
 
-	// 3. Marriage state - Temporary prohibition
-	if err := v.validateMarriageState(ctx, personA, personB); err != nil {
+func (v *MarriageValidator) validatePermitted(ctx context.Context, personA, personB *domain.Member) error {
+	// 1. Blood Relationships (Nasab) - Permanent prohibitions
+	if err := v.validateBloodRelationships(ctx, personA, personB); err != nil {
 		return err
 	}
 
-	return nil
+	// 2. In-Law Relationships (Marriage-based) - Permanent prohibitions
+	return v.validateInLawRelationships(ctx, personA, personB)
 }
 
-func (v *MarriageValidator) MarriageDate(ctx context.Context, fatherID, motherID int, marriageDate *time.Time) error {
+// MarriageDate checks the marriage against the couple's and their children's
+// birth dates, failing only when the date ranges cannot be reconciled
+func (v *MarriageValidator) MarriageDate(ctx context.Context, fatherID, motherID int, marriageDate *domain.DateRange) error {
 	if marriageDate == nil {
 		return nil
 	}
@@ -60,7 +80,7 @@ func (v *MarriageValidator) MarriageDate(ctx context.Context, fatherID, motherID
 	if err != nil {
 		return err
 	}
-	if father.DateOfBirth != nil && marriageDate.Before(*father.DateOfBirth) {
+	if fatherBirth := father.BirthRange(); fatherBirth != nil && marriageDate.IsDefinitelyBefore(fatherBirth) {
 		return domain.NewValidationError("error.spouse.marriage_before_father_birth")
 	}
 
@@ -68,7 +88,7 @@ func (v *MarriageValidator) MarriageDate(ctx context.Context, fatherID, motherID
 	if err != nil {
 		return err
 	}
-	if mother.DateOfBirth != nil && marriageDate.Before(*mother.DateOfBirth) {
+	if motherBirth := mother.BirthRange(); motherBirth != nil && marriageDate.IsDefinitelyBefore(motherBirth) {
 		return domain.NewValidationError("error.spouse.marriage_before_mother_birth")
 	}
 
@@ -79,7 +99,7 @@ func (v *MarriageValidator) MarriageDate(ctx context.Context, fatherID, motherID
 	}
 
 	for _, child := range children {
-		if child.DateOfBirth != nil && marriageDate.After(*child.DateOfBirth) {
+		if childBirth := child.BirthRange(); childBirth != nil && childBirth.IsDefinitelyBefore(marriageDate) {
 			return domain.NewValidationError("error.spouse.marriage_after_child_birth")
 		}
 	}
@@ -352,15 +372,33 @@ func (v *MarriageValidator) isStepRelationship(ctx context.Context, personA, per
 	return false, nil
 }
 
-// isCurrentlyMarried checks if a person has an active marriage
+// isCurrentlyMarried checks if a person has an active marriage, a marriage
+// without a divorce date has still ended once its family unit is marked
+// divorced or widowed
 func (v *MarriageValidator) isCurrentlyMarried(ctx context.Context, memberID int) (bool, error) {
 	spouses, err := v.spouseRepo.GetByMemberID(ctx, memberID)
 	if err != nil && !domain.IsDomainError(err, domain.ErrCodeNotFound) {
 		return false, err
 	}
 
+	units, err := v.graphRepo.ListFamilyUnitsByPartnerID(ctx, memberID)
+	if err != nil {
+		return false, err
+	}
+
+	dissolved := make(map[int]bool)
+	for _, unit := range units {
+		if unit.SourceSpouseID != nil && unit.IsDissolved() {
+			dissolved[*unit.SourceSpouseID] = true
+		}
+		if unit.Source == domain.FamilyUnitSourceManual && unit.RelationshipType == domain.RelationshipTypeMarriage &&
+			unit.Status == domain.FamilyStatusActive && len(unit.PartnerIDs) == 2 {
+			return true, nil
+		}
+	}
+
 	for _, spouse := range spouses {
-		if spouse.DivorceDate == nil {
+		if spouse.DivorceDate == nil && !dissolved[spouse.SpouseID] {
 			return true, nil
 		}
 	}
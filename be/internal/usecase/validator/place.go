@@ -0,0 +1,36 @@
+package validator
+
+import (
+	"context"
+
+	"github.com/escalopa/family-tree/internal/domain"
+	"github.com/escalopa/family-tree/internal/usecase"
+)
+
+type PlaceValidator struct {
+	placeRepo usecase.PlaceRepository
+}
+
+func NewPlaceValidator(placeRepo usecase.PlaceRepository) *PlaceValidator {
+	return &PlaceValidator{placeRepo: placeRepo}
+}
+
+// InTree checks that every referenced place belongs to the tree, nil references are skipped
+func (v *PlaceValidator) InTree(ctx context.Context, treeID int, placeIDs ...*int) error {
+	for _, placeID := range placeIDs {
+		if placeID == nil {
+			continue
+		}
+		place, err := v.placeRepo.Get(ctx, *placeID)
+		if err != nil {
+			if domain.IsDomainError(err, domain.ErrCodeNotFound) {
+				return domain.NewValidationError("error.place.not_in_tree")
+			}
+			return err
+		}
+		if place.TreeID != treeID {
+			return domain.NewValidationError("error.place.not_in_tree")
+		}
+	}
+	return nil
+}
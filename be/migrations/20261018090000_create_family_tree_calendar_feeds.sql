@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS family_tree_calendar_feeds (
+    feed_id SERIAL,
+    tree_id INT NOT NULL,
+    user_id INT NOT NULL,
+    token VARCHAR(128) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_accessed_at TIMESTAMP,
+    revoked_at TIMESTAMP
+);
+
+ALTER TABLE family_tree_calendar_feeds
+    ADD CONSTRAINT pk_family_tree_calendar_feeds PRIMARY KEY (feed_id),
+    ADD CONSTRAINT fk_family_tree_calendar_feeds_tree FOREIGN KEY (tree_id) REFERENCES family_trees(tree_id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_family_tree_calendar_feeds_user FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE,
+    ADD CONSTRAINT uq_family_tree_calendar_feeds_token UNIQUE (token);
+
+CREATE INDEX IF NOT EXISTS idx_family_tree_calendar_feeds_tree_user ON family_tree_calendar_feeds(tree_id, user_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS family_tree_calendar_feeds CASCADE;
+
+-- +goose StatementEnd
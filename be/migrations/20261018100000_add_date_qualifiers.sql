@@ -0,0 +1,62 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Each date column keeps the start of the span it describes so ordering by it
+-- stays meaningful; *_end closes the span for between, year and month dates.
+ALTER TABLE members
+    ADD COLUMN IF NOT EXISTS date_of_birth_qualifier VARCHAR(10) NOT NULL DEFAULT 'exact',
+    ADD COLUMN IF NOT EXISTS date_of_birth_end DATE,
+    ADD COLUMN IF NOT EXISTS date_of_death_qualifier VARCHAR(10) NOT NULL DEFAULT 'exact',
+    ADD COLUMN IF NOT EXISTS date_of_death_end DATE;
+
+ALTER TABLE members
+    ADD CONSTRAINT chk_members_date_of_birth_qualifier CHECK (date_of_birth_qualifier IN ('exact', 'about', 'before', 'after', 'between', 'year', 'month')),
+    ADD CONSTRAINT chk_members_date_of_death_qualifier CHECK (date_of_death_qualifier IN ('exact', 'about', 'before', 'after', 'between', 'year', 'month')),
+    ADD CONSTRAINT chk_members_date_of_birth_end CHECK (date_of_birth_end IS NULL OR (date_of_birth IS NOT NULL AND date_of_birth_end >= date_of_birth)),
+    ADD CONSTRAINT chk_members_date_of_death_end CHECK (date_of_death_end IS NULL OR (date_of_death IS NOT NULL AND date_of_death_end >= date_of_death));
+
+ALTER TABLE members_spouse
+    ADD COLUMN IF NOT EXISTS marriage_date_qualifier VARCHAR(10) NOT NULL DEFAULT 'exact',
+    ADD COLUMN IF NOT EXISTS marriage_date_end DATE,
+    ADD COLUMN IF NOT EXISTS divorce_date_qualifier VARCHAR(10) NOT NULL DEFAULT 'exact',
+    ADD COLUMN IF NOT EXISTS divorce_date_end DATE;
+
+-- Approximate dates can overlap, the ordering is now checked over ranges in the application
+ALTER TABLE members_spouse
+    DROP CONSTRAINT IF EXISTS chk_marriage_dates;
+
+ALTER TABLE members_spouse
+    ADD CONSTRAINT chk_members_spouse_marriage_date_qualifier CHECK (marriage_date_qualifier IN ('exact', 'about', 'before', 'after', 'between', 'year', 'month')),
+    ADD CONSTRAINT chk_members_spouse_divorce_date_qualifier CHECK (divorce_date_qualifier IN ('exact', 'about', 'before', 'after', 'between', 'year', 'month')),
+    ADD CONSTRAINT chk_members_spouse_marriage_date_end CHECK (marriage_date_end IS NULL OR (marriage_date IS NOT NULL AND marriage_date_end >= marriage_date)),
+    ADD CONSTRAINT chk_members_spouse_divorce_date_end CHECK (divorce_date_end IS NULL OR (divorce_date IS NOT NULL AND divorce_date_end >= divorce_date));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE members_spouse
+    DROP CONSTRAINT IF EXISTS chk_members_spouse_divorce_date_end,
+    DROP CONSTRAINT IF EXISTS chk_members_spouse_marriage_date_end,
+    DROP CONSTRAINT IF EXISTS chk_members_spouse_divorce_date_qualifier,
+    DROP CONSTRAINT IF EXISTS chk_members_spouse_marriage_date_qualifier,
+    DROP COLUMN IF EXISTS divorce_date_end,
+    DROP COLUMN IF EXISTS divorce_date_qualifier,
+    DROP COLUMN IF EXISTS marriage_date_end,
+    DROP COLUMN IF EXISTS marriage_date_qualifier;
+
+ALTER TABLE members_spouse
+    ADD CONSTRAINT chk_marriage_dates CHECK (divorce_date IS NULL OR marriage_date IS NULL OR divorce_date >= marriage_date);
+
+ALTER TABLE members
+    DROP CONSTRAINT IF EXISTS chk_members_date_of_death_end,
+    DROP CONSTRAINT IF EXISTS chk_members_date_of_birth_end,
+    DROP CONSTRAINT IF EXISTS chk_members_date_of_death_qualifier,
+    DROP CONSTRAINT IF EXISTS chk_members_date_of_birth_qualifier,
+    DROP COLUMN IF EXISTS date_of_death_end,
+    DROP COLUMN IF EXISTS date_of_death_qualifier,
+    DROP COLUMN IF EXISTS date_of_birth_end,
+    DROP COLUMN IF EXISTS date_of_birth_qualifier;
+
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Dates are stored in the Gregorian calendar; *_calendar records the calendar
+-- the editor entered them in so they can be shown back the same way.
+ALTER TABLE members
+    ADD COLUMN IF NOT EXISTS date_of_birth_calendar VARCHAR(10) NOT NULL DEFAULT 'gregorian',
+    ADD COLUMN IF NOT EXISTS date_of_death_calendar VARCHAR(10) NOT NULL DEFAULT 'gregorian';
+
+ALTER TABLE members
+    ADD CONSTRAINT chk_members_date_of_birth_calendar CHECK (date_of_birth_calendar IN ('gregorian', 'hijri')),
+    ADD CONSTRAINT chk_members_date_of_death_calendar CHECK (date_of_death_calendar IN ('gregorian', 'hijri'));
+
+ALTER TABLE members_spouse
+    ADD COLUMN IF NOT EXISTS marriage_date_calendar VARCHAR(10) NOT NULL DEFAULT 'gregorian',
+    ADD COLUMN IF NOT EXISTS divorce_date_calendar VARCHAR(10) NOT NULL DEFAULT 'gregorian';
+
+ALTER TABLE members_spouse
+    ADD CONSTRAINT chk_members_spouse_marriage_date_calendar CHECK (marriage_date_calendar IN ('gregorian', 'hijri')),
+    ADD CONSTRAINT chk_members_spouse_divorce_date_calendar CHECK (divorce_date_calendar IN ('gregorian', 'hijri'));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE members_spouse
+    DROP CONSTRAINT IF EXISTS chk_members_spouse_divorce_date_calendar,
+    DROP CONSTRAINT IF EXISTS chk_members_spouse_marriage_date_calendar,
+    DROP COLUMN IF EXISTS divorce_date_calendar,
+    DROP COLUMN IF EXISTS marriage_date_calendar;
+
+ALTER TABLE members
+    DROP CONSTRAINT IF EXISTS chk_members_date_of_death_calendar,
+    DROP CONSTRAINT IF EXISTS chk_members_date_of_birth_calendar,
+    DROP COLUMN IF EXISTS date_of_death_calendar,
+    DROP COLUMN IF EXISTS date_of_birth_calendar;
+
+-- +goose StatementEnd
@@ -0,0 +1,74 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS places (
+    place_id SERIAL,
+    tree_id INT NOT NULL,
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE places
+    ADD CONSTRAINT pk_places PRIMARY KEY (place_id),
+    ADD CONSTRAINT fk_places_tree FOREIGN KEY (tree_id) REFERENCES family_trees(tree_id) ON DELETE CASCADE,
+    ADD CONSTRAINT chk_places_latitude CHECK (latitude IS NULL OR latitude BETWEEN -90 AND 90),
+    ADD CONSTRAINT chk_places_longitude CHECK (longitude IS NULL OR longitude BETWEEN -180 AND 180),
+    ADD CONSTRAINT chk_places_coordinates CHECK ((latitude IS NULL) = (longitude IS NULL));
+
+CREATE INDEX IF NOT EXISTS idx_places_tree_id ON places(tree_id);
+
+CREATE TABLE IF NOT EXISTS place_names (
+    place_name_id SERIAL PRIMARY KEY,
+    place_id INT NOT NULL,
+    language_code VARCHAR(10) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+ALTER TABLE place_names
+    ADD CONSTRAINT fk_place_names_place FOREIGN KEY (place_id) REFERENCES places(place_id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_place_names_language FOREIGN KEY (language_code) REFERENCES languages(language_code);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_place_names_place_language ON place_names(place_id, language_code);
+
+-- Deleting a place only forgets where the fact happened, the fact itself stays
+ALTER TABLE members
+    ADD COLUMN IF NOT EXISTS birth_place_id INT,
+    ADD COLUMN IF NOT EXISTS death_place_id INT,
+    ADD COLUMN IF NOT EXISTS burial_place_id INT;
+
+ALTER TABLE members
+    ADD CONSTRAINT fk_members_birth_place FOREIGN KEY (birth_place_id) REFERENCES places(place_id) ON DELETE SET NULL,
+    ADD CONSTRAINT fk_members_death_place FOREIGN KEY (death_place_id) REFERENCES places(place_id) ON DELETE SET NULL,
+    ADD CONSTRAINT fk_members_burial_place FOREIGN KEY (burial_place_id) REFERENCES places(place_id) ON DELETE SET NULL;
+
+ALTER TABLE members_spouse
+    ADD COLUMN IF NOT EXISTS marriage_place_id INT;
+
+ALTER TABLE members_spouse
+    ADD CONSTRAINT fk_members_spouse_marriage_place FOREIGN KEY (marriage_place_id) REFERENCES places(place_id) ON DELETE SET NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE members_spouse
+    DROP CONSTRAINT IF EXISTS fk_members_spouse_marriage_place,
+    DROP COLUMN IF EXISTS marriage_place_id;
+
+ALTER TABLE members
+    DROP CONSTRAINT IF EXISTS fk_members_burial_place,
+    DROP CONSTRAINT IF EXISTS fk_members_death_place,
+    DROP CONSTRAINT IF EXISTS fk_members_birth_place,
+    DROP COLUMN IF EXISTS burial_place_id,
+    DROP COLUMN IF EXISTS death_place_id,
+    DROP COLUMN IF EXISTS birth_place_id;
+
+DROP TABLE IF EXISTS place_names CASCADE;
+DROP TABLE IF EXISTS places CASCADE;
+
+-- +goose StatementEnd
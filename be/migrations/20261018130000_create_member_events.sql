@@ -0,0 +1,52 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS member_events (
+    event_id SERIAL,
+    member_id INT NOT NULL,
+    event_type VARCHAR(30) NOT NULL,
+    custom_type VARCHAR(100),
+    event_date DATE,
+    event_date_qualifier VARCHAR(10) NOT NULL DEFAULT 'exact',
+    event_date_end DATE,
+    event_date_calendar VARCHAR(10) NOT NULL DEFAULT 'gregorian',
+    place_id INT,
+    description TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+ALTER TABLE member_events
+    ADD CONSTRAINT pk_member_events PRIMARY KEY (event_id),
+    ADD CONSTRAINT fk_member_events_member FOREIGN KEY (member_id) REFERENCES members(member_id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_member_events_place FOREIGN KEY (place_id) REFERENCES places(place_id) ON DELETE SET NULL,
+    ADD CONSTRAINT chk_member_events_type CHECK (event_type IN ('education', 'migration', 'military_service', 'hajj', 'residence', 'religious', 'custom')),
+    ADD CONSTRAINT chk_member_events_custom_type CHECK ((event_type = 'custom') = (custom_type IS NOT NULL)),
+    ADD CONSTRAINT chk_member_events_date_qualifier CHECK (event_date_qualifier IN ('exact', 'about', 'before', 'after', 'between', 'year', 'month')),
+    ADD CONSTRAINT chk_member_events_date_calendar CHECK (event_date_calendar IN ('gregorian', 'hijri')),
+    ADD CONSTRAINT chk_member_events_date_end CHECK (event_date_end IS NULL OR (event_date IS NOT NULL AND event_date_end >= event_date));
+
+CREATE INDEX IF NOT EXISTS idx_member_events_member_id ON member_events(member_id) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS member_event_participants (
+    event_id INT NOT NULL,
+    member_id INT NOT NULL
+);
+
+ALTER TABLE member_event_participants
+    ADD CONSTRAINT pk_member_event_participants PRIMARY KEY (event_id, member_id),
+    ADD CONSTRAINT fk_member_event_participants_event FOREIGN KEY (event_id) REFERENCES member_events(event_id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_member_event_participants_member FOREIGN KEY (member_id) REFERENCES members(member_id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_member_event_participants_member_id ON member_event_participants(member_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS member_event_participants CASCADE;
+DROP TABLE IF EXISTS member_events CASCADE;
+
+-- +goose StatementEnd
@@ -0,0 +1,63 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS media_items (
+    media_id SERIAL,
+    tree_id INT NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    caption TEXT,
+    media_date DATE,
+    media_date_qualifier VARCHAR(10) NOT NULL DEFAULT 'exact',
+    media_date_end DATE,
+    media_date_calendar VARCHAR(10) NOT NULL DEFAULT 'gregorian',
+    place_id INT,
+    uploaded_by INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+ALTER TABLE media_items
+    ADD CONSTRAINT pk_media_items PRIMARY KEY (media_id),
+    ADD CONSTRAINT fk_media_items_tree FOREIGN KEY (tree_id) REFERENCES family_trees(tree_id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_media_items_place FOREIGN KEY (place_id) REFERENCES places(place_id) ON DELETE SET NULL,
+    ADD CONSTRAINT fk_media_items_uploaded_by FOREIGN KEY (uploaded_by) REFERENCES users(user_id),
+    ADD CONSTRAINT chk_media_items_date_qualifier CHECK (media_date_qualifier IN ('exact', 'about', 'before', 'after', 'between', 'year', 'month')),
+    ADD CONSTRAINT chk_media_items_date_calendar CHECK (media_date_calendar IN ('gregorian', 'hijri')),
+    ADD CONSTRAINT chk_media_items_date_end CHECK (media_date_end IS NULL OR (media_date IS NOT NULL AND media_date_end >= media_date));
+
+CREATE INDEX IF NOT EXISTS idx_media_items_tree_id ON media_items(tree_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_media_items_storage_key ON media_items(storage_key);
+
+-- The face rectangle is stored as fractions of the image size so it survives resizing
+CREATE TABLE IF NOT EXISTS media_tags (
+    media_id INT NOT NULL,
+    member_id INT NOT NULL,
+    face_x REAL,
+    face_y REAL,
+    face_width REAL,
+    face_height REAL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE media_tags
+    ADD CONSTRAINT pk_media_tags PRIMARY KEY (media_id, member_id),
+    ADD CONSTRAINT fk_media_tags_media FOREIGN KEY (media_id) REFERENCES media_items(media_id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_media_tags_member FOREIGN KEY (member_id) REFERENCES members(member_id) ON DELETE CASCADE,
+    ADD CONSTRAINT chk_media_tags_face CHECK (
+        (face_x IS NULL AND face_y IS NULL AND face_width IS NULL AND face_height IS NULL)
+        OR (face_x >= 0 AND face_y >= 0 AND face_width > 0 AND face_height > 0
+            AND face_x + face_width <= 1 AND face_y + face_height <= 1)
+    );
+
+CREATE INDEX IF NOT EXISTS idx_media_tags_member_id ON media_tags(member_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS media_tags CASCADE;
+DROP TABLE IF EXISTS media_items CASCADE;
+
+-- +goose StatementEnd
@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS sources (
+    source_id SERIAL,
+    tree_id INT NOT NULL,
+    source_type VARCHAR(20) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    author VARCHAR(255),
+    description TEXT,
+    attachment_key VARCHAR(255),
+    created_by INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE sources
+    ADD CONSTRAINT pk_sources PRIMARY KEY (source_id),
+    ADD CONSTRAINT fk_sources_tree FOREIGN KEY (tree_id) REFERENCES family_trees(tree_id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_sources_created_by FOREIGN KEY (created_by) REFERENCES users(user_id),
+    ADD CONSTRAINT chk_sources_type CHECK (source_type IN ('document', 'interview', 'gravestone', 'registry', 'other'));
+
+CREATE INDEX IF NOT EXISTS idx_sources_tree_id ON sources(tree_id);
+
+-- A citation points at one fact: a field of a member, a spouse relation or a member event
+CREATE TABLE IF NOT EXISTS citations (
+    citation_id SERIAL,
+    source_id INT NOT NULL,
+    subject_type VARCHAR(10) NOT NULL,
+    subject_id INT NOT NULL,
+    field_name VARCHAR(50) NOT NULL,
+    confidence VARCHAR(10) NOT NULL,
+    detail TEXT,
+    created_by INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE citations
+    ADD CONSTRAINT pk_citations PRIMARY KEY (citation_id),
+    ADD CONSTRAINT fk_citations_source FOREIGN KEY (source_id) REFERENCES sources(source_id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_citations_created_by FOREIGN KEY (created_by) REFERENCES users(user_id),
+    ADD CONSTRAINT chk_citations_subject_type CHECK (subject_type IN ('member', 'spouse', 'event')),
+    ADD CONSTRAINT chk_citations_confidence CHECK (confidence IN ('low', 'medium', 'high'));
+
+CREATE INDEX IF NOT EXISTS idx_citations_source_id ON citations(source_id);
+CREATE INDEX IF NOT EXISTS idx_citations_subject ON citations(subject_type, subject_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS citations CASCADE;
+DROP TABLE IF EXISTS sources CASCADE;
+
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS member_biographies (
+    member_id INT NOT NULL,
+    language_code VARCHAR(10) NOT NULL,
+    biography TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE member_biographies
+    ADD CONSTRAINT pk_member_biographies PRIMARY KEY (member_id, language_code),
+    ADD CONSTRAINT fk_member_biographies_member FOREIGN KEY (member_id) REFERENCES members(member_id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_member_biographies_language FOREIGN KEY (language_code) REFERENCES languages(language_code);
+
+-- Research notes are kept apart from the member row so they never reach non-editors
+CREATE TABLE IF NOT EXISTS member_notes (
+    member_id INT NOT NULL,
+    notes TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE member_notes
+    ADD CONSTRAINT pk_member_notes PRIMARY KEY (member_id),
+    ADD CONSTRAINT fk_member_notes_member FOREIGN KEY (member_id) REFERENCES members(member_id) ON DELETE CASCADE;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS member_notes CASCADE;
+DROP TABLE IF EXISTS member_biographies CASCADE;
+
+-- +goose StatementEnd
@@ -0,0 +1,59 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS tree_custom_fields (
+    field_id SERIAL,
+    tree_id INT NOT NULL,
+    field_key VARCHAR(50) NOT NULL,
+    field_type VARCHAR(20) NOT NULL,
+    options TEXT[] NOT NULL DEFAULT '{}',
+    is_required BOOLEAN NOT NULL DEFAULT FALSE,
+    display_order INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE tree_custom_fields
+    ADD CONSTRAINT pk_tree_custom_fields PRIMARY KEY (field_id),
+    ADD CONSTRAINT fk_tree_custom_fields_tree FOREIGN KEY (tree_id) REFERENCES family_trees(tree_id) ON DELETE CASCADE,
+    ADD CONSTRAINT chk_tree_custom_fields_type CHECK (field_type IN ('text', 'number', 'date', 'enum', 'member')),
+    ADD CONSTRAINT chk_tree_custom_fields_key CHECK (field_key ~ '^[a-z][a-z0-9_]*$');
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_tree_custom_fields_tree_key ON tree_custom_fields(tree_id, field_key);
+
+CREATE TABLE IF NOT EXISTS tree_custom_field_names (
+    field_id INT NOT NULL,
+    language_code VARCHAR(10) NOT NULL,
+    name VARCHAR(255) NOT NULL
+);
+
+ALTER TABLE tree_custom_field_names
+    ADD CONSTRAINT pk_tree_custom_field_names PRIMARY KEY (field_id, language_code),
+    ADD CONSTRAINT fk_tree_custom_field_names_field FOREIGN KEY (field_id) REFERENCES tree_custom_fields(field_id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_tree_custom_field_names_language FOREIGN KEY (language_code) REFERENCES languages(language_code);
+
+-- Values are stored as canonical text: numbers without trailing zeros, dates
+-- as YYYY-MM-DD and member references as the member ID
+CREATE TABLE IF NOT EXISTS member_custom_values (
+    member_id INT NOT NULL,
+    field_id INT NOT NULL,
+    value TEXT NOT NULL
+);
+
+ALTER TABLE member_custom_values
+    ADD CONSTRAINT pk_member_custom_values PRIMARY KEY (member_id, field_id),
+    ADD CONSTRAINT fk_member_custom_values_member FOREIGN KEY (member_id) REFERENCES members(member_id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_member_custom_values_field FOREIGN KEY (field_id) REFERENCES tree_custom_fields(field_id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_member_custom_values_field_id ON member_custom_values(field_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS member_custom_values CASCADE;
+DROP TABLE IF EXISTS tree_custom_field_names CASCADE;
+DROP TABLE IF EXISTS tree_custom_fields CASCADE;
+
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- The name column stays the given name (ism), the other parts are optional
+-- and per language like the name itself
+ALTER TABLE member_names
+    ADD COLUMN IF NOT EXISTS kunya VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS laqab VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS family_name VARCHAR(255);
+
+-- Per language full-name format of the tree, an empty object uses the defaults
+ALTER TABLE family_trees
+    ADD COLUMN IF NOT EXISTS name_settings JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE family_trees
+    DROP COLUMN IF EXISTS name_settings;
+
+ALTER TABLE member_names
+    DROP COLUMN IF EXISTS family_name,
+    DROP COLUMN IF EXISTS laqab,
+    DROP COLUMN IF EXISTS kunya;
+
+-- +goose StatementEnd
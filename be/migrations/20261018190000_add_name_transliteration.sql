@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Names written by the transliteration rules stay flagged until an editor
+-- confirms or rewrites them
+ALTER TABLE member_names
+    ADD COLUMN IF NOT EXISTS is_suggested BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Per tree spelling of an Arabic name or word, used instead of the rules
+CREATE TABLE IF NOT EXISTS tree_name_spellings (
+    tree_id INT NOT NULL,
+    arabic VARCHAR(255) NOT NULL,
+    language_code VARCHAR(10) NOT NULL,
+    spelling VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE tree_name_spellings
+    ADD CONSTRAINT pk_tree_name_spellings PRIMARY KEY (tree_id, arabic, language_code),
+    ADD CONSTRAINT fk_tree_name_spellings_tree FOREIGN KEY (tree_id) REFERENCES family_trees(tree_id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_tree_name_spellings_language FOREIGN KEY (language_code) REFERENCES languages(language_code);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS tree_name_spellings CASCADE;
+
+ALTER TABLE member_names
+    DROP COLUMN IF EXISTS is_suggested;
+
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Empty leaves the living status to be inferred from dates and descendants
+ALTER TABLE members
+    ADD COLUMN IF NOT EXISTS living_status VARCHAR(10) NOT NULL DEFAULT '';
+
+ALTER TABLE members
+    ADD CONSTRAINT chk_members_living_status CHECK (living_status IN ('', 'living', 'deceased'));
+
+-- What a share link shows of living members, redacted unless the owner opts out
+ALTER TABLE family_tree_share_links
+    ADD COLUMN IF NOT EXISTS living_policy VARCHAR(10) NOT NULL DEFAULT 'redact';
+
+ALTER TABLE family_tree_share_links
+    ADD CONSTRAINT chk_family_tree_share_links_living_policy CHECK (living_policy IN ('redact', 'names', 'show'));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE family_tree_share_links
+    DROP CONSTRAINT IF EXISTS chk_family_tree_share_links_living_policy,
+    DROP COLUMN IF EXISTS living_policy;
+
+ALTER TABLE members
+    DROP CONSTRAINT IF EXISTS chk_members_living_status,
+    DROP COLUMN IF EXISTS living_status;
+
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Field visibility rules of a tree, NULL keeps the default policy
+ALTER TABLE family_trees
+    ADD COLUMN IF NOT EXISTS privacy_policy JSONB;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE family_trees
+    DROP COLUMN IF EXISTS privacy_policy;
+
+-- +goose StatementEnd
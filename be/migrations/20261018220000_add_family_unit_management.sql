@@ -0,0 +1,346 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Units created by hand have neither a source spouse nor legacy parents, only
+-- units built from father_id and mother_id are unique per parent pair
+DROP INDEX IF EXISTS uq_family_units_legacy_parent_pair;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_family_units_legacy_parent_pair
+    ON family_units(tree_id, COALESCE(legacy_father_id, 0), COALESCE(legacy_mother_id, 0))
+    WHERE source_spouse_id IS NULL
+      AND (legacy_father_id IS NOT NULL OR legacy_mother_id IS NOT NULL);
+
+-- Keeps a widowed or separated status set on the unit while the marriage has
+-- no divorce date, and only moves biological children into the unit
+CREATE OR REPLACE FUNCTION sync_family_unit_from_spouse()
+RETURNS TRIGGER AS $$
+DECLARE
+    unit_id INT;
+    unit_tree_id INT;
+BEGIN
+    SELECT tree_id INTO unit_tree_id
+    FROM members
+    WHERE member_id = NEW.father_id;
+
+    IF unit_tree_id IS NULL THEN
+        RETURN NEW;
+    END IF;
+
+    INSERT INTO family_units (
+        tree_id,
+        relationship_type,
+        status,
+        start_date,
+        end_date,
+        source_spouse_id,
+        legacy_father_id,
+        legacy_mother_id,
+        deleted_at,
+        updated_at
+    )
+    VALUES (
+        unit_tree_id,
+        'marriage',
+        CASE WHEN NEW.divorce_date IS NULL THEN 'active' ELSE 'divorced' END,
+        NEW.marriage_date,
+        NEW.divorce_date,
+        NEW.spouse_id,
+        NEW.father_id,
+        NEW.mother_id,
+        NEW.deleted_at,
+        CURRENT_TIMESTAMP
+    )
+    ON CONFLICT (source_spouse_id)
+    WHERE source_spouse_id IS NOT NULL
+    DO UPDATE SET
+        tree_id = EXCLUDED.tree_id,
+        relationship_type = EXCLUDED.relationship_type,
+        status = CASE
+            WHEN EXCLUDED.status = 'active' AND family_units.status IN ('widowed', 'separated') THEN family_units.status
+            ELSE EXCLUDED.status
+        END,
+        start_date = EXCLUDED.start_date,
+        end_date = EXCLUDED.end_date,
+        legacy_father_id = EXCLUDED.legacy_father_id,
+        legacy_mother_id = EXCLUDED.legacy_mother_id,
+        deleted_at = EXCLUDED.deleted_at,
+        updated_at = CURRENT_TIMESTAMP
+    RETURNING family_unit_id INTO unit_id;
+
+    DELETE FROM family_unit_partners WHERE family_unit_id = unit_id;
+    INSERT INTO family_unit_partners (family_unit_id, person_id, partner_order)
+    VALUES
+        (unit_id, NEW.father_id, 1),
+        (unit_id, NEW.mother_id, 2)
+    ON CONFLICT (family_unit_id, person_id) DO NOTHING;
+
+    UPDATE family_unit_children
+    SET family_unit_id = unit_id
+    WHERE relation_type = 'biological'
+      AND child_person_id IN (
+        SELECT member_id
+        FROM members
+        WHERE father_id = NEW.father_id
+          AND mother_id = NEW.mother_id
+          AND deleted_at IS NULL
+    );
+
+    INSERT INTO family_unit_children (family_unit_id, child_person_id, relation_type)
+    SELECT unit_id, member_id, 'biological'
+    FROM members
+    WHERE father_id = NEW.father_id
+      AND mother_id = NEW.mother_id
+      AND deleted_at IS NULL
+    ON CONFLICT (family_unit_id, child_person_id) DO UPDATE SET
+        relation_type = EXCLUDED.relation_type;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Only replaces the biological link of a member, adopted, step and foster
+-- links are managed through the family unit API
+CREATE OR REPLACE FUNCTION sync_family_unit_from_member_parentage()
+RETURNS TRIGGER AS $$
+DECLARE
+    unit_id INT;
+    parent_tree_id INT;
+BEGIN
+    IF TG_OP = 'UPDATE' THEN
+        DELETE FROM family_unit_children
+        WHERE child_person_id = OLD.member_id
+          AND relation_type = 'biological';
+    END IF;
+
+    IF NEW.deleted_at IS NOT NULL OR (NEW.father_id IS NULL AND NEW.mother_id IS NULL) THEN
+        RETURN NEW;
+    END IF;
+
+    IF NEW.father_id IS NOT NULL AND NEW.mother_id IS NOT NULL THEN
+        SELECT fu.family_unit_id INTO unit_id
+        FROM family_units fu
+        WHERE fu.legacy_father_id = NEW.father_id
+          AND fu.legacy_mother_id = NEW.mother_id
+          AND fu.deleted_at IS NULL
+        ORDER BY CASE WHEN fu.source_spouse_id IS NULL THEN 1 ELSE 0 END, fu.family_unit_id
+        LIMIT 1;
+    END IF;
+
+    IF unit_id IS NULL THEN
+        parent_tree_id := NEW.tree_id;
+
+        INSERT INTO family_units (
+            tree_id,
+            relationship_type,
+            status,
+            legacy_father_id,
+            legacy_mother_id,
+            updated_at
+        )
+        VALUES (
+            parent_tree_id,
+            'unknown',
+            'unknown',
+            NEW.father_id,
+            NEW.mother_id,
+            CURRENT_TIMESTAMP
+        )
+        ON CONFLICT (tree_id, COALESCE(legacy_father_id, 0), COALESCE(legacy_mother_id, 0))
+        WHERE source_spouse_id IS NULL
+          AND (legacy_father_id IS NOT NULL OR legacy_mother_id IS NOT NULL)
+        DO UPDATE SET
+            updated_at = CURRENT_TIMESTAMP,
+            deleted_at = NULL
+        RETURNING family_unit_id INTO unit_id;
+
+        IF NEW.father_id IS NOT NULL THEN
+            INSERT INTO family_unit_partners (family_unit_id, person_id, partner_order)
+            VALUES (unit_id, NEW.father_id, 1)
+            ON CONFLICT (family_unit_id, person_id) DO NOTHING;
+        END IF;
+
+        IF NEW.mother_id IS NOT NULL THEN
+            INSERT INTO family_unit_partners (family_unit_id, person_id, partner_order)
+            VALUES (unit_id, NEW.mother_id, 2)
+            ON CONFLICT (family_unit_id, person_id) DO NOTHING;
+        END IF;
+    END IF;
+
+    INSERT INTO family_unit_children (family_unit_id, child_person_id, relation_type)
+    VALUES (unit_id, NEW.member_id, 'biological')
+    ON CONFLICT (family_unit_id, child_person_id) DO UPDATE SET
+        relation_type = EXCLUDED.relation_type;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DELETE FROM family_units
+WHERE source_spouse_id IS NULL
+  AND legacy_father_id IS NULL
+  AND legacy_mother_id IS NULL;
+
+DROP INDEX IF EXISTS uq_family_units_legacy_parent_pair;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_family_units_legacy_parent_pair
+    ON family_units(tree_id, COALESCE(legacy_father_id, 0), COALESCE(legacy_mother_id, 0))
+    WHERE source_spouse_id IS NULL;
+
+CREATE OR REPLACE FUNCTION sync_family_unit_from_spouse()
+RETURNS TRIGGER AS $$
+DECLARE
+    unit_id INT;
+    unit_tree_id INT;
+BEGIN
+    SELECT tree_id INTO unit_tree_id
+    FROM members
+    WHERE member_id = NEW.father_id;
+
+    IF unit_tree_id IS NULL THEN
+        RETURN NEW;
+    END IF;
+
+    INSERT INTO family_units (
+        tree_id,
+        relationship_type,
+        status,
+        start_date,
+        end_date,
+        source_spouse_id,
+        legacy_father_id,
+        legacy_mother_id,
+        deleted_at,
+        updated_at
+    )
+    VALUES (
+        unit_tree_id,
+        'marriage',
+        CASE WHEN NEW.divorce_date IS NULL THEN 'active' ELSE 'divorced' END,
+        NEW.marriage_date,
+        NEW.divorce_date,
+        NEW.spouse_id,
+        NEW.father_id,
+        NEW.mother_id,
+        NEW.deleted_at,
+        CURRENT_TIMESTAMP
+    )
+    ON CONFLICT (source_spouse_id)
+    WHERE source_spouse_id IS NOT NULL
+    DO UPDATE SET
+        tree_id = EXCLUDED.tree_id,
+        relationship_type = EXCLUDED.relationship_type,
+        status = EXCLUDED.status,
+        start_date = EXCLUDED.start_date,
+        end_date = EXCLUDED.end_date,
+        legacy_father_id = EXCLUDED.legacy_father_id,
+        legacy_mother_id = EXCLUDED.legacy_mother_id,
+        deleted_at = EXCLUDED.deleted_at,
+        updated_at = CURRENT_TIMESTAMP
+    RETURNING family_unit_id INTO unit_id;
+
+    DELETE FROM family_unit_partners WHERE family_unit_id = unit_id;
+    INSERT INTO family_unit_partners (family_unit_id, person_id, partner_order)
+    VALUES
+        (unit_id, NEW.father_id, 1),
+        (unit_id, NEW.mother_id, 2)
+    ON CONFLICT (family_unit_id, person_id) DO NOTHING;
+
+    UPDATE family_unit_children
+    SET family_unit_id = unit_id
+    WHERE child_person_id IN (
+        SELECT member_id
+        FROM members
+        WHERE father_id = NEW.father_id
+          AND mother_id = NEW.mother_id
+          AND deleted_at IS NULL
+    );
+
+    INSERT INTO family_unit_children (family_unit_id, child_person_id, relation_type)
+    SELECT unit_id, member_id, 'biological'
+    FROM members
+    WHERE father_id = NEW.father_id
+      AND mother_id = NEW.mother_id
+      AND deleted_at IS NULL
+    ON CONFLICT (family_unit_id, child_person_id) DO UPDATE SET
+        relation_type = EXCLUDED.relation_type;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION sync_family_unit_from_member_parentage()
+RETURNS TRIGGER AS $$
+DECLARE
+    unit_id INT;
+    parent_tree_id INT;
+BEGIN
+    IF TG_OP = 'UPDATE' THEN
+        DELETE FROM family_unit_children WHERE child_person_id = OLD.member_id;
+    END IF;
+
+    IF NEW.deleted_at IS NOT NULL OR (NEW.father_id IS NULL AND NEW.mother_id IS NULL) THEN
+        RETURN NEW;
+    END IF;
+
+    IF NEW.father_id IS NOT NULL AND NEW.mother_id IS NOT NULL THEN
+        SELECT fu.family_unit_id INTO unit_id
+        FROM family_units fu
+        WHERE fu.legacy_father_id = NEW.father_id
+          AND fu.legacy_mother_id = NEW.mother_id
+          AND fu.deleted_at IS NULL
+        ORDER BY CASE WHEN fu.source_spouse_id IS NULL THEN 1 ELSE 0 END, fu.family_unit_id
+        LIMIT 1;
+    END IF;
+
+    IF unit_id IS NULL THEN
+        parent_tree_id := NEW.tree_id;
+
+        INSERT INTO family_units (
+            tree_id,
+            relationship_type,
+            status,
+            legacy_father_id,
+            legacy_mother_id,
+            updated_at
+        )
+        VALUES (
+            parent_tree_id,
+            'unknown',
+            'unknown',
+            NEW.father_id,
+            NEW.mother_id,
+            CURRENT_TIMESTAMP
+        )
+        ON CONFLICT (tree_id, COALESCE(legacy_father_id, 0), COALESCE(legacy_mother_id, 0))
+        WHERE source_spouse_id IS NULL
+        DO UPDATE SET
+            updated_at = CURRENT_TIMESTAMP,
+            deleted_at = NULL
+        RETURNING family_unit_id INTO unit_id;
+
+        IF NEW.father_id IS NOT NULL THEN
+            INSERT INTO family_unit_partners (family_unit_id, person_id, partner_order)
+            VALUES (unit_id, NEW.father_id, 1)
+            ON CONFLICT (family_unit_id, person_id) DO NOTHING;
+        END IF;
+
+        IF NEW.mother_id IS NOT NULL THEN
+            INSERT INTO family_unit_partners (family_unit_id, person_id, partner_order)
+            VALUES (unit_id, NEW.mother_id, 2)
+            ON CONFLICT (family_unit_id, person_id) DO NOTHING;
+        END IF;
+    END IF;
+
+    INSERT INTO family_unit_children (family_unit_id, child_person_id, relation_type)
+    VALUES (unit_id, NEW.member_id, 'biological')
+    ON CONFLICT (family_unit_id, child_person_id) DO UPDATE SET
+        relation_type = EXCLUDED.relation_type;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Pictures replaced or removed from a member are kept in storage so history
+-- can roll back to them, until the tree's trash is purged
+CREATE TABLE IF NOT EXISTS superseded_pictures (
+    storage_key TEXT NOT NULL,
+    tree_id INT NOT NULL,
+    superseded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE superseded_pictures
+    ADD CONSTRAINT pk_superseded_pictures PRIMARY KEY (storage_key),
+    ADD CONSTRAINT fk_superseded_pictures_tree FOREIGN KEY (tree_id) REFERENCES family_trees(tree_id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_superseded_pictures_tree ON superseded_pictures(tree_id);
+
+CREATE INDEX IF NOT EXISTS idx_members_tree_deleted_at
+    ON members(tree_id, deleted_at)
+    WHERE deleted_at IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_members_tree_deleted_at;
+DROP TABLE IF EXISTS superseded_pictures CASCADE;
+
+-- +goose StatementEnd
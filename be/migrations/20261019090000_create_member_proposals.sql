@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Edits suggested by tree members without edit rights, waiting on an editor.
+-- The payload is the proposed member or spouse relationship, the base is what
+-- the proposer edited so a proposal overtaken by later edits can be told apart
+CREATE TABLE IF NOT EXISTS member_proposals (
+    proposal_id SERIAL,
+    tree_id INT NOT NULL,
+    proposer_user_id INT NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    member_id INT,
+    spouse_id INT,
+    payload JSONB,
+    base_version INT,
+    base_values JSONB,
+    comment TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reviewer_user_id INT,
+    review_comment TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    reviewed_at TIMESTAMP
+);
+
+ALTER TABLE member_proposals
+    ADD CONSTRAINT pk_member_proposals PRIMARY KEY (proposal_id),
+    ADD CONSTRAINT fk_member_proposals_tree FOREIGN KEY (tree_id) REFERENCES family_trees(tree_id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_member_proposals_proposer FOREIGN KEY (proposer_user_id) REFERENCES users(user_id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_member_proposals_member FOREIGN KEY (member_id) REFERENCES members(member_id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_member_proposals_spouse FOREIGN KEY (spouse_id) REFERENCES members_spouse(spouse_id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_member_proposals_reviewer FOREIGN KEY (reviewer_user_id) REFERENCES users(user_id) ON DELETE SET NULL,
+    ADD CONSTRAINT chk_member_proposals_kind CHECK (kind IN ('create_member', 'update_member', 'add_spouse', 'update_spouse', 'remove_spouse')),
+    ADD CONSTRAINT chk_member_proposals_status CHECK (status IN ('pending', 'changes_requested', 'approved', 'rejected'));
+
+CREATE INDEX IF NOT EXISTS idx_member_proposals_tree_status ON member_proposals(tree_id, status);
+CREATE INDEX IF NOT EXISTS idx_member_proposals_proposer ON member_proposals(proposer_user_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS member_proposals CASCADE;
+
+-- +goose StatementEnd
@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Security relevant actions like logins, invitations, share links and account
+-- changes. Events are only ever appended, so the actor, target and tree are
+-- kept as plain ids that outlive what they point to
+CREATE TABLE IF NOT EXISTS audit_events (
+    audit_id BIGSERIAL,
+    actor_user_id INT,                      -- NULL for anonymous visitors and failed logins
+    action VARCHAR(50) NOT NULL,
+    target_type VARCHAR(30) NOT NULL,
+    target_id VARCHAR(100),
+    tree_id INT,                            -- tree the event belongs to, its owners can read it
+    details JSONB,
+    ip_address VARCHAR(64),
+    user_agent TEXT,
+    request_id VARCHAR(64),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE audit_events
+    ADD CONSTRAINT pk_audit_events PRIMARY KEY (audit_id);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_tree ON audit_events(tree_id, audit_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor ON audit_events(actor_user_id, audit_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_target ON audit_events(target_type, target_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_action ON audit_events(action);
+
+CREATE OR REPLACE FUNCTION reject_audit_event_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit events are append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_audit_events_append_only
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION reject_audit_event_change();
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS audit_events CASCADE;
+DROP FUNCTION IF EXISTS reject_audit_event_change();
+
+-- +goose StatementEnd
@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Every request stamps the history and score rows it writes with a changeset
+-- id so related edits can be reviewed and reverted together. Only explicit
+-- batches opened ahead of time and reverted changesets get a row here, the
+-- rest exist through the rows that carry their id
+CREATE TABLE IF NOT EXISTS changesets (
+    changeset_id UUID NOT NULL DEFAULT gen_random_uuid(),
+    tree_id INT NOT NULL,
+    user_id INT NOT NULL,
+    description VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    reverted_at TIMESTAMP,
+    reverted_by INT,
+    reverted_in UUID                        -- changeset the revert was written in
+);
+
+ALTER TABLE changesets
+    ADD CONSTRAINT pk_changesets PRIMARY KEY (changeset_id),
+    ADD CONSTRAINT fk_changesets_tree FOREIGN KEY (tree_id) REFERENCES family_trees(tree_id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_changesets_user FOREIGN KEY (user_id) REFERENCES users(user_id),
+    ADD CONSTRAINT fk_changesets_reverted_by FOREIGN KEY (reverted_by) REFERENCES users(user_id);
+
+CREATE INDEX IF NOT EXISTS idx_changesets_tree_id ON changesets(tree_id);
+
+ALTER TABLE members_history ADD COLUMN IF NOT EXISTS changeset_id UUID;
+ALTER TABLE user_scores ADD COLUMN IF NOT EXISTS changeset_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_members_history_changeset_id ON members_history(changeset_id);
+CREATE INDEX IF NOT EXISTS idx_user_scores_changeset_id ON user_scores(changeset_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_user_scores_changeset_id;
+DROP INDEX IF EXISTS idx_members_history_changeset_id;
+ALTER TABLE user_scores DROP COLUMN IF EXISTS changeset_id;
+ALTER TABLE members_history DROP COLUMN IF EXISTS changeset_id;
+DROP TABLE IF EXISTS changesets CASCADE;
+
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- The role an invitation grants once accepted, ownership is only ever
+-- transferred so an invitation never grants it
+ALTER TABLE family_tree_invitations ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'editor';
+
+ALTER TABLE family_tree_invitations
+    ADD CONSTRAINT chk_family_tree_invitations_role CHECK (role IN ('editor', 'viewer'));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE family_tree_invitations DROP CONSTRAINT IF EXISTS chk_family_tree_invitations_role;
+ALTER TABLE family_tree_invitations DROP COLUMN IF EXISTS role;
+
+-- +goose StatementEnd
@@ -41,6 +41,7 @@ const HistoryDiffDialog: React.FC<HistoryDiffDialogProps> = ({ open, onClose, hi
       mother_id: t('member.motherId'),
       nicknames: t('member.nicknames'),
       profession: t('member.profession'),
+      notes: t('member.notes'),
       spouse_id: t('spouse.spouseId'),
       marriage_date: t('spouse.marriageDate'),
       divorce_date: t('spouse.divorceDate'),
@@ -52,6 +53,11 @@ const HistoryDiffDialog: React.FC<HistoryDiffDialogProps> = ({ open, onClose, hi
       return `${t('member.name')} (${langCode})`;
     }
 
+    if (field.startsWith('biography_')) {
+      const langCode = field.substring(10).toUpperCase();
+      return `${t('member.biography')} (${langCode})`;
+    }
+
     return labels[field] || field;
   };
 
@@ -108,7 +114,12 @@ const HistoryDiffDialog: React.FC<HistoryDiffDialogProps> = ({ open, onClose, hi
           }
         }
       });
-    } else if (history.change_type === 'UPDATE' || history.change_type === 'UPDATE_SPOUSE') {
+    } else if ([
+      'UPDATE',
+      'UPDATE_SPOUSE',
+      'UPDATE_BIOGRAPHY',
+      'UPDATE_NOTES',
+    ].includes(history.change_type)) {
       // For UPDATE operations, show only changed fields
       const oldValues = history.old_values || {};
       const newValues = history.new_values || {};
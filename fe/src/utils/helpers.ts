@@ -151,6 +151,8 @@ export const getChangeTypeColor = (changeType: string): 'default' | 'primary' |
     case 'INSERT':
       return 'success';
     case 'UPDATE':
+    case 'UPDATE_BIOGRAPHY':
+    case 'UPDATE_NOTES':
       return 'info';
     case 'DELETE':
       return 'error';